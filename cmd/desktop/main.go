@@ -4,16 +4,44 @@
 package main
 
 import (
+	"discoveryx/internal/config"
 	"discoveryx/internal/constants"
 	"discoveryx/internal/core/game"
+	"discoveryx/internal/utils/random"
+	"flag"
 	"github.com/hajimehoshi/ebiten/v2"
+	"log"
 )
 
 func main() {
+	seed := flag.String("seed", "", "run seed (a number or any text, e.g. a date); empty picks a fresh seed per run")
+	profileName := flag.String("profile", string(config.DefaultProfile), "config profile (development, production, mobile or web)")
+	configPath := flag.String("config", "", "optional YAML file applied on top of the profile")
+	flag.Parse()
+
+	profile, err := config.ParseProfile(*profileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg, err := config.Load(profile, *configPath)
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	config.Set(cfg)
+
 	ebiten.SetWindowSize(constants.ScreenWidth, constants.ScreenHeight)
 	ebiten.SetWindowTitle("DiscoveryX")
 
-	if err := ebiten.RunGame(game.New()); err != nil {
+	g := game.New()
+	if *seed != "" {
+		runSeed, err := random.ParseSeed(*seed)
+		if err != nil {
+			log.Fatalf("invalid seed: %v", err)
+		}
+		g.SetSeed(runSeed)
+	}
+
+	if err := ebiten.RunGame(g); err != nil {
 		panic(err)
 	}
 }
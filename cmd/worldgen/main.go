@@ -0,0 +1,277 @@
+// Command worldgen runs the procedural world generator without opening a window.
+// It writes the generated WorldMap as JSON and as a downscaled PNG overview so that
+// layouts can be reproduced from a seed and compared between generator changes.
+//
+// The -seed flag takes the run seed the game prints at the start of a run ("Starting
+// run with seed ..."), a number or any text, and derives the world generation seed
+// from it like the game does, so a run from a bug report can be reproduced. The
+// -worldgen-seed flag sets the world generation seed directly instead.
+//
+// With -batch N it instead generates N worlds with consecutive world generation seeds,
+// starting at the one given, validates each of them against the generator invariants
+// and prints a statistical report. The command exits with status 1 if any world failed to
+// generate or broke an invariant, so it can guard against generator regressions.
+//
+// Usage:
+//
+//	go run ./cmd/worldgen -seed 42 -json world.json -png world.png
+//	go run ./cmd/worldgen -profile production -config myconfig.yaml -tile 24
+//	go run ./cmd/worldgen -worldgen-config worldgen.json -tile 24
+//	go run ./cmd/worldgen -seed 42 -synth-variants 3 -png world.png
+//	go run ./cmd/worldgen -worldgen-seed 1 -batch 500 -report report.json
+//
+// The generator settings start from the worldgen section of the config profile,
+// loaded like the game does with an optional YAML override file (-profile, -config).
+// A JSON encoded worldgen.WorldGenConfig given with -worldgen-config is applied on
+// top of them, and values given on the command line override both.
+package main
+
+import (
+	"discoveryx/internal/config"
+	"discoveryx/internal/core/worldgen"
+	"discoveryx/internal/utils/random"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image/png"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "worldgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the arguments, generates the world and writes the requested outputs
+func run(args []string) error {
+	flags := flag.NewFlagSet("worldgen", flag.ContinueOnError)
+
+	profileName := flags.String("profile", string(config.DefaultProfile), "config profile (development, production, mobile or web)")
+	configPath := flags.String("config", "", "optional YAML file applied on top of the profile")
+	worldgenConfigPath := flags.String("worldgen-config", "", "path to a JSON encoded WorldGenConfig applied on top of the profile")
+	seed := flags.String("seed", "", "run seed as printed by the game (a number or any text); empty picks a fresh seed")
+	worldgenSeed := flags.Int64("worldgen-seed", 0, "world generation seed, used as is instead of deriving it from -seed")
+	minLength := flags.Int("min-length", 0, "minimum number of main path cells")
+	maxLength := flags.Int("max-length", 0, "maximum number of main path cells")
+	branchProbability := flags.Float64("branch-prob", 0, "probability of creating a branch (0.0-1.0)")
+	branchMaxDepth := flags.Int("branch-depth", 0, "maximum branch depth from the main path")
+	deadEndProbability := flags.Float64("dead-end-prob", 0, "probability of a branch ending in a dead-end (0.0-1.0)")
+	subBranchProbability := flags.Float64("sub-branch-prob", 0, "probability of branches from branches (0.0-1.0)")
+	weights := flags.String("weights", "", "snippet type weights, e.g. path=10,junction=5,dead-end=2")
+	synthVariants := flags.Int("synth-variants", 0, "synthesized snippets per connector layout (0 disables synthesis)")
+	synthWeight := flags.Int("synth-weight", 0, "selection weight of each synthesized snippet")
+	jsonPath := flags.String("json", "worldgen.json", "output path for the JSON world map (empty to skip)")
+	pngPath := flags.String("png", "worldgen.png", "output path for the PNG overview (empty to skip)")
+	tileSize := flags.Int("tile", 16, "size of one world cell in the PNG overview, in pixels")
+	batch := flags.Int("batch", 0, "generate this many worlds with consecutive seeds and print a report")
+	reportPath := flags.String("report", "", "output path for the JSON batch report (batch mode only)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	profile, err := config.ParseProfile(*profileName)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(profile, *configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	worldConfig := worldgen.NewWorldGenConfig(cfg.WorldGen)
+
+	if *worldgenConfigPath != "" {
+		data, err := os.ReadFile(*worldgenConfigPath)
+		if err != nil {
+			return fmt.Errorf("failed to read worldgen config: %w", err)
+		}
+		if err := json.Unmarshal(data, worldConfig); err != nil {
+			return fmt.Errorf("failed to parse worldgen config %s: %w", *worldgenConfigPath, err)
+		}
+	}
+
+	// Apply only the flags that were explicitly set, so they override the config files
+	var flagErr error
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "seed":
+			runSeed, err := random.ParseSeed(*seed)
+			if err != nil {
+				flagErr = err
+				return
+			}
+			worldConfig.Seed = runSeed.Derive(random.StreamWorldGen)
+		case "worldgen-seed":
+			worldConfig.Seed = *worldgenSeed
+		case "min-length":
+			worldConfig.MainPathMinLength = *minLength
+		case "max-length":
+			worldConfig.MainPathMaxLength = *maxLength
+		case "branch-prob":
+			worldConfig.BranchProbability = *branchProbability
+		case "branch-depth":
+			worldConfig.BranchMaxDepth = *branchMaxDepth
+		case "dead-end-prob":
+			worldConfig.DeadEndProbability = *deadEndProbability
+		case "sub-branch-prob":
+			worldConfig.SubBranchProbability = *subBranchProbability
+		case "weights":
+			parsed, err := parseTypeWeights(*weights)
+			if err != nil {
+				flagErr = err
+				return
+			}
+			worldConfig.SnippetTypeWeights = parsed
+		case "synth-variants":
+			worldConfig.Synthesis.VariantsPerLayout = *synthVariants
+		case "synth-weight":
+			worldConfig.Synthesis.Weight = *synthWeight
+		}
+	})
+	if flagErr != nil {
+		return flagErr
+	}
+	if isSet(flags, "seed") && isSet(flags, "worldgen-seed") {
+		return fmt.Errorf("-seed and -worldgen-seed are mutually exclusive")
+	}
+
+	generator, err := worldgen.NewHeadlessWorldGenerator()
+	if err != nil {
+		return err
+	}
+
+	if *batch > 0 {
+		return runBatch(generator, worldConfig, *batch, *reportPath)
+	}
+
+	worldMap, err := generator.GenerateWorld(worldConfig)
+	if err != nil {
+		return fmt.Errorf("generation failed for seed %d: %w", worldConfig.Seed, err)
+	}
+
+	fmt.Printf("seed=%d cells=%d main_path=%d branch_cells=%d\n",
+		worldConfig.Seed, worldMap.GetCellCount(), worldMap.GetMainPathLength(), worldMap.GetBranchCount())
+
+	for _, violation := range worldgen.ValidateWorldMap(worldMap, worldConfig) {
+		fmt.Fprintln(os.Stderr, "violation:", violation)
+	}
+
+	if *jsonPath != "" {
+		if err := writeJSON(*jsonPath, worldMap.Export(worldConfig)); err != nil {
+			return err
+		}
+		fmt.Println("wrote", *jsonPath)
+	}
+
+	if *pngPath != "" {
+		if err := writePNG(*pngPath, worldMap, *tileSize); err != nil {
+			return err
+		}
+		fmt.Println("wrote", *pngPath)
+	}
+
+	return nil
+}
+
+// runBatch generates count worlds, prints the report and optionally writes it as JSON
+func runBatch(generator *worldgen.WorldGenerator, config *worldgen.WorldGenConfig, count int, reportPath string) error {
+	report := generator.RunBatch(config, count)
+
+	if err := report.WriteText(os.Stdout); err != nil {
+		return err
+	}
+
+	if reportPath != "" {
+		file, err := os.Create(reportPath)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", reportPath, err)
+		}
+		defer file.Close()
+
+		if err := report.WriteJSON(file); err != nil {
+			return err
+		}
+		fmt.Println("wrote", reportPath)
+	}
+
+	if !report.OK() {
+		return fmt.Errorf("%d of %d worlds failed to generate or broke an invariant", report.Failures+report.Invalid, report.Worlds)
+	}
+	return nil
+}
+
+// isSet reports whether a flag was given on the command line
+func isSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
+
+// parseTypeWeights parses a list like "path=10,junction=5,dead-end=2"
+func parseTypeWeights(value string) (map[worldgen.SnippetType]int, error) {
+	weights := make(map[worldgen.SnippetType]int)
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, weightText, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid weight %q, expected type=weight", entry)
+		}
+
+		snippetType := worldgen.SnippetType(strings.TrimSpace(name))
+		switch snippetType {
+		case worldgen.SnippetTypePath, worldgen.SnippetTypeJunction, worldgen.SnippetTypeDeadEnd:
+		default:
+			return nil, fmt.Errorf("unknown snippet type %q", name)
+		}
+
+		weight, err := strconv.Atoi(strings.TrimSpace(weightText))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight for %s: %q", name, weightText)
+		}
+
+		weights[snippetType] = weight
+	}
+
+	return weights, nil
+}
+
+// writeJSON writes the world map export to the given path
+func writeJSON(path string, export *worldgen.WorldMapExport) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	return export.WriteJSON(file)
+}
+
+// writePNG renders the world overview and writes it to the given path
+func writePNG(path string, worldMap *worldgen.WorldMap, tileSize int) error {
+	overview, err := worldgen.RenderOverview(worldMap, tileSize)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, overview); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	return nil
+}
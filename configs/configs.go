@@ -0,0 +1,12 @@
+// Package configs embeds the per-profile configuration files
+// (development.yaml, production.yaml, mobile.yaml, web.yaml) into the binary,
+// so every platform can load its profile without a filesystem.
+// The files are parsed by the internal/config package.
+package configs
+
+import "embed"
+
+// Files contains all profile configuration files
+//
+//go:embed *.yaml
+var Files embed.FS
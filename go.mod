@@ -5,6 +5,7 @@ go 1.24.1
 require (
 	github.com/hajimehoshi/ebiten/v2 v2.8.8
 	github.com/tducasse/ebiten-collisions v0.0.0-20220322101126-d1a0a59a4b98
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -1 +1,223 @@
-package config
\ No newline at end of file
+// Package config provides typed game configuration loaded from the profile
+// files in the configs directory (development.yaml, production.yaml,
+// mobile.yaml and web.yaml).
+//
+// Every tunable value has a default (see Defaults), which mirrors the values
+// in the constants package. A profile file only needs to list the values it
+// changes; everything else keeps its default. Loading a profile validates all
+// values and reports unknown keys, so typos in a profile are caught early.
+//
+// The active configuration is process-wide and is read by gameplay code
+// through Get. The profile is chosen by build target (see DefaultProfile)
+// and can be overridden on desktop with command line flags.
+package config
+
+import "sync"
+
+// Profile names a configuration profile. Each profile has a matching
+// <profile>.yaml file in the configs directory.
+type Profile string
+
+const (
+	ProfileDevelopment Profile = "development" // Desktop development builds
+	ProfileProduction  Profile = "production"  // Desktop release builds
+	ProfileMobile      Profile = "mobile"      // iOS and Android builds
+	ProfileWeb         Profile = "web"         // WebAssembly builds
+)
+
+// Profiles lists all known profiles
+var Profiles = []Profile{ProfileDevelopment, ProfileProduction, ProfileMobile, ProfileWeb}
+
+// Config holds all tunable game settings
+type Config struct {
+	WorldGen  WorldGenSettings  `yaml:"worldgen"`
+	Camera    CameraSettings    `yaml:"camera"`
+	Physics   PhysicsSettings   `yaml:"physics"`
+	Player    PlayerSettings    `yaml:"player"`
+	Weapons   WeaponSettings    `yaml:"weapons"`
+	Enemies   EnemySettings     `yaml:"enemies"`
+	Streaming StreamingSettings `yaml:"streaming"`
+}
+
+// WorldGenSettings configures procedural world generation.
+// It feeds worldgen.WorldGenConfig; the seed is not configured here
+// because it is chosen per run.
+type WorldGenSettings struct {
+	MainPathMinLength    int               `yaml:"main_path_min_length"`   // Minimum number of snippets in main path
+	MainPathMaxLength    int               `yaml:"main_path_max_length"`   // Maximum number of snippets in main path
+	BranchProbability    float64           `yaml:"branch_probability"`     // Probability of creating a branch (0.0-1.0)
+	BranchMaxDepth       int               `yaml:"branch_max_depth"`       // Maximum branch depth from main path
+	DeadEndProbability   float64           `yaml:"dead_end_probability"`   // Probability of branch ending in dead-end
+	SubBranchProbability float64           `yaml:"sub_branch_probability"` // Probability of branches from branches
+	SnippetTypeWeights   map[string]int    `yaml:"snippet_type_weights"`   // Relative weights for snippet types
+	Synthesis            SynthesisSettings `yaml:"synthesis"`              // Procedurally synthesized snippets
+	BiomeMode            string            `yaml:"biome_mode"`             // How the world is split into biomes ("main-path" or "branch")
+	Biomes               []BiomeSettings   `yaml:"biomes"`                 // Themed regions of the world (empty for a single region)
+	Infinite             bool              `yaml:"infinite"`               // Grow the world endlessly around the generated loop
+	ArenaCount           int               `yaml:"arena_count"`            // Dead-ends and large junctions marked as boss arenas (0 disables bosses)
+}
+
+// BiomeSettings configures a themed region of the world.
+// Empty values fall back to the global settings.
+type BiomeSettings struct {
+	Name               string         `yaml:"name"`                 // Unique name of the biome
+	Snippets           []string       `yaml:"snippets"`             // Snippet filename patterns, e.g. "Worldgen_l*" (empty for all snippets)
+	SnippetTypeWeights map[string]int `yaml:"snippet_type_weights"` // Relative weights for snippet types
+	Background         string         `yaml:"background"`           // Asset path of the background image
+	EnemyTypes         []string       `yaml:"enemy_types"`          // Enemy types spawned in this biome
+	LightRadius        float64        `yaml:"light_radius"`         // Radius of the player light as a fraction of the screen width
+}
+
+// SynthesisSettings configures procedurally synthesized world snippets,
+// which are mixed into the hand-drawn snippets by weight
+type SynthesisSettings struct {
+	VariantsPerLayout   int     `yaml:"variants_per_layout"`  // Synthesized snippets per connector layout (0 disables synthesis)
+	Weight              int     `yaml:"weight"`               // Selection weight of each synthesized snippet (hand-drawn snippets mostly use 10)
+	FillProbability     float64 `yaml:"fill_probability"`     // Initial probability of a grid point being rock
+	SmoothingIterations int     `yaml:"smoothing_iterations"` // Number of cellular automaton passes
+}
+
+// CameraSettings configures how the camera follows the player
+type CameraSettings struct {
+	DeadZoneX           float64 `yaml:"dead_zone_x"`          // Horizontal deadzone as a fraction of screen width
+	DeadZoneY           float64 `yaml:"dead_zone_y"`          // Vertical deadzone as a fraction of screen height
+	InterpolationFactor float64 `yaml:"interpolation_factor"` // How smoothly the camera moves (lower = smoother)
+	VelocityThreshold   float64 `yaml:"velocity_threshold"`   // Player velocity below which the camera centers
+	CenteringStrength   float64 `yaml:"centering_strength"`   // How quickly the camera centers on a stopped player
+}
+
+// PhysicsSettings configures global physics forces
+type PhysicsSettings struct {
+	GravityForce         float64 `yaml:"gravity_force"`          // Strength of the gravity force
+	LowVelocityThreshold float64 `yaml:"low_velocity_threshold"` // Velocity below which gravity is applied
+	StepsPerSecond       int     `yaml:"steps_per_second"`       // Fixed rate at which the physics world is stepped
+	MaxStepsPerUpdate    int     `yaml:"max_steps_per_update"`   // Steps after which a slow frame drops the remaining time
+	Broadphase           string  `yaml:"broadphase"`             // Broadphase for collision detection: grid, tree or sweep_and_prune
+
+	PlayerContact ContactSettings `yaml:"player_contact"` // Response of the player's ship to hitting a wall
+	BulletContact ContactSettings `yaml:"bullet_contact"` // Response of ricochet rounds to hitting a wall
+	DebrisContact ContactSettings `yaml:"debris_contact"` // Response of debris to hitting a wall
+}
+
+// ContactSettings configures how an entity bounces off, slides along and is damaged by walls
+type ContactSettings struct {
+	Restitution     float64 `yaml:"restitution"`      // Part of the speed into the wall that bounces back (0-1)
+	Friction        float64 `yaml:"friction"`         // Friction coefficient that slows down the speed along the wall
+	DamagePerSpeed  float64 `yaml:"damage_per_speed"` // Damage per unit per second of speed into the wall above the threshold
+	DamageThreshold float64 `yaml:"damage_threshold"` // Speed into the wall in units per second that does no damage
+}
+
+// PlayerSettings configures player movement
+type PlayerSettings struct {
+	RotationPerSecond       float64 `yaml:"rotation_per_second"`       // Keyboard rotation speed in radians per second
+	MaxAcceleration         float64 `yaml:"max_acceleration"`          // Maximum velocity of the ship
+	RotationSmoothingMin    float64 `yaml:"rotation_smoothing_min"`    // Rotation smoothing at full speed
+	RotationSmoothingMax    float64 `yaml:"rotation_smoothing_max"`    // Rotation smoothing when standing still
+	VelocitySmoothingFactor float64 `yaml:"velocity_smoothing_factor"` // Smoothing factor for velocity changes
+	CurvePower              float64 `yaml:"curve_power"`               // How strongly speed affects the turning radius
+}
+
+// WeaponSettings configures player and enemy weapons
+type WeaponSettings struct {
+	FireInterval       float64 `yaml:"fire_interval"`        // Seconds between player shots while holding fire
+	EnemyFireInterval  float64 `yaml:"enemy_fire_interval"`  // Seconds between enemy shots
+	EnemyShootRadius   float64 `yaml:"enemy_shoot_radius"`   // Distance at which enemies start shooting
+	BulletInitialSpeed float64 `yaml:"bullet_initial_speed"` // Starting speed of bullets in units per frame
+	BulletAcceleration float64 `yaml:"bullet_acceleration"`  // Multiplicative acceleration factor per frame
+	BulletMaxLifetime  float64 `yaml:"bullet_max_lifetime"`  // Seconds before a bullet despawns
+	RicochetBounces    int     `yaml:"ricochet_bounces"`     // Times player bullets bounce off walls before a wall destroys them, 0 for rounds that do not ricochet
+	PlayerBulletDamage float64 `yaml:"player_bullet_damage"` // Damage dealt by player bullets
+	EnemyBulletDamage  float64 `yaml:"enemy_bullet_damage"`  // Damage dealt by enemy bullets
+}
+
+// EnemySettings tunes enemy stats on top of the enemy archetypes
+type EnemySettings struct {
+	HealthByType map[string]float64 `yaml:"health_by_type"` // Maximum health per enemy type, overriding its archetype ("Default" is the fallback for types without one)
+	Director     DirectorSettings   `yaml:"director"`       // Waves of enemies spawned during the run
+	Feedback     FeedbackSettings   `yaml:"feedback"`       // How hits on enemies are shown
+
+	// Deprecated: behaviors come from the enemy archetypes. Entries still override the
+	// behavior of their enemy type ("Default" applies to types without an archetype).
+	BehaviorByType map[string]string `yaml:"behavior_by_type"`
+}
+
+// FeedbackSettings configures how hits on enemies are shown to the player. Every effect
+// adds draw calls, so all of them are off by default for phones and enabled by the desktop profiles.
+type FeedbackSettings struct {
+	HealthBars           bool    `yaml:"health_bars"`            // Show a health bar above enemies once they were hit
+	HitFlash             bool    `yaml:"hit_flash"`              // Flash enemies white when they are hit
+	HitFlashDuration     float64 `yaml:"hit_flash_duration"`     // Seconds the hit flash fades over
+	DamageNumbers        bool    `yaml:"damage_numbers"`         // Float the damage of each hit up from the enemy
+	DamageNumberLifetime float64 `yaml:"damage_number_lifetime"` // Seconds a damage number rises before it has faded
+	MaxDamageNumbers     int     `yaml:"max_damage_numbers"`     // Damage numbers shown at once; the oldest is reused when all are in use
+}
+
+// DirectorSettings configures the spawn director, which sends waves of enemies into the
+// loaded chunks out of view. Waves grow with the progress along the main path, the time
+// played and the kill rate, and stop for a quiet period once the player is under pressure.
+type DirectorSettings struct {
+	Enabled            bool    `yaml:"enabled"`               // Spawn waves during the run
+	WaveInterval       float64 `yaml:"wave_interval"`         // Seconds between waves while building up
+	WaveSize           int     `yaml:"wave_size"`             // Enemies in the first wave
+	MaxWaveSize        int     `yaml:"max_wave_size"`         // Most enemies in a wave
+	WaveSpread         float64 `yaml:"wave_spread"`           // Seconds over which the enemies of a wave arrive
+	WavesPerCycle      int     `yaml:"waves_per_cycle"`       // Waves before a quiet period
+	QuietPeriod        float64 `yaml:"quiet_period"`          // Seconds without waves after a cycle or a pressure peak
+	PeakPressure       float64 `yaml:"peak_pressure"`         // Pressure at which the waves stop for a quiet period
+	PressureDecay      float64 `yaml:"pressure_decay"`        // Rate per second at which the pressure fades
+	ProgressRamp       float64 `yaml:"progress_ramp"`         // Extra difficulty at the end of the main path (1 doubles the waves)
+	TimeRamp           float64 `yaml:"time_ramp"`             // Extra difficulty per minute played
+	KillRateBonus      float64 `yaml:"kill_rate_bonus"`       // Extra difficulty per kill per minute
+	MaxEnemiesPerChunk int     `yaml:"max_enemies_per_chunk"` // Waves do not spawn into chunks with this many enemies
+	SpawnMaxDistance   float64 `yaml:"spawn_max_distance"`    // Farthest distance from the player at which waves spawn
+}
+
+// StreamingSettings configures how world chunks are loaded and unloaded
+// as the player moves through the world
+type StreamingSettings struct {
+	ImageBudgetMB float64 `yaml:"image_budget_mb"` // Snippet image memory kept for unloaded chunks (0 frees it immediately)
+	UnloadMargin  int     `yaml:"unload_margin"`   // Chunks beyond the visibility radius before a chunk is unloaded
+}
+
+// active holds the process-wide configuration
+var active = struct {
+	sync.RWMutex
+	config *Config
+}{}
+
+// Get returns the active configuration.
+// If no configuration has been set yet, the profile of the current build
+// target is loaded. If that fails, the defaults are used so the game can
+// always start.
+func Get() *Config {
+	active.RLock()
+	cfg := active.config
+	active.RUnlock()
+
+	if cfg != nil {
+		return cfg
+	}
+
+	loaded, err := LoadProfile(DefaultProfile)
+	if err != nil {
+		reportLoadError(err)
+		loaded = Defaults()
+	}
+
+	active.Lock()
+	if active.config == nil {
+		active.config = loaded
+	}
+	cfg = active.config
+	active.Unlock()
+
+	return cfg
+}
+
+// Set replaces the active configuration.
+// It should be called during startup, before the game is created.
+func Set(cfg *Config) {
+	active.Lock()
+	active.config = cfg
+	active.Unlock()
+}
@@ -1 +1,109 @@
-package config
\ No newline at end of file
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDefaultsAreValid tests that the defaults pass validation
+func TestDefaultsAreValid(t *testing.T) {
+	if err := Defaults().Validate(); err != nil {
+		t.Fatalf("Expected defaults to be valid, got %v", err)
+	}
+}
+
+// TestLoadEmbeddedProfiles tests that every profile file is present and valid
+func TestLoadEmbeddedProfiles(t *testing.T) {
+	for _, profile := range Profiles {
+		t.Run(string(profile), func(t *testing.T) {
+			if _, err := LoadProfile(profile); err != nil {
+				t.Fatalf("Failed to load profile: %v", err)
+			}
+		})
+	}
+}
+
+// TestParseKeepsDefaults tests that keys missing from a file keep their default value
+func TestParseKeepsDefaults(t *testing.T) {
+	cfg, err := Parse([]byte("weapons:\n  fire_interval: 0.1\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	if cfg.Weapons.FireInterval != 0.1 {
+		t.Errorf("Expected fire_interval 0.1, got %v", cfg.Weapons.FireInterval)
+	}
+	if cfg.Weapons.EnemyFireInterval != Defaults().Weapons.EnemyFireInterval {
+		t.Errorf("Expected enemy_fire_interval to keep its default, got %v", cfg.Weapons.EnemyFireInterval)
+	}
+	if cfg.Enemies.HealthByType["Default"] != Defaults().Enemies.HealthByType["Default"] {
+		t.Error("Expected enemy health to keep its defaults")
+	}
+}
+
+// TestParseDeprecatedBehaviorByType tests that files written for enemies.behavior_by_type still load
+func TestParseDeprecatedBehaviorByType(t *testing.T) {
+	cfg, err := Parse([]byte("enemies:\n  behavior_by_type:\n    Default: turret\n    Pilz: hunter\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+	if cfg.Enemies.BehaviorByType["Pilz"] != "hunter" {
+		t.Errorf("Expected the hunter behavior for Pilz, got %q", cfg.Enemies.BehaviorByType["Pilz"])
+	}
+}
+
+// TestParseRejectsInvalidConfig tests that unknown keys and out of range values are reported
+func TestParseRejectsInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		contains string
+	}{
+		{name: "Unknown key", input: "weapons:\n  fire_intervall: 0.1\n", contains: "fire_intervall"},
+		{name: "Probability out of range", input: "worldgen:\n  branch_probability: 1.5\n", contains: "worldgen.branch_probability"},
+		{name: "Max below min", input: "worldgen:\n  main_path_min_length: 30\n  main_path_max_length: 20\n", contains: "worldgen.main_path_max_length"},
+		{name: "Unknown snippet type", input: "worldgen:\n  snippet_type_weights:\n    loop: 1\n", contains: "worldgen.snippet_type_weights.loop"},
+		{name: "Zero fire interval", input: "weapons:\n  fire_interval: 0\n", contains: "weapons.fire_interval"},
+		{name: "Unknown biome mode", input: "worldgen:\n  biome_mode: random\n", contains: "worldgen.biome_mode"},
+		{name: "Duplicate biome", input: "worldgen:\n  biomes:\n    - name: ice\n    - name: ice\n", contains: "duplicate biome"},
+		{name: "Negative enemy health", input: "enemies:\n  health_by_type:\n    Pilz: -1\n", contains: "enemies.health_by_type.Pilz"},
+		{name: "Unknown enemy behavior", input: "enemies:\n  behavior_by_type:\n    Pilz: sleeper\n", contains: "enemies.behavior_by_type.Pilz"},
+		{name: "Spawns beyond loaded chunks", input: "enemies:\n  director:\n    spawn_max_distance: 20000\n", contains: "enemies.director.spawn_max_distance"},
+		{name: "No damage numbers", input: "enemies:\n  feedback:\n    max_damage_numbers: 0\n", contains: "enemies.feedback.max_damage_numbers"},
+		{name: "Negative unload margin", input: "streaming:\n  unload_margin: -1\n", contains: "streaming.unload_margin"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.input))
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), tc.contains) {
+				t.Errorf("Expected error to mention %q, got %v", tc.contains, err)
+			}
+		})
+	}
+}
+
+// TestLoadOverrideFile tests applying a file from disk on top of a profile
+func TestLoadOverrideFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "override.yaml")
+	if err := os.WriteFile(path, []byte("player:\n  max_acceleration: 500\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write override file: %v", err)
+	}
+
+	cfg, err := Load(ProfileDevelopment, path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Player.MaxAcceleration != 500 {
+		t.Errorf("Expected max_acceleration 500, got %v", cfg.Player.MaxAcceleration)
+	}
+
+	if _, err := Load(ProfileDevelopment, filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected an error for a missing override file")
+	}
+}
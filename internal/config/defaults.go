@@ -1 +1,108 @@
-package config
\ No newline at end of file
+package config
+
+import "discoveryx/internal/constants"
+
+// Defaults returns the default configuration.
+// These values are used for every key that a profile file does not set.
+func Defaults() *Config {
+	return &Config{
+		WorldGen: WorldGenSettings{
+			MainPathMinLength:    20,
+			MainPathMaxLength:    40,
+			BranchProbability:    0.3,
+			BranchMaxDepth:       5,
+			DeadEndProbability:   0.5,
+			SubBranchProbability: 0.2,
+			SnippetTypeWeights: map[string]int{
+				"path":     10, // Higher weight for path segments
+				"junction": 5,  // Medium weight for junctions
+				"dead-end": 2,  // Lower weight for dead-ends
+			},
+			Synthesis: SynthesisSettings{
+				VariantsPerLayout:   0, // Only hand-drawn snippets by default
+				Weight:              5,
+				FillProbability:     0.55,
+				SmoothingIterations: 4,
+			},
+			BiomeMode:  "main-path",
+			ArenaCount: 2,
+		},
+		Camera: CameraSettings{
+			DeadZoneX:           constants.CameraDeadZoneX,
+			DeadZoneY:           constants.CameraDeadZoneY,
+			InterpolationFactor: constants.CameraInterpolationFactor,
+			VelocityThreshold:   constants.CameraVelocityThreshold,
+			CenteringStrength:   constants.CameraCenteringStrength,
+		},
+		Physics: PhysicsSettings{
+			GravityForce:         constants.GravityForce,
+			LowVelocityThreshold: constants.LowVelocityThreshold,
+			StepsPerSecond:       constants.PhysicsStepsPerSecond,
+			MaxStepsPerUpdate:    constants.PhysicsMaxStepsPerUpdate,
+			Broadphase:           constants.PhysicsBroadphase,
+			PlayerContact: ContactSettings{
+				Restitution:     0.3,
+				Friction:        0.2,
+				DamagePerSpeed:  0.1,
+				DamageThreshold: 90.0,
+			},
+			BulletContact: ContactSettings{
+				Restitution: 0.9,
+				Friction:    0.05,
+			},
+			DebrisContact: ContactSettings{
+				Restitution: 0.5,
+				Friction:    0.3,
+			},
+		},
+		Player: PlayerSettings{
+			RotationPerSecond:       constants.RotationPerSecond,
+			MaxAcceleration:         constants.MaxAcceleration,
+			RotationSmoothingMin:    constants.RotationSmoothingMin,
+			RotationSmoothingMax:    constants.RotationSmoothingMax,
+			VelocitySmoothingFactor: constants.VelocitySmoothingFactor,
+			CurvePower:              constants.CurvePower,
+		},
+		Weapons: WeaponSettings{
+			FireInterval:       0.25,
+			EnemyFireInterval:  0.5,
+			EnemyShootRadius:   150.0,
+			BulletInitialSpeed: 3.0,
+			BulletAcceleration: 1.05,
+			BulletMaxLifetime:  2.0,
+			RicochetBounces:    0,
+			PlayerBulletDamage: 25.0,
+			EnemyBulletDamage:  15.0,
+		},
+		Enemies: EnemySettings{
+			HealthByType: map[string]float64{
+				"Default": 30.0,
+			},
+			Director: DirectorSettings{
+				Enabled:            true,
+				WaveInterval:       20,
+				WaveSize:           3,
+				MaxWaveSize:        12,
+				WaveSpread:         3,
+				WavesPerCycle:      3,
+				QuietPeriod:        30,
+				PeakPressure:       0.5, // Losing half of the health in a short time
+				PressureDecay:      0.05,
+				ProgressRamp:       1.0,
+				TimeRamp:           0.05,
+				KillRateBonus:      0.05,
+				MaxEnemiesPerChunk: 40,
+				SpawnMaxDistance:   1500,
+			},
+			Feedback: FeedbackSettings{
+				HitFlashDuration:     0.12,
+				DamageNumberLifetime: 0.8,
+				MaxDamageNumbers:     32,
+			},
+		},
+		Streaming: StreamingSettings{
+			ImageBudgetMB: 128,
+			UnloadMargin:  1,
+		},
+	}
+}
@@ -1 +1,106 @@
-package config
\ No newline at end of file
+package config
+
+import (
+	"bytes"
+	"discoveryx/configs"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"log"
+	"os"
+)
+
+// ParseProfile converts a profile name (e.g. from a command line flag) into a Profile
+func ParseProfile(name string) (Profile, error) {
+	for _, profile := range Profiles {
+		if string(profile) == name {
+			return profile, nil
+		}
+	}
+	return "", fmt.Errorf("unknown config profile %q", name)
+}
+
+// Load loads the embedded profile and then applies the optional override file
+// from disk on top of it. An empty overridePath loads only the profile.
+// The result is validated before it is returned.
+func Load(profile Profile, overridePath string) (*Config, error) {
+	cfg, err := loadEmbeddedProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if overridePath != "" {
+		data, err := os.ReadFile(overridePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := decodeInto(cfg, data, overridePath); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// LoadProfile loads and validates the embedded file of the given profile
+func LoadProfile(profile Profile) (*Config, error) {
+	return Load(profile, "")
+}
+
+// Parse parses YAML data on top of the defaults and validates the result
+func Parse(data []byte) (*Config, error) {
+	cfg := Defaults()
+	if err := decodeInto(cfg, data, "config"); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadEmbeddedProfile reads the embedded profile file on top of the defaults
+func loadEmbeddedProfile(profile Profile) (*Config, error) {
+	filename := string(profile) + ".yaml"
+
+	data, err := configs.Files.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile %s: %w", profile, err)
+	}
+
+	cfg := Defaults()
+	if err := decodeInto(cfg, data, filename); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// decodeInto decodes YAML data into an existing configuration.
+// Keys that are not present keep their current value, and unknown keys are reported
+// as errors so typos in profile files do not go unnoticed.
+func decodeInto(cfg *Config, data []byte, source string) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	err := decoder.Decode(cfg)
+	if errors.Is(err, io.EOF) {
+		// Empty file (or only comments): nothing to override
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+
+	return nil
+}
+
+// reportLoadError logs a configuration error that is recovered by falling back to defaults
+func reportLoadError(err error) {
+	log.Printf("config: %v (falling back to defaults)", err)
+}
@@ -0,0 +1,6 @@
+//go:build !mobile && !android && !ios && !js && !release
+
+package config
+
+// DefaultProfile is the profile loaded when none is requested explicitly
+const DefaultProfile = ProfileDevelopment
@@ -0,0 +1,6 @@
+//go:build release && !mobile && !android && !ios && !js
+
+package config
+
+// DefaultProfile is the profile loaded when none is requested explicitly
+const DefaultProfile = ProfileProduction
@@ -0,0 +1,6 @@
+//go:build js && !mobile && !android && !ios
+
+package config
+
+// DefaultProfile is the profile loaded when none is requested explicitly
+const DefaultProfile = ProfileWeb
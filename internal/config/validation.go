@@ -1 +1,204 @@
-package config
\ No newline at end of file
+package config
+
+import (
+	"discoveryx/internal/constants"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+)
+
+// snippetTypes lists the snippet types that may appear in worldgen.snippet_type_weights
+var snippetTypes = []string{"path", "junction", "dead-end"}
+
+// broadphases lists the broadphases that may be set in physics.broadphase
+var broadphases = []string{"grid", "tree", "sweep_and_prune"}
+
+// EnemyBehaviors lists the built-in enemy behaviors that may be set in the deprecated
+// enemies.behavior_by_type. It must match the behaviors of the enemies package, which tests it.
+var EnemyBehaviors = []string{"turret", "hunter", "skirmisher", "flyer", "burrower"}
+
+// validator collects all validation errors instead of stopping at the first one
+type validator struct {
+	errs []error
+}
+
+// check records an error for the key if the condition does not hold
+func (v *validator) check(ok bool, key, format string, args ...interface{}) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+}
+
+// probability checks that a value is within [0, 1]
+func (v *validator) probability(key string, value float64) {
+	v.check(value >= 0 && value <= 1, key, "must be between 0 and 1, got %v", value)
+}
+
+// positive checks that a value is greater than zero
+func (v *validator) positive(key string, value float64) {
+	v.check(value > 0, key, "must be greater than 0, got %v", value)
+}
+
+// nonNegative checks that a value is zero or greater
+func (v *validator) nonNegative(key string, value float64) {
+	v.check(value >= 0, key, "must not be negative, got %v", value)
+}
+
+// contact checks the values of a wall contact response
+func (v *validator) contact(key string, contact ContactSettings) {
+	v.probability(key+".restitution", contact.Restitution)
+	v.nonNegative(key+".friction", contact.Friction)
+	v.nonNegative(key+".damage_per_speed", contact.DamagePerSpeed)
+	v.nonNegative(key+".damage_threshold", contact.DamageThreshold)
+}
+
+// Validate checks that all values are within their allowed ranges.
+// All problems are reported at once, joined into a single error.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	// World generation
+	wg := c.WorldGen
+	v.check(wg.MainPathMinLength >= 4, "worldgen.main_path_min_length", "must be at least 4 to form a loop, got %d", wg.MainPathMinLength)
+	v.check(wg.MainPathMaxLength >= wg.MainPathMinLength, "worldgen.main_path_max_length",
+		"must not be smaller than main_path_min_length (%d), got %d", wg.MainPathMinLength, wg.MainPathMaxLength)
+	v.probability("worldgen.branch_probability", wg.BranchProbability)
+	v.check(wg.BranchMaxDepth >= 0, "worldgen.branch_max_depth", "must not be negative, got %d", wg.BranchMaxDepth)
+	v.probability("worldgen.dead_end_probability", wg.DeadEndProbability)
+	v.probability("worldgen.sub_branch_probability", wg.SubBranchProbability)
+	for _, name := range sortedKeys(wg.SnippetTypeWeights) {
+		key := "worldgen.snippet_type_weights." + name
+		v.check(isSnippetType(name), key, "unknown snippet type, expected one of %v", snippetTypes)
+		v.check(wg.SnippetTypeWeights[name] >= 0, key, "must not be negative, got %d", wg.SnippetTypeWeights[name])
+	}
+	v.check(wg.Synthesis.VariantsPerLayout >= 0, "worldgen.synthesis.variants_per_layout",
+		"must not be negative, got %d", wg.Synthesis.VariantsPerLayout)
+	v.check(wg.Synthesis.Weight >= 0, "worldgen.synthesis.weight", "must not be negative, got %d", wg.Synthesis.Weight)
+	v.probability("worldgen.synthesis.fill_probability", wg.Synthesis.FillProbability)
+	v.check(wg.Synthesis.SmoothingIterations >= 0, "worldgen.synthesis.smoothing_iterations",
+		"must not be negative, got %d", wg.Synthesis.SmoothingIterations)
+	v.check(wg.BiomeMode == "main-path" || wg.BiomeMode == "branch", "worldgen.biome_mode",
+		"must be main-path or branch, got %q", wg.BiomeMode)
+	v.check(wg.ArenaCount >= 0, "worldgen.arena_count", "must not be negative, got %d", wg.ArenaCount)
+	biomeNames := make(map[string]bool)
+	for i, biome := range wg.Biomes {
+		key := fmt.Sprintf("worldgen.biomes[%d]", i)
+		v.check(biome.Name != "", key+".name", "must not be empty")
+		v.check(!biomeNames[biome.Name], key+".name", "duplicate biome %q", biome.Name)
+		biomeNames[biome.Name] = true
+		for _, pattern := range biome.Snippets {
+			_, err := path.Match(pattern, "")
+			v.check(err == nil, key+".snippets", "invalid pattern %q", pattern)
+		}
+		for _, name := range sortedKeys(biome.SnippetTypeWeights) {
+			weightKey := key + ".snippet_type_weights." + name
+			v.check(isSnippetType(name), weightKey, "unknown snippet type, expected one of %v", snippetTypes)
+			v.check(biome.SnippetTypeWeights[name] >= 0, weightKey, "must not be negative, got %d", biome.SnippetTypeWeights[name])
+		}
+		v.check(biome.LightRadius >= 0, key+".light_radius", "must not be negative, got %v", biome.LightRadius)
+	}
+
+	// Camera
+	v.probability("camera.dead_zone_x", c.Camera.DeadZoneX)
+	v.probability("camera.dead_zone_y", c.Camera.DeadZoneY)
+	v.check(c.Camera.InterpolationFactor > 0 && c.Camera.InterpolationFactor <= 1, "camera.interpolation_factor",
+		"must be greater than 0 and at most 1, got %v", c.Camera.InterpolationFactor)
+	v.positive("camera.velocity_threshold", c.Camera.VelocityThreshold)
+	v.probability("camera.centering_strength", c.Camera.CenteringStrength)
+
+	// Physics
+	v.nonNegative("physics.gravity_force", c.Physics.GravityForce)
+	v.nonNegative("physics.low_velocity_threshold", c.Physics.LowVelocityThreshold)
+	v.check(c.Physics.StepsPerSecond > 0, "physics.steps_per_second", "must be greater than 0, got %d", c.Physics.StepsPerSecond)
+	v.check(c.Physics.MaxStepsPerUpdate > 0, "physics.max_steps_per_update", "must be greater than 0, got %d", c.Physics.MaxStepsPerUpdate)
+	v.check(slices.Contains(broadphases, c.Physics.Broadphase), "physics.broadphase",
+		"unknown broadphase %q, expected one of %v", c.Physics.Broadphase, broadphases)
+	v.contact("physics.player_contact", c.Physics.PlayerContact)
+	v.contact("physics.bullet_contact", c.Physics.BulletContact)
+	v.contact("physics.debris_contact", c.Physics.DebrisContact)
+
+	// Player
+	v.positive("player.max_acceleration", c.Player.MaxAcceleration)
+	v.check(c.Player.RotationPerSecond != 0, "player.rotation_per_second", "must not be 0")
+	v.probability("player.rotation_smoothing_min", c.Player.RotationSmoothingMin)
+	v.probability("player.rotation_smoothing_max", c.Player.RotationSmoothingMax)
+	v.check(c.Player.RotationSmoothingMin <= c.Player.RotationSmoothingMax, "player.rotation_smoothing_min",
+		"must not be greater than rotation_smoothing_max (%v), got %v", c.Player.RotationSmoothingMax, c.Player.RotationSmoothingMin)
+	v.check(c.Player.VelocitySmoothingFactor > 0 && c.Player.VelocitySmoothingFactor <= 1, "player.velocity_smoothing_factor",
+		"must be greater than 0 and at most 1, got %v", c.Player.VelocitySmoothingFactor)
+	v.positive("player.curve_power", c.Player.CurvePower)
+
+	// Weapons
+	v.positive("weapons.fire_interval", c.Weapons.FireInterval)
+	v.positive("weapons.enemy_fire_interval", c.Weapons.EnemyFireInterval)
+	v.nonNegative("weapons.enemy_shoot_radius", c.Weapons.EnemyShootRadius)
+	v.positive("weapons.bullet_initial_speed", c.Weapons.BulletInitialSpeed)
+	v.positive("weapons.bullet_acceleration", c.Weapons.BulletAcceleration)
+	v.positive("weapons.bullet_max_lifetime", c.Weapons.BulletMaxLifetime)
+	v.check(c.Weapons.RicochetBounces >= 0, "weapons.ricochet_bounces", "must not be negative, got %d", c.Weapons.RicochetBounces)
+	v.nonNegative("weapons.player_bullet_damage", c.Weapons.PlayerBulletDamage)
+	v.nonNegative("weapons.enemy_bullet_damage", c.Weapons.EnemyBulletDamage)
+
+	// Enemies
+	_, hasDefault := c.Enemies.HealthByType["Default"]
+	v.check(hasDefault, "enemies.health_by_type", "must contain a Default entry")
+	for _, name := range sortedKeys(c.Enemies.HealthByType) {
+		v.positive("enemies.health_by_type."+name, c.Enemies.HealthByType[name])
+	}
+	for _, name := range sortedKeys(c.Enemies.BehaviorByType) {
+		behavior := c.Enemies.BehaviorByType[name]
+		v.check(slices.Contains(EnemyBehaviors, behavior), "enemies.behavior_by_type."+name,
+			"unknown behavior %q, expected one of %v", behavior, EnemyBehaviors)
+	}
+	director := c.Enemies.Director
+	v.positive("enemies.director.wave_interval", director.WaveInterval)
+	v.check(director.WaveSize > 0, "enemies.director.wave_size", "must be greater than 0, got %d", director.WaveSize)
+	v.check(director.MaxWaveSize >= director.WaveSize, "enemies.director.max_wave_size",
+		"must not be less than wave_size (%d), got %d", director.WaveSize, director.MaxWaveSize)
+	v.nonNegative("enemies.director.wave_spread", director.WaveSpread)
+	v.check(director.WavesPerCycle > 0, "enemies.director.waves_per_cycle", "must be greater than 0, got %d", director.WavesPerCycle)
+	v.nonNegative("enemies.director.quiet_period", director.QuietPeriod)
+	v.positive("enemies.director.peak_pressure", director.PeakPressure)
+	v.nonNegative("enemies.director.pressure_decay", director.PressureDecay)
+	v.nonNegative("enemies.director.progress_ramp", director.ProgressRamp)
+	v.nonNegative("enemies.director.time_ramp", director.TimeRamp)
+	v.nonNegative("enemies.director.kill_rate_bonus", director.KillRateBonus)
+	v.check(director.MaxEnemiesPerChunk > 0, "enemies.director.max_enemies_per_chunk",
+		"must be greater than 0, got %d", director.MaxEnemiesPerChunk)
+	v.positive("enemies.director.spawn_max_distance", director.SpawnMaxDistance)
+	v.check(director.SpawnMaxDistance <= constants.WorldLoadedRadius, "enemies.director.spawn_max_distance",
+		"must not exceed the loaded radius of %d, got %v", constants.WorldLoadedRadius, director.SpawnMaxDistance)
+	feedback := c.Enemies.Feedback
+	v.positive("enemies.feedback.hit_flash_duration", feedback.HitFlashDuration)
+	v.positive("enemies.feedback.damage_number_lifetime", feedback.DamageNumberLifetime)
+	v.check(feedback.MaxDamageNumbers > 0, "enemies.feedback.max_damage_numbers",
+		"must be greater than 0, got %d", feedback.MaxDamageNumbers)
+
+	// Streaming
+	v.nonNegative("streaming.image_budget_mb", c.Streaming.ImageBudgetMB)
+	v.check(c.Streaming.UnloadMargin >= 0, "streaming.unload_margin", "must not be negative, got %d", c.Streaming.UnloadMargin)
+
+	return errors.Join(v.errs...)
+}
+
+// isSnippetType reports whether the name is a known snippet type
+func isSnippetType(name string) bool {
+	for _, snippetType := range snippetTypes {
+		if snippetType == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of a map in sorted order, for stable error messages
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -9,4 +9,14 @@ const (
 	// LowVelocityThreshold is the threshold below which gravity is applied
 	// When the player's velocity is above this threshold, no gravity is applied
 	LowVelocityThreshold = 10.0
+
+	// PhysicsStepsPerSecond is the fixed rate at which the physics world is stepped
+	PhysicsStepsPerSecond = 60
+
+	// PhysicsMaxStepsPerUpdate limits the steps per update, so a slow frame cannot
+	// make the following frames even slower
+	PhysicsMaxStepsPerUpdate = 5
+
+	// PhysicsBroadphase is the broadphase that finds nearby shapes for collision detection
+	PhysicsBroadphase = "grid"
 )
\ No newline at end of file
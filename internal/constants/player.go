@@ -20,4 +20,7 @@ const (
 	MinSwipeDuration = 200 * time.Millisecond
 	// CurvePower controls how much the turning radius is affected by speed (higher = tighter turns at low speeds)
 	CurvePower = 1.7
+	// DriftDecay is the rate per second at which velocity that does not come from steering,
+	// like the bounce off a wall or the push of a current, fades
+	DriftDecay = 4.0
 )
@@ -0,0 +1,14 @@
+// Package constants provides centralized constants for the entire application
+package constants
+
+// World grid constants
+const (
+	// WorldCellSize is the size of a world cell in pixels
+	WorldCellSize = 1000
+	// WorldChunkSize is the number of cells along each side of a chunk
+	WorldChunkSize = 4
+	// WorldVisibilityRadius is the number of chunks in each direction around the player that are loaded
+	WorldVisibilityRadius = 4
+	// WorldLoadedRadius is the distance in pixels around the player that always lies in loaded chunks
+	WorldLoadedRadius = WorldVisibilityRadius * WorldChunkSize * WorldCellSize
+)
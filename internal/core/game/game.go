@@ -10,6 +10,7 @@ import (
 	"discoveryx/internal/input"
 	"discoveryx/internal/scenes"
 	"discoveryx/internal/screen"
+	"discoveryx/internal/utils/random"
 	"github.com/hajimehoshi/ebiten/v2"
 	"time"
 )
@@ -216,6 +217,14 @@ func (g *Game) GoToScene(scene scenes.Scene) {
 	g.sceneManager.GoToScene(scene)
 }
 
+// SetSeed sets the run seed used for every new run.
+// The seed drives world generation, enemy placement and all other random
+// gameplay, so the same seed always produces the same run. Passing 0 lets
+// each run choose a fresh seed.
+func (g *Game) SetSeed(seed random.Seed) {
+	g.sceneManager.SetSeed(seed)
+}
+
 // SetDynamicResizing enables or disables dynamic screen resizing.
 // When disabled, the game will maintain a fixed size regardless of window size.
 //
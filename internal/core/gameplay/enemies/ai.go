@@ -1 +1,427 @@
-package enemies
\ No newline at end of file
+package enemies
+
+import (
+	"discoveryx/internal/config"
+	"discoveryx/internal/core/physics"
+	"discoveryx/internal/core/worldgen"
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+)
+
+// State is a state of the state machine that drives an enemy
+type State string
+
+const (
+	StateIdle   State = "idle"   // Stays where it is
+	StatePatrol State = "patrol" // Moves between waypoints around its home
+	StateChase  State = "chase"  // Moves towards the position where it last perceived the target
+	StateFlee   State = "flee"   // Moves away from the target
+	StateStrafe State = "strafe" // Circles the target at the strafe distance
+	StateBurrow State = "burrow" // Hides in the ground, where it can neither be hit nor shoot
+)
+
+// States lists all states of the enemy state machine
+var States = []State{StateIdle, StatePatrol, StateChase, StateFlee, StateStrafe, StateBurrow}
+
+// Condition is what must hold for a rule to pick its state. Zero fields are not checked.
+type Condition struct {
+	SeesTarget  bool    `json:"sees_target,omitempty" yaml:"sees_target"`   // The target is perceived (seen now or remembered)
+	Within      float64 `json:"within,omitempty" yaml:"within"`             // The target is closer than this distance
+	Beyond      float64 `json:"beyond,omitempty" yaml:"beyond"`             // The target is farther than this distance
+	HealthBelow float64 `json:"health_below,omitempty" yaml:"health_below"` // Health is below this fraction of the maximum health
+}
+
+// Rule switches the enemy to a state when its condition holds
+type Rule struct {
+	State State     `json:"state" yaml:"state"`
+	When  Condition `json:"when" yaml:"when"`
+}
+
+// Behavior defines how a type of enemy perceives, moves and fights. The rules are checked
+// in order and the first one whose condition holds picks the state; if none holds, the
+// enemy falls back to the default state. Behaviors are plain data, so new kinds of enemies
+// need a new behavior instead of new code.
+type Behavior struct {
+	Default State  `json:"default" yaml:"default"` // State when no rule holds
+	Rules   []Rule `json:"rules" yaml:"rules"`     // Rules in order of priority
+
+	// Perception
+	SightRange float64 `json:"sight_range" yaml:"sight_range"` // Distance at which the target can be seen, if no wall is in between
+	Memory     float64 `json:"memory" yaml:"memory"`           // Seconds the target is still perceived after it was last seen
+	Reaction   float64 `json:"reaction" yaml:"reaction"`       // Seconds in a state before the enemy can change to another
+
+	// Movement
+	Speed          float64 `json:"speed" yaml:"speed"`                     // Speed in units per second, 0 for enemies that never move on their own
+	PatrolRadius   float64 `json:"patrol_radius" yaml:"patrol_radius"`     // Distance of the patrol waypoints from home
+	StrafeDistance float64 `json:"strafe_distance" yaml:"strafe_distance"` // Distance kept from the target while strafing
+	BurrowTime     float64 `json:"burrow_time" yaml:"burrow_time"`         // Seconds spent burrowed before surfacing
+	BurrowCooldown float64 `json:"burrow_cooldown" yaml:"burrow_cooldown"` // Seconds after surfacing before burrowing again
+	Navigates      bool    `json:"navigates" yaml:"navigates"`             // Follows the cave to its patrol waypoints and the target instead of flying straight at them
+
+	// Weapon
+	FireRange    float64 `json:"fire_range" yaml:"fire_range"`       // Distance at which the enemy shoots at a visible target, 0 never shoots
+	FireInterval float64 `json:"fire_interval" yaml:"fire_interval"` // Seconds between shots
+}
+
+// Built-in behaviors, picked by the behavior of the enemy archetypes.
+// They do not shoot; the weapon of the archetype is added by BehaviorFor.
+var Behaviors = map[string]Behavior{
+	// Stays on its wall and shoots at the target in range, like enemies always did
+	"turret": {
+		Default: StateIdle,
+	},
+
+	// Patrols around its home, chases the target it sees, circles it once close and flees when hurt
+	"hunter": {
+		Default: StatePatrol,
+		Rules: []Rule{
+			{State: StateFlee, When: Condition{SeesTarget: true, HealthBelow: 0.3}},
+			{State: StateStrafe, When: Condition{SeesTarget: true, Within: 140}},
+			{State: StateChase, When: Condition{SeesTarget: true}},
+		},
+		SightRange: 300, Memory: 2, Reaction: 0.25,
+		Speed: 90, PatrolRadius: 120, StrafeDistance: 110,
+	},
+
+	// Keeps its distance: backs off when the target comes close and circles it otherwise
+	"skirmisher": {
+		Default: StateIdle,
+		Rules: []Rule{
+			{State: StateFlee, When: Condition{SeesTarget: true, Within: 100}},
+			{State: StateStrafe, When: Condition{SeesTarget: true}},
+		},
+		SightRange: 250, Memory: 1, Reaction: 0.4,
+		Speed: 120, StrafeDistance: 180,
+	},
+
+	// Flies through the cave around its home and hunts the target it saw across several cells
+	"flyer": {
+		Default: StatePatrol,
+		Rules: []Rule{
+			{State: StateFlee, When: Condition{SeesTarget: true, HealthBelow: 0.25}},
+			{State: StateStrafe, When: Condition{SeesTarget: true, Within: 120}},
+			{State: StateChase, When: Condition{SeesTarget: true}},
+		},
+		SightRange: 350, Memory: 8, Reaction: 0.2,
+		Speed: 110, PatrolRadius: 200, StrafeDistance: 100, Navigates: true,
+	},
+
+	// Hides in the ground when the target comes close and shoots at it from afar
+	"burrower": {
+		Default: StateIdle,
+		Rules: []Rule{
+			{State: StateBurrow, When: Condition{SeesTarget: true, Within: 80}},
+		},
+		SightRange: 200, Reaction: 0.2,
+		BurrowTime: 2, BurrowCooldown: 3,
+	},
+}
+
+// BehaviorFor returns the behavior of an enemy type with the weapon of its archetype.
+// Weapon values the archetype leaves out, and enemy types without an archetype, use the
+// enemy weapon settings of the active config; types without an archetype are turrets.
+// The deprecated enemies.behavior_by_type of the config still overrides the behavior.
+// Enemies see at least as far as they shoot.
+func BehaviorFor(enemyType string) Behavior {
+	cfg := config.Get()
+	weapons := cfg.Weapons
+	weapon := &WeaponArchetype{}
+	behavior := Behaviors["turret"]
+	override, overridden := cfg.Enemies.BehaviorByType[enemyType]
+	if archetype, exists := GetArchetype(enemyType); exists {
+		weapon = archetype.Weapon
+		behavior = Behaviors[archetype.Behavior]
+	} else if !overridden {
+		override, overridden = cfg.Enemies.BehaviorByType["Default"]
+	}
+	if overridden {
+		behavior = Behaviors[override]
+	}
+	if weapon == nil {
+		return behavior
+	}
+
+	behavior.FireRange = weapon.Range
+	if behavior.FireRange == 0 {
+		behavior.FireRange = weapons.EnemyShootRadius
+	}
+	behavior.FireInterval = weapon.Interval
+	if behavior.FireInterval == 0 {
+		behavior.FireInterval = weapons.EnemyFireInterval
+	}
+	behavior.SightRange = stdmath.Max(behavior.SightRange, behavior.FireRange)
+	return behavior
+}
+
+// Environment is what the AI of an enemy perceives of and does in the game world.
+// The game scene implements it.
+type Environment interface {
+	// Target returns the position of the target, false if there is nothing to attack
+	Target() (math.Vector, bool)
+
+	// LineOfSight checks that no wall lies between two positions
+	LineOfSight(from, to math.Vector) bool
+
+	// Fire shoots a bullet from the enemy in the direction of the rotation in radians
+	Fire(enemy *Enemy, rotation float64)
+
+	Navigator
+}
+
+// Navigator finds the way through the cave for enemies whose behavior navigates.
+// worldgen.Navigation implements it for the generated world.
+type Navigator interface {
+	// NextWaypoint returns the position to move to next on the way through the cave
+	// between two positions, false if no way is known
+	NextWaypoint(from, to math.Vector) (math.Vector, bool)
+}
+
+// Ensure Navigation of the generated world implements the Navigator interface
+var _ Navigator = (*worldgen.Navigation)(nil)
+
+// Brain runs the state machine of one enemy. It is updated by the physics world at
+// a fixed rate (see Enemy.BeforeStep), and only uses the position of the enemy and
+// its home for the patrol and strafe patterns, so the same run plays the same way.
+type Brain struct {
+	Behavior Behavior
+	State    State
+	Home     math.Vector // Position the enemy patrols around, where it was spawned
+
+	env            Environment
+	stateTime      float64     // Seconds in the current state
+	lastSeen       math.Vector // Position where the target was last seen
+	sinceSeen      float64     // Seconds since the target was last seen
+	waypoint       int         // Index of the current patrol waypoint
+	strafeSign     float64     // Direction around the target while strafing: 1 clockwise, -1 counter-clockwise
+	burrowCooldown float64     // Seconds until the enemy may burrow again
+	routeGoal      math.Vector // Position the current route leads to
+	routeWaypoint  math.Vector // Next position on the current route
+	routeAge       float64     // Seconds since the route was found, infinite if there is none
+}
+
+// Patrol and steering constants
+const (
+	patrolWaypoints     = 4    // Waypoints on the circle around home
+	waypointReached     = 12.0 // Distance at which a waypoint counts as reached
+	steeringRate        = 6.0  // Rate per second at which the velocity turns towards the desired velocity
+	strafeCorrection    = 1.5  // How strongly strafing corrects the distance to the target
+	movingRotationSpeed = 5.0  // Speed in units per second above which moving enemies face their direction
+	routeInterval       = 0.5  // Seconds after which navigating enemies look for their way again
+)
+
+// NewBrain creates the brain of an enemy at its home position, starting in the default state
+func NewBrain(behavior Behavior, home math.Vector) *Brain {
+	// Derive the patrol start and strafe direction from the home position instead of a random stream
+	phase := int(stdmath.Abs(stdmath.Floor(home.X/7) + stdmath.Floor(home.Y/13)))
+	strafeSign := 1.0
+	if phase%2 == 1 {
+		strafeSign = -1
+	}
+
+	return &Brain{
+		Behavior:   behavior,
+		State:      behavior.Default,
+		Home:       home,
+		sinceSeen:  stdmath.Inf(1),
+		waypoint:   phase % patrolWaypoints,
+		strafeSign: strafeSign,
+		routeAge:   stdmath.Inf(1),
+	}
+}
+
+// SetEnvironment connects the brain to the game world. Without an environment the enemy idles.
+func (b *Brain) SetEnvironment(env Environment) {
+	b.env = env
+}
+
+// Think perceives the environment, changes the state if a rule says so, steers the body
+// and shoots. It is called once per physics step.
+func (b *Brain) Think(enemy *Enemy, body *physics.Body, dt float64) {
+	if b.env == nil {
+		return
+	}
+
+	// Perception: the target is seen in sight range with no wall in between, and remembered for a while
+	target, hasTarget := b.env.Target()
+	distance := stdmath.Inf(1)
+	sees := false
+	if hasTarget {
+		distance = math.Distance(enemy.Position, target)
+		sees = distance <= b.Behavior.SightRange && b.env.LineOfSight(enemy.Position, target)
+	}
+	if sees {
+		b.lastSeen = target
+		b.sinceSeen = 0
+	} else {
+		b.sinceSeen += dt
+	}
+	perceives := sees || b.sinceSeen <= b.Behavior.Memory
+
+	b.stateTime += dt
+	b.burrowCooldown = stdmath.Max(0, b.burrowCooldown-dt)
+	b.decide(enemy, perceives, distance)
+
+	b.move(enemy, body, dt, perceives)
+	b.shoot(enemy, dt, sees, distance, target)
+}
+
+// decide switches to the state of the first rule that holds, once the enemy has been in its
+// state for the reaction time. Burrowed enemies stay in the ground for the burrow time.
+func (b *Brain) decide(enemy *Enemy, perceives bool, distance float64) {
+	if b.stateTime < b.Behavior.Reaction {
+		return
+	}
+	if b.State == StateBurrow && b.stateTime < b.Behavior.BurrowTime {
+		return
+	}
+
+	// Enemies surface after the burrow time and stay up for the cooldown
+	canBurrow := b.State != StateBurrow && b.burrowCooldown <= 0
+
+	next := b.Behavior.Default
+	for _, rule := range b.Behavior.Rules {
+		if rule.State == StateBurrow && !canBurrow {
+			continue
+		}
+		if b.holds(rule.When, enemy, perceives, distance) {
+			next = rule.State
+			break
+		}
+	}
+
+	if next != b.State {
+		if b.State == StateBurrow {
+			b.burrowCooldown = b.Behavior.BurrowCooldown
+		}
+		b.State = next
+		b.stateTime = 0
+	}
+}
+
+// holds checks a condition against what the enemy perceives
+func (b *Brain) holds(condition Condition, enemy *Enemy, perceives bool, distance float64) bool {
+	if condition.SeesTarget && !perceives {
+		return false
+	}
+	if condition.Within > 0 && distance >= condition.Within {
+		return false
+	}
+	if condition.Beyond > 0 && distance <= condition.Beyond {
+		return false
+	}
+	if condition.HealthBelow > 0 && enemy.Health >= condition.HealthBelow*enemy.MaxHealth {
+		return false
+	}
+	return true
+}
+
+// move steers the body towards the velocity of the current state. Enemies without a speed
+// are left alone; their bodies ignore force fields (see NewBody), so they stay where they are mounted.
+func (b *Brain) move(enemy *Enemy, body *physics.Body, dt float64, perceives bool) {
+	speed := b.Behavior.Speed
+	if speed <= 0 {
+		return
+	}
+
+	var desired math.Vector
+	switch b.State {
+	case StatePatrol:
+		angle := 2 * stdmath.Pi * float64(b.waypoint) / patrolWaypoints
+		waypoint := math.Vector{
+			X: b.Home.X + stdmath.Sin(angle)*b.Behavior.PatrolRadius,
+			Y: b.Home.Y - stdmath.Cos(angle)*b.Behavior.PatrolRadius,
+		}
+		if math.Distance(enemy.Position, waypoint) <= waypointReached {
+			b.waypoint = (b.waypoint + 1) % patrolWaypoints
+		}
+		desired = towards(enemy.Position, b.navigate(enemy, waypoint, dt), speed)
+
+	case StateChase:
+		if perceives {
+			desired = towards(enemy.Position, b.navigate(enemy, b.lastSeen, dt), speed)
+		}
+
+	case StateFlee:
+		if perceives {
+			desired = towards(enemy.Position, b.lastSeen, -speed)
+		}
+
+	case StateStrafe:
+		if perceives {
+			// Circle around the target, moving in or out to keep the strafe distance
+			dx, dy := enemy.Position.X-b.lastSeen.X, enemy.Position.Y-b.lastSeen.Y
+			distance := stdmath.Hypot(dx, dy)
+			if distance > 0 {
+				radialX, radialY := dx/distance, dy/distance
+				correction := stdmath.Max(-1, stdmath.Min(1, (b.Behavior.StrafeDistance-distance)/b.Behavior.StrafeDistance*strafeCorrection))
+				desired = math.Vector{
+					X: (-radialY*b.strafeSign + radialX*correction) * speed,
+					Y: (radialX*b.strafeSign + radialY*correction) * speed,
+				}
+			}
+		}
+	}
+
+	// Turn the velocity smoothly, which keeps the pushes of force fields and walls visible
+	blend := stdmath.Min(1, steeringRate*dt)
+	body.Velocity.X += (desired.X - body.Velocity.X) * blend
+	body.Velocity.Y += (desired.Y - body.Velocity.Y) * blend
+
+	if stdmath.Hypot(body.Velocity.X, body.Velocity.Y) > movingRotationSpeed {
+		enemy.Rotation = stdmath.Atan2(body.Velocity.X, -body.Velocity.Y) * (180.0 / stdmath.Pi)
+	}
+}
+
+// navigate returns the position to move to on the way to a goal. Enemies that navigate
+// follow the cave and look for their way again when the goal moved, the waypoint was
+// reached or the route got old; the others head straight for the goal.
+func (b *Brain) navigate(enemy *Enemy, goal math.Vector, dt float64) math.Vector {
+	if !b.Behavior.Navigates {
+		return goal
+	}
+
+	b.routeAge += dt
+	if b.routeAge >= routeInterval || math.Distance(goal, b.routeGoal) > waypointReached ||
+		math.Distance(enemy.Position, b.routeWaypoint) <= waypointReached {
+		waypoint, found := b.env.NextWaypoint(enemy.Position, goal)
+		if !found {
+			waypoint = goal
+		}
+		b.routeGoal = goal
+		b.routeWaypoint = waypoint
+		b.routeAge = 0
+	}
+	return b.routeWaypoint
+}
+
+// shoot fires at the target when it is seen in fire range, unless the enemy is burrowed or fleeing
+func (b *Brain) shoot(enemy *Enemy, dt float64, sees bool, distance float64, target math.Vector) {
+	interval := b.Behavior.FireInterval
+	canShoot := b.State != StateBurrow && b.State != StateFlee
+	if sees && canShoot && distance <= b.Behavior.FireRange {
+		enemy.TimeSinceLastShot += dt
+		if enemy.TimeSinceLastShot >= interval {
+			b.env.Fire(enemy, stdmath.Atan2(target.X-enemy.Position.X, -(target.Y-enemy.Position.Y)))
+			enemy.TimeSinceLastShot = 0
+		}
+	} else if enemy.TimeSinceLastShot > interval {
+		// Be ready to shoot as soon as the target shows up
+		enemy.TimeSinceLastShot = interval
+	}
+}
+
+// IsBurrowed reports whether the brain keeps its enemy hidden in the ground
+func (b *Brain) IsBurrowed() bool {
+	return b.State == StateBurrow
+}
+
+// towards returns the velocity with the speed from one position to another.
+// A negative speed moves away from the other position.
+func towards(from, to math.Vector, speed float64) math.Vector {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	distance := stdmath.Hypot(dx, dy)
+	if distance == 0 {
+		return math.Vector{}
+	}
+	return math.Vector{X: dx / distance * speed, Y: dy / distance * speed}
+}
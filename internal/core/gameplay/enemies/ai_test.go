@@ -0,0 +1,199 @@
+package enemies
+
+import (
+	"discoveryx/internal/core/physics"
+	"discoveryx/internal/core/worldgen"
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+	"testing"
+)
+
+// fakeEnvironment is a target at a fixed position, optionally hidden behind a wall.
+// Without a navigator, the way to any position is straight.
+type fakeEnvironment struct {
+	target    math.Vector
+	blocked   bool
+	shots     []float64
+	navigator Navigator
+}
+
+func (f *fakeEnvironment) Target() (math.Vector, bool)           { return f.target, true }
+func (f *fakeEnvironment) LineOfSight(from, to math.Vector) bool { return !f.blocked }
+func (f *fakeEnvironment) Fire(enemy *Enemy, rotation float64)   { f.shots = append(f.shots, rotation) }
+func (f *fakeEnvironment) NextWaypoint(from, to math.Vector) (math.Vector, bool) {
+	if f.navigator != nil {
+		return f.navigator.NextWaypoint(from, to)
+	}
+	return to, true
+}
+
+// think runs the brain of an enemy for a number of steps of 1/60 second, moving it with its velocity
+func think(enemy *Enemy, steps int) *physics.Body {
+	body := physics.NewBody(enemy, enemy.Position)
+	for i := 0; i < steps; i++ {
+		enemy.Brain.Think(enemy, body, 1.0/60)
+		enemy.Position.X += body.Velocity.X / 60
+		enemy.Position.Y += body.Velocity.Y / 60
+	}
+	return body
+}
+
+// newTestEnemy creates an enemy at the origin with a behavior and a fake environment
+func newTestEnemy(behavior Behavior, env *fakeEnvironment) *Enemy {
+	enemy := &Enemy{Health: 100, MaxHealth: 100, Brain: NewBrain(behavior, math.Vector{})}
+	enemy.Brain.SetEnvironment(env)
+	return enemy
+}
+
+// armed returns a built-in behavior with a weapon
+func armed(name string, fireRange, interval float64) Behavior {
+	behavior := Behaviors[name]
+	behavior.FireRange = fireRange
+	behavior.FireInterval = interval
+	return behavior
+}
+
+// TestBrainStates tests that the rules of a behavior pick the states from what the enemy perceives
+func TestBrainStates(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		behavior string
+		target   math.Vector
+		blocked  bool
+		health   float64
+		expected State
+	}{
+		{"Patrols without a target", "hunter", math.Vector{X: 1000}, false, 100, StatePatrol},
+		{"Patrols when a wall hides the target", "hunter", math.Vector{X: 200}, true, 100, StatePatrol},
+		{"Chases a seen target", "hunter", math.Vector{X: 250}, false, 100, StateChase},
+		{"Strafes a close target", "hunter", math.Vector{X: 50}, false, 100, StateStrafe},
+		{"Flees when hurt", "hunter", math.Vector{X: 50}, false, 20, StateFlee},
+		{"Backs off from a close target", "skirmisher", math.Vector{X: 50}, false, 100, StateFlee},
+		{"Burrows when the target comes close", "burrower", math.Vector{X: 50}, false, 100, StateBurrow},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			enemy := newTestEnemy(Behaviors[tc.behavior], &fakeEnvironment{target: tc.target, blocked: tc.blocked})
+			enemy.Health = tc.health
+
+			think(enemy, 30)
+			if enemy.Brain.State != tc.expected {
+				t.Errorf("Expected state %s, got %s", tc.expected, enemy.Brain.State)
+			}
+		})
+	}
+}
+
+// TestBrainChasesAndShoots tests that a chasing enemy moves towards the target and aims at it
+func TestBrainChasesAndShoots(t *testing.T) {
+	chaser := Behavior{
+		Default:    StateIdle,
+		Rules:      []Rule{{State: StateChase, When: Condition{SeesTarget: true}}},
+		SightRange: 300, Speed: 60, FireRange: 300, FireInterval: 0.5,
+	}
+	env := &fakeEnvironment{target: math.Vector{X: 0, Y: -180}}
+	enemy := newTestEnemy(chaser, env)
+
+	think(enemy, 60)
+	if enemy.Position.Y >= -20 || stdmath.Abs(enemy.Position.X) > 1e-9 {
+		t.Errorf("Expected the enemy to move up towards the target, got %v", enemy.Position)
+	}
+	if len(env.shots) != 1 {
+		t.Fatalf("Expected 1 shot in a second, got %d", len(env.shots))
+	}
+	if stdmath.Abs(env.shots[0]) > 1e-9 {
+		t.Errorf("Expected the shot to point up (0), got %v", env.shots[0])
+	}
+}
+
+// TestBrainBurrowsAndSurfaces tests that a burrowed enemy can neither be hit nor shoot, and surfaces again
+func TestBrainBurrowsAndSurfaces(t *testing.T) {
+	env := &fakeEnvironment{target: math.Vector{X: 50}}
+	enemy := newTestEnemy(armed("burrower", 160, 0.7), env)
+
+	think(enemy, 60)
+	if !enemy.IsBurrowed() || enemy.GetCollider().Radius != 0 {
+		t.Fatalf("Expected the enemy to be burrowed without a collider, got state %s", enemy.Brain.State)
+	}
+	if len(env.shots) != 0 {
+		t.Errorf("Expected no shots while burrowed, got %d", len(env.shots))
+	}
+
+	// After the burrow time the cooldown keeps it above ground, where it shoots again
+	think(enemy, 120)
+	if enemy.IsBurrowed() {
+		t.Errorf("Expected the enemy to surface after the burrow time")
+	}
+	if len(env.shots) == 0 {
+		t.Errorf("Expected the surfaced enemy to shoot")
+	}
+}
+
+// TestBrainIsDeterministic tests that two enemies with the same home and surroundings move the same way
+func TestBrainIsDeterministic(t *testing.T) {
+	env := &fakeEnvironment{target: math.Vector{X: 120, Y: 40}}
+	first := newTestEnemy(Behaviors["hunter"], env)
+	second := newTestEnemy(Behaviors["hunter"], env)
+
+	think(first, 300)
+	think(second, 300)
+	if first.Position != second.Position || first.Brain.State != second.Brain.State {
+		t.Errorf("Expected the same result, got %v (%s) and %v (%s)",
+			first.Position, first.Brain.State, second.Position, second.Brain.State)
+	}
+}
+
+// TestStationaryEnemiesIgnoreForces tests that force fields only push enemies that can move back on their own
+func TestStationaryEnemiesIgnoreForces(t *testing.T) {
+	turret := &Enemy{Brain: NewBrain(Behaviors["turret"], math.Vector{})}
+	hunter := &Enemy{Brain: NewBrain(Behaviors["hunter"], math.Vector{})}
+
+	if !turret.NewBody().IgnoreForces {
+		t.Error("Expected the body of a turret to ignore force fields")
+	}
+	if hunter.NewBody().IgnoreForces {
+		t.Error("Expected the body of a hunter to be pushed by force fields")
+	}
+}
+
+// TestFlyerNavigatesAroundRock tests that a navigating enemy chases its target around a
+// pillar between them instead of flying into it
+func TestFlyerNavigatesAroundRock(t *testing.T) {
+	// A room with a pillar hanging from the ceiling in the middle, on a 20x20 grid
+	mask := &worldgen.RockMask{Size: 20, Values: make([]float64, 400)}
+	for gy := 0; gy < mask.Size; gy++ {
+		for gx := 0; gx < mask.Size; gx++ {
+			border := gx == 0 || gx == 19 || gy == 0 || gy == 19
+			if border || (gx >= 9 && gx <= 10 && gy <= 13) {
+				mask.Values[gy*mask.Size+gx] = 1
+			}
+		}
+	}
+	cell := &worldgen.WorldCell{Snippet: &worldgen.WorldSnippet{
+		Connectors: []worldgen.SnippetConnector{worldgen.ConnectorTop}, Mask: mask, Synthesized: true,
+	}}
+	worldMap := worldgen.NewWorldMap()
+	worldMap.AddCell(cell)
+	chunk := worldgen.NewWorldChunk(0, 0)
+	chunk.AddCell(cell)
+	navigation := worldgen.NewNavigation(worldMap)
+	navigation.ChunkLoaded(chunk)
+
+	behavior := Behaviors["flyer"]
+	behavior.SightRange = 1000
+	env := &fakeEnvironment{target: math.Vector{X: 750, Y: 300}, navigator: navigation}
+	enemy := newTestEnemy(behavior, env)
+	enemy.Position = math.Vector{X: 250, Y: 300}
+
+	body := physics.NewBody(enemy, enemy.Position)
+	for i := 0; i < 900; i++ {
+		enemy.Brain.Think(enemy, body, 1.0/60)
+		enemy.Position.X += body.Velocity.X / 60
+		enemy.Position.Y += body.Velocity.Y / 60
+		if mask.IsRock(int(enemy.Position.X), int(enemy.Position.Y)) {
+			t.Fatalf("Expected the flyer to stay out of the rock, got to %v", enemy.Position)
+		}
+	}
+	if distance := math.Distance(enemy.Position, env.target); distance > 2*behavior.StrafeDistance {
+		t.Errorf("Expected the flyer to reach the target, stopped %v away at %v", distance, enemy.Position)
+	}
+}
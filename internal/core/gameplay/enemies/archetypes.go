@@ -0,0 +1,205 @@
+package enemies
+
+import (
+	"discoveryx/internal/assets"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// SpawnSurface is where the spawner places enemies of an archetype
+type SpawnSurface string
+
+const (
+	SurfaceWall  SpawnSurface = "wall"  // Anchored to any wall, facing away from it
+	SurfaceFloor SpawnSurface = "floor" // Anchored to walls that face up, standing on the ground
+	SurfaceFree  SpawnSurface = "free"  // Floating in the open air of a cell
+)
+
+// SpawnSurfaces lists all spawn surfaces
+var SpawnSurfaces = []SpawnSurface{SurfaceWall, SurfaceFloor, SurfaceFree}
+
+// DefaultSpawnWeight is the key of the spawn weight used in biomes without their own weight
+const DefaultSpawnWeight = "default"
+
+// WeaponArchetype describes the gun of an enemy. A zero range, interval or damage falls back to the enemy
+// weapons settings of the config (weapons.enemy_shoot_radius, enemy_fire_interval
+// and enemy_bullet_damage).
+type WeaponArchetype struct {
+	Range    float64 `json:"range"`    // Distance at which the enemy shoots at a visible target
+	Interval float64 `json:"interval"` // Seconds between shots
+	Damage   float64 `json:"damage"`   // Damage of each bullet
+	Bounces  int     `json:"bounces"`  // Times each bullet bounces off walls before a wall destroys it, 0 for rounds that do not ricochet
+}
+
+// Archetype defines a kind of enemy: how it looks, how tough it is, how it fights and
+// where it spawns. The archetypes of the game are embedded from archetypes.json, so new
+// enemies are added there instead of in code.
+type Archetype struct {
+	Name           string             `json:"name"`            // Enemy type, unique among the archetypes
+	Sprite         string             `json:"sprite"`          // Asset path of the sprite
+	Scale          float64            `json:"scale"`           // Scale of the sprite when drawn
+	ColliderRadius float64            `json:"collider_radius"` // Radius of the collider, 0 to derive it from the sprite
+	Health         float64            `json:"health"`          // Maximum health
+	Weapon         *WeaponArchetype   `json:"weapon"`          // Gun of the enemy, nil for enemies that never shoot
+	Behavior       string             `json:"behavior"`        // Name of the AI behavior (see Behaviors)
+	Surface        SpawnSurface       `json:"surface"`         // Where the enemy spawns
+	SpawnWeights   map[string]float64 `json:"spawn_weights"`   // Relative spawn weight per biome name, with "default" for the others
+	Score          int                `json:"score"`           // Score for destroying the enemy
+}
+
+// SpawnWeight returns the relative spawn weight of the archetype in a biome.
+// An empty biome name is the world outside of biomes.
+func (a *Archetype) SpawnWeight(biome string) float64 {
+	if weight, exists := a.SpawnWeights[biome]; exists && biome != "" {
+		return weight
+	}
+	return a.SpawnWeights[DefaultSpawnWeight]
+}
+
+// Size returns the width and height of the sprite as drawn
+func (a *Archetype) Size() (float64, float64) {
+	img := assets.GetImage(a.Sprite)
+	if img == nil {
+		return 0, 0
+	}
+	return float64(img.Bounds().Dx()) * a.Scale, float64(img.Bounds().Dy()) * a.Scale
+}
+
+// validate checks that the archetype can be spawned and driven by a behavior
+func (a *Archetype) validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("needs a name")
+	}
+	if a.Sprite == "" {
+		return fmt.Errorf("needs a sprite")
+	}
+	if a.Scale <= 0 {
+		return fmt.Errorf("scale must be greater than 0, got %v", a.Scale)
+	}
+	if a.ColliderRadius < 0 {
+		return fmt.Errorf("collider_radius must not be negative, got %v", a.ColliderRadius)
+	}
+	if a.Health <= 0 {
+		return fmt.Errorf("health must be greater than 0, got %v", a.Health)
+	}
+	if a.Weapon != nil && (a.Weapon.Range < 0 || a.Weapon.Interval < 0 || a.Weapon.Damage < 0 || a.Weapon.Bounces < 0) {
+		return fmt.Errorf("weapon values must not be negative, got %+v", *a.Weapon)
+	}
+	if _, exists := Behaviors[a.Behavior]; !exists {
+		return fmt.Errorf("unknown behavior %q", a.Behavior)
+	}
+	known := false
+	for _, surface := range SpawnSurfaces {
+		known = known || a.Surface == surface
+	}
+	if !known {
+		return fmt.Errorf("unknown surface %q, expected one of %v", a.Surface, SpawnSurfaces)
+	}
+	for biome, weight := range a.SpawnWeights {
+		if weight < 0 {
+			return fmt.Errorf("spawn weight of %q must not be negative, got %v", biome, weight)
+		}
+	}
+	return nil
+}
+
+// archetypeFile is the layout of archetypes.json
+type archetypeFile struct {
+	Archetypes []*Archetype `json:"archetypes"`
+}
+
+// ParseArchetypes reads and validates a list of archetypes from JSON, keyed by name
+func ParseArchetypes(data []byte) (map[string]*Archetype, error) {
+	var file archetypeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse archetypes: %w", err)
+	}
+
+	archetypes := make(map[string]*Archetype, len(file.Archetypes))
+	for i, archetype := range file.Archetypes {
+		if err := archetype.validate(); err != nil {
+			return nil, fmt.Errorf("archetype %d (%s): %w", i, archetype.Name, err)
+		}
+		if _, exists := archetypes[archetype.Name]; exists {
+			return nil, fmt.Errorf("duplicate archetype %q", archetype.Name)
+		}
+		archetypes[archetype.Name] = archetype
+	}
+	return archetypes, nil
+}
+
+//go:embed archetypes.json
+var archetypesJSON []byte
+
+// Embedded archetypes, parsed on first use
+var (
+	archetypesOnce sync.Once
+	archetypes     map[string]*Archetype
+)
+
+// loadArchetypes returns the embedded archetypes. The file ships with the game and is
+// checked by the tests, so a broken file is a programming error.
+func loadArchetypes() map[string]*Archetype {
+	archetypesOnce.Do(func() {
+		var err error
+		archetypes, err = ParseArchetypes(archetypesJSON)
+		if err != nil {
+			panic(fmt.Sprintf("embedded enemy archetypes: %v", err))
+		}
+	})
+	return archetypes
+}
+
+// GetArchetype returns the archetype of an enemy type, false if there is none
+func GetArchetype(enemyType string) (*Archetype, bool) {
+	archetype, exists := loadArchetypes()[enemyType]
+	return archetype, exists
+}
+
+// ArchetypeNames returns the names of all archetypes in alphabetical order
+func ArchetypeNames() []string {
+	names := make([]string, 0, len(loadArchetypes()))
+	for name := range loadArchetypes() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pickArchetype picks one of the enemy types by the spawn weights of their archetypes in a biome.
+// Only archetypes that spawn on one of the surfaces are considered; the types are visited
+// in the given order, so the pick only depends on the random generator.
+// It returns nil if no archetype fits.
+func pickArchetype(rng *rand.Rand, enemyTypes []string, biome string, surfaces ...SpawnSurface) *Archetype {
+	candidates := make([]*Archetype, 0, len(enemyTypes))
+	total := 0.0
+	for _, enemyType := range enemyTypes {
+		archetype, exists := GetArchetype(enemyType)
+		if !exists || archetype.SpawnWeight(biome) <= 0 {
+			continue
+		}
+		for _, surface := range surfaces {
+			if archetype.Surface == surface {
+				candidates = append(candidates, archetype)
+				total += archetype.SpawnWeight(biome)
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	roll := rng.Float64() * total
+	for _, archetype := range candidates {
+		roll -= archetype.SpawnWeight(biome)
+		if roll < 0 {
+			return archetype
+		}
+	}
+	return candidates[len(candidates)-1]
+}
@@ -0,0 +1,116 @@
+package enemies
+
+import (
+	"discoveryx/internal/config"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestEmbeddedArchetypes tests that the archetypes shipped with the game are valid
+func TestEmbeddedArchetypes(t *testing.T) {
+	archetypes, err := ParseArchetypes(archetypesJSON)
+	if err != nil {
+		t.Fatalf("Failed to parse the embedded archetypes: %v", err)
+	}
+	if _, exists := archetypes["enemy_1"]; !exists {
+		t.Error("Expected the enemy_1 archetype")
+	}
+}
+
+// TestParseArchetypesRejectsInvalid tests that broken archetypes are reported with their name
+func TestParseArchetypesRejectsInvalid(t *testing.T) {
+	valid := `{"name": "a", "sprite": "a.png", "scale": 1, "health": 10, "behavior": "turret", "surface": "wall"}`
+
+	for _, tc := range []struct {
+		name     string
+		input    string
+		contains string
+	}{
+		{"Unknown behavior", `{"name": "a", "sprite": "a.png", "scale": 1, "health": 10, "behavior": "dance", "surface": "wall"}`, "unknown behavior"},
+		{"Unknown surface", `{"name": "a", "sprite": "a.png", "scale": 1, "health": 10, "behavior": "turret", "surface": "ceiling"}`, "unknown surface"},
+		{"No health", `{"name": "a", "sprite": "a.png", "scale": 1, "behavior": "turret", "surface": "wall"}`, "health"},
+		{"Negative bounces", `{"name": "a", "sprite": "a.png", "scale": 1, "health": 10, "behavior": "turret", "surface": "wall", "weapon": {"bounces": -1}}`, "weapon"},
+		{"Duplicate name", valid + "," + valid, "duplicate archetype"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseArchetypes([]byte(`{"archetypes": [` + tc.input + `]}`))
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), tc.contains) {
+				t.Errorf("Expected error to mention %q, got %v", tc.contains, err)
+			}
+		})
+	}
+}
+
+// TestPickArchetype tests that archetypes are picked by surface and by the spawn weight of the biome
+func TestPickArchetype(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	types := []string{"enemy_1", "burrower", "hunter", "unknown"}
+
+	for i := 0; i < 50; i++ {
+		if archetype := pickArchetype(rng, types, "", SurfaceFree); archetype == nil || archetype.Name != "hunter" {
+			t.Fatalf("Expected only the free-floating hunter in the open air, got %v", archetype)
+		}
+		if archetype := pickArchetype(rng, types, "", SurfaceWall); archetype == nil || archetype.Name != "enemy_1" {
+			t.Fatalf("Expected only enemy_1 on walls that are no floor, got %v", archetype)
+		}
+	}
+	if archetype := pickArchetype(rng, []string{"unknown"}, "", SurfaceWall); archetype != nil {
+		t.Errorf("Expected no archetype for unknown types, got %v", archetype.Name)
+	}
+
+	// Biomes without their own weight use the default weight
+	hunter, _ := GetArchetype("hunter")
+	if hunter.SpawnWeight("depths") != hunter.SpawnWeights[DefaultSpawnWeight] {
+		t.Errorf("Expected the default weight in a biome without its own, got %v", hunter.SpawnWeight("depths"))
+	}
+}
+
+// TestBehaviorForUsesWeapon tests that the weapon of the archetype arms its behavior
+func TestBehaviorForUsesWeapon(t *testing.T) {
+	hunter, _ := GetArchetype("hunter")
+	behavior := BehaviorFor("hunter")
+
+	if behavior.FireRange != hunter.Weapon.Range || behavior.FireInterval != hunter.Weapon.Interval {
+		t.Errorf("Expected the weapon %+v, got range %v and interval %v", *hunter.Weapon, behavior.FireRange, behavior.FireInterval)
+	}
+	if behavior.Speed != Behaviors["hunter"].Speed {
+		t.Errorf("Expected the movement of the hunter behavior, got speed %v", behavior.Speed)
+	}
+}
+
+// TestBehaviorForDeprecatedOverride tests that enemies.behavior_by_type still overrides the archetype behavior
+func TestBehaviorForDeprecatedOverride(t *testing.T) {
+	previous := config.Get()
+	t.Cleanup(func() { config.Set(previous) })
+	cfg := config.Defaults()
+	cfg.Enemies.BehaviorByType = map[string]string{"hunter": "burrower", "Default": "skirmisher"}
+	config.Set(cfg)
+
+	if behavior := BehaviorFor("hunter"); behavior.BurrowTime != Behaviors["burrower"].BurrowTime {
+		t.Errorf("Expected the hunter to burrow, got %+v", behavior)
+	}
+	if behavior := BehaviorFor("Pilz"); behavior.Speed != Behaviors["skirmisher"].Speed {
+		t.Errorf("Expected types without an archetype to use the Default entry, got speed %v", behavior.Speed)
+	}
+}
+
+// TestConfigKnowsBehaviors tests that the config accepts exactly the built-in behaviors in enemies.behavior_by_type
+func TestConfigKnowsBehaviors(t *testing.T) {
+	names := make([]string, 0, len(Behaviors))
+	for name := range Behaviors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	known := slices.Clone(config.EnemyBehaviors)
+	sort.Strings(known)
+	if !slices.Equal(names, known) {
+		t.Errorf("Expected config.EnemyBehaviors to list %v, got %v", names, config.EnemyBehaviors)
+	}
+}
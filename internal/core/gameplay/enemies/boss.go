@@ -0,0 +1,310 @@
+package enemies
+
+import (
+	"discoveryx/internal/core/physics"
+	"discoveryx/internal/core/worldgen"
+	"discoveryx/internal/utils/math"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	stdmath "math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// AttackPattern is how a boss fires its volleys
+type AttackPattern string
+
+const (
+	PatternAimed  AttackPattern = "aimed"  // A fan of bullets centered on the target
+	PatternRing   AttackPattern = "ring"   // Bullets evenly spread around the boss
+	PatternSpiral AttackPattern = "spiral" // A ring that turns a little with every volley
+)
+
+// AttackPatterns lists all attack patterns
+var AttackPatterns = []AttackPattern{PatternAimed, PatternRing, PatternSpiral}
+
+// BossPhase is how a boss fights while its health is at or below a threshold
+type BossPhase struct {
+	HealthBelow float64       `json:"health_below"` // Fraction of the maximum health at which the phase starts, 1 for the first phase
+	Pattern     AttackPattern `json:"pattern"`      // How the volleys are fired
+	Bullets     int           `json:"bullets"`      // Bullets per volley
+	Interval    float64       `json:"interval"`     // Seconds between volleys
+	Spread      float64       `json:"spread"`       // Angle in degrees the bullets of an aimed volley cover
+	Turn        float64       `json:"turn"`         // Degrees a spiral turns with every volley
+	Orbit       float64       `json:"orbit"`        // Radius of the circle the boss flies around the arena center, 0 to stay in place
+	Speed       float64       `json:"speed"`        // Speed along the orbit in units per second
+}
+
+// BossReward is what a defeated boss leaves behind for the player to collect
+type BossReward struct {
+	Health float64 `json:"health"` // Health restored to the player
+	Score  int     `json:"score"`  // Score added on top of the score for the boss
+}
+
+// BossDefinition defines a boss: how it looks, how tough it is, its phases and its reward.
+// The bosses of the game are embedded from bosses.json, like the enemy archetypes.
+type BossDefinition struct {
+	Name           string      `json:"name"`            // Unique name of the boss
+	Sprite         string      `json:"sprite"`          // Asset path of the sprite
+	Scale          float64     `json:"scale"`           // Scale of the sprite when drawn
+	ColliderRadius float64     `json:"collider_radius"` // Radius of the collider
+	Health         float64     `json:"health"`          // Maximum health
+	Damage         float64     `json:"damage"`          // Damage of each bullet, 0 for the enemy bullet damage of the config
+	Score          int         `json:"score"`           // Score for destroying the boss
+	Phases         []BossPhase `json:"phases"`          // Phases by descending health threshold
+	Reward         BossReward  `json:"reward"`          // Reward dropped when the boss is destroyed
+}
+
+// validate checks that the boss can be spawned and fights in every phase
+func (d *BossDefinition) validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("needs a name")
+	}
+	if d.Sprite == "" {
+		return fmt.Errorf("needs a sprite")
+	}
+	if d.Scale <= 0 || d.ColliderRadius <= 0 || d.Health <= 0 {
+		return fmt.Errorf("scale, collider_radius and health must be greater than 0")
+	}
+	if d.Damage < 0 || d.Reward.Health < 0 {
+		return fmt.Errorf("damage and reward health must not be negative")
+	}
+	if len(d.Phases) == 0 || d.Phases[0].HealthBelow != 1 {
+		return fmt.Errorf("needs a first phase with health_below 1")
+	}
+	for i, phase := range d.Phases {
+		if i > 0 && (phase.HealthBelow <= 0 || phase.HealthBelow >= d.Phases[i-1].HealthBelow) {
+			return fmt.Errorf("phase %d: health_below must be between 0 and the threshold of the phase before", i)
+		}
+		known := false
+		for _, pattern := range AttackPatterns {
+			known = known || phase.Pattern == pattern
+		}
+		if !known {
+			return fmt.Errorf("phase %d: unknown pattern %q, expected one of %v", i, phase.Pattern, AttackPatterns)
+		}
+		if phase.Bullets <= 0 || phase.Interval <= 0 {
+			return fmt.Errorf("phase %d: bullets and interval must be greater than 0", i)
+		}
+		if phase.Orbit < 0 || phase.Speed < 0 {
+			return fmt.Errorf("phase %d: orbit and speed must not be negative", i)
+		}
+	}
+	return nil
+}
+
+// bossFile is the layout of bosses.json
+type bossFile struct {
+	Bosses []*BossDefinition `json:"bosses"`
+}
+
+// ParseBosses reads and validates a list of boss definitions from JSON, keyed by name
+func ParseBosses(data []byte) (map[string]*BossDefinition, error) {
+	var file bossFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse bosses: %w", err)
+	}
+
+	bosses := make(map[string]*BossDefinition, len(file.Bosses))
+	for i, boss := range file.Bosses {
+		if err := boss.validate(); err != nil {
+			return nil, fmt.Errorf("boss %d (%s): %w", i, boss.Name, err)
+		}
+		if _, exists := bosses[boss.Name]; exists {
+			return nil, fmt.Errorf("duplicate boss %q", boss.Name)
+		}
+		bosses[boss.Name] = boss
+	}
+	return bosses, nil
+}
+
+//go:embed bosses.json
+var bossesJSON []byte
+
+// Embedded bosses, parsed on first use
+var (
+	bossesOnce sync.Once
+	bosses     map[string]*BossDefinition
+)
+
+// loadBosses returns the embedded bosses. Like the archetypes, a broken file is a programming error.
+func loadBosses() map[string]*BossDefinition {
+	bossesOnce.Do(func() {
+		var err error
+		bosses, err = ParseBosses(bossesJSON)
+		if err != nil {
+			panic(fmt.Sprintf("embedded bosses: %v", err))
+		}
+	})
+	return bosses
+}
+
+// BossNames returns the names of all bosses in alphabetical order
+func BossNames() []string {
+	names := make([]string, 0, len(loadBosses()))
+	for name := range loadBosses() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BossForArena picks the boss of an arena cell. The pick only depends on the seed and the
+// cell, so the same run always meets the same bosses in the same places.
+func BossForArena(seed int64, cell worldgen.CellCoord) *BossDefinition {
+	names := BossNames()
+	if len(names) == 0 {
+		return nil
+	}
+	rng := rand.New(rand.NewSource(chunkSeed(seed, cell.X, cell.Y)))
+	return loadBosses()[names[rng.Intn(len(names))]]
+}
+
+// NewBoss creates a boss enemy in the open space closest to the center of its arena cell.
+// It waits there until Wake is called.
+func NewBoss(definition *BossDefinition, cell *worldgen.WorldCell) *Enemy {
+	arena := arenaCenter(cell, definition.ColliderRadius)
+	archetype := &Archetype{
+		Name:           definition.Name,
+		Sprite:         definition.Sprite,
+		Scale:          definition.Scale,
+		ColliderRadius: definition.ColliderRadius,
+		Health:         definition.Health,
+		Weapon:         &WeaponArchetype{Damage: definition.Damage},
+		Surface:        SurfaceFree,
+		Score:          definition.Score,
+	}
+	return &Enemy{
+		Type:      definition.Name,
+		Position:  arena,
+		ImagePath: definition.Sprite,
+		Health:    definition.Health,
+		MaxHealth: definition.Health,
+		Archetype: archetype,
+		Boss:      &Boss{Definition: definition, Arena: arena},
+		scale:     definition.Scale,
+	}
+}
+
+// arenaStep is the distance between the rings of positions searched for the center of an arena
+const arenaStep = 25.0
+
+// arenaCenter returns the position closest to the center of a cell where a circle fits into the air.
+// Positions are searched on rings around the center; the center is used if none fits.
+func arenaCenter(cell *worldgen.WorldCell, radius float64) math.Vector {
+	origin := math.Vector{X: float64(cell.X * worldgen.CellSize), Y: float64(cell.Y * worldgen.CellSize)}
+	center := worldgen.CellSize / 2.0
+	for ring := 0.0; ring < center; ring += arenaStep {
+		points := max(1, int(2*stdmath.Pi*ring/arenaStep))
+		for i := 0; i < points; i++ {
+			angle := 2 * stdmath.Pi * float64(i) / float64(points)
+			x, y := center+stdmath.Sin(angle)*ring, center-stdmath.Cos(angle)*ring
+			if isAreaInAir(cell, int(x), int(y), radius) {
+				return math.Vector{X: origin.X + x, Y: origin.Y + y}
+			}
+		}
+	}
+	return math.Vector{X: origin.X + center, Y: origin.Y + center}
+}
+
+// Boss runs the phases of a boss enemy. The phase follows the health of the boss; each
+// phase fires its pattern at the target and flies its orbit around the arena center.
+// Like the brain of other enemies it is updated at the fixed rate of the physics world.
+type Boss struct {
+	Definition *BossDefinition
+	Arena      math.Vector // Center of the arena cell
+
+	env       Environment
+	awake     bool
+	phase     int
+	sinceShot float64 // Seconds since the last volley
+	spin      float64 // Angle in degrees a spiral has turned
+	orbit     float64 // Angle in radians of the boss on its orbit
+}
+
+// SetEnvironment connects the boss to the game world
+func (b *Boss) SetEnvironment(env Environment) {
+	b.env = env
+}
+
+// Wake starts the fight, usually when the player entered the arena
+func (b *Boss) Wake() {
+	b.awake = true
+}
+
+// Awake reports whether the boss is fighting
+func (b *Boss) Awake() bool {
+	return b.awake
+}
+
+// Phase returns the index of the current phase
+func (b *Boss) Phase() int {
+	return b.phase
+}
+
+// Think moves to the phase of the health of the boss, flies its orbit and fires its volleys
+func (b *Boss) Think(enemy *Enemy, body *physics.Body, dt float64) {
+	if b.env == nil || !b.awake {
+		return
+	}
+
+	// The last phase whose threshold the health has fallen to
+	for b.phase+1 < len(b.Definition.Phases) && enemy.Health <= b.Definition.Phases[b.phase+1].HealthBelow*enemy.MaxHealth {
+		b.phase++
+	}
+	phase := b.Definition.Phases[b.phase]
+
+	// Fly around the arena center, or back to it in phases without an orbit
+	goal := b.Arena
+	if phase.Orbit > 0 {
+		b.orbit += phase.Speed / phase.Orbit * dt
+		goal = math.Vector{X: b.Arena.X + stdmath.Sin(b.orbit)*phase.Orbit, Y: b.Arena.Y - stdmath.Cos(b.orbit)*phase.Orbit}
+	}
+	desired := towards(enemy.Position, goal, stdmath.Min(stdmath.Max(phase.Speed, bossReturnSpeed), math.Distance(enemy.Position, goal)*steeringRate))
+	blend := stdmath.Min(1, steeringRate*dt)
+	body.Velocity.X += (desired.X - body.Velocity.X) * blend
+	body.Velocity.Y += (desired.Y - body.Velocity.Y) * blend
+
+	target, hasTarget := b.env.Target()
+	if !hasTarget {
+		return
+	}
+	aim := stdmath.Atan2(target.X-enemy.Position.X, -(target.Y - enemy.Position.Y))
+	enemy.Rotation = aim * (180.0 / stdmath.Pi)
+
+	b.sinceShot += dt
+	if b.sinceShot < phase.Interval {
+		return
+	}
+	b.sinceShot = 0
+	for _, rotation := range volley(phase, aim, b.spin) {
+		b.env.Fire(enemy, rotation)
+	}
+	if phase.Pattern == PatternSpiral {
+		b.spin += phase.Turn
+	}
+}
+
+// bossReturnSpeed is the speed at which a boss flies back to its orbit or the arena center
+const bossReturnSpeed = 80.0
+
+// volley returns the directions in radians of the bullets of one volley of a phase
+func volley(phase BossPhase, aim, spin float64) []float64 {
+	rotations := make([]float64, phase.Bullets)
+	for i := range rotations {
+		switch phase.Pattern {
+		case PatternAimed:
+			spread := phase.Spread * (stdmath.Pi / 180.0)
+			offset := 0.0
+			if phase.Bullets > 1 {
+				offset = spread * (float64(i)/float64(phase.Bullets-1) - 0.5)
+			}
+			rotations[i] = aim + offset
+		case PatternRing, PatternSpiral:
+			rotations[i] = spin*(stdmath.Pi/180.0) + 2*stdmath.Pi*float64(i)/float64(phase.Bullets)
+		}
+	}
+	return rotations
+}
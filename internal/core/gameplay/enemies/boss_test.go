@@ -0,0 +1,138 @@
+package enemies
+
+import (
+	"discoveryx/internal/core/physics"
+	"discoveryx/internal/core/worldgen"
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+	"strings"
+	"testing"
+)
+
+// TestEmbeddedBosses tests that the bosses shipped with the game are valid
+func TestEmbeddedBosses(t *testing.T) {
+	bosses, err := ParseBosses(bossesJSON)
+	if err != nil {
+		t.Fatalf("Failed to parse the embedded bosses: %v", err)
+	}
+	if len(bosses) == 0 {
+		t.Error("Expected at least one boss")
+	}
+}
+
+// TestParseBossesRejectsInvalid tests that broken bosses are reported with their name
+func TestParseBossesRejectsInvalid(t *testing.T) {
+	phase := `{"health_below": 1, "pattern": "ring", "bullets": 8, "interval": 1}`
+	boss := func(name, phases string) string {
+		return `{"name": "` + name + `", "sprite": "a.png", "scale": 1, "collider_radius": 30, "health": 100, "phases": [` + phases + `]}`
+	}
+
+	for _, tc := range []struct {
+		name     string
+		input    string
+		contains string
+	}{
+		{"No phases", boss("a", ""), "first phase"},
+		{"Unknown pattern", boss("a", `{"health_below": 1, "pattern": "laser", "bullets": 8, "interval": 1}`), "unknown pattern"},
+		{"Rising threshold", boss("a", phase+`,{"health_below": 1, "pattern": "aimed", "bullets": 3, "interval": 1}`), "health_below"},
+		{"No bullets", boss("a", `{"health_below": 1, "pattern": "ring", "interval": 1}`), "bullets"},
+		{"Duplicate name", boss("a", phase) + "," + boss("a", phase), "duplicate boss"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseBosses([]byte(`{"bosses": [` + tc.input + `]}`))
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), tc.contains) {
+				t.Errorf("Expected error to mention %q, got %v", tc.contains, err)
+			}
+		})
+	}
+}
+
+// TestBossForArenaIsDeterministic tests that an arena always gets the same boss for the same seed
+func TestBossForArenaIsDeterministic(t *testing.T) {
+	seen := make(map[string]bool)
+	for x := 0; x < 20; x++ {
+		cell := worldgen.CellCoord{X: x, Y: -x}
+		first := BossForArena(42, cell)
+		if first == nil {
+			t.Fatal("Expected a boss")
+		}
+		if again := BossForArena(42, cell); again != first {
+			t.Errorf("Expected %s again for %v, got %s", first.Name, cell, again.Name)
+		}
+		seen[first.Name] = true
+	}
+	if len(seen) != len(BossNames()) {
+		t.Errorf("Expected every boss in some of the arenas, got %v", seen)
+	}
+}
+
+// TestVolley tests the directions of the bullets of each pattern
+func TestVolley(t *testing.T) {
+	aimed := volley(BossPhase{Pattern: PatternAimed, Bullets: 3, Spread: 90}, 1, 0)
+	if len(aimed) != 3 || stdmath.Abs(aimed[0]-(1-stdmath.Pi/4)) > 1e-9 || aimed[1] != 1 || stdmath.Abs(aimed[2]-(1+stdmath.Pi/4)) > 1e-9 {
+		t.Errorf("Expected an aimed fan of 90 degrees around the aim, got %v", aimed)
+	}
+
+	ring := volley(BossPhase{Pattern: PatternRing, Bullets: 4}, 1, 0)
+	for i, rotation := range ring {
+		if stdmath.Abs(rotation-float64(i)*stdmath.Pi/2) > 1e-9 {
+			t.Errorf("Expected bullet %d of the ring at %v, got %v", i, float64(i)*stdmath.Pi/2, rotation)
+		}
+	}
+
+	spiral := volley(BossPhase{Pattern: PatternSpiral, Bullets: 4}, 1, 90)
+	if stdmath.Abs(spiral[0]-stdmath.Pi/2) > 1e-9 {
+		t.Errorf("Expected the spiral to be turned by its spin, got %v", spiral[0])
+	}
+}
+
+// TestBossPhases tests that a boss sleeps until woken and moves through its phases as it loses health
+func TestBossPhases(t *testing.T) {
+	definition := &BossDefinition{
+		Name: "test", Sprite: "a.png", Scale: 1, ColliderRadius: 30, Health: 100,
+		Phases: []BossPhase{
+			{HealthBelow: 1, Pattern: PatternRing, Bullets: 6, Interval: 0.5},
+			{HealthBelow: 0.5, Pattern: PatternAimed, Bullets: 3, Interval: 0.5, Spread: 30},
+			{HealthBelow: 0.2, Pattern: PatternSpiral, Bullets: 8, Interval: 0.5, Turn: 10},
+		},
+	}
+	env := &fakeEnvironment{target: math.Vector{X: 200}}
+	enemy := &Enemy{Health: 100, MaxHealth: 100, Boss: &Boss{Definition: definition}}
+	enemy.SetEnvironment(env)
+	body := physics.NewBody(enemy, enemy.Position)
+
+	fight := func(seconds float64) {
+		for i := 0; i < int(seconds*60); i++ {
+			enemy.Boss.Think(enemy, body, 1.0/60)
+		}
+	}
+
+	fight(1)
+	if len(env.shots) != 0 {
+		t.Fatalf("Expected a sleeping boss not to fire, got %d shots", len(env.shots))
+	}
+
+	enemy.Boss.Wake()
+	fight(0.55)
+	if enemy.Boss.Phase() != 0 || len(env.shots) != 6 {
+		t.Errorf("Expected one ring volley of 6 in the first phase, got phase %d and %d shots", enemy.Boss.Phase(), len(env.shots))
+	}
+
+	for _, tc := range []struct {
+		health float64
+		phase  int
+	}{
+		{60, 0},
+		{50, 1},
+		{10, 2},
+	} {
+		enemy.Health = tc.health
+		fight(0.1)
+		if enemy.Boss.Phase() != tc.phase {
+			t.Errorf("Expected phase %d at %v health, got %d", tc.phase, tc.health, enemy.Boss.Phase())
+		}
+	}
+}
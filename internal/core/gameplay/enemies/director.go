@@ -0,0 +1,287 @@
+package enemies
+
+import (
+	"discoveryx/internal/config"
+	"discoveryx/internal/core/worldgen"
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+	"math/rand"
+)
+
+// DirectorPhase is what the spawn director is doing
+type DirectorPhase int
+
+const (
+	// PhaseBuildUp sends waves of enemies that grow with the difficulty
+	PhaseBuildUp DirectorPhase = iota
+
+	// PhaseQuiet sends no waves, so the player can recover
+	PhaseQuiet
+)
+
+// Director constants
+const (
+	killRateWindow     = 30.0 // Seconds over which the kill rate is averaged
+	waveSpawnChance    = 0.5  // Chance of each wall spot of a cell when placing a wave enemy
+	maxPlacementTries  = 10   // Updates in which a wave enemy is retried before it is dropped
+	quietPressureRatio = 0.5  // Fraction of the peak pressure below which a quiet period may end
+)
+
+// Director spawns waves of enemies during the run, like a game master watching the player.
+// It tracks the progress along the main path, the damage the player takes and the kill
+// rate. Waves grow with the progress, the time played and the kill rate; after a few waves,
+// or when the player takes too much damage, the director stops for a quiet period.
+// Wave enemies arrive one by one in loaded cells out of view, in chunks below their cap,
+// and are handed to the enemy store so they are saved with their chunk.
+type Director struct {
+	settings   config.DirectorSettings
+	spawner    *Spawner
+	store      *EnemyStore
+	enemyTypes []string
+	rng        *rand.Rand // Random stream of the director, derived from the run seed
+
+	phase        DirectorPhase
+	phaseTime    float64 // Seconds in the current phase
+	waveTimer    float64 // Seconds until the next wave
+	wavesInCycle int     // Waves sent since the last quiet period
+	pending      []pendingSpawn
+
+	elapsed  float64                     // Seconds played
+	pressure float64                     // Recent damage taken, as a fraction of the maximum health
+	killRate float64                     // Kills per minute, averaged over the kill rate window
+	visited  map[worldgen.CellCoord]bool // Main path cells the player has been in
+	progress float64                     // Fraction of the main path cells visited
+}
+
+// pendingSpawn is an enemy of the current wave that has not arrived yet
+type pendingSpawn struct {
+	delay float64 // Seconds until the enemy arrives
+	tries int     // Updates in which no spot was found for the enemy
+}
+
+// NewDirector creates a director that spawns enemies of the types with the spawner and hands
+// them to the store. The seed makes the choices of the director reproducible.
+func NewDirector(settings config.DirectorSettings, spawner *Spawner, store *EnemyStore, enemyTypes []string, seed int64) *Director {
+	return &Director{
+		settings:   settings,
+		spawner:    spawner,
+		store:      store,
+		enemyTypes: enemyTypes,
+		rng:        rand.New(rand.NewSource(seed)),
+		phase:      PhaseBuildUp,
+		waveTimer:  settings.WaveInterval,
+		visited:    make(map[worldgen.CellCoord]bool),
+	}
+}
+
+// PlayerDamaged tells the director that the player lost a fraction of the maximum health
+func (d *Director) PlayerDamaged(fraction float64) {
+	d.pressure += fraction
+}
+
+// EnemyKilled tells the director that the player destroyed an enemy
+func (d *Director) EnemyKilled() {
+	d.killRate += 60 / killRateWindow
+}
+
+// Phase returns what the director is doing
+func (d *Director) Phase() DirectorPhase {
+	return d.phase
+}
+
+// Progress returns the fraction of the main path cells the player has visited
+func (d *Director) Progress() float64 {
+	return d.progress
+}
+
+// Difficulty returns the factor by which waves are larger than the first one
+func (d *Director) Difficulty() float64 {
+	return 1 + d.progress*d.settings.ProgressRamp + d.elapsed/60*d.settings.TimeRamp + d.killRate*d.settings.KillRateBonus
+}
+
+// Update advances the director by the elapsed time and places the wave enemies that are due.
+// Enemies are only placed farther than the view radius from the player, so they never pop
+// up on screen. It returns the new enemies, which the caller adds to the game.
+func (d *Director) Update(world *worldgen.GeneratedWorld, player math.Vector, viewRadius float64, active []*Enemy, dt float64) []*Enemy {
+	if !d.settings.Enabled {
+		return nil
+	}
+
+	d.observe(world, player)
+	due := d.advance(dt)
+	if len(due) == 0 {
+		return nil
+	}
+
+	cells := d.spawnCells(world, player, viewRadius)
+	occupied := make([]math.Vector, 0, len(active))
+	for _, enemy := range active {
+		occupied = append(occupied, enemy.Position)
+	}
+
+	var spawned []*Enemy
+	for _, spawn := range due {
+		enemy := d.place(world, cells, player, viewRadius, occupied)
+		if enemy == nil {
+			// Try again on the next update, until the enemy ran out of tries
+			if spawn.tries++; spawn.tries < maxPlacementTries {
+				d.pending = append(d.pending, spawn)
+			}
+			continue
+		}
+		spawned = append(spawned, enemy)
+		occupied = append(occupied, enemy.Position)
+	}
+	return spawned
+}
+
+// observe updates the progress along the main path from the position of the player
+func (d *Director) observe(world *worldgen.GeneratedWorld, player math.Vector) {
+	cell := world.GetCellAt(int(stdmath.Floor(player.X)), int(stdmath.Floor(player.Y)))
+	if cell == nil || !cell.IsMainPath {
+		return
+	}
+
+	d.visited[worldgen.CellCoord{X: cell.X, Y: cell.Y}] = true
+	if mainPath := len(world.GetWorldMap().MainPathCells); mainPath > 0 {
+		d.progress = stdmath.Min(1, float64(len(d.visited))/float64(mainPath))
+	}
+}
+
+// advance moves the phases and waves forward by the elapsed time.
+// It returns the wave enemies that are due to arrive.
+func (d *Director) advance(dt float64) []pendingSpawn {
+	d.elapsed += dt
+	d.phaseTime += dt
+	d.pressure = stdmath.Max(0, d.pressure-d.settings.PressureDecay*dt)
+	d.killRate *= stdmath.Exp(-dt / killRateWindow)
+
+	switch d.phase {
+	case PhaseBuildUp:
+		// Too much pressure calls off the rest of the wave
+		if d.pressure >= d.settings.PeakPressure {
+			d.enter(PhaseQuiet)
+			return nil
+		}
+
+		d.waveTimer -= dt
+		if d.waveTimer <= 0 && len(d.pending) == 0 {
+			if d.wavesInCycle >= d.settings.WavesPerCycle {
+				d.enter(PhaseQuiet)
+				return nil
+			}
+			d.startWave()
+		}
+
+	case PhaseQuiet:
+		if d.phaseTime >= d.settings.QuietPeriod && d.pressure < d.settings.PeakPressure*quietPressureRatio {
+			d.enter(PhaseBuildUp)
+		}
+		return nil
+	}
+
+	var due []pendingSpawn
+	remaining := d.pending[:0]
+	for _, spawn := range d.pending {
+		spawn.delay -= dt
+		if spawn.delay <= 0 {
+			due = append(due, spawn)
+			continue
+		}
+		remaining = append(remaining, spawn)
+	}
+	d.pending = remaining
+	return due
+}
+
+// enter switches to a phase. Quiet periods drop the enemies that have not arrived yet,
+// and each build-up starts with a full wave interval.
+func (d *Director) enter(phase DirectorPhase) {
+	d.phase = phase
+	d.phaseTime = 0
+	if phase == PhaseQuiet {
+		d.pending = nil
+	} else {
+		d.wavesInCycle = 0
+		d.waveTimer = d.settings.WaveInterval
+	}
+}
+
+// startWave schedules the enemies of a wave, arriving evenly over the wave spread
+func (d *Director) startWave() {
+	size := int(stdmath.Round(float64(d.settings.WaveSize) * d.Difficulty()))
+	if size > d.settings.MaxWaveSize {
+		size = d.settings.MaxWaveSize
+	}
+
+	for i := 0; i < size; i++ {
+		d.pending = append(d.pending, pendingSpawn{delay: d.settings.WaveSpread * float64(i) / float64(size)})
+	}
+	d.wavesInCycle++
+	d.waveTimer = d.settings.WaveInterval
+}
+
+// spawnCells returns the loaded cells within the spawn distance of the player that are not
+// completely in view, in a fixed order
+func (d *Director) spawnCells(world *worldgen.GeneratedWorld, player math.Vector, viewRadius float64) []*worldgen.WorldCell {
+	reach := d.settings.SpawnMaxDistance
+	minX := worldgen.FloorDiv(int(stdmath.Floor(player.X-reach)), worldgen.CellSize)
+	maxX := worldgen.FloorDiv(int(stdmath.Floor(player.X+reach)), worldgen.CellSize)
+	minY := worldgen.FloorDiv(int(stdmath.Floor(player.Y-reach)), worldgen.CellSize)
+	maxY := worldgen.FloorDiv(int(stdmath.Floor(player.Y+reach)), worldgen.CellSize)
+
+	var cells []*worldgen.WorldCell
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			chunkX, chunkY := worldgen.FloorDiv(x, worldgen.ChunkSize), worldgen.FloorDiv(y, worldgen.ChunkSize)
+			chunk := world.GetChunk(chunkX, chunkY)
+			if chunk == nil || !chunk.IsLoaded {
+				continue
+			}
+			cell := chunk.GetCell(x-chunkX*worldgen.ChunkSize, y-chunkY*worldgen.ChunkSize)
+			if cell == nil || cell.Snippet == nil {
+				continue
+			}
+
+			// Distances from the player to the nearest point and the farthest corner of the cell
+			left, top := float64(x*worldgen.CellSize), float64(y*worldgen.CellSize)
+			right, bottom := left+worldgen.CellSize, top+worldgen.CellSize
+			nearest := math.Vector{X: stdmath.Max(left, stdmath.Min(player.X, right)), Y: stdmath.Max(top, stdmath.Min(player.Y, bottom))}
+			farthest := math.Vector{X: left, Y: top}
+			if player.X < (left+right)/2 {
+				farthest.X = right
+			}
+			if player.Y < (top+bottom)/2 {
+				farthest.Y = bottom
+			}
+
+			if math.Distance(player, nearest) <= reach && math.Distance(player, farthest) > viewRadius {
+				cells = append(cells, cell)
+			}
+		}
+	}
+	return cells
+}
+
+// place spawns one wave enemy in a random cell whose chunk is below its cap.
+// It returns nil if the cell has no free spot out of view.
+func (d *Director) place(world *worldgen.GeneratedWorld, cells []*worldgen.WorldCell, player math.Vector, viewRadius float64, occupied []math.Vector) *Enemy {
+	if len(cells) == 0 {
+		return nil
+	}
+
+	cell := cells[d.rng.Intn(len(cells))]
+	chunk := worldgen.ChunkCoord{X: worldgen.FloorDiv(cell.X, worldgen.ChunkSize), Y: worldgen.FloorDiv(cell.Y, worldgen.ChunkSize)}
+	if d.store.Count(chunk) >= d.settings.MaxEnemiesPerChunk {
+		return nil
+	}
+
+	for _, enemy := range d.spawner.SpawnEnemiesInCell(world, cell, d.rng, d.enemyTypes, waveSpawnChance, 1, occupied) {
+		if math.Distance(player, enemy.Position) <= viewRadius {
+			continue
+		}
+		d.store.Adopt(chunk, enemy)
+		return enemy
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package enemies
+
+import (
+	"discoveryx/internal/config"
+	"discoveryx/internal/core/worldgen"
+	"discoveryx/internal/utils/math"
+	"testing"
+)
+
+// testDirectorSettings returns director settings with round numbers for the tests
+func testDirectorSettings() config.DirectorSettings {
+	return config.DirectorSettings{
+		Enabled:            true,
+		WaveInterval:       10,
+		WaveSize:           2,
+		MaxWaveSize:        5,
+		WaveSpread:         2,
+		WavesPerCycle:      2,
+		QuietPeriod:        20,
+		PeakPressure:       0.5,
+		PressureDecay:      0.05,
+		ProgressRamp:       1,
+		TimeRamp:           0,
+		KillRateBonus:      0,
+		MaxEnemiesPerChunk: 10,
+		SpawnMaxDistance:   1500,
+	}
+}
+
+// run advances a director in steps of 1/10 second and returns the number of enemies that were due
+func run(d *Director, seconds float64) int {
+	due := 0
+	for i := 0; i < int(seconds*10+0.5); i++ {
+		due += len(d.advance(0.1))
+	}
+	return due
+}
+
+// TestDirectorWavesAndQuietPeriods tests that the director sends its waves and then rests
+func TestDirectorWavesAndQuietPeriods(t *testing.T) {
+	d := NewDirector(testDirectorSettings(), nil, nil, nil, 1)
+
+	if due := run(d, 9.5); due != 0 {
+		t.Errorf("Expected no enemies before the first wave, got %d", due)
+	}
+	if due := run(d, 3); due != 2 {
+		t.Errorf("Expected the first wave of 2 enemies, got %d", due)
+	}
+	if due := run(d, 10); due != 2 {
+		t.Errorf("Expected the second wave of 2 enemies, got %d", due)
+	}
+
+	// After the waves of a cycle the director rests for the quiet period
+	if due := run(d, 10); due != 0 || d.Phase() != PhaseQuiet {
+		t.Errorf("Expected a quiet period after 2 waves, got %d enemies in phase %d", due, d.Phase())
+	}
+	run(d, 20)
+	if d.Phase() != PhaseBuildUp {
+		t.Errorf("Expected the build-up to start again after the quiet period")
+	}
+}
+
+// TestDirectorBacksOffUnderPressure tests that heavy damage calls off a wave until the player recovered
+func TestDirectorBacksOffUnderPressure(t *testing.T) {
+	d := NewDirector(testDirectorSettings(), nil, nil, nil, 1)
+	run(d, 10.5)
+
+	d.PlayerDamaged(0.8)
+	if due := run(d, 3); due != 0 || d.Phase() != PhaseQuiet {
+		t.Fatalf("Expected the wave to be called off, got %d enemies in phase %d", due, d.Phase())
+	}
+
+	// The pressure decays to 0.2 in 12 seconds, but the quiet period lasts 20
+	run(d, 16)
+	if d.Phase() != PhaseQuiet {
+		t.Errorf("Expected the quiet period to last at least %v seconds", d.settings.QuietPeriod)
+	}
+	run(d, 1.5)
+	if d.Phase() != PhaseBuildUp {
+		t.Errorf("Expected the build-up to start again once the pressure is low")
+	}
+}
+
+// TestDirectorWavesGrowWithProgress tests that waves grow with the progress and stop at the maximum size
+func TestDirectorWavesGrowWithProgress(t *testing.T) {
+	for _, tc := range []struct {
+		progress float64
+		expected int
+	}{
+		{0, 2},
+		{0.5, 3},
+		{1, 4},
+		{3, 5},
+	} {
+		d := NewDirector(testDirectorSettings(), nil, nil, nil, 1)
+		d.progress = tc.progress
+		if due := run(d, 13); due != tc.expected {
+			t.Errorf("Expected a wave of %d at progress %v, got %d", tc.expected, tc.progress, due)
+		}
+	}
+}
+
+// TestDirectorSpawnsOnlyInLoadedChunks tests that waves are not sent into chunks that are not loaded
+func TestDirectorSpawnsOnlyInLoadedChunks(t *testing.T) {
+	generator, err := worldgen.NewHeadlessWorldGenerator()
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	worldConfig := worldgen.DefaultWorldGenConfig()
+	worldConfig.Seed = 42
+	world, err := worldgen.NewGeneratedWorld(640, 480, generator, worldConfig)
+	if err != nil {
+		t.Fatalf("Failed to generate world: %v", err)
+	}
+	x, y := world.GetPlayerPosition()
+	player := math.Vector{X: x, Y: y}
+
+	settings := testDirectorSettings()
+	settings.SpawnMaxDistance = 6000
+	d := NewDirector(settings, nil, nil, nil, 1)
+	cells := d.spawnCells(world, player, 500)
+	if len(cells) == 0 {
+		t.Fatal("Expected cells to spawn in around the player")
+	}
+
+	unloaded := world.GetChunk(worldgen.FloorDiv(cells[0].X, worldgen.ChunkSize), worldgen.FloorDiv(cells[0].Y, worldgen.ChunkSize))
+	unloaded.Unload()
+	remaining := d.spawnCells(world, player, 500)
+	if len(remaining) >= len(cells) {
+		t.Errorf("Expected fewer cells after unloading a chunk, got %d of %d", len(remaining), len(cells))
+	}
+	for _, cell := range remaining {
+		if unloaded.GetKey() == (worldgen.ChunkCoord{X: worldgen.FloorDiv(cell.X, worldgen.ChunkSize), Y: worldgen.FloorDiv(cell.Y, worldgen.ChunkSize)}) {
+			t.Fatalf("Expected no cells of the unloaded chunk, got (%d, %d)", cell.X, cell.Y)
+		}
+	}
+}
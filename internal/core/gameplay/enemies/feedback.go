@@ -0,0 +1,250 @@
+package enemies
+
+import (
+	"discoveryx/internal/config"
+	"discoveryx/internal/rendering/shaders"
+	"discoveryx/internal/utils/math"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+	stdmath "math"
+	"strconv"
+	"sync"
+)
+
+// Hit feedback constants
+const (
+	healthBarWidth   = 36.0 // Width of the health bar in pixels
+	healthBarHeight  = 4.0  // Height of the health bar in pixels
+	healthBarOffset  = 8.0  // Gap in pixels between the top of the collider and the health bar
+	damageNumberRise = 40.0 // Distance in pixels a damage number rises over its lifetime
+	digitPixel       = 2.0  // Size in pixels of a pixel of the digit glyphs
+)
+
+// Colors of the hit feedback
+var (
+	healthBarBackground = color.RGBA{R: 60, G: 0, B: 0, A: 200}
+	healthBarForeground = color.RGBA{R: 230, G: 40, B: 40, A: 255}
+	damageNumberColor   = color.RGBA{R: 255, G: 230, B: 120, A: 255}
+)
+
+// feedbackPixel is a white pixel that is scaled and tinted for health bars
+var feedbackPixel *ebiten.Image
+
+// whitePixel returns the white pixel of the health bars, creating it on first use
+func whitePixel() *ebiten.Image {
+	if feedbackPixel == nil {
+		feedbackPixel = ebiten.NewImage(1, 1)
+		feedbackPixel.Fill(color.White)
+	}
+	return feedbackPixel
+}
+
+// The flash shader is compiled on first use. If it fails to compile, enemies are not flashed.
+var (
+	flashOnce   sync.Once
+	flashShader *shaders.FlashShader
+)
+
+// flashAmount returns how far the enemy is blended towards white, fading from 1 right after a hit to 0
+func (e *Enemy) flashAmount(feedback config.FeedbackSettings) float64 {
+	if !feedback.HitFlash || !e.hit || e.sinceHit >= feedback.HitFlashDuration {
+		return 0
+	}
+	return 1 - e.sinceHit/feedback.HitFlashDuration
+}
+
+// drawFlash draws a sprite with the transformation of the options, blended towards white by the amount
+func drawFlash(screen, sprite *ebiten.Image, op *ebiten.DrawImageOptions, amount float64) {
+	flashOnce.Do(func() {
+		var err error
+		if flashShader, err = shaders.NewFlashShader(); err != nil {
+			fmt.Printf("Hit flash disabled: %v\n", err)
+		}
+	})
+	if flashShader == nil {
+		screen.DrawImage(sprite, op)
+		return
+	}
+
+	shaderOp := &ebiten.DrawRectShaderOptions{GeoM: op.GeoM, ColorScale: op.ColorScale}
+	shaderOp.Images[0] = sprite
+	shaderOp.Uniforms = map[string]any{"Amount": float32(amount)}
+	bounds := sprite.Bounds()
+	screen.DrawRectShader(bounds.Dx(), bounds.Dy(), flashShader.Shader(), shaderOp)
+}
+
+// drawHealthBar draws the health of the enemy as a bar above it, centered on its screen position
+func (e *Enemy) drawHealthBar(screen *ebiten.Image, screenX, screenY float64) {
+	if e.MaxHealth <= 0 {
+		return
+	}
+
+	left := screenX - healthBarWidth/2
+	top := screenY - e.GetCollider().Radius - healthBarOffset - healthBarHeight
+	fraction := stdmath.Max(0, stdmath.Min(1, e.Health/e.MaxHealth))
+
+	drawRect(screen, left, top, healthBarWidth, healthBarHeight, healthBarBackground, 1)
+	drawRect(screen, left, top, healthBarWidth*fraction, healthBarHeight, healthBarForeground, 1)
+}
+
+// drawRect fills a rectangle on the screen with a color at an opacity
+func drawRect(screen *ebiten.Image, x, y, width, height float64, c color.Color, alpha float64) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(width, height)
+	op.GeoM.Translate(x, y)
+	op.ColorScale.ScaleWithColor(c)
+	op.ColorScale.ScaleAlpha(float32(alpha))
+	screen.DrawImage(whitePixel(), op)
+}
+
+// damageNumber is the damage of one hit rising from the position of the enemy
+type damageNumber struct {
+	position math.Vector // Position of the enemy when it was hit, in world coordinates
+	text     string      // Damage as shown
+	age      float64     // Seconds since the hit
+	active   bool
+}
+
+// DamageNumbers shows the damage of hits as numbers that rise from the enemy and fade out.
+// The numbers are kept in a pool of fixed size, so hits do not allocate; when every number
+// is in use, the oldest one is reused for the new hit.
+type DamageNumbers struct {
+	numbers  []damageNumber
+	lifetime float64 // Seconds a number rises before it has faded
+}
+
+// NewDamageNumbers creates a pool of damage numbers from the feedback settings
+func NewDamageNumbers(settings config.FeedbackSettings) *DamageNumbers {
+	return &DamageNumbers{
+		numbers:  make([]damageNumber, settings.MaxDamageNumbers),
+		lifetime: settings.DamageNumberLifetime,
+	}
+}
+
+// Add shows the damage of a hit at a position in world coordinates.
+// Fractions of damage are rounded to whole numbers.
+func (d *DamageNumbers) Add(position math.Vector, damage float64) {
+	if len(d.numbers) == 0 {
+		return
+	}
+
+	// Take a free number, or the oldest one if all are in use
+	slot := 0
+	for i := range d.numbers {
+		if !d.numbers[i].active {
+			slot = i
+			break
+		}
+		if d.numbers[i].age > d.numbers[slot].age {
+			slot = i
+		}
+	}
+	d.numbers[slot] = damageNumber{
+		position: position,
+		text:     strconv.Itoa(int(stdmath.Round(damage))),
+		active:   true,
+	}
+}
+
+// Active returns the number of damage numbers being shown
+func (d *DamageNumbers) Active() int {
+	count := 0
+	for i := range d.numbers {
+		if d.numbers[i].active {
+			count++
+		}
+	}
+	return count
+}
+
+// Update ages the damage numbers and frees the ones that have faded out
+func (d *DamageNumbers) Update(deltaTime float64) {
+	for i := range d.numbers {
+		number := &d.numbers[i]
+		if !number.active {
+			continue
+		}
+		number.age += deltaTime
+		if number.age >= d.lifetime {
+			number.active = false
+		}
+	}
+}
+
+// Draw renders the damage numbers, rising and fading as they age.
+//
+// Parameters:
+// - screen: The target image where the numbers should be drawn
+// - offsetX, offsetY: Camera offset values for scrolling
+// - worldWidth, worldHeight: Current dimensions of the game world
+func (d *DamageNumbers) Draw(screen *ebiten.Image, offsetX, offsetY float64, worldWidth, worldHeight int) {
+	for i := range d.numbers {
+		number := &d.numbers[i]
+		if !number.active {
+			continue
+		}
+
+		progress := number.age / d.lifetime
+		width := float64(len(number.text))*(digitWidth+1)*digitPixel - digitPixel
+		x := float64(worldWidth)/2 + number.position.X + offsetX - width/2
+		y := float64(worldHeight)/2 + number.position.Y + offsetY - progress*damageNumberRise
+		drawDigits(screen, number.text, x, y, 1-progress*progress)
+	}
+}
+
+// Digit glyphs, 3 by 5 pixels. Each row is 3 bits, the highest bit on the left.
+const (
+	digitWidth  = 3
+	digitHeight = 5
+)
+
+var digitGlyphs = [10][digitHeight]uint8{
+	{7, 5, 5, 5, 7}, // 0
+	{2, 6, 2, 2, 7}, // 1
+	{7, 1, 7, 4, 7}, // 2
+	{7, 1, 3, 1, 7}, // 3
+	{5, 5, 7, 1, 1}, // 4
+	{7, 4, 7, 1, 7}, // 5
+	{7, 4, 7, 5, 7}, // 6
+	{7, 1, 1, 1, 1}, // 7
+	{7, 5, 7, 5, 7}, // 8
+	{7, 5, 7, 1, 7}, // 9
+}
+
+// digitImages holds an image per digit glyph, created on first use
+var digitImages [10]*ebiten.Image
+
+// digitImage returns the image of a digit glyph, drawing it on first use
+func digitImage(digit int) *ebiten.Image {
+	if digitImages[digit] == nil {
+		img := ebiten.NewImage(digitWidth, digitHeight)
+		for y, row := range digitGlyphs[digit] {
+			for x := 0; x < digitWidth; x++ {
+				if row&(1<<(digitWidth-1-x)) != 0 {
+					img.Set(x, y, color.White)
+				}
+			}
+		}
+		digitImages[digit] = img
+	}
+	return digitImages[digit]
+}
+
+// drawDigits draws a number with its top left corner at a screen position and an opacity
+func drawDigits(screen *ebiten.Image, text string, x, y, alpha float64) {
+	for i, char := range text {
+		if char < '0' || char > '9' {
+			continue
+		}
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(digitPixel, digitPixel)
+		op.GeoM.Translate(x+float64(i)*(digitWidth+1)*digitPixel, y)
+		op.ColorScale.ScaleWithColor(damageNumberColor)
+		op.ColorScale.ScaleAlpha(float32(alpha))
+		screen.DrawImage(digitImage(int(char-'0')), op)
+	}
+}
@@ -0,0 +1,61 @@
+package enemies
+
+import (
+	"discoveryx/internal/config"
+	"discoveryx/internal/utils/math"
+	"testing"
+)
+
+// TestDamageNumbersReuseOldest tests that the pool never grows and hands the oldest number to a new hit
+func TestDamageNumbersReuseOldest(t *testing.T) {
+	numbers := NewDamageNumbers(config.FeedbackSettings{MaxDamageNumbers: 3, DamageNumberLifetime: 1})
+
+	for i := 0; i < 3; i++ {
+		numbers.Add(math.Vector{X: float64(i)}, 10)
+		numbers.Update(0.1)
+	}
+	numbers.Add(math.Vector{X: 3}, 24.6)
+
+	if numbers.Active() != 3 || len(numbers.numbers) != 3 {
+		t.Fatalf("Expected 3 numbers in a pool of 3, got %d active of %d", numbers.Active(), len(numbers.numbers))
+	}
+	if first := numbers.numbers[0]; first.position.X != 3 || first.text != "25" || first.age != 0 {
+		t.Errorf("Expected the oldest number to show the new hit, got %+v", first)
+	}
+
+	numbers.Update(0.95)
+	if numbers.Active() != 1 {
+		t.Errorf("Expected only the new number left after the others faded, got %d", numbers.Active())
+	}
+	numbers.Update(0.2)
+	if numbers.Active() != 0 {
+		t.Errorf("Expected all numbers to have faded, got %d", numbers.Active())
+	}
+}
+
+// TestHitFlashFades tests that a hit flashes the enemy only when enabled, fading over the flash duration
+func TestHitFlashFades(t *testing.T) {
+	feedback := config.FeedbackSettings{HitFlash: true, HitFlashDuration: 0.2}
+	enemy := &Enemy{Health: 100, MaxHealth: 100}
+
+	if amount := enemy.flashAmount(feedback); amount != 0 {
+		t.Errorf("Expected no flash before the first hit, got %v", amount)
+	}
+
+	enemy.TakeDamage(10)
+	if amount := enemy.flashAmount(feedback); amount != 1 {
+		t.Errorf("Expected a full flash right after the hit, got %v", amount)
+	}
+	if amount := enemy.flashAmount(config.FeedbackSettings{HitFlashDuration: 0.2}); amount != 0 {
+		t.Errorf("Expected no flash when disabled, got %v", amount)
+	}
+
+	enemy.sinceHit = 0.1
+	if amount := enemy.flashAmount(feedback); amount != 0.5 {
+		t.Errorf("Expected half the flash halfway through, got %v", amount)
+	}
+	enemy.sinceHit = 0.2
+	if amount := enemy.flashAmount(feedback); amount != 0 {
+		t.Errorf("Expected the flash to be over, got %v", amount)
+	}
+}
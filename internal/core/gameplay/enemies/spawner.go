@@ -8,6 +8,7 @@ import (
 	"log"
 	stdmath "math"
 	"math/rand"
+	"slices"
 	"time"
 )
 
@@ -17,6 +18,8 @@ type EnemyConfig struct {
 	MinWallLength             float64 // Minimum wall length required for enemy placement
 	MaxWallDeviation          float64 // Maximum allowed deviation in wall flatness
 	MinDistanceBetweenEnemies float64 // Minimum distance between enemies
+	FreeFloatingPerCell       int     // Attempts per cell to place an enemy that floats in the open air
+	Seed                      int64   // Random seed for reproducible placement
 }
 
 // Spawner handles the spawning of enemies in the game world
@@ -32,13 +35,17 @@ func NewSpawner() *Spawner {
 			MinWallLength:             8.0,  // Assuming enemy is about 16 pixels wide
 			MaxWallDeviation:          80.0, // Allow 10 degree deviation in wall flatness
 			MinDistanceBetweenEnemies: 15.0, // Minimum 32 pixels between enemies
+			FreeFloatingPerCell:       1,
+			Seed:                      time.Now().UnixNano(),
 		},
 	}
 }
 
-// SpawnObjectsOnWalls spawns objects on walls in the visible world
-func SpawnObjectsOnWalls(world *worldgen.GeneratedWorld, objectTypes []string, spawnChance float64, minDistanceBetweenObjects float64) []*Enemy {
+// SpawnObjectsOnWalls spawns objects on walls in the visible world.
+// The seed makes the placement reproducible for a given world.
+func SpawnObjectsOnWalls(world *worldgen.GeneratedWorld, objectTypes []string, spawnChance float64, minDistanceBetweenObjects float64, seed int64) []*Enemy {
 	spawner := NewSpawner()
+	spawner.Config.Seed = seed
 	// Ensure minimum distance is at least 32.0 units to prevent enemies from spawning on top of each other
 	if minDistanceBetweenObjects < 32.0 {
 		minDistanceBetweenObjects = 32.0
@@ -50,39 +57,22 @@ func SpawnObjectsOnWalls(world *worldgen.GeneratedWorld, objectTypes []string, s
 
 // SpawnEnemiesOnWalls spawns enemies on suitable walls in the visible world
 func (s *Spawner) SpawnEnemiesOnWalls(world *worldgen.GeneratedWorld, enemyTypes []string, spawnChance float64) []*Enemy {
-	// Random generator
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-
-	// Load enemy image to get dimensions
-	enemyImage := assets.GetImage(s.Config.ImagePath)
-	// Get the original dimensions
-	originalWidth := float64(enemyImage.Bounds().Dx())
-	originalHeight := float64(enemyImage.Bounds().Dy())
-	// Apply the same 0.5 scaling factor that's used in Enemy.Draw()
-	enemyWidth := originalWidth * 0.5
-	enemyHeight := originalHeight * 0.5
-
-	// List of all spawned enemies to ensure minimum distance
-	spawnedPositions := []math.Vector{}
 	// List of created enemy entities to return
 	spawnedEnemies := []*Enemy{}
 
 	// Get player position to determine visible chunks
 	playerX, playerY := world.GetPlayerPosition()
-	playerCellX := int(playerX) / worldgen.CellSize
-	playerCellY := int(playerY) / worldgen.CellSize
-	playerChunkX := playerCellX / worldgen.ChunkSize
-	playerChunkY := playerCellY / worldgen.ChunkSize
-
-	// Set a maximum number of enemies to spawn to prevent excessive processing
-	maxTotalEnemies := 500
+	playerCellX := worldgen.FloorDiv(int(playerX), worldgen.CellSize)
+	playerCellY := worldgen.FloorDiv(int(playerY), worldgen.CellSize)
+	playerChunkX := worldgen.FloorDiv(playerCellX, worldgen.ChunkSize)
+	playerChunkY := worldgen.FloorDiv(playerCellY, worldgen.ChunkSize)
 
 	// Iterate through chunks within visibility radius
 	for y := playerChunkY - worldgen.VisibilityRadius; y <= playerChunkY+worldgen.VisibilityRadius; y++ {
 		for x := playerChunkX - worldgen.VisibilityRadius; x <= playerChunkX+worldgen.VisibilityRadius; x++ {
 			// Check if we've already spawned the maximum number of enemies
 			if len(spawnedEnemies) >= maxTotalEnemies {
-				return spawnedEnemies
+				return spawnedEnemies[:maxTotalEnemies]
 			}
 
 			chunk := world.GetChunk(x, y)
@@ -90,475 +80,538 @@ func (s *Spawner) SpawnEnemiesOnWalls(world *worldgen.GeneratedWorld, enemyTypes
 				continue
 			}
 
-			// Step 1: Collection of wall points
-			if constants.DebugLogging {
-				log.Printf("Step 1: Starting collection of wall points for chunk (%d, %d)", x, y)
+			spawnedEnemies = append(spawnedEnemies, s.SpawnEnemiesInChunk(world, chunk, enemyTypes, spawnChance)...)
+		}
+	}
+
+	if len(spawnedEnemies) > maxTotalEnemies {
+		spawnedEnemies = spawnedEnemies[:maxTotalEnemies]
+	}
+
+	if constants.DebugLogging {
+		log.Printf("SpawnEnemiesOnWalls: Finished spawning %d enemies", len(spawnedEnemies))
+	}
+	return spawnedEnemies
+}
+
+// SpawnEnemiesInChunk spawns enemies on the walls and in the open air of a single loaded chunk.
+// The enemy types are picked by the spawn weights of their archetypes in the biome of each spot,
+// among the archetypes that spawn on that kind of surface; types without an archetype are skipped.
+// The placement only depends on the spawner seed and the chunk coordinates,
+// so a chunk gets the same enemies no matter in which order chunks are loaded.
+func (s *Spawner) SpawnEnemiesInChunk(world *worldgen.GeneratedWorld, chunk *worldgen.WorldChunk, enemyTypes []string, spawnChance float64) []*Enemy {
+	// Random generator, seeded from the config and the chunk so the same world gets the same enemies
+	rng := rand.New(rand.NewSource(chunkSeed(s.Config.Seed, chunk.X, chunk.Y)))
+
+	// Only the cells of the chunk itself, so the placement does not depend on which other chunks are loaded
+	cells := []*worldgen.WorldCell{}
+	for localY := 0; localY < worldgen.ChunkSize; localY++ {
+		for localX := 0; localX < worldgen.ChunkSize; localX++ {
+			// Calculate world coordinates for this cell
+			worldX := (chunk.X*worldgen.ChunkSize + localX) * worldgen.CellSize
+			worldY := (chunk.Y*worldgen.ChunkSize + localY) * worldgen.CellSize
+
+			if cell := world.GetCellAt(worldX, worldY); cell != nil {
+				cells = append(cells, cell)
 			}
+		}
+	}
 
-			allWallPoints := []worldgen.WallPoint{}
+	return s.spawnInCells(world, cells, rng, enemyTypes, spawnChance, maxTotalEnemies, nil)
+}
 
-			// Iterate through all possible cell positions in this chunk
-			for localY := 0; localY < worldgen.ChunkSize; localY++ {
-				for localX := 0; localX < worldgen.ChunkSize; localX++ {
-					// Calculate world coordinates for this cell
-					worldX := (x*worldgen.ChunkSize + localX) * worldgen.CellSize
-					worldY := (y*worldgen.ChunkSize + localY) * worldgen.CellSize
+// SpawnEnemiesInCell spawns up to limit enemies on the walls and in the open air of one cell,
+// at least the minimum distance away from the occupied positions. The director uses it to
+// add enemies to a loaded cell during the run, with its own random generator.
+func (s *Spawner) SpawnEnemiesInCell(world *worldgen.GeneratedWorld, cell *worldgen.WorldCell, rng *rand.Rand,
+	enemyTypes []string, spawnChance float64, limit int, occupied []math.Vector) []*Enemy {
+	return s.spawnInCells(world, []*worldgen.WorldCell{cell}, rng, enemyTypes, spawnChance, limit, occupied)
+}
 
-					// Get the cell at these coordinates
-					cell := world.GetCellAt(worldX, worldY)
-					if cell == nil {
-						continue
-					}
+// spawnInCells places up to limit enemies in the cells. Walls are cut into segments that
+// get enemies by the spawn chance; then free-floating enemies are placed in the open air.
+func (s *Spawner) spawnInCells(world *worldgen.GeneratedWorld, cells []*worldgen.WorldCell, rng *rand.Rand,
+	enemyTypes []string, spawnChance float64, limit int, occupied []math.Vector) []*Enemy {
 
-					// Get wall points in world coordinates
-					wallPoints := cell.GetWallsInWorldCoordinates()
-					if wallPoints == nil {
-						continue
-					}
+	// Load enemy image to get the width used to space enemies along the walls;
+	// each enemy is anchored with the size of its own archetype
+	enemyImage := assets.GetImage(s.Config.ImagePath)
+	// Get the original width
+	originalWidth := float64(enemyImage.Bounds().Dx())
+	// Apply the default scaling factor of Enemy.Draw()
+	enemyWidth := originalWidth * defaultEnemyScale
 
-					// Add all wall points to our collection
-					allWallPoints = append(allWallPoints, wallPoints...)
-				}
-			}
+	// List of all spawned enemies to ensure minimum distance
+	spawnedPositions := append([]math.Vector{}, occupied...)
+	// List of created enemy entities to return
+	spawnedEnemies := []*Enemy{}
 
-			if constants.DebugLogging {
-				log.Printf("Step 1: Collected %d wall points for chunk (%d, %d)", len(allWallPoints), x, y)
-			}
+	// Step 1: Collection of wall outlines
+	if constants.DebugLogging {
+		log.Printf("Step 1: Starting collection of wall outlines for %d cells", len(cells))
+	}
 
-			// Limit the number of wall points to process to prevent excessive computation
-			maxWallPoints := 2000
-			if len(allWallPoints) > maxWallPoints {
-				// If we have too many wall points, randomly select a subset
-				shuffledIndices := rng.Perm(len(allWallPoints))
-				newWallPoints := make([]worldgen.WallPoint, maxWallPoints)
-				for i := 0; i < maxWallPoints; i++ {
-					newWallPoints[i] = allWallPoints[shuffledIndices[i]]
-				}
-				allWallPoints = newWallPoints
+	allOutlines := []worldgen.WallOutline{}
+	for _, cell := range cells {
+		// Add the wall outlines in world coordinates to our collection
+		allOutlines = append(allOutlines, cell.GetWallOutlinesInWorldCoordinates()...)
+	}
+
+	if constants.DebugLogging {
+		log.Printf("Step 1: Collected %d wall outlines for %d cells", len(allOutlines), len(cells))
+	}
+
+	// Step 2: Segmentation of the outlines
+	if constants.DebugLogging {
+		log.Printf("Step 2: Starting segmentation of wall outlines for %d cells", len(cells))
+	}
+
+	// Every straight piece of an outline is a wall segment
+	wallSegments := s.findWallSegments(allOutlines)
+
+	// Limit the number of wall segments to process
+	maxSegments := 100
+	if len(wallSegments) > maxSegments {
+		// If we have too many wall segments, randomly select a subset
+		shuffledIndices := rng.Perm(len(wallSegments))
+		selectedSegments := make([][]worldgen.WallPoint, maxSegments)
+		for i := 0; i < maxSegments; i++ {
+			selectedSegments[i] = wallSegments[shuffledIndices[i]]
+		}
+		wallSegments = selectedSegments
+	}
+
+	if constants.DebugLogging {
+		log.Printf("Step 2: Created %d wall segments for %d cells", len(wallSegments), len(cells))
+	}
+
+	// For each wall segment, try to spawn enemies
+	for _, segment := range wallSegments {
+		// Check if the segment is long enough for an enemy
+		segmentLength := s.getSegmentLength(segment)
+		if constants.DebugLogging {
+			log.Printf("Segment length: %.2f (minimum: %.2f)", segmentLength, s.Config.MinWallLength)
+		}
+		if len(segment) < 2 || segmentLength < s.Config.MinWallLength {
+			if constants.DebugLogging {
+				log.Printf("Segment length check failed: %.2f (minimum: %.2f)", segmentLength, s.Config.MinWallLength)
 			}
+			continue
+		}
 
-			// Step 2: Connecting points to a line and segmentation
+		// Check if the segment is flat enough
+		isFlat := s.isSegmentFlat(segment, s.Config.MaxWallDeviation)
+		if constants.DebugLogging {
+			log.Printf("Segment flatness: %v (max deviation: %.2f)", isFlat, s.Config.MaxWallDeviation)
+		}
+		if !isFlat {
 			if constants.DebugLogging {
-				log.Printf("Step 2: Starting connection of wall points to segments for chunk (%d, %d)", x, y)
+				log.Printf("Segment flatness check failed (max deviation: %.2f)", s.Config.MaxWallDeviation)
 			}
+			continue
+		}
+
+		// Calculate how many enemies can fit on this segment
+		// We need space for the enemy width plus the minimum distance between enemies
+		// The formula accounts for the fact that we don't need extra space after the last enemy
+		maxEnemies := int((segmentLength + s.Config.MinDistanceBetweenEnemies) / (enemyWidth + s.Config.MinDistanceBetweenEnemies))
+
+		if constants.DebugLogging {
+			log.Printf("maxEnemies calculation: maxEnemies=%d (segmentLength=%.2f, enemyWidth=%.2f, minDistance=%.2f)",
+				maxEnemies, segmentLength, enemyWidth, s.Config.MinDistanceBetweenEnemies)
+		}
 
-			// Find continuous wall segments
-			wallSegments := s.findWallSegments(allWallPoints)
+		// If segment is too short for even one enemy, skip
+		if maxEnemies < 1 {
+			continue
+		}
 
-			// Limit the number of wall segments to process
-			maxSegments := 100
-			if len(wallSegments) > maxSegments {
-				wallSegments = wallSegments[:maxSegments]
+		// Limit the maximum number of enemies per segment
+		if maxEnemies > 10 {
+			maxEnemies = 10
+		}
+
+		// Determine how many enemies to actually spawn (based on chance)
+		numToSpawn := 0
+		for i := 0; i < maxEnemies; i++ {
+			if rng.Float64() <= spawnChance {
+				numToSpawn++
 			}
+		}
 
+		if constants.DebugLogging {
+			log.Printf("Spawn chance calculation: numToSpawn=%d (maxEnemies: %d, chance: %.2f)", numToSpawn, maxEnemies, spawnChance)
+		}
+
+		// If no enemies to spawn, skip
+		if numToSpawn == 0 {
 			if constants.DebugLogging {
-				log.Printf("Step 2: Created %d wall segments for chunk (%d, %d)", len(wallSegments), x, y)
+				log.Printf("No enemies to spawn (numToSpawn=0), skipping segment")
 			}
+			continue
+		}
 
-			// For each wall segment, try to spawn enemies
-			for _, segment := range wallSegments {
-				// Check if the segment is long enough for an enemy
-				segmentLength := s.getSegmentLength(segment)
-				if constants.DebugLogging {
-					log.Printf("Segment length: %.2f (minimum: %.2f)", segmentLength, s.Config.MinWallLength)
-				}
-				if len(segment) < 2 || segmentLength < s.Config.MinWallLength {
-					if constants.DebugLogging {
-						log.Printf("Segment length check failed: %.2f (minimum: %.2f)", segmentLength, s.Config.MinWallLength)
-					}
-					continue
-				}
+		// Calculate spacing between enemies
+		// If there's only one enemy to spawn, place it in the middle of the segment
+		// Otherwise, distribute enemies evenly along the segment
+		var spacing float64
+		if numToSpawn == 1 {
+			spacing = segmentLength / 2.0
+		} else {
+			// This formula ensures enemies are evenly spaced and the first/last enemies
+			// are not placed exactly at the segment endpoints
+			spacing = segmentLength / float64(numToSpawn+1)
+		}
 
-				// Check if the segment is flat enough
-				isFlat := s.isSegmentFlat(segment, s.Config.MaxWallDeviation)
-				if constants.DebugLogging {
-					log.Printf("Segment flatness: %v (max deviation: %.2f)", isFlat, s.Config.MaxWallDeviation)
-				}
-				if !isFlat {
-					if constants.DebugLogging {
-						log.Printf("Segment flatness check failed (max deviation: %.2f)", s.Config.MaxWallDeviation)
-					}
-					continue
-				}
+		// For each enemy to spawn
+		for i := 1; i <= numToSpawn; i++ {
+			// Check if we've already spawned the maximum number of enemies
+			if len(spawnedEnemies) >= limit {
+				return spawnedEnemies
+			}
 
-				// Calculate how many enemies can fit on this segment
-				// We need space for the enemy width plus the minimum distance between enemies
-				// The formula accounts for the fact that we don't need extra space after the last enemy
-				maxEnemies := int((segmentLength + s.Config.MinDistanceBetweenEnemies) / (enemyWidth + s.Config.MinDistanceBetweenEnemies))
+			// Step 3: Placement of the enemy on the segment
+			if constants.DebugLogging {
+				log.Printf("Step 3: Starting placement of enemy %d/%d on segment (length: %.2f)", i, numToSpawn, segmentLength)
+			}
 
-				if constants.DebugLogging {
-					log.Printf("maxEnemies calculation: maxEnemies=%d (segmentLength=%.2f, enemyWidth=%.2f, minDistance=%.2f)",
-						maxEnemies, segmentLength, enemyWidth, s.Config.MinDistanceBetweenEnemies)
-				}
+			// Calculate position along the segment
+			t := float64(i) * spacing / segmentLength
+			spawnPos := s.interpolateSegment(segment, t)
 
-				// If segment is too short for even one enemy, skip
-				if maxEnemies < 1 {
-					continue
-				}
+			if constants.DebugLogging {
+				log.Printf("Step 3: Calculated initial position at (%.2f, %.2f) with t=%.2f", spawnPos.X, spawnPos.Y, t)
+			}
 
-				// Limit the maximum number of enemies per segment
-				if maxEnemies > 10 {
-					maxEnemies = 10
-				}
+			// Check minimum distance to other spawned enemies
+			tooClose := false
+			for _, pos := range spawnedPositions {
+				dx := spawnPos.X - pos.X
+				dy := spawnPos.Y - pos.Y
+				distSq := dx*dx + dy*dy
 
-				// Determine how many enemies to actually spawn (based on chance)
-				numToSpawn := 0
-				for i := 0; i < maxEnemies; i++ {
-					if rng.Float64() <= spawnChance {
-						numToSpawn++
-					}
+				if distSq < s.Config.MinDistanceBetweenEnemies*s.Config.MinDistanceBetweenEnemies {
+					tooClose = true
+					break
 				}
+			}
 
+			if tooClose {
 				if constants.DebugLogging {
-					log.Printf("Spawn chance calculation: numToSpawn=%d (maxEnemies: %d, chance: %.2f)", numToSpawn, maxEnemies, spawnChance)
+					log.Printf("Step 3: Position too close to existing enemy, skipping")
 				}
+				continue
+			}
 
-				// If no enemies to spawn, skip
-				if numToSpawn == 0 {
-					if constants.DebugLogging {
-						log.Printf("No enemies to spawn (numToSpawn=0), skipping segment")
-					}
-					continue
-				}
+			// Step 4: Calculation of rotation
+			if constants.DebugLogging {
+				log.Printf("Step 4: Starting calculation of rotation for enemy at (%.2f, %.2f)", spawnPos.X, spawnPos.Y)
+			}
 
-				// Calculate spacing between enemies
-				// If there's only one enemy to spawn, place it in the middle of the segment
-				// Otherwise, distribute enemies evenly along the segment
-				var spacing float64
-				if numToSpawn == 1 {
-					spacing = segmentLength / 2.0
-				} else {
-					// This formula ensures enemies are evenly spaced and the first/last enemies
-					// are not placed exactly at the segment endpoints
-					spacing = segmentLength / float64(numToSpawn+1)
-				}
+			// Calculate wall normal at this position
+			normal := s.getSegmentNormalAt(segment, t)
 
-				// For each enemy to spawn
-				for i := 1; i <= numToSpawn; i++ {
-					// Check if we've already spawned the maximum number of enemies
-					if len(spawnedEnemies) >= maxTotalEnemies {
-						return spawnedEnemies
-					}
+			if constants.DebugLogging {
+				log.Printf("Step 4: Got normal vector (%.2f, %.2f)", normal.X, normal.Y)
+			}
 
-					// Step 3: Placement of the enemy on the segment
-					if constants.DebugLogging {
-						log.Printf("Step 3: Starting placement of enemy %d/%d on segment (length: %.2f)", i, numToSpawn, segmentLength)
-					}
+			// Ensure normal is normalized
+			normalX, normalY := normal.X, normal.Y
+			magnitude := stdmath.Sqrt(normalX*normalX + normalY*normalY)
+			if magnitude > 0 {
+				normalX /= magnitude
+				normalY /= magnitude
+			}
 
-					// Calculate position along the segment
-					t := float64(i) * spacing / segmentLength
-					spawnPos := s.interpolateSegment(segment, t)
+			// Calculate rotation angle based on normal vector
+			// Default enemy faces up (0 degrees), so we need to rotate based on normal
+			// Use the normal vector directly without the extra 90-degree rotation
+			// atan2 gives angle in radians, convert to degrees
+			angle := stdmath.Atan2(normalX, -normalY) * 180 / stdmath.Pi
 
-					if constants.DebugLogging {
-						log.Printf("Step 3: Calculated initial position at (%.2f, %.2f) with t=%.2f", spawnPos.X, spawnPos.Y, t)
-					}
+			if constants.DebugLogging {
+				log.Printf("Step 4: Calculated rotation angle: %.2f degrees", angle)
+			}
 
-					// Check minimum distance to other spawned enemies
-					tooClose := false
-					for _, pos := range spawnedPositions {
-						dx := spawnPos.X - pos.X
-						dy := spawnPos.Y - pos.Y
-						distSq := dx*dx + dy*dy
-
-						if distSq < s.Config.MinDistanceBetweenEnemies*s.Config.MinDistanceBetweenEnemies {
-							tooClose = true
-							break
-						}
-					}
+			// Choose an enemy type from the roster of the biome at this wall,
+			// with the archetypes that stand on the ground only on walls that face up
+			surfaces := []SpawnSurface{SurfaceWall}
+			if normalY <= -floorNormalY {
+				surfaces = append(surfaces, SurfaceFloor)
+			}
+			biomeName, roster := spawnBiomeAt(world, spawnPos, enemyTypes)
+			archetype := pickArchetype(rng, roster, biomeName, surfaces...)
+			if archetype == nil {
+				continue
+			}
+			anchorWidth, anchorHeight := archetype.Size()
+
+			// Initial offset spawn position in direction of normal vector
+			// This is just a starting point, we'll adjust it based on transparency checks
+			initialOffsetX := 5.0 // Initial offset to ensure enemy is on the wall
+			spawnX := spawnPos.X + normalX*initialOffsetX
+			spawnY := spawnPos.Y + normalY*initialOffsetX
+
+			// Get the cell and snippet at the spawn position
+			cell := world.GetCellAt(int(spawnX), int(spawnY))
+			if cell == nil || cell.Snippet == nil {
+				continue // Skip if we can't get the cell or snippet
+			}
+			if !slices.Contains(cells, cell) {
+				continue // Skip other cells, so the placement does not depend on which of them are loaded
+			}
 
-					if tooClose {
-						if constants.DebugLogging {
-							log.Printf("Step 3: Position too close to existing enemy, skipping")
-						}
-						continue
-					}
+			// Step 5: Validation and adjustment of position
+			if constants.DebugLogging {
+				log.Printf("Step 5: Starting validation and adjustment of position for enemy at (%.2f, %.2f)", spawnX, spawnY)
+			}
 
-					// Step 4: Calculation of rotation
-					if constants.DebugLogging {
-						log.Printf("Step 4: Starting calculation of rotation for enemy at (%.2f, %.2f)", spawnPos.X, spawnPos.Y)
-					}
+			// Adjust position to ensure enemy is properly anchored to the wall
+			// We need to check if the bottom points of the enemy are in the rock (non-transparent)
+			// and if the center of the enemy is in the air (transparent)
+			validPosition := false
+			maxAdjustmentAttempts := 10
+			adjustmentStep := 1.0 // Step size for position adjustment
 
-					// Calculate wall normal at this position
-					normal := s.getSegmentNormalAt(segment, t)
+			for attempt := 0; attempt < maxAdjustmentAttempts && !validPosition; attempt++ {
+				if constants.DebugLogging {
+					log.Printf("Step 5: Adjustment attempt %d/%d at position (%.2f, %.2f)", attempt+1, maxAdjustmentAttempts, spawnX, spawnY)
+				}
 
-					if constants.DebugLogging {
-						log.Printf("Step 4: Got normal vector (%.2f, %.2f)", normal.X, normal.Y)
-					}
+				// Calculate world coordinates relative to the cell
+				relativeX := int(spawnX) % worldgen.CellSize
+				relativeY := int(spawnY) % worldgen.CellSize
+				if relativeX < 0 {
+					relativeX += worldgen.CellSize
+				}
+				if relativeY < 0 {
+					relativeY += worldgen.CellSize
+				}
 
-					// Ensure normal is normalized
-					normalX, normalY := normal.X, normal.Y
-					magnitude := stdmath.Sqrt(normalX*normalX + normalY*normalY)
-					if magnitude > 0 {
-						normalX /= magnitude
-						normalY /= magnitude
-					}
+				// Calculate the bottom left and bottom right points of the enemy
+				// These points should be in the rock (non-transparent)
+				// First calculate the points as if the enemy is facing upward (0 degrees)
+				bottomLeftX := -int(anchorWidth / 4)
+				bottomLeftY := int(anchorHeight / 4)
+				bottomRightX := int(anchorWidth / 4)
+				bottomRightY := int(anchorHeight / 4)
+				centerX := 0
+				centerY := -int(anchorHeight / 4)
+
+				// Convert angle to radians for rotation calculation
+				angleRad := angle * stdmath.Pi / 180.0
+				cosAngle := stdmath.Cos(angleRad)
+				sinAngle := stdmath.Sin(angleRad)
+
+				// Rotate the points according to the calculated angle
+				rotatedBottomLeftX := int(float64(bottomLeftX)*cosAngle - float64(bottomLeftY)*sinAngle)
+				rotatedBottomLeftY := int(float64(bottomLeftX)*sinAngle + float64(bottomLeftY)*cosAngle)
+				rotatedBottomRightX := int(float64(bottomRightX)*cosAngle - float64(bottomRightY)*sinAngle)
+				rotatedBottomRightY := int(float64(bottomRightX)*sinAngle + float64(bottomRightY)*cosAngle)
+				rotatedCenterX := int(float64(centerX)*cosAngle - float64(centerY)*sinAngle)
+				rotatedCenterY := int(float64(centerX)*sinAngle + float64(centerY)*cosAngle)
+
+				// Add the relative position to get the final coordinates
+				bottomLeftX = relativeX + rotatedBottomLeftX
+				bottomLeftY = relativeY + rotatedBottomLeftY
+				bottomRightX = relativeX + rotatedBottomRightX
+				bottomRightY = relativeY + rotatedBottomRightY
+				centerX = relativeX + rotatedCenterX
+				centerY = relativeY + rotatedCenterY
+
+				// Check if the bottom points are in the rock and the center is in the air
+				bottomLeftInRock := isPointInRock(cell, bottomLeftX, bottomLeftY)
+				bottomRightInRock := isPointInRock(cell, bottomRightX, bottomRightY)
+				centerInAir := !isPointInRock(cell, centerX, centerY)
 
-					// Calculate rotation angle based on normal vector
-					// Default enemy faces up (0 degrees), so we need to rotate based on normal
-					// Use the normal vector directly without the extra 90-degree rotation
-					// atan2 gives angle in radians, convert to degrees
-					angle := stdmath.Atan2(normalX, -normalY) * 180 / stdmath.Pi
+				if constants.DebugLogging {
+					log.Printf("Step 5: Check points - bottomLeft(%d,%d): inRock=%v, bottomRight(%d,%d): inRock=%v, center(%d,%d): inAir=%v",
+						bottomLeftX, bottomLeftY, bottomLeftInRock,
+						bottomRightX, bottomRightY, bottomRightInRock,
+						centerX, centerY, centerInAir)
+				}
 
+				if bottomLeftInRock && bottomRightInRock && centerInAir {
+					validPosition = true
 					if constants.DebugLogging {
-						log.Printf("Step 4: Calculated rotation angle: %.2f degrees", angle)
+						log.Printf("Step 5: Found valid position at (%.2f, %.2f)", spawnX, spawnY)
 					}
-
-					// Choose random enemy type
-					enemyType := enemyTypes[rng.Intn(len(enemyTypes))]
-
-					// Initial offset spawn position in direction of normal vector
-					// This is just a starting point, we'll adjust it based on transparency checks
-					initialOffsetX := 5.0 // Initial offset to ensure enemy is on the wall
-					spawnX := spawnPos.X + normalX*initialOffsetX
-					spawnY := spawnPos.Y + normalY*initialOffsetX
-
-					// Get the cell and snippet at the spawn position
-					cell := world.GetCellAt(int(spawnX), int(spawnY))
-					if cell == nil || cell.Snippet == nil {
-						continue // Skip if we can't get the cell or snippet
+				} else if !bottomLeftInRock || !bottomRightInRock {
+					// If bottom points are not in rock, move deeper into the wall
+					spawnX += normalX * adjustmentStep
+					spawnY += normalY * adjustmentStep
+					if constants.DebugLogging {
+						log.Printf("Step 5: Bottom points not in rock, moving deeper into wall to (%.2f, %.2f)", spawnX, spawnY)
 					}
-
-					// Step 5: Validation and adjustment of position
+				} else if !centerInAir {
+					// If center is not in air, move away from the wall
+					spawnX -= normalX * adjustmentStep
+					spawnY -= normalY * adjustmentStep
 					if constants.DebugLogging {
-						log.Printf("Step 5: Starting validation and adjustment of position for enemy at (%.2f, %.2f)", spawnX, spawnY)
+						log.Printf("Step 5: Center not in air, moving away from wall to (%.2f, %.2f)", spawnX, spawnY)
 					}
+				}
+			}
 
-					// Adjust position to ensure enemy is properly anchored to the wall
-					// We need to check if the bottom points of the enemy are in the rock (non-transparent)
-					// and if the center of the enemy is in the air (transparent)
-					validPosition := false
-					maxAdjustmentAttempts := 10
-					adjustmentStep := 1.0 // Step size for position adjustment
-
-					for attempt := 0; attempt < maxAdjustmentAttempts && !validPosition; attempt++ {
-						if constants.DebugLogging {
-							log.Printf("Step 5: Adjustment attempt %d/%d at position (%.2f, %.2f)", attempt+1, maxAdjustmentAttempts, spawnX, spawnY)
-						}
-
-						// Calculate world coordinates relative to the cell
-						relativeX := int(spawnX) % worldgen.CellSize
-						relativeY := int(spawnY) % worldgen.CellSize
-						if relativeX < 0 {
-							relativeX += worldgen.CellSize
-						}
-						if relativeY < 0 {
-							relativeY += worldgen.CellSize
-						}
-
-						// Calculate the bottom left and bottom right points of the enemy
-						// These points should be in the rock (non-transparent)
-						// First calculate the points as if the enemy is facing upward (0 degrees)
-						bottomLeftX := -int(enemyWidth / 4)
-						bottomLeftY := int(enemyHeight / 4)
-						bottomRightX := int(enemyWidth / 4)
-						bottomRightY := int(enemyHeight / 4)
-						centerX := 0
-						centerY := -int(enemyHeight / 4)
-
-						// Convert angle to radians for rotation calculation
-						angleRad := angle * stdmath.Pi / 180.0
-						cosAngle := stdmath.Cos(angleRad)
-						sinAngle := stdmath.Sin(angleRad)
-
-						// Rotate the points according to the calculated angle
-						rotatedBottomLeftX := int(float64(bottomLeftX)*cosAngle - float64(bottomLeftY)*sinAngle)
-						rotatedBottomLeftY := int(float64(bottomLeftX)*sinAngle + float64(bottomLeftY)*cosAngle)
-						rotatedBottomRightX := int(float64(bottomRightX)*cosAngle - float64(bottomRightY)*sinAngle)
-						rotatedBottomRightY := int(float64(bottomRightX)*sinAngle + float64(bottomRightY)*cosAngle)
-						rotatedCenterX := int(float64(centerX)*cosAngle - float64(centerY)*sinAngle)
-						rotatedCenterY := int(float64(centerX)*sinAngle + float64(centerY)*cosAngle)
-
-						// Add the relative position to get the final coordinates
-						bottomLeftX = relativeX + rotatedBottomLeftX
-						bottomLeftY = relativeY + rotatedBottomLeftY
-						bottomRightX = relativeX + rotatedBottomRightX
-						bottomRightY = relativeY + rotatedBottomRightY
-						centerX = relativeX + rotatedCenterX
-						centerY = relativeY + rotatedCenterY
-
-						// Check if the bottom points are in the rock and the center is in the air
-						bottomLeftInRock := s.isPointInRock(cell, bottomLeftX, bottomLeftY)
-						bottomRightInRock := s.isPointInRock(cell, bottomRightX, bottomRightY)
-						centerInAir := !s.isPointInRock(cell, centerX, centerY)
-
-						if constants.DebugLogging {
-							log.Printf("Step 5: Check points - bottomLeft(%d,%d): inRock=%v, bottomRight(%d,%d): inRock=%v, center(%d,%d): inAir=%v",
-								bottomLeftX, bottomLeftY, bottomLeftInRock,
-								bottomRightX, bottomRightY, bottomRightInRock,
-								centerX, centerY, centerInAir)
-						}
-
-						if bottomLeftInRock && bottomRightInRock && centerInAir {
-							validPosition = true
-							if constants.DebugLogging {
-								log.Printf("Step 5: Found valid position at (%.2f, %.2f)", spawnX, spawnY)
-							}
-						} else if !bottomLeftInRock || !bottomRightInRock {
-							// If bottom points are not in rock, move deeper into the wall
-							spawnX += normalX * adjustmentStep
-							spawnY += normalY * adjustmentStep
-							if constants.DebugLogging {
-								log.Printf("Step 5: Bottom points not in rock, moving deeper into wall to (%.2f, %.2f)", spawnX, spawnY)
-							}
-						} else if !centerInAir {
-							// If center is not in air, move away from the wall
-							spawnX -= normalX * adjustmentStep
-							spawnY -= normalY * adjustmentStep
-							if constants.DebugLogging {
-								log.Printf("Step 5: Center not in air, moving away from wall to (%.2f, %.2f)", spawnX, spawnY)
-							}
-						}
-					}
+			// Skip if we couldn't find a valid position
+			if !validPosition {
+				if constants.DebugLogging {
+					log.Printf("Step 5: Could not find valid position after %d attempts, skipping", maxAdjustmentAttempts)
+				}
+				continue
+			}
 
-					// Skip if we couldn't find a valid position
-					if !validPosition {
-						if constants.DebugLogging {
-							log.Printf("Step 5: Could not find valid position after %d attempts, skipping", maxAdjustmentAttempts)
-						}
-						continue
-					}
+			// Check minimum distance again with the adjusted position
+			tooClose = false
+			for _, pos := range spawnedPositions {
+				dx := spawnX - pos.X
+				dy := spawnY - pos.Y
+				distSq := dx*dx + dy*dy
 
-					// Check minimum distance again with the adjusted position
-					tooClose = false
-					for _, pos := range spawnedPositions {
-						dx := spawnX - pos.X
-						dy := spawnY - pos.Y
-						distSq := dx*dx + dy*dy
-
-						if distSq < s.Config.MinDistanceBetweenEnemies*s.Config.MinDistanceBetweenEnemies {
-							tooClose = true
-							break
-						}
-					}
+				if distSq < s.Config.MinDistanceBetweenEnemies*s.Config.MinDistanceBetweenEnemies {
+					tooClose = true
+					break
+				}
+			}
 
-					if tooClose {
-						if constants.DebugLogging {
-							log.Printf("Step 5: Adjusted position too close to existing enemy, skipping")
-						}
-						continue
-					}
+			if tooClose {
+				if constants.DebugLogging {
+					log.Printf("Step 5: Adjusted position too close to existing enemy, skipping")
+				}
+				continue
+			}
 
-					// Step 6: Finalization of placement
-					if constants.DebugLogging {
-						log.Printf("Step 6: Starting finalization of enemy placement at (%.2f, %.2f) with rotation %.2f", spawnX, spawnY, angle)
-					}
+			// Step 6: Finalization of placement
+			if constants.DebugLogging {
+				log.Printf("Step 6: Starting finalization of enemy placement at (%.2f, %.2f) with rotation %.2f", spawnX, spawnY, angle)
+			}
 
-					// Create the enemy entity with the adjusted position
-					imagePath := "images/gameScene/Enemies/" + enemyType + ".png"
-					enemy := NewEnemy(enemyType, spawnX, spawnY, angle, imagePath)
+			// Create the enemy entity with the adjusted position
+			enemy := NewEnemy(archetype.Name, spawnX, spawnY, angle, archetype.Sprite)
 
-					// Add to the list of spawned enemies
-					spawnedEnemies = append(spawnedEnemies, enemy)
-					spawnedPositions = append(spawnedPositions, math.Vector{X: spawnX, Y: spawnY})
+			// Add to the list of spawned enemies
+			spawnedEnemies = append(spawnedEnemies, enemy)
+			spawnedPositions = append(spawnedPositions, math.Vector{X: spawnX, Y: spawnY})
 
-					if constants.DebugLogging {
-						log.Printf("Step 6: Successfully created enemy of type '%s' at (%.2f, %.2f) with rotation %.2f", enemyType, spawnX, spawnY, angle)
-						log.Printf("Step 6: Total enemies spawned so far: %d", len(spawnedEnemies))
-					}
-				}
+			if constants.DebugLogging {
+				log.Printf("Step 6: Successfully created enemy of type '%s' at (%.2f, %.2f) with rotation %.2f", archetype.Name, spawnX, spawnY, angle)
+				log.Printf("Step 6: Total enemies spawned so far: %d", len(spawnedEnemies))
 			}
 		}
 	}
 
-	if constants.DebugLogging {
-		log.Printf("SpawnEnemiesOnWalls: Finished spawning %d enemies", len(spawnedEnemies))
-	}
-	return spawnedEnemies
+	// Step 7: Enemies that float in the open air of the cells
+	return s.spawnFreeFloating(world, cells, rng, enemyTypes, spawnChance, limit, spawnedEnemies, spawnedPositions)
 }
 
-// findWallSegments groups wall points into continuous segments
-func (s *Spawner) findWallSegments(wallPoints []worldgen.WallPoint) [][]worldgen.WallPoint {
-	if len(wallPoints) == 0 {
-		return nil
-	}
+// maxTotalEnemies limits the number of enemies spawned at once to prevent excessive processing
+const maxTotalEnemies = 500
 
-	// Group wall points into segments based on proximity and normal similarity
-	segments := [][]worldgen.WallPoint{}
-	visited := make([]bool, len(wallPoints))
+// floorNormalY is how much a wall normal must point up for the wall to count as floor
+const floorNormalY = 0.7
+
+// freeFloatingClearance is the distance that free-floating enemies keep from rock
+const freeFloatingClearance = 8.0
 
-	for i := 0; i < len(wallPoints); i++ {
-		if visited[i] {
+// spawnFreeFloating places enemies of free-floating archetypes at random spots of the cells,
+// where they and the clearance around them are in the open air. The enemies are appended
+// to spawned until it holds limit enemies, keeping the distance to the spawned positions.
+func (s *Spawner) spawnFreeFloating(world *worldgen.GeneratedWorld, cells []*worldgen.WorldCell, rng *rand.Rand,
+	enemyTypes []string, spawnChance float64, limit int, spawned []*Enemy, spawnedPositions []math.Vector) []*Enemy {
+	for _, cell := range cells {
+		if cell.Snippet == nil {
 			continue
 		}
 
-		// Start a new segment with this wall point
-		segment := []worldgen.WallPoint{wallPoints[i]}
-		visited[i] = true
-
-		// Find all connected wall points
-		s.growSegment(wallPoints, visited, &segment, i)
-
-		// Add the segment to our list
-		segments = append(segments, segment)
-	}
-
-	return segments
-}
-
-// growSegment iteratively adds connected wall points to a segment
-func (s *Spawner) growSegment(wallPoints []worldgen.WallPoint, visited []bool, segment *[]worldgen.WallPoint, startIdx int) {
-	// Use a queue to process points in a breadth-first manner
-	queue := []int{startIdx}
-
-	// Process points until the queue is empty
-	for len(queue) > 0 {
-		// Get the next point from the queue
-		currentIdx := queue[0]
-		queue = queue[1:] // Remove the processed point
-
-		current := wallPoints[currentIdx]
-
-		// Check all other wall points
-		for i := 0; i < len(wallPoints); i++ {
-			if visited[i] {
+		for attempt := 0; attempt < s.Config.FreeFloatingPerCell; attempt++ {
+			if len(spawned) >= limit {
+				return spawned
+			}
+			if rng.Float64() > spawnChance {
 				continue
 			}
 
-			candidate := wallPoints[i]
-
-			// Check if this point is close enough to be part of the same segment
-			dx := candidate.X - current.X
-			dy := candidate.Y - current.Y
-			distSq := dx*dx + dy*dy
-
-			// Normalize both normals before calculating dot product
-			currentNormalX, currentNormalY := current.Normal.X, current.Normal.Y
-			currentMagnitude := stdmath.Sqrt(currentNormalX*currentNormalX + currentNormalY*currentNormalY)
-			if currentMagnitude > 0 {
-				currentNormalX /= currentMagnitude
-				currentNormalY /= currentMagnitude
+			// Pick a spot and a type for it; the random numbers are drawn even if the spot is
+			// rejected, so the following spots do not depend on the checks
+			relativeX := rng.Intn(worldgen.CellSize)
+			relativeY := rng.Intn(worldgen.CellSize)
+			position := math.Vector{
+				X: float64(cell.X*worldgen.CellSize + relativeX),
+				Y: float64(cell.Y*worldgen.CellSize + relativeY),
+			}
+			biomeName, roster := spawnBiomeAt(world, position, enemyTypes)
+			archetype := pickArchetype(rng, roster, biomeName, SurfaceFree)
+			if archetype == nil {
+				continue
 			}
 
-			candidateNormalX, candidateNormalY := candidate.Normal.X, candidate.Normal.Y
-			candidateMagnitude := stdmath.Sqrt(candidateNormalX*candidateNormalX + candidateNormalY*candidateNormalY)
-			if candidateMagnitude > 0 {
-				candidateNormalX /= candidateMagnitude
-				candidateNormalY /= candidateMagnitude
+			width, height := archetype.Size()
+			clearance := stdmath.Max(width, height)/2 + freeFloatingClearance
+			if !isAreaInAir(cell, relativeX, relativeY, clearance) || tooCloseToAny(position, spawnedPositions, s.Config.MinDistanceBetweenEnemies) {
+				continue
 			}
 
-			// Check if normals are similar enough (dot product close to 1)
-			normalDot := candidateNormalX*currentNormalX + candidateNormalY*currentNormalY
+			spawned = append(spawned, NewEnemy(archetype.Name, position.X, position.Y, 0, archetype.Sprite))
+			spawnedPositions = append(spawnedPositions, position)
+		}
+	}
+	return spawned
+}
 
-			// Ensure dot product is within valid range [-1, 1]
-			if normalDot < -1.0 {
-				normalDot = -1.0
-			} else if normalDot > 1.0 {
-				normalDot = 1.0
-			}
+// isAreaInAir checks that a circle in a cell lies in the air: its center and eight points
+// on its edge are transparent, and all of them are inside the cell.
+func isAreaInAir(cell *worldgen.WorldCell, x, y int, radius float64) bool {
+	for i := -1; i < 8; i++ {
+		px, py := float64(x), float64(y)
+		if i >= 0 {
+			angle := float64(i) * stdmath.Pi / 4
+			px += stdmath.Sin(angle) * radius
+			py -= stdmath.Cos(angle) * radius
+		}
+		if px < 0 || py < 0 || px >= worldgen.CellSize || py >= worldgen.CellSize {
+			return false
+		}
+		if isPointInRock(cell, int(px), int(py)) {
+			return false
+		}
+	}
+	return true
+}
 
-			// Convert dot product to angle in degrees
-			angle := stdmath.Acos(normalDot) * 180 / stdmath.Pi
+// tooCloseToAny checks if a position is closer than the minimum distance to any of the positions
+func tooCloseToAny(position math.Vector, positions []math.Vector, minDistance float64) bool {
+	for _, other := range positions {
+		if math.Distance(position, other) < minDistance {
+			return true
+		}
+	}
+	return false
+}
 
-			// If point is close and has similar normal (angle less than 45 degrees), add to segment
-			// 25.0 is distance squared (5 pixels)
-			if distSq < 900.0 && angle < 45.0 {
-				*segment = append(*segment, candidate)
-				visited[i] = true
-				// Add this point to the queue for processing
-				queue = append(queue, i)
-			}
+// chunkSeed derives the random seed of a chunk from the spawner seed (splitmix64 finalizer)
+func chunkSeed(seed int64, chunkX, chunkY int) int64 {
+	mixed := uint64(seed) ^ uint64(int64(chunkX))*0x9e3779b97f4a7c15 ^ uint64(int64(chunkY))*0xc2b2ae3d27d4eb4f
+	mixed ^= mixed >> 30
+	mixed *= 0xbf58476d1ce4e5b9
+	mixed ^= mixed >> 27
+	mixed *= 0x94d049bb133111eb
+	mixed ^= mixed >> 31
+	return int64(mixed)
+}
+
+// findWallSegments splits wall outlines into straight wall segments.
+// Each segment consists of its two end points, both with the normal of the segment.
+func (s *Spawner) findWallSegments(outlines []worldgen.WallOutline) [][]worldgen.WallPoint {
+	segments := [][]worldgen.WallPoint{}
+	for _, outline := range outlines {
+		for _, segment := range outline.Segments {
+			segments = append(segments, []worldgen.WallPoint{
+				{X: segment.A.X, Y: segment.A.Y, Normal: segment.Normal},
+				{X: segment.B.X, Y: segment.B.Y, Normal: segment.Normal},
+			})
 		}
 	}
+	return segments
 }
 
 // getSegmentLength calculates the length of a wall segment
@@ -738,13 +791,14 @@ func (s *Spawner) getSegmentNormalAt(segment []worldgen.WallPoint, t float64) ma
 	return segment[len(segment)-1].Normal
 }
 
-// isPointInRock checks if a point in a cell is in a rock (non-transparent) or in air (transparent)
-func (s *Spawner) isPointInRock(cell *worldgen.WorldCell, x, y int) bool {
+// isPointInRock checks if a point in a cell is in a rock (non-transparent) or in air (transparent).
+// Synthesized snippets whose image was freed by chunk streaming are checked against their rock mask.
+func isPointInRock(cell *worldgen.WorldCell, x, y int) bool {
 	if constants.DebugLogging {
 		log.Printf("isPointInRock: Checking point (%d, %d)", x, y)
 	}
 
-	if cell == nil || cell.Snippet == nil || cell.Snippet.Image == nil {
+	if cell == nil || cell.Snippet == nil || (cell.Snippet.Image == nil && cell.Snippet.Mask == nil) {
 		if constants.DebugLogging {
 			log.Printf("isPointInRock: Invalid cell or snippet, returning false")
 		}
@@ -755,7 +809,10 @@ func (s *Spawner) isPointInRock(cell *worldgen.WorldCell, x, y int) bool {
 	img := cell.Snippet.Image
 
 	// Get image dimensions
-	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	width, height := worldgen.CellSize, worldgen.CellSize
+	if img != nil {
+		width, height = img.Bounds().Dx(), img.Bounds().Dy()
+	}
 
 	if constants.DebugLogging {
 		log.Printf("isPointInRock: Image dimensions: %dx%d", width, height)
@@ -810,6 +867,10 @@ func (s *Spawner) isPointInRock(cell *worldgen.WorldCell, x, y int) bool {
 		}
 	}
 
+	if img == nil {
+		return cell.Snippet.Mask.IsRock(x, y)
+	}
+
 	// Use At() method to get the color at the specified point
 	// This is more efficient than creating a new image and reading all pixel data
 	_, _, _, a := img.At(x, y).RGBA()
@@ -828,3 +889,16 @@ func (s *Spawner) isPointInRock(cell *worldgen.WorldCell, x, y int) bool {
 
 	return isRock
 }
+
+// spawnBiomeAt returns the name of the biome at a position and the enemy types that may spawn there.
+// Biomes with their own roster override the default enemy types. The name is empty outside of biomes.
+func spawnBiomeAt(world *worldgen.GeneratedWorld, position math.Vector, defaultTypes []string) (string, []string) {
+	biome := world.GetBiomeAt(int(position.X), int(position.Y))
+	if biome == nil {
+		return "", defaultTypes
+	}
+	if len(biome.EnemyTypes) > 0 {
+		return biome.Name, biome.EnemyTypes
+	}
+	return biome.Name, defaultTypes
+}
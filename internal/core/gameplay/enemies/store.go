@@ -0,0 +1,140 @@
+package enemies
+
+import (
+	"discoveryx/internal/core/worldgen"
+	"discoveryx/internal/utils/math"
+)
+
+// EnemyStore keeps track of the enemies of each chunk while chunks are streamed
+// in and out. The first time a chunk is loaded its enemies are spawned; when the
+// chunk is unloaded the surviving enemies are saved, and they are restored with
+// the same state when the chunk is loaded again. Killed enemies stay dead.
+type EnemyStore struct {
+	spawner     *Spawner
+	enemyTypes  []string
+	spawnChance float64
+
+	saved  map[worldgen.ChunkCoord][]enemySnapshot // Surviving enemies of unloaded chunks that were loaded before
+	owners map[*Enemy]worldgen.ChunkCoord          // Chunk that each live enemy belongs to
+}
+
+// enemySnapshot is the state of an enemy that is kept while its chunk is unloaded.
+// The AI state and the velocity are kept too, so an enemy that was chasing the player
+// keeps chasing when its chunk comes back.
+type enemySnapshot struct {
+	Type              string
+	Position          math.Vector
+	Rotation          float64
+	ImagePath         string
+	Health            float64
+	TimeSinceLastShot float64
+	Brain             *Brain      // Copy of the brain without its environment, nil for enemies without one
+	Velocity          math.Vector // Velocity of the body in units per second
+}
+
+// NewEnemyStore creates a store that spawns enemies of the given types with the spawner
+func NewEnemyStore(spawner *Spawner, enemyTypes []string, spawnChance float64) *EnemyStore {
+	return &EnemyStore{
+		spawner:     spawner,
+		enemyTypes:  enemyTypes,
+		spawnChance: spawnChance,
+		saved:       make(map[worldgen.ChunkCoord][]enemySnapshot),
+		owners:      make(map[*Enemy]worldgen.ChunkCoord),
+	}
+}
+
+// Load returns the enemies of a chunk that was just loaded.
+// Enemies are spawned the first time, and restored from the saved state afterwards.
+func (s *EnemyStore) Load(world *worldgen.GeneratedWorld, chunk *worldgen.WorldChunk) []*Enemy {
+	key := chunk.GetKey()
+
+	var loaded []*Enemy
+	if snapshots, exists := s.saved[key]; exists {
+		loaded = make([]*Enemy, 0, len(snapshots))
+		for _, snapshot := range snapshots {
+			enemy := NewEnemy(snapshot.Type, snapshot.Position.X, snapshot.Position.Y, snapshot.Rotation, snapshot.ImagePath)
+			enemy.Health = snapshot.Health
+			enemy.TimeSinceLastShot = snapshot.TimeSinceLastShot
+			enemy.Brain = snapshot.Brain
+			enemy.velocity = snapshot.Velocity
+			loaded = append(loaded, enemy)
+		}
+		delete(s.saved, key)
+	} else {
+		loaded = s.spawner.SpawnEnemiesInChunk(world, chunk, s.enemyTypes, s.spawnChance)
+	}
+
+	for _, enemy := range loaded {
+		s.owners[enemy] = key
+	}
+	return loaded
+}
+
+// Adopt makes a chunk the owner of an enemy that was spawned after the chunk was loaded,
+// so the enemy is saved and restored with the chunk.
+func (s *EnemyStore) Adopt(chunk worldgen.ChunkCoord, enemy *Enemy) {
+	s.owners[enemy] = chunk
+}
+
+// Forget drops an enemy that was removed from the game, so it no longer counts for its chunk.
+func (s *EnemyStore) Forget(enemy *Enemy) {
+	delete(s.owners, enemy)
+}
+
+// Count returns the number of live enemies that a loaded chunk owns.
+func (s *EnemyStore) Count(chunk worldgen.ChunkCoord) int {
+	count := 0
+	for enemy, owner := range s.owners {
+		if owner == chunk && !enemy.IsDying {
+			count++
+		}
+	}
+	return count
+}
+
+// Unload saves the surviving enemies of a chunk that is about to be unloaded.
+// It returns the enemies that remain active and the enemies that were unloaded,
+// which the caller should remove from its systems.
+func (s *EnemyStore) Unload(chunk *worldgen.WorldChunk, active []*Enemy) (remaining, unloaded []*Enemy) {
+	key := chunk.GetKey()
+
+	snapshots := []enemySnapshot{}
+	for _, enemy := range active {
+		if owner, exists := s.owners[enemy]; !exists || owner != key {
+			remaining = append(remaining, enemy)
+			continue
+		}
+
+		unloaded = append(unloaded, enemy)
+		if enemy.IsDying {
+			continue
+		}
+		snapshot := enemySnapshot{
+			Type:              enemy.Type,
+			Position:          enemy.Position,
+			Rotation:          enemy.Rotation,
+			ImagePath:         enemy.ImagePath,
+			Health:            enemy.Health,
+			TimeSinceLastShot: enemy.TimeSinceLastShot,
+		}
+		if enemy.Brain != nil {
+			brain := *enemy.Brain
+			brain.env = nil // Connected again when the enemy is added back to the game
+			snapshot.Brain = &brain
+		}
+		if enemy.body != nil {
+			snapshot.Velocity = enemy.body.Velocity
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	s.saved[key] = snapshots
+
+	// Forget all enemies of the chunk, including the ones that were removed after being killed
+	for enemy, owner := range s.owners {
+		if owner == key {
+			delete(s.owners, enemy)
+		}
+	}
+
+	return remaining, unloaded
+}
@@ -0,0 +1,42 @@
+package enemies
+
+import (
+	"discoveryx/internal/core/worldgen"
+	"discoveryx/internal/utils/math"
+	"testing"
+)
+
+// TestEnemyStoreKeepsAIState tests that an enemy comes back with its AI state and velocity
+// after its chunk was unloaded and loaded again
+func TestEnemyStoreKeepsAIState(t *testing.T) {
+	env := &fakeEnvironment{target: math.Vector{X: 260}}
+	enemy := newTestEnemy(Behaviors["hunter"], env)
+	enemy.Type = "hunter"
+	body := think(enemy, 30)
+	enemy.body = body
+	if enemy.Brain.State != StateChase {
+		t.Fatalf("Expected the hunter to chase its target before unloading, got %v", enemy.Brain.State)
+	}
+
+	store := NewEnemyStore(nil, nil, 0)
+	chunk := worldgen.NewWorldChunk(0, 0)
+	store.Adopt(chunk.GetKey(), enemy)
+	if _, unloaded := store.Unload(chunk, []*Enemy{enemy}); len(unloaded) != 1 {
+		t.Fatalf("Expected the enemy to be unloaded, got %v", unloaded)
+	}
+
+	loaded := store.Load(nil, chunk)
+	if len(loaded) != 1 {
+		t.Fatalf("Expected the enemy to be restored, got %d enemies", len(loaded))
+	}
+	restored := loaded[0]
+	if restored.Brain.State != StateChase || restored.Brain.lastSeen != enemy.Brain.lastSeen || restored.Brain.Home != enemy.Brain.Home {
+		t.Errorf("Expected the brain %+v, got %+v", *enemy.Brain, *restored.Brain)
+	}
+	if restored.Brain.env != nil {
+		t.Error("Expected the restored brain to wait for its environment")
+	}
+	if velocity := restored.NewBody().Velocity; velocity != body.Velocity {
+		t.Errorf("Expected the body to start with the velocity %v, got %v", body.Velocity, velocity)
+	}
+}
@@ -10,6 +10,7 @@ package enemies
 
 import (
 	"discoveryx/internal/assets"
+	"discoveryx/internal/config"
 	"discoveryx/internal/core/physics"
 	"discoveryx/internal/utils/math"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -22,14 +23,14 @@ import (
 // Each enemy has a specific type, position, rotation, and visual representation.
 //
 // Enemies are typically spawned by the Spawner along walls and other environmental
-// features. They remain stationary in the current implementation but could be
-// extended with movement patterns and attack behaviors in future versions.
+// features. How they move and attack is decided by their Brain, which runs the
+// behavior of their type (see ai.go).
 //
 // The Enemy struct implements the basic functionality needed for rendering
-// and updating enemy state, while specific behaviors would be implemented
+// and updating enemy state, while specific behaviors are implemented
 // in the AI system.
 type Enemy struct {
-	Type              string        // Type of enemy (e.g., "Pilz", "Kristall", etc.)
+	Type              string        // Type of enemy, the name of its archetype (e.g., "enemy_1", "hunter")
 	Position          math.Vector   // Position in world coordinates relative to center
 	Rotation          float64       // Rotation angle in degrees (0-360)
 	Image             *ebiten.Image // Cached enemy sprite for rendering
@@ -43,6 +44,15 @@ type Enemy struct {
 	DeathTimer        float64       // Timer for tracking death animation
 	ExplosionFrame    int           // Current frame of the explosion animation
 	ExplosionImage    *ebiten.Image // Explosion sprite sheet
+	hit               bool          // Whether the enemy was hit, which shows its health bar
+	sinceHit          float64       // Seconds since the last hit, for the hit flash
+
+	Archetype *Archetype    // Definition of the enemy type, nil for types without one
+	Brain     *Brain        // State machine that moves the enemy and makes it shoot
+	Boss      *Boss         // Phases of a boss, which it fights instead of a brain; nil for other enemies
+	scale     float64       // Scale of the sprite when drawn
+	body      *physics.Body // Physics body of the enemy, nil until NewBody is called
+	velocity  math.Vector   // Velocity the body starts with, restored when the chunk of the enemy loads again
 }
 
 // NewEnemy creates a new enemy with the specified parameters.
@@ -52,28 +62,34 @@ type Enemy struct {
 // mass enemy creation.
 //
 // Parameters:
-// - enemyType: The type of enemy to create (e.g., "Pilz", "Kristall")
+// - enemyType: The type of enemy to create, usually the name of an archetype (e.g., "enemy_1")
 // - x, y: The position coordinates relative to the world center
 // - rotation: The rotation angle in degrees (0-360)
-// - imagePath: The path to the enemy's sprite in the assets system
+// - imagePath: The path to the enemy's sprite in the assets system, empty for the sprite of the archetype
 //
 // The created enemy is not automatically added to the game world;
 // the caller is responsible for storing and managing the returned enemy.
-// EnemyHealthByType maps enemy types to their maximum health values
-var EnemyHealthByType = map[string]float64{
-	"Pilz":     50.0,
-	"Kristall": 75.0,
-	"Default":  30.0,
-}
-
+//
+// The stats, weapon and behavior come from the archetype of the enemy type (see
+// archetypes.json). The maximum health can be overridden per type in enemies.health_by_type
+// of the active config, whose "Default" entry is used for types without an archetype.
+// The enemy patrols around the position it is created at.
 func NewEnemy(enemyType string, x, y float64, rotation float64, imagePath string) *Enemy {
 	// Determine the maximum health based on enemy type
-	maxHealth := EnemyHealthByType["Default"]
-	if health, exists := EnemyHealthByType[enemyType]; exists {
+	healthByType := config.Get().Enemies.HealthByType
+	maxHealth := healthByType["Default"]
+	archetype, hasArchetype := GetArchetype(enemyType)
+	if hasArchetype {
+		maxHealth = archetype.Health
+		if imagePath == "" {
+			imagePath = archetype.Sprite
+		}
+	}
+	if health, exists := healthByType[enemyType]; exists {
 		maxHealth = health
 	}
 
-	return &Enemy{
+	enemy := &Enemy{
 		Type:              enemyType,
 		Position:          math.Vector{X: x, Y: y},
 		Rotation:          rotation,
@@ -87,9 +103,21 @@ func NewEnemy(enemyType string, x, y float64, rotation float64, imagePath string
 		DeathTimer:        0,
 		ExplosionFrame:    0,
 		ExplosionImage:    nil, // Will be loaded when needed
+
+		Brain: NewBrain(BehaviorFor(enemyType), math.Vector{X: x, Y: y}),
+		scale: defaultEnemyScale,
+	}
+	if hasArchetype {
+		enemy.Archetype = archetype
+		enemy.scale = archetype.Scale
 	}
+	return enemy
 }
 
+// defaultEnemyScale is the scale of the sprites of enemies without an archetype.
+// Player is at 1/3 scale, so enemies are approximately 1.5x the size of the player.
+const defaultEnemyScale = 0.5
+
 // Constants for enemy behavior
 const (
 	ExplosionFrameCount = 8    // Number of frames in the explosion animation
@@ -100,9 +128,10 @@ const (
 // Update updates the enemy's state for the current frame.
 // This method is called once per frame for each active enemy and handles:
 // 1. Lazy loading of the enemy's sprite image (only when first needed)
-// 2. Physics interactions with the environment
-// 3. Death animation if the enemy is dying
-// 4. Any state changes or animations
+// 2. Death animation if the enemy is dying
+// 3. Any state changes or animations
+//
+// Movement is done by the physics world through the body of the enemy (see NewBody).
 //
 // This method returns an error if the update fails, which can be used
 // to signal that the enemy should be removed or that the game should
@@ -133,6 +162,9 @@ func (e *Enemy) Update(deltaTime float64) bool {
 		return false // Keep the enemy until animation completes
 	}
 
+	// Fade the hit flash
+	e.sinceHit += deltaTime
+
 	// Load image if not already loaded - lazy initialization
 	// This defers image loading until actually needed and visible
 	if e.Image == nil {
@@ -140,15 +172,71 @@ func (e *Enemy) Update(deltaTime float64) bool {
 		e.Image = assets.GetImage(e.ImagePath)
 	}
 
-	// Apply gravity to the enemy's position with a high velocity value (100.0)
-	// Since this is above the LowVelocityThreshold (10.0), gravity is NOT applied
-	// This ensures enemies stay at their original spawn positions
-	// The deltaTime parameter represents a time step of one frame
-	e.Position = physics.ApplyGravity(e.Position, 100.0, deltaTime)
-
 	return false // Don't remove the enemy
 }
 
+// enemyDrag is the rate per second at which enemies come to rest after force fields pushed them
+const enemyDrag = 3.0
+
+// NewBody creates the physics body of the enemy at its current position.
+// Enemies move as their brain steers them and are not pulled by gravity; force fields
+// push them around and they slide along the walls they run or are pushed into, so the
+// enemy must be registered with the collision manager of the world. Stationary enemies,
+// like turrets mounted on walls, are not moved by force fields, as they could never return.
+func (e *Enemy) NewBody() *physics.Body {
+	e.body = physics.NewBody(e, e.Position)
+	e.body.Velocity = e.velocity
+	e.body.Drag = enemyDrag
+	e.body.CollideWithWalls = true
+	e.body.IgnoreForces = e.IsStationary()
+	e.body.Controller = e
+	return e.body
+}
+
+// Body returns the physics body of the enemy, or nil if it has none.
+func (e *Enemy) Body() *physics.Body {
+	return e.body
+}
+
+// BeforeStep keeps the body of the enemy from turning and lets its brain or boss phases steer it.
+// Thinking at the fixed rate of the physics world keeps the AI deterministic.
+// It implements physics.BodyController.
+func (e *Enemy) BeforeStep(body *physics.Body, dt float64) {
+	body.AngularVelocity = 0
+	switch {
+	case e.IsDying:
+	case e.Boss != nil:
+		e.Boss.Think(e, body, dt)
+	case e.Brain != nil:
+		e.Brain.Think(e, body, dt)
+	}
+}
+
+// SetEnvironment connects the brain or the boss phases of the enemy to the game world
+func (e *Enemy) SetEnvironment(env Environment) {
+	if e.Boss != nil {
+		e.Boss.SetEnvironment(env)
+	}
+	if e.Brain != nil {
+		e.Brain.SetEnvironment(env)
+	}
+}
+
+// IsStationary reports whether the enemy never moves on its own, like a turret mounted on a wall
+func (e *Enemy) IsStationary() bool {
+	return e.Boss == nil && (e.Brain == nil || e.Brain.Behavior.Speed <= 0)
+}
+
+// IsBurrowed reports whether the enemy is hidden in the ground, where it cannot be hit
+func (e *Enemy) IsBurrowed() bool {
+	return e.Brain != nil && e.Brain.IsBurrowed()
+}
+
+// AfterStep moves the enemy to its body. It implements physics.BodyController.
+func (e *Enemy) AfterStep(body *physics.Body, hits []physics.SweepHit) {
+	e.Position = body.Position
+}
+
 // TakeDamage reduces the enemy's health by the specified amount.
 // If health reaches zero or below, the enemy starts its death animation.
 //
@@ -165,6 +253,8 @@ func (e *Enemy) TakeDamage(amount float64) bool {
 
 	// Reduce health by the damage amount
 	e.Health -= amount
+	e.hit = true
+	e.sinceHit = 0
 
 	// Check if the enemy has died
 	if e.Health <= 0 {
@@ -184,8 +274,8 @@ func (e *Enemy) TakeDamage(amount float64) bool {
 // Returns:
 // - physics.CircleCollider: The enemy's collision area
 func (e *Enemy) GetCollider() physics.CircleCollider {
-	// Skip collision if the enemy is dying
-	if e.IsDying {
+	// Skip collision if the enemy is dying or burrowed
+	if e.IsDying || e.IsBurrowed() {
 		return physics.CircleCollider{Position: e.Position, Radius: 0}
 	}
 
@@ -194,6 +284,11 @@ func (e *Enemy) GetCollider() physics.CircleCollider {
 		e.Image = assets.GetImage(e.ImagePath)
 	}
 
+	// Archetypes may set the radius instead of deriving it from the sprite
+	if e.Archetype != nil && e.Archetype.ColliderRadius > 0 {
+		return physics.CircleCollider{Position: e.Position, Radius: e.Archetype.ColliderRadius}
+	}
+
 	// If the image is still nil (could happen if the asset doesn't exist),
 	// return a default collider with a reasonable radius
 	if e.Image == nil {
@@ -203,7 +298,7 @@ func (e *Enemy) GetCollider() physics.CircleCollider {
 		}
 	}
 
-	return physics.GetEntityCollider(e.Position, e.Image, 0.5)
+	return physics.GetEntityCollider(e.Position, e.Image, e.scale)
 }
 
 // Draw renders the enemy on the screen with proper transformations.
@@ -286,6 +381,11 @@ func (e *Enemy) Draw(screen *ebiten.Image, offsetX, offsetY float64, worldWidth,
 		return
 	}
 
+	// Burrowed enemies are hidden in the ground
+	if e.IsBurrowed() {
+		return
+	}
+
 	// Create transformation options for rendering
 	op := &ebiten.DrawImageOptions{}
 
@@ -297,10 +397,8 @@ func (e *Enemy) Draw(screen *ebiten.Image, offsetX, offsetY float64, worldWidth,
 	// Apply rotation, converting from degrees to radians
 	op.GeoM.Rotate(e.Rotation * (stdmath.Pi / 180.0))
 
-	// Apply scaling to make enemies slightly larger than the player
-	// Player is at 1/3 scale, so we use 0.5 scale for enemies
-	// This makes enemies approximately 1.5x the size of the player
-	op.GeoM.Scale(0.5, 0.5)
+	// Apply the scale of the enemy type
+	op.GeoM.Scale(e.scale, e.scale)
 
 	// Calculate the screen center using the provided world dimensions
 	// This is the reference point for all world-space coordinates
@@ -314,11 +412,20 @@ func (e *Enemy) Draw(screen *ebiten.Image, offsetX, offsetY float64, worldWidth,
 	screenX := centerX + e.Position.X + offsetX
 	screenY := centerY + e.Position.Y + offsetY
 
-	// Move to the calculated position, adjusting for the scaling factor
-	// Since we're scaling by 0.5, we need to multiply the screen position by 2.0
-	// to compensate for the scaling effect on the translation
-	op.GeoM.Translate(screenX*2.0, screenY*2.0)
+	// Move to the calculated position. The translation comes after the scale,
+	// so it is not scaled and the sprite is centered on the enemy.
+	op.GeoM.Translate(screenX, screenY)
+
+	// Draw the enemy sprite with all transformations applied, flashing white after a hit
+	feedback := config.Get().Enemies.Feedback
+	if amount := e.flashAmount(feedback); amount > 0 {
+		drawFlash(screen, e.Image, op, amount)
+	} else {
+		screen.DrawImage(e.Image, op)
+	}
 
-	// Draw the enemy sprite with all transformations applied
-	screen.DrawImage(e.Image, op)
+	// Show how close a damaged enemy is to dying
+	if feedback.HealthBars && e.hit {
+		e.drawHealthBar(screen, screenX, screenY)
+	}
 }
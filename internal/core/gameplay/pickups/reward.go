@@ -0,0 +1,89 @@
+// Package pickups implements items that the player collects by touching them.
+package pickups
+
+import (
+	"discoveryx/internal/core/physics"
+	"discoveryx/internal/utils/math"
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+	stdmath "math"
+)
+
+// Reward behavior constants
+const (
+	RewardRadius    = 16.0 // Radius of the reward collider
+	rewardPulseRate = 3.0  // Pulses of the reward glow per second
+)
+
+// rewardColor is the color of the reward glow
+var rewardColor = color.RGBA{R: 120, G: 255, B: 170, A: 255}
+
+// rewardImage is a white square that is scaled, rotated and tinted for the reward
+var rewardImage *ebiten.Image
+
+// Reward is dropped by a defeated boss. The player collects it by touching it, which
+// restores health and adds to the score. It lies where it was dropped until collected.
+type Reward struct {
+	Position  math.Vector // Position in world coordinates relative to center
+	Health    float64     // Health restored to the player
+	Score     int         // Score added when collected
+	time      float64     // Seconds since the reward was dropped, for the pulse
+	collected bool
+}
+
+// NewReward creates a reward at a position
+func NewReward(position math.Vector, health float64, score int) *Reward {
+	return &Reward{Position: position, Health: health, Score: score}
+}
+
+// Update advances the pulse of the reward
+func (r *Reward) Update(deltaTime float64) {
+	r.time += deltaTime
+}
+
+// Collect marks the reward as collected. It returns false if it already was,
+// so a reward the player keeps touching is only handed out once.
+func (r *Reward) Collect() bool {
+	if r.collected {
+		return false
+	}
+	r.collected = true
+	return true
+}
+
+// IsCollected reports whether the player collected the reward
+func (r *Reward) IsCollected() bool {
+	return r.collected
+}
+
+// GetCollider returns the circular collider the player touches to collect the reward
+func (r *Reward) GetCollider() physics.CircleCollider {
+	return physics.CircleCollider{Position: r.Position, Radius: RewardRadius}
+}
+
+// Draw renders the reward as a turning, pulsing diamond.
+//
+// Parameters:
+// - screen: The target image where the reward should be drawn
+// - offsetX, offsetY: Camera offset values for scrolling
+// - worldWidth, worldHeight: Current dimensions of the game world
+func (r *Reward) Draw(screen *ebiten.Image, offsetX, offsetY float64, worldWidth, worldHeight int) {
+	if rewardImage == nil {
+		rewardImage = ebiten.NewImage(1, 1)
+		rewardImage.Fill(color.White)
+	}
+
+	pulse := 0.5 + 0.5*stdmath.Sin(2*stdmath.Pi*rewardPulseRate*r.time)
+	size := RewardRadius * (1.2 + 0.2*pulse)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(-0.5, -0.5)
+	op.GeoM.Scale(size, size)
+	op.GeoM.Rotate(stdmath.Pi/4 + r.time)
+	op.GeoM.Translate(float64(worldWidth)/2+r.Position.X+offsetX, float64(worldHeight)/2+r.Position.Y+offsetY)
+
+	op.ColorScale.ScaleWithColor(rewardColor)
+	op.ColorScale.ScaleAlpha(float32(0.6 + 0.4*pulse))
+
+	screen.DrawImage(rewardImage, op)
+}
@@ -118,6 +118,30 @@ func (p *Player) GetCollider() physics.CircleCollider {
 	return collider
 }
 
+// GetHullCollider returns a polygon collider that follows the outline of the ship.
+// The hull is a triangle with the nose in the flight direction, and rotates with the player.
+//
+// Returns:
+// - physics.PolygonCollider: The player's hull
+func (p *Player) GetHullCollider() physics.PolygonCollider {
+	// Sprite size after the same 1/3 scaling that's used in Draw
+	halfWidth, halfHeight := 15.0/3.0, 15.0/3.0
+	if p.sprite != nil {
+		halfWidth = float64(p.sprite.Bounds().Dx()) / 6
+		halfHeight = float64(p.sprite.Bounds().Dy()) / 6
+	}
+
+	return physics.PolygonCollider{
+		Position: p.position,
+		Vertices: []math.Vector{
+			{X: 0, Y: -halfHeight},         // Nose
+			{X: halfWidth, Y: halfHeight},  // Right wing
+			{X: -halfWidth, Y: halfHeight}, // Left wing
+		},
+		Rotation: p.rotation,
+	}
+}
+
 // GetAABBCollider returns an AABB collider for the player.
 // This is used for precise collision detection with walls.
 //
@@ -136,8 +160,8 @@ func (p *Player) GetAABBCollider() physics.AABBCollider {
 }
 
 // CheckWallCollision checks if the player is colliding with a wall and handles the collision.
-// DEPRECATED: This method uses circle-based collision detection which has been replaced by AABB-based
-// collision detection in GameScene.CheckWallCollisionUsingWorldData. This method is kept for
+// DEPRECATED: This method uses circle-based collision detection which has been replaced by the swept
+// hull movement in CollisionManager.MoveEntity. This method is kept for
 // backward compatibility with tests but should not be used in the main game code.
 //
 // Parameters:
@@ -147,9 +171,9 @@ func (p *Player) GetAABBCollider() physics.AABBCollider {
 // - bool: True if a collision occurred, false otherwise
 func (p *Player) CheckWallCollision(walls []physics.RectCollider) bool {
 	// This method is deprecated and should not be used in the main game code.
-	// Use GameScene.CheckWallCollisionUsingWorldData instead, which uses AABB-based collision detection.
+	// Use CollisionManager.MoveEntity instead, which sweeps the hull against the wall outlines.
 	if constants.DebugPlayerWallCollision {
-		fmt.Printf("WARNING: Deprecated method Player.CheckWallCollision called. Use GameScene.CheckWallCollisionUsingWorldData instead.\n")
+		fmt.Printf("WARNING: Deprecated method Player.CheckWallCollision called. Use CollisionManager.MoveEntity instead.\n")
 	}
 
 	playerCollider := p.GetCollider()
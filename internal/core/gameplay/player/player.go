@@ -11,11 +11,13 @@ package player
 
 import (
 	"discoveryx/internal/assets"
+	"discoveryx/internal/config"
 	"discoveryx/internal/constants"
 	"discoveryx/internal/core/ecs"
 	"discoveryx/internal/core/physics"
 	"discoveryx/internal/input"
 	"discoveryx/internal/utils/math"
+	"fmt"
 	"github.com/hajimehoshi/ebiten/v2"
 	stdmath "math"
 	"time"
@@ -52,6 +54,17 @@ type Player struct {
 	isInvincible       bool    // Whether the player is currently invincible after taking damage
 	invincibilityTimer float64 // Timer for tracking invincibility duration
 	shouldRender       bool    // Whether the player should be rendered (for invincibility flashing)
+
+	// body moves the player at the fixed rate of the physics world, nil until NewBody is called
+	body *physics.Body
+
+	// drift is the velocity in units per second that does not come from steering, like the
+	// bounce off a wall or the push of a current. It does not follow the rotation of the
+	// ship and fades over time.
+	drift math.Vector
+
+	// steering is the velocity in units per second that steering gave the body in the last step
+	steering math.Vector
 }
 
 // NewPlayer creates a new player instance with default settings.
@@ -129,6 +142,9 @@ func (p *Player) SetVelocity(velocity float64) {
 func (p *Player) SetRotation(rotation float64) {
 	p.rotation = rotation
 	p.targetRotation = rotation
+	if p.body != nil {
+		p.body.Rotation = rotation
+	}
 }
 
 // GetRotation returns the player's current rotation in radians.
@@ -148,6 +164,10 @@ func (p *Player) GetRotation() float64 {
 // - Cutscene positioning
 func (p *Player) SetPosition(position math.Vector) {
 	p.position = position
+	p.drift = math.Vector{}
+	if p.body != nil {
+		p.body.SetPosition(position)
+	}
 }
 
 // Draw renders the player sprite to the screen with proper transformation.
@@ -172,17 +192,23 @@ func (p *Player) Draw(screen *ebiten.Image, cameraOffsetX, cameraOffsetY float64
 	const scale = 1.0 / 3.0
 	op.GeoM.Scale(scale, scale)
 
+	// With a body, draw between the last two physics steps for smooth movement
+	position, rotation := p.position, p.rotation
+	if p.body != nil {
+		position, rotation = p.body.RenderPosition(), p.body.RenderRotation()
+	}
+
 	// Apply transformations in the correct order:
 	// 1. Center the sprite on its origin point
 	op.GeoM.Translate(-halfW*scale, -halfH*scale)
 	// 2. Rotate around the origin
-	op.GeoM.Rotate(p.rotation)
+	op.GeoM.Rotate(rotation)
 	// 3. Position at the world center
 	centerX := float64(p.world.GetWidth()) / 2
 	centerY := float64(p.world.GetHeight()) / 2
 	op.GeoM.Translate(centerX, centerY)
 	// 4. Apply the player's position offset from center
-	op.GeoM.Translate(p.position.X, p.position.Y)
+	op.GeoM.Translate(position.X, position.Y)
 	// 5. Apply camera offset for scrolling
 	op.GeoM.Translate(cameraOffsetX, cameraOffsetY)
 
@@ -268,7 +294,7 @@ func (p *Player) HandleTouchInput(touch input.TouchHandler) {
 	}
 
 	// Cap velocity at the maximum allowed acceleration
-	newVel = stdmath.Min(newVel, constants.MaxAcceleration)
+	newVel = stdmath.Min(newVel, config.Get().Player.MaxAcceleration)
 
 	// Maintain momentum: if already moving fast, don't slow down too abruptly
 	// This prevents jerky movement when adjusting direction
@@ -293,7 +319,9 @@ func (p *Player) HandleTouchInput(touch input.TouchHandler) {
 // - Small velocity when rotating in place for better feedback
 //
 // This method is called by the Update method every frame to process keyboard input.
-func (p *Player) HandleKeyboardInput(keyboard input.KeyboardHandler) {
+// The deltaTime parameter is the time since the last frame in seconds, so the
+// player turns at the same speed regardless of the frame rate.
+func (p *Player) HandleKeyboardInput(keyboard input.KeyboardHandler, deltaTime float64) {
 	// Check which arrow keys are currently pressed
 	leftPressed := keyboard.IsKeyPressed(input.KeyLeft)
 	rightPressed := keyboard.IsKeyPressed(input.KeyRight)
@@ -309,15 +337,17 @@ func (p *Player) HandleKeyboardInput(keyboard input.KeyboardHandler) {
 	// Mark the player as actively moving
 	p.isMoving = true
 
+	settings := config.Get().Player
+
 	// Process rotation from left/right keys
 	// Left key rotates counterclockwise (positive in radians)
 	if leftPressed {
-		p.targetRotation += constants.RotationPerSecond / 60.0
+		p.targetRotation += settings.RotationPerSecond * deltaTime
 	}
 
 	// Right key rotates clockwise (negative in radians)
 	if rightPressed {
-		p.targetRotation -= constants.RotationPerSecond / 60.0
+		p.targetRotation -= settings.RotationPerSecond * deltaTime
 	}
 
 	// Keep rotation in the valid range [0, 2π)
@@ -331,7 +361,7 @@ func (p *Player) HandleKeyboardInput(keyboard input.KeyboardHandler) {
 	// Process acceleration from up key
 	if upPressed {
 		// Move at maximum speed when pressing up
-		p.targetVelocity = constants.MaxAcceleration
+		p.targetVelocity = settings.MaxAcceleration
 	} else if leftPressed || rightPressed {
 		// Apply a small velocity when only rotating
 		// This helps provide visual feedback that the controls are working
@@ -351,6 +381,10 @@ func (p *Player) HandleKeyboardInput(keyboard input.KeyboardHandler) {
 // 4. Applying physics effects like friction and environmental forces
 // 5. Ensuring values stay within valid ranges
 //
+// Once the player has a physics body (see NewBody), steps 2-5 are done by the
+// physics world at a fixed rate through BeforeStep and AfterStep, and Update
+// only processes input and the health system.
+//
 // The deltaTime parameter ensures frame-rate independent movement,
 // making the game behave consistently regardless of the device's performance.
 func (p *Player) Update(inputManager *input.Manager, deltaTime float64) error {
@@ -359,13 +393,33 @@ func (p *Player) Update(inputManager *input.Manager, deltaTime float64) error {
 	touch := inputManager.Touch()
 
 	// Process keyboard input first (base controls)
-	p.HandleKeyboardInput(keyboard)
+	p.HandleKeyboardInput(keyboard, deltaTime)
 
 	// If touch is active, it overrides keyboard input
 	if touch != nil && touch.IsHolding() {
 		p.HandleTouchInput(touch)
 	}
 
+	// Without a body, the player moves itself once per frame
+	if p.body == nil && deltaTime > 0 {
+		rotation, velocity := p.steer(deltaTime)
+		p.rotation = rotation
+		p.position.X += velocity.X * deltaTime
+		p.position.Y += velocity.Y * deltaTime
+	}
+
+	// Update health-related state (invincibility frames, etc.)
+	p.UpdateHealthSystem(deltaTime)
+
+	return nil
+}
+
+// steer moves the rotation and speed toward their targets over dt seconds.
+// It returns the new rotation and the velocity in units per second that
+// moves the player along it, including the pull of gravity.
+func (p *Player) steer(dt float64) (float64, math.Vector) {
+	settings := config.Get().Player
+
 	// ---- ROTATION HANDLING ----
 
 	// Calculate rotation difference and normalize to shortest path
@@ -386,14 +440,14 @@ func (p *Player) Update(inputManager *input.Manager, deltaTime float64) error {
 
 	// Calculate rotation smoothing factor based on speed
 	// Faster movement = slower rotation (more realistic turning)
-	speedRatio := p.playerVelocity / constants.MaxAcceleration
-	adjustedSpeedRatio := stdmath.Pow(speedRatio, constants.CurvePower)
+	speedRatio := p.playerVelocity / settings.MaxAcceleration
+	adjustedSpeedRatio := stdmath.Pow(speedRatio, settings.CurvePower)
 
 	// Interpolate between min and max smoothing based on speed
-	factor := constants.RotationSmoothingMax - (constants.RotationSmoothingMax-constants.RotationSmoothingMin)*adjustedSpeedRatio
+	factor := settings.RotationSmoothingMax - (settings.RotationSmoothingMax-settings.RotationSmoothingMin)*adjustedSpeedRatio
 
 	// Clamp the factor to valid range
-	factor = stdmath.Max(constants.RotationSmoothingMin, stdmath.Min(constants.RotationSmoothingMax, factor))
+	factor = stdmath.Max(settings.RotationSmoothingMin, stdmath.Min(settings.RotationSmoothingMax, factor))
 
 	// Ensure small rotations are still noticeable
 	// This prevents very small adjustments from being ignored
@@ -404,13 +458,8 @@ func (p *Player) Update(inputManager *input.Manager, deltaTime float64) error {
 
 	// Apply rotation with smoothing
 	// The factor determines how quickly we rotate toward the target
-	// Multiply by deltaTime*60 to make it frame-rate independent
-	p.rotation += rotationDiff * factor * deltaTime * 60.0
-
-	// Keep rotation in the valid range [0, 2π)
-	for p.rotation >= 2*stdmath.Pi {
-		p.rotation -= 2 * stdmath.Pi
-	}
+	// Multiply by dt*60 to make it frame-rate independent
+	rotation := normalizeRotation(p.rotation + rotationDiff*factor*dt*60.0)
 
 	// ---- VELOCITY HANDLING ----
 
@@ -421,36 +470,35 @@ func (p *Player) Update(inputManager *input.Manager, deltaTime float64) error {
 	if p.isMoving && stdmath.Abs(rotationDiff) > stdmath.Pi/2 {
 		// Apply stronger smoothing during sharp turns (>90 degrees)
 		// This simulates slowing down to turn, then speeding up again
-		p.playerVelocity += velocityDiff * (constants.VelocitySmoothingFactor * 1.5) * deltaTime * 60.0
+		p.playerVelocity += velocityDiff * (settings.VelocitySmoothingFactor * 1.5) * dt * 60.0
 	} else {
 		// Normal velocity smoothing for straight movement or gentle turns
-		p.playerVelocity += velocityDiff * constants.VelocitySmoothingFactor * deltaTime * 60.0
+		p.playerVelocity += velocityDiff * settings.VelocitySmoothingFactor * dt * 60.0
 	}
 
 	// Clamp velocity to valid range
-	if p.playerVelocity > constants.MaxAcceleration {
-		p.playerVelocity = constants.MaxAcceleration
+	if p.playerVelocity > settings.MaxAcceleration {
+		p.playerVelocity = settings.MaxAcceleration
 	} else if p.playerVelocity < 0 {
 		p.playerVelocity = 0
 	}
 
-	// ---- POSITION UPDATING ----
+	// ---- MOVEMENT ----
+
+	var velocity math.Vector
 
 	// Apply movement if velocity is above minimum threshold
 	if p.playerVelocity > 0.02 {
-		// Calculate movement vector based on rotation and velocity
+		// Calculate movement direction based on rotation
 		// sin(rotation) gives X component, cos(rotation) gives Y component
 		// Note: Y is negated because in screen coordinates, Y increases downward
-		dx := stdmath.Sin(p.rotation) * p.playerVelocity * deltaTime * 60.0
-		dy := stdmath.Cos(p.rotation) * -p.playerVelocity * deltaTime * 60.0
-
-		// Update position - this will be checked for collisions in the game scene
-		p.position.X += dx
-		p.position.Y += dy
+		// The speed is in units per frame, so scale it by 60 to get units per second
+		velocity.X = stdmath.Sin(rotation) * p.playerVelocity * 60.0
+		velocity.Y = stdmath.Cos(rotation) * -p.playerVelocity * 60.0
 	} else if !p.isMoving {
 		// Apply friction when not actively moving
 		// This creates a natural deceleration effect
-		frictionFactor := stdmath.Pow(0.95, deltaTime*60.0) // 5% reduction per frame at 60fps
+		frictionFactor := stdmath.Pow(0.95, dt*60.0) // 5% reduction per frame at 60fps
 		p.playerVelocity *= frictionFactor
 
 		// Stop completely if velocity becomes negligible
@@ -461,10 +509,100 @@ func (p *Player) Update(inputManager *input.Manager, deltaTime float64) error {
 
 	// Apply environmental physics effects
 	// This handles interactions with the game world like gravity wells
-	p.position = physics.ApplyGravity(p.position, p.playerVelocity, deltaTime)
+	gravity := physics.GravityVelocity(p.playerVelocity)
+	velocity.X += gravity.X
+	velocity.Y += gravity.Y
 
-	// Update health-related state (invincibility frames, etc.)
-	p.UpdateHealthSystem(deltaTime)
+	return rotation, velocity
+}
 
-	return nil
+// NewBody creates the physics body of the player at its current position and rotation.
+// The body bounces off and slides along walls with the player_contact profile of the config;
+// the player must be registered with the collision manager of the world for that. From then
+// on the player steers the body in BeforeStep and follows it in AfterStep.
+func (p *Player) NewBody() *physics.Body {
+	p.body = physics.NewBody(p, p.position)
+	p.body.Rotation = p.rotation
+	p.body.CollideWithWalls = true
+	p.body.Contact = physics.NewContactProfile(config.Get().Physics.PlayerContact)
+	p.body.Controller = p
+	return p.body
+}
+
+// Body returns the physics body of the player, or nil if it has none.
+func (p *Player) Body() *physics.Body {
+	return p.body
+}
+
+// BeforeStep turns the player toward its target rotation and sets the velocity of
+// its body from the current speed and the fading drift. It implements physics.BodyController.
+func (p *Player) BeforeStep(body *physics.Body, dt float64) {
+	rotation, velocity := p.steer(dt)
+
+	// Turn the body through the shortest angle, so the rotation can be interpolated
+	rotationDiff := rotation - p.rotation
+	for rotationDiff > stdmath.Pi {
+		rotationDiff -= 2 * stdmath.Pi
+	}
+	for rotationDiff < -stdmath.Pi {
+		rotationDiff += 2 * stdmath.Pi
+	}
+	body.AngularVelocity = rotationDiff / dt
+
+	fade := stdmath.Exp(-constants.DriftDecay * dt)
+	p.drift.X *= fade
+	p.drift.Y *= fade
+	p.steering = velocity
+	body.Velocity = math.Vector{X: velocity.X + p.drift.X, Y: velocity.Y + p.drift.Y}
+}
+
+// AfterStep moves the player to its body. Whatever the step added to the velocity of the
+// body, like the push of force fields, is kept as drift. When the body hit a wall, the
+// physics world already bounced its velocity off the wall. The part of that velocity along
+// the heading of the ship becomes its speed, so the player does not keep pushing into the
+// wall, and the rest becomes drift. Hard impacts damage the player.
+// It implements physics.BodyController.
+func (p *Player) AfterStep(body *physics.Body, hits []physics.SweepHit) {
+	p.position = body.Position
+	p.rotation = normalizeRotation(body.Rotation)
+
+	if len(hits) > 0 {
+		previousVelocity := p.playerVelocity
+
+		// Keep the speed along the heading, but never speed up from a hit
+		headingX, headingY := stdmath.Sin(p.rotation), -stdmath.Cos(p.rotation)
+		forward := stdmath.Max(0, body.Velocity.X*headingX+body.Velocity.Y*headingY)
+		if forward/60.0 < p.playerVelocity {
+			p.playerVelocity = forward / 60.0
+		}
+		p.steering = math.Vector{X: headingX * p.playerVelocity * 60.0, Y: headingY * p.playerVelocity * 60.0}
+
+		// The hardest impact of the step deals the damage
+		damage := 0.0
+		for _, hit := range hits {
+			damage = stdmath.Max(damage, body.Contact.Damage(hit.ImpactSpeed))
+		}
+		if damage > 0 {
+			p.TakeDamage(damage)
+		}
+
+		// Debug output for collision velocity adjustment
+		if constants.DebugPlayerWallCollision {
+			fmt.Printf("Wall collision detected: %d hits, first normal (%.2f, %.2f) at time %.2f, impact speed %.2f, damage %.2f, velocity reduced from %.2f to %.2f\n",
+				len(hits), hits[0].Normal.X, hits[0].Normal.Y, hits[0].Time, hits[0].ImpactSpeed, damage, previousVelocity, p.playerVelocity)
+		}
+	}
+
+	p.drift = math.Vector{X: body.Velocity.X - p.steering.X, Y: body.Velocity.Y - p.steering.Y}
+}
+
+// normalizeRotation keeps a rotation in the valid range [0, 2π).
+func normalizeRotation(rotation float64) float64 {
+	for rotation >= 2*stdmath.Pi {
+		rotation -= 2 * stdmath.Pi
+	}
+	for rotation < 0 {
+		rotation += 2 * stdmath.Pi
+	}
+	return rotation
 }
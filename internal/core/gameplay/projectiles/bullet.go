@@ -10,24 +10,25 @@ package projectiles
 
 import (
 	"discoveryx/internal/assets"
+	"discoveryx/internal/config"
 	"discoveryx/internal/core/physics"
 	"discoveryx/internal/utils/math"
 	"github.com/hajimehoshi/ebiten/v2"
 	stdmath "math"
 )
 
-// Bullet behavior constants control the movement, lifetime, and damage of bullets.
-// These values are carefully tuned to create a satisfying projectile feel
-// with bullets that start relatively slow but quickly accelerate.
-const (
-	bulletInitialSpeed = 3.0  // Starting speed of bullets in units per frame
-	bulletAcceleration = 1.05 // Multiplicative acceleration factor per frame (5% increase)
-	bulletMaxLifetime  = 2.0  // Maximum lifetime in seconds before automatic despawn
-
-	// Damage values for different bullet types
-	playerBulletDamage = 25.0 // Damage dealt by player bullets
-	enemyBulletDamage  = 15.0 // Damage dealt by enemy bullets
-)
+// Bullet behavior (initial speed, acceleration, lifetime and damage) is read from
+// the weapons section of the active config. The defaults are tuned so bullets
+// start relatively slow but quickly accelerate.
+
+// bulletDamage returns the damage dealt by a player or enemy bullet
+func bulletDamage(isPlayerBullet bool) float64 {
+	weapons := config.Get().Weapons
+	if isPlayerBullet {
+		return weapons.PlayerBulletDamage
+	}
+	return weapons.EnemyBulletDamage
+}
 
 // Bullet represents a projectile fired by the player.
 // It accelerates exponentially until its lifetime expires, creating a
@@ -41,17 +42,20 @@ type Bullet struct {
 	Position   math.Vector   // Current position in world coordinates relative to center
 	Rotation   float64       // Current rotation in radians (0 = up, increases clockwise)
 	speed      float64       // Current speed in units per frame (increases over time)
+	lastPosition math.Vector // Position at the start of the last update
 	lifetime   float64       // Current lifetime in seconds (increases until max)
 	Image      *ebiten.Image // Sprite used to render the bullet
 	accelerate bool          // Whether the bullet accelerates each frame
 	Damage     float64       // Amount of damage this bullet deals on hit
 	IsPlayerBullet bool      // Whether this bullet was fired by the player (true) or an enemy (false)
+	body       *physics.Body // Moves the bullet at the fixed rate of the physics world, nil if the bullet moves itself
+	Bounces    int           // Bounces off walls left before a wall destroys the bullet, 0 for rounds that do not ricochet
 }
 
 // NewBullet creates a new bullet at the given position and rotation.
 // This factory function initializes a Bullet instance with the provided
-// position and rotation, setting its initial speed from the bulletInitialSpeed
-// constant and starting its lifetime at zero.
+// position and rotation, setting its initial speed from the configured
+// bullet_initial_speed and starting its lifetime at zero.
 //
 // Parameters:
 //   - pos: The starting position vector for the bullet (typically the player's position
@@ -61,43 +65,44 @@ type Bullet struct {
 // The created bullet is not automatically added to the game world;
 // the caller is responsible for storing and managing the returned bullet.
 func NewBullet(pos math.Vector, rotation float64, img *ebiten.Image, isPlayerBullet bool) *Bullet {
-	// Determine damage based on whether this is a player or enemy bullet
-	damage := enemyBulletDamage
-	if isPlayerBullet {
-		damage = playerBulletDamage
-	}
-
 	return &Bullet{
 		Position:       pos,
+		lastPosition:   pos,
 		Rotation:       rotation,
-		speed:          bulletInitialSpeed, // Start with the base speed
+		speed:          config.Get().Weapons.BulletInitialSpeed, // Start with the base speed
 		lifetime:       0,                  // Initialize lifetime to zero
 		Image:          img,
 		accelerate:     true,
-		Damage:         damage,
+		Damage:         bulletDamage(isPlayerBullet),
 		IsPlayerBullet: isPlayerBullet,
+		Bounces:        playerBounces(isPlayerBullet),
 	}
 }
 
+// playerBounces returns the ricochet bounces of player bullets from weapons.ricochet_bounces.
+// Enemy bullets only ricochet if the archetype of the enemy sets it on the bullet.
+func playerBounces(isPlayerBullet bool) int {
+	if isPlayerBullet {
+		return config.Get().Weapons.RicochetBounces
+	}
+	return 0
+}
+
 // NewLinearBullet creates a bullet that moves with a constant speed.
 // The bullet does not accelerate over time, providing a simpler
 // movement pattern typically used by enemy projectiles.
 func NewLinearBullet(pos math.Vector, rotation float64, img *ebiten.Image, isPlayerBullet bool) *Bullet {
-	// Determine damage based on whether this is a player or enemy bullet
-	damage := enemyBulletDamage
-	if isPlayerBullet {
-		damage = playerBulletDamage
-	}
-
 	return &Bullet{
 		Position:       pos,
+		lastPosition:   pos,
 		Rotation:       rotation,
-		speed:          bulletInitialSpeed,
+		speed:          config.Get().Weapons.BulletInitialSpeed,
 		lifetime:       0,
 		Image:          img,
 		accelerate:     false,
-		Damage:         damage,
+		Damage:         bulletDamage(isPlayerBullet),
 		IsPlayerBullet: isPlayerBullet,
+		Bounces:        playerBounces(isPlayerBullet),
 	}
 }
 
@@ -111,6 +116,9 @@ func NewLinearBullet(pos math.Vector, rotation float64, img *ebiten.Image, isPla
 // bullets start relatively slow but quickly gain speed, creating a sense
 // of power and momentum.
 //
+// Once the bullet has a physics body (see NewBody), steps 1 and 2 are done by
+// the physics world at a fixed rate through BeforeStep and AfterStep.
+//
 // Parameters:
 // - deltaTime: The time elapsed since the last frame in seconds
 //
@@ -118,26 +126,121 @@ func NewLinearBullet(pos math.Vector, rotation float64, img *ebiten.Image, isPla
 // - true if the bullet's lifetime has expired and it should be removed
 // - false if the bullet is still active and should continue to exist
 func (b *Bullet) Update(deltaTime float64) bool {
-	// Apply exponential acceleration only if enabled
-	// The bulletAcceleration value is raised to the power of deltaTime*60.0
+	// The path of this update starts here
+	b.lastPosition = b.Position
+
+	// Without a body, the bullet moves itself once per frame
+	if b.body == nil {
+		b.accelerateBy(deltaTime)
+
+		velocity := b.velocity()
+		b.Position.X += velocity.X * deltaTime
+		b.Position.Y += velocity.Y * deltaTime
+	}
+
+	// Increment lifetime and check if it has expired
+	b.lifetime += deltaTime
+	return b.lifetime >= config.Get().Weapons.BulletMaxLifetime
+}
+
+// accelerateBy applies the exponential acceleration of dt seconds, if enabled.
+func (b *Bullet) accelerateBy(dt float64) {
+	// The configured acceleration factor is raised to the power of dt*60.0
 	// to ensure consistent acceleration regardless of frame rate
 	if b.accelerate {
-		b.speed *= stdmath.Pow(bulletAcceleration, deltaTime*60.0)
+		b.speed *= stdmath.Pow(config.Get().Weapons.BulletAcceleration, dt*60.0)
 	}
+}
 
-	// Calculate movement vector based on rotation and speed
+// velocity returns the velocity of the bullet in units per second.
+func (b *Bullet) velocity() math.Vector {
 	// sin(rotation) gives X component, cos(rotation) gives Y component
 	// Note: Y is negated because in screen coordinates, Y increases downward
-	dx := stdmath.Sin(b.Rotation) * b.speed * deltaTime * 60.0
-	dy := stdmath.Cos(b.Rotation) * -b.speed * deltaTime * 60.0
+	// The speed is in units per frame, so scale it by 60 to get units per second
+	return math.Vector{
+		X: stdmath.Sin(b.Rotation) * b.speed * 60.0,
+		Y: stdmath.Cos(b.Rotation) * -b.speed * 60.0,
+	}
+}
 
-	// Update position by adding the movement vector
-	b.Position.X += dx
-	b.Position.Y += dy
+// NewBody creates the physics body of the bullet at its current position.
+// From then on the bullet is moved by the physics world instead of by Update.
+// Walls are not checked by the body, since bullets are removed when they hit one.
+func (b *Bullet) NewBody() *physics.Body {
+	b.body = physics.NewBody(b, b.Position)
+	b.body.Rotation = b.Rotation
+	b.body.Controller = b
+	return b.body
+}
 
-	// Increment lifetime and check if it has expired
-	b.lifetime += deltaTime
-	return b.lifetime >= bulletMaxLifetime
+// Body returns the physics body of the bullet, or nil if it has none.
+func (b *Bullet) Body() *physics.Body {
+	return b.body
+}
+
+// BeforeStep accelerates the bullet and sets the velocity of its body.
+// It implements physics.BodyController.
+func (b *Bullet) BeforeStep(body *physics.Body, dt float64) {
+	b.accelerateBy(dt)
+	body.Velocity = b.velocity()
+}
+
+// AfterStep moves the bullet to its body. Force fields may have bent the path of the
+// body, so the bullet turns into the direction it now flies and takes on its speed.
+// It implements physics.BodyController.
+func (b *Bullet) AfterStep(body *physics.Body, hits []physics.SweepHit) {
+	b.Position = body.Position
+
+	if speed := stdmath.Hypot(body.Velocity.X, body.Velocity.Y); speed > 0 {
+		b.Rotation = stdmath.Atan2(body.Velocity.X, -body.Velocity.Y)
+		b.speed = speed / 60.0
+	}
+}
+
+// Ricochet bounces the bullet off a wall it hit, with the bullet_contact profile of the config.
+// The bullet goes on from the point of the hit in its new direction, and its path of this
+// update starts there. Bullets only ricochet as often as their Bounces allow.
+//
+// Returns:
+// - true if the bullet bounced off the wall
+// - false if the wall stopped the bullet and it should be removed
+func (b *Bullet) Ricochet(hit physics.SweepHit) bool {
+	if b.Bounces <= 0 {
+		return false
+	}
+
+	profile := physics.NewContactProfile(config.Get().Physics.BulletContact)
+	response := profile.Respond(b.velocity(), hit.Normal)
+	speed := stdmath.Hypot(response.Velocity.X, response.Velocity.Y)
+	if speed == 0 {
+		return false
+	}
+	b.Bounces--
+
+	// Turn the bullet into its new direction; rotation 0 points up (-Y)
+	b.Rotation = stdmath.Atan2(response.Velocity.X, -response.Velocity.Y)
+	b.speed = speed / 60.0
+	b.lastPosition = hit.Position
+	b.SetPosition(hit.Position)
+	if b.body != nil {
+		b.body.Rotation = b.Rotation
+		b.body.Velocity = response.Velocity
+	}
+	return true
+}
+
+// GetLastPosition returns the position of the bullet at the start of the last update.
+// The bullet moved in a straight line from there to its current position.
+func (b *Bullet) GetLastPosition() math.Vector {
+	return b.lastPosition
+}
+
+// SetPosition moves the bullet, for example to the point where it hit a wall.
+func (b *Bullet) SetPosition(position math.Vector) {
+	b.Position = position
+	if b.body != nil {
+		b.body.SetPosition(position)
+	}
 }
 
 // GetCollider returns a circular collider for the bullet.
@@ -150,6 +253,29 @@ func (b *Bullet) GetCollider() physics.CircleCollider {
 	return physics.GetEntityCollider(b.Position, b.Image, 0.25)
 }
 
+// GetTrailCollider returns a polygon collider that covers the path of the bullet in the last update.
+// Fast bullets move further than their own size in a single frame, so hits are detected
+// along the whole path instead of only at the end of it.
+//
+// Returns:
+// - physics.PolygonCollider: The area the bullet passed through, rotated with the bullet
+func (b *Bullet) GetTrailCollider() physics.PolygonCollider {
+	radius := b.GetCollider().Radius
+	travelled := math.Distance(b.lastPosition, b.Position)
+
+	// The bullet flies towards -Y before rotation, so its path lies behind it on +Y
+	return physics.PolygonCollider{
+		Position: b.Position,
+		Vertices: []math.Vector{
+			{X: -radius, Y: -radius},
+			{X: radius, Y: -radius},
+			{X: radius, Y: travelled + radius},
+			{X: -radius, Y: travelled + radius},
+		},
+		Rotation: b.Rotation,
+	}
+}
+
 // GetSpeed returns the current speed of the bullet.
 // This is used for continuous collision detection.
 //
@@ -207,6 +333,12 @@ func (b *Bullet) Draw(screen *ebiten.Image, offsetX, offsetY float64, worldWidth
 	// Apply rotation to match the bullet's direction
 	op.GeoM.Rotate(b.Rotation)
 
+	// With a body, draw between the last two physics steps for smooth movement
+	position := b.Position
+	if b.body != nil {
+		position = b.body.RenderPosition()
+	}
+
 	// Calculate the screen center using the provided world dimensions
 	// This is the reference point for all world-space coordinates
 	centerX := float64(worldWidth) / 2
@@ -216,8 +348,8 @@ func (b *Bullet) Draw(screen *ebiten.Image, offsetX, offsetY float64, worldWidth
 	// 1. Start at the center of the screen
 	// 2. Add the bullet's world position (which is relative to center)
 	// 3. Apply the camera offset for scrolling
-	screenX := centerX + b.Position.X + offsetX
-	screenY := centerY + b.Position.Y + offsetY
+	screenX := centerX + position.X + offsetX
+	screenY := centerY + position.Y + offsetY
 
 	// Move to the calculated position, adjusting for the scaling factor
 	// Since we're scaling by 0.5, we need to multiply the screen position by 2.0
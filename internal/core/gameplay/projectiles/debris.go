@@ -0,0 +1,146 @@
+package projectiles
+
+import (
+	"discoveryx/internal/config"
+	"discoveryx/internal/core/physics"
+	"discoveryx/internal/utils/math"
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+	stdmath "math"
+)
+
+// Debris behavior constants
+const (
+	DebrisLifetime = 1.5 // Seconds before a piece of debris disappears
+	DebrisDrag     = 1.5 // Rate per second at which debris slows down
+	DebrisSpin     = 8.0 // Fastest rotation of a piece of debris in radians per second
+)
+
+// debrisColor is the color of debris pieces before they fade out
+var debrisColor = color.RGBA{R: 200, G: 170, B: 140, A: 255}
+
+// debrisImage is a white square that is scaled, rotated and tinted for every piece
+var debrisImage *ebiten.Image
+
+// Debris is a small piece that flies off when something is destroyed. It bounces off
+// and slides along the walls with the debris_contact profile of the config, slows
+// down and fades out until its lifetime expires.
+type Debris struct {
+	Position math.Vector // Current position in world coordinates relative to center
+	Rotation float64     // Current rotation in radians
+	size     float64     // Edge length of the square piece
+	velocity math.Vector // Velocity in units per second, used until the piece has a body
+	spin     float64     // Angular velocity in radians per second
+	lifetime float64     // Seconds since the piece was created
+	body     *physics.Body
+}
+
+// NewDebris creates a piece of debris at a position, flying with a velocity in units per second.
+func NewDebris(position, velocity math.Vector, size, spin float64) *Debris {
+	return &Debris{
+		Position: position,
+		size:     size,
+		velocity: velocity,
+		spin:     spin,
+	}
+}
+
+// SpawnDebris creates count pieces of debris that burst out of a position in all directions.
+// The directions, speeds and sizes are spread evenly, so the burst looks irregular without
+// using the random streams of the run.
+func SpawnDebris(position math.Vector, count int, speed float64) []*Debris {
+	pieces := make([]*Debris, count)
+	for i := range pieces {
+		// Scatter the pieces around the circle, each a little off its even share
+		angle := (float64(i) + 0.5*stdmath.Sin(float64(i*7))) * 2 * stdmath.Pi / float64(count)
+		pieceSpeed := speed * (0.5 + 0.5*float64((i*5)%count)/float64(count))
+		velocity := math.Vector{X: stdmath.Sin(angle) * pieceSpeed, Y: -stdmath.Cos(angle) * pieceSpeed}
+
+		size := 3.0 + float64((i*3)%4)
+		spin := DebrisSpin * stdmath.Cos(float64(i*11))
+		pieces[i] = NewDebris(position, velocity, size, spin)
+	}
+	return pieces
+}
+
+// Update ages the piece and, if it has no body, moves it.
+//
+// Returns:
+// - true if the lifetime of the piece has expired and it should be removed
+// - false if the piece is still active
+func (d *Debris) Update(deltaTime float64) bool {
+	if d.body == nil {
+		damping := stdmath.Exp(-DebrisDrag * deltaTime)
+		d.velocity.X *= damping
+		d.velocity.Y *= damping
+		d.Position.X += d.velocity.X * deltaTime
+		d.Position.Y += d.velocity.Y * deltaTime
+		d.Rotation += d.spin * deltaTime
+	}
+
+	d.lifetime += deltaTime
+	return d.lifetime >= DebrisLifetime
+}
+
+// NewBody creates the physics body of the piece. The body bounces off walls, so the piece
+// must be registered with the collision manager of the world.
+func (d *Debris) NewBody() *physics.Body {
+	d.body = physics.NewBody(d, d.Position)
+	d.body.Velocity = d.velocity
+	d.body.AngularVelocity = d.spin
+	d.body.Drag = DebrisDrag
+	d.body.CollideWithWalls = true
+	d.body.Contact = physics.NewContactProfile(config.Get().Physics.DebrisContact)
+	d.body.Controller = d
+	return d.body
+}
+
+// Body returns the physics body of the piece, or nil if it has none.
+func (d *Debris) Body() *physics.Body {
+	return d.body
+}
+
+// BeforeStep does nothing, debris flies freely. It implements physics.BodyController.
+func (d *Debris) BeforeStep(body *physics.Body, dt float64) {}
+
+// AfterStep moves the piece to its body. It implements physics.BodyController.
+func (d *Debris) AfterStep(body *physics.Body, hits []physics.SweepHit) {
+	d.Position = body.Position
+	d.Rotation = body.Rotation
+	d.velocity = body.Velocity
+}
+
+// GetCollider returns a circular collider around the piece, used to bounce it off walls.
+func (d *Debris) GetCollider() physics.CircleCollider {
+	return physics.CircleCollider{Position: d.Position, Radius: d.size / 2}
+}
+
+// Draw renders the piece as a rotated square that fades out over its lifetime.
+//
+// Parameters:
+// - screen: The target image where the piece should be drawn
+// - offsetX, offsetY: Camera offset values for scrolling
+// - worldWidth, worldHeight: Current dimensions of the game world
+func (d *Debris) Draw(screen *ebiten.Image, offsetX, offsetY float64, worldWidth, worldHeight int) {
+	if debrisImage == nil {
+		debrisImage = ebiten.NewImage(1, 1)
+		debrisImage.Fill(color.White)
+	}
+
+	// With a body, draw between the last two physics steps for smooth movement
+	position, rotation := d.Position, d.Rotation
+	if d.body != nil {
+		position, rotation = d.body.RenderPosition(), d.body.RenderRotation()
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(-0.5, -0.5)
+	op.GeoM.Scale(d.size, d.size)
+	op.GeoM.Rotate(rotation)
+	op.GeoM.Translate(float64(worldWidth)/2+position.X+offsetX, float64(worldHeight)/2+position.Y+offsetY)
+
+	op.ColorScale.ScaleWithColor(debrisColor)
+	op.ColorScale.ScaleAlpha(float32(1 - d.lifetime/DebrisLifetime))
+
+	screen.DrawImage(debrisImage, op)
+}
@@ -0,0 +1,35 @@
+package physics
+
+import (
+	"discoveryx/internal/core/physics/collisions"
+	"fmt"
+)
+
+// Broadphase names, as used by physics.broadphase in the config
+const (
+	BroadphaseGrid          = "grid"            // Uniform grid (collisions.SpatialHash)
+	BroadphaseTree          = "tree"            // Dynamic AABB tree (collisions.AABBTree)
+	BroadphaseSweepAndPrune = "sweep_and_prune" // Objects sorted along X (collisions.SweepAndPrune)
+)
+
+// Broadphases lists the names of all broadphases
+var Broadphases = []string{BroadphaseGrid, BroadphaseTree, BroadphaseSweepAndPrune}
+
+// treeMargin enlarges the boxes in the AABB tree, so entities that move a little
+// do not have to be moved in the tree every frame
+const treeMargin = 10.0
+
+// NewBroadphase returns a function that creates the named broadphase.
+// The grid uses the cell size; the other broadphases do not need one.
+func NewBroadphase(name string, cellSize float64) (func() collisions.Broadphase, error) {
+	switch name {
+	case BroadphaseGrid:
+		return func() collisions.Broadphase { return collisions.NewSpatialHash(cellSize) }, nil
+	case BroadphaseTree:
+		return func() collisions.Broadphase { return collisions.NewAABBTree(treeMargin) }, nil
+	case BroadphaseSweepAndPrune:
+		return func() collisions.Broadphase { return collisions.NewSweepAndPrune() }, nil
+	default:
+		return nil, fmt.Errorf("unknown broadphase %q, expected one of %v", name, Broadphases)
+	}
+}
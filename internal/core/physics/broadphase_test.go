@@ -0,0 +1,387 @@
+package physics
+
+import (
+	"discoveryx/internal/core/physics/collisions"
+	"discoveryx/internal/core/worldgen"
+	stdmath "math"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// Shape counts and speeds of the recorded scenario, close to a run of the game
+const (
+	scenarioFrames         = 600   // Frames the player flies along the main path
+	scenarioEnemiesPerCell = 4     // Enemies placed in every cell
+	scenarioShotInterval   = 8     // Frames between two shots of the player
+	scenarioBulletFrames   = 60    // Frames a bullet flies
+	scenarioBulletSpeed    = 15.0  // Bullet speed in units per frame
+	scenarioOpening        = 200.0 // Width of the openings in the cell walls
+	scenarioWallPiece      = 100.0 // Length of the wall segments, like the traced outlines
+)
+
+// broadphaseScenario is a recording of the shapes of a run through a generated world.
+// The walls and enemies stay in place, while the player flies along the main path and
+// fires bullets. Replaying it against a broadphase measures the work of real frames.
+type broadphaseScenario struct {
+	static []collisions.Object // Wall segments and enemies
+	frames []scenarioFrame     // Moving shapes in every frame
+}
+
+// scenarioFrame is the state of the moving shapes in one frame
+type scenarioFrame struct {
+	playerX, playerY float64
+	bullets          [][2]float64 // Positions of the bullets in flight, oldest first
+	fired            bool         // Whether a bullet was fired in this frame
+}
+
+var (
+	scenarioOnce   sync.Once
+	scenario       *broadphaseScenario
+	scenarioErrMsg string
+)
+
+// loadScenario records the scenario once from a world generated with a fixed seed.
+// The walls are the cell borders with openings at the connectors, since the snippet
+// outlines need the snippet images, which are not loaded without a graphics context.
+func loadScenario(tb testing.TB) *broadphaseScenario {
+	scenarioOnce.Do(func() {
+		generator, err := worldgen.NewHeadlessWorldGenerator()
+		if err != nil {
+			scenarioErrMsg = err.Error()
+			return
+		}
+		config := worldgen.DefaultWorldGenConfig()
+		config.Seed = 42
+		worldMap, err := generator.GenerateWorld(config)
+		if err != nil {
+			scenarioErrMsg = err.Error()
+			return
+		}
+		scenario = recordScenario(worldMap, rand.New(rand.NewSource(config.Seed)))
+	})
+	if scenario == nil {
+		tb.Skipf("Could not generate the scenario world: %s", scenarioErrMsg)
+	}
+	return scenario
+}
+
+// recordScenario creates the shapes of a world map and the frames of a flight along its main path
+func recordScenario(worldMap *worldgen.WorldMap, rng *rand.Rand) *broadphaseScenario {
+	s := &broadphaseScenario{}
+
+	// Visit the cells in a fixed order, so every recording is the same
+	keys := make([]worldgen.CellCoord, 0, len(worldMap.Cells))
+	for key := range worldMap.Cells {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Y != keys[j].Y {
+			return keys[i].Y < keys[j].Y
+		}
+		return keys[i].X < keys[j].X
+	})
+
+	for _, key := range keys {
+		cell := worldMap.Cells[key]
+		s.static = append(s.static, cellWalls(cell)...)
+
+		for i := 0; i < scenarioEnemiesPerCell; i++ {
+			x := float64(cell.X*worldgen.CellSize) + 200 + rng.Float64()*(worldgen.CellSize-400)
+			y := float64(cell.Y*worldgen.CellSize) + 200 + rng.Float64()*(worldgen.CellSize-400)
+			s.static = append(s.static, collisions.NewCircle(x, y, 25))
+		}
+	}
+
+	// Fly through the centers of the main path cells, firing in the direction of flight
+	path := worldMap.MainPathCells
+	var bullets [][4]float64 // x, y, dx, dy
+	for frame := 0; frame < scenarioFrames && len(path) > 1; frame++ {
+		progress := float64(frame) / scenarioFrames * float64(len(path)-1)
+		from, to := path[int(progress)], path[minInt(int(progress)+1, len(path)-1)]
+		t := progress - stdmath.Floor(progress)
+		fromX, fromY := cellCenter(from)
+		toX, toY := cellCenter(to)
+		x, y := fromX+(toX-fromX)*t, fromY+(toY-fromY)*t
+
+		fired := frame%scenarioShotInterval == 0
+		if fired {
+			dx, dy := toX-fromX, toY-fromY
+			length := stdmath.Hypot(dx, dy)
+			bullets = append(bullets, [4]float64{x, y, dx / length * scenarioBulletSpeed, dy / length * scenarioBulletSpeed})
+		}
+		if len(bullets) > scenarioBulletFrames/scenarioShotInterval {
+			bullets = bullets[1:]
+		}
+
+		positions := make([][2]float64, len(bullets))
+		for i := range bullets {
+			bullets[i][0] += bullets[i][2]
+			bullets[i][1] += bullets[i][3]
+			positions[i] = [2]float64{bullets[i][0], bullets[i][1]}
+		}
+		s.frames = append(s.frames, scenarioFrame{playerX: x, playerY: y, bullets: positions, fired: fired})
+	}
+
+	return s
+}
+
+// cellCenter returns the world position of the center of a cell
+func cellCenter(cell *worldgen.WorldCell) (float64, float64) {
+	return float64(cell.X*worldgen.CellSize) + worldgen.CellSize/2, float64(cell.Y*worldgen.CellSize) + worldgen.CellSize/2
+}
+
+// cellWalls returns wall segments along the borders of a cell, with an opening at every connector
+func cellWalls(cell *worldgen.WorldCell) []collisions.Object {
+	open := make(map[worldgen.SnippetConnector]bool)
+	for _, connector := range cell.GetRotatedConnectors() {
+		open[connector] = true
+	}
+
+	left, top := float64(cell.X*worldgen.CellSize), float64(cell.Y*worldgen.CellSize)
+	size := float64(worldgen.CellSize)
+	sides := []struct {
+		connector      worldgen.SnippetConnector
+		x1, y1, x2, y2 float64
+	}{
+		{worldgen.ConnectorTop, left, top, left + size, top},
+		{worldgen.ConnectorRight, left + size, top, left + size, top + size},
+		{worldgen.ConnectorBottom, left + size, top + size, left, top + size},
+		{worldgen.ConnectorLeft, left, top + size, left, top},
+	}
+
+	var walls []collisions.Object
+	for _, side := range sides {
+		for along := 0.0; along < size; along += scenarioWallPiece {
+			middle := along + scenarioWallPiece/2
+			if open[side.connector] && stdmath.Abs(middle-size/2) < scenarioOpening/2 {
+				continue
+			}
+			t1, t2 := along/size, (along+scenarioWallPiece)/size
+			walls = append(walls, collisions.NewSegment(
+				side.x1+(side.x2-side.x1)*t1, side.y1+(side.y2-side.y1)*t1,
+				side.x1+(side.x2-side.x1)*t2, side.y1+(side.y2-side.y1)*t2))
+		}
+	}
+	return walls
+}
+
+// minInt returns the smaller of two integers
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// replay runs all frames of the scenario against a broadphase. Every frame moves the player
+// and the bullets, finds the shapes near each of them like the swept movement does, and
+// finds all overlapping pairs like the contact events do.
+func (s *broadphaseScenario) replay(broadphase collisions.Broadphase) int {
+	for _, obj := range s.static {
+		broadphase.Add(obj)
+	}
+
+	player := collisions.NewCircle(s.frames[0].playerX, s.frames[0].playerY, 30)
+	broadphase.Add(player)
+
+	var bullets []*collisions.Circle
+	found := 0
+	for _, frame := range s.frames {
+		player.X, player.Y = frame.playerX, frame.playerY
+		broadphase.Update(player)
+
+		// Bullets are removed in the order they were fired
+		for len(bullets) > len(frame.bullets) || (frame.fired && len(bullets) == len(frame.bullets)) {
+			broadphase.Remove(bullets[0])
+			bullets = bullets[1:]
+		}
+		for len(bullets) < len(frame.bullets) {
+			bullet := collisions.NewCircle(frame.playerX, frame.playerY, 4)
+			broadphase.Add(bullet)
+			bullets = append(bullets, bullet)
+		}
+		for i, bullet := range bullets {
+			bullet.X, bullet.Y = frame.bullets[i][0], frame.bullets[i][1]
+			broadphase.Update(bullet)
+		}
+
+		found += len(broadphase.Candidates(player))
+		for _, bullet := range bullets {
+			found += len(broadphase.Candidates(bullet))
+		}
+		found += len(broadphase.Pairs())
+	}
+
+	for _, obj := range s.static {
+		broadphase.Remove(obj)
+	}
+	broadphase.Remove(player)
+	for _, bullet := range bullets {
+		broadphase.Remove(bullet)
+	}
+	return found
+}
+
+// BenchmarkBroadphaseReplay measures every broadphase on the frames of a run through a generated world
+func BenchmarkBroadphaseReplay(b *testing.B) {
+	s := loadScenario(b)
+
+	for _, name := range Broadphases {
+		newBroadphase, err := NewBroadphase(name, 100.0)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				s.replay(newBroadphase())
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(s.frames)), "ns/frame")
+		})
+	}
+}
+
+// BenchmarkBroadphaseBuild measures adding all walls and enemies of a generated world
+func BenchmarkBroadphaseBuild(b *testing.B) {
+	s := loadScenario(b)
+
+	for _, name := range Broadphases {
+		newBroadphase, err := NewBroadphase(name, 100.0)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				broadphase := newBroadphase()
+				for _, obj := range s.static {
+					broadphase.Add(obj)
+				}
+			}
+		})
+	}
+}
+
+// TestBroadphasesAgree tests that all broadphases find the same pairs and candidates while shapes move
+func TestBroadphasesAgree(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+
+	// A mix of circles and wall segments, some of them on negative coordinates
+	var objects []collisions.Object
+	for i := 0; i < 150; i++ {
+		x, y := rng.Float64()*1200-600, rng.Float64()*1200-600
+		if i%3 == 0 {
+			objects = append(objects, collisions.NewSegment(x, y, x+rng.Float64()*200-100, y+rng.Float64()*200-100))
+		} else {
+			objects = append(objects, collisions.NewCircle(x, y, 5+rng.Float64()*40))
+		}
+	}
+
+	broadphases := make(map[string]collisions.Broadphase)
+	for _, name := range Broadphases {
+		newBroadphase, err := NewBroadphase(name, 100.0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		broadphases[name] = newBroadphase()
+		for _, obj := range objects {
+			broadphases[name].Add(obj)
+		}
+	}
+
+	for round := 0; round < 5; round++ {
+		expected := bruteForcePairs(objects)
+		for _, name := range Broadphases {
+			broadphase := broadphases[name]
+			if got := pairKeys(broadphase.Pairs(), objects); !equalKeys(got, expected) {
+				t.Errorf("Round %d: %s found %d pairs, expected %d", round, name, len(got), len(expected))
+			}
+
+			box := collisions.AABB{MinX: -100, MinY: -100, MaxX: 150, MaxY: 50}
+			if got, want := len(broadphase.Query(box)), len(bruteForceQuery(objects, box)); got != want {
+				t.Errorf("Round %d: %s found %d objects in the box, expected %d", round, name, got, want)
+			}
+			if got, want := len(broadphase.Candidates(objects[0])), len(bruteForceQuery(objects, collisions.Bounds(objects[0])))-1; got != want {
+				t.Errorf("Round %d: %s found %d candidates, expected %d", round, name, got, want)
+			}
+		}
+
+		// Move the circles and remove one object
+		for _, obj := range objects {
+			if circle, ok := obj.(*collisions.Circle); ok {
+				circle.X += rng.Float64()*60 - 30
+				circle.Y += rng.Float64()*60 - 30
+				for _, broadphase := range broadphases {
+					broadphase.Update(circle)
+				}
+			}
+		}
+		removed := objects[len(objects)-1]
+		objects = objects[:len(objects)-1]
+		for _, broadphase := range broadphases {
+			broadphase.Remove(removed)
+			if broadphase.Len() != len(objects) {
+				t.Fatalf("Expected %d objects after removing one, got %d", len(objects), broadphase.Len())
+			}
+		}
+	}
+}
+
+// bruteForcePairs returns the index pairs of all objects whose bounds overlap
+func bruteForcePairs(objects []collisions.Object) map[[2]int]bool {
+	pairs := make(map[[2]int]bool)
+	for i := range objects {
+		for j := i + 1; j < len(objects); j++ {
+			if collisions.Bounds(objects[i]).Overlaps(collisions.Bounds(objects[j])) {
+				pairs[[2]int{i, j}] = true
+			}
+		}
+	}
+	return pairs
+}
+
+// bruteForceQuery returns all objects whose bounds overlap the box
+func bruteForceQuery(objects []collisions.Object, box collisions.AABB) []collisions.Object {
+	var found []collisions.Object
+	for _, obj := range objects {
+		if collisions.Bounds(obj).Overlaps(box) {
+			found = append(found, obj)
+		}
+	}
+	return found
+}
+
+// pairKeys converts object pairs to index pairs, the smaller index first
+func pairKeys(pairs [][2]collisions.Object, objects []collisions.Object) map[[2]int]bool {
+	index := make(map[collisions.Object]int, len(objects))
+	for i, obj := range objects {
+		index[obj] = i
+	}
+
+	keys := make(map[[2]int]bool, len(pairs))
+	for _, pair := range pairs {
+		a, b := index[pair[0]], index[pair[1]]
+		if a > b {
+			a, b = b, a
+		}
+		keys[[2]int{a, b}] = true
+	}
+	return keys
+}
+
+// equalKeys checks if two sets of index pairs are the same
+func equalKeys(a, b map[[2]int]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key := range a {
+		if !b[key] {
+			return false
+		}
+	}
+	return true
+}
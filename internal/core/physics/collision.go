@@ -24,6 +24,23 @@ type RectCollider struct {
 	Height   float64     // Height of the rectangle
 }
 
+// PolygonCollider represents a convex polygon collision area that rotates with its entity.
+// It is used for entities that are not round, like the ship hull.
+type PolygonCollider struct {
+	Position math.Vector   // Center position of the polygon
+	Vertices []math.Vector // Vertices relative to the position, in order around the polygon
+	Rotation float64       // Rotation in radians (clockwise, like the sprites)
+}
+
+// toShape converts the collider to a polygon shape for the collision system.
+func (c PolygonCollider) toShape() *PolygonShape {
+	return &PolygonShape{
+		Position: c.Position,
+		Vertices: c.Vertices,
+		Rotation: c.Rotation,
+	}
+}
+
 // AABBCollider represents an Axis-Aligned Bounding Box for collision detection.
 // It is used for precise collision detection between the player and walls.
 type AABBCollider struct {
@@ -64,7 +81,8 @@ func CheckCircleCollision(c1, c2 CircleCollider) bool {
 	dy := c1.Position.Y - c2.Position.Y
 	distance := stdmath.Sqrt(dx*dx + dy*dy)
 
-	// If the distance is less than the sum of the radii, the circles are colliding
+	// If the distance is less than the sum of the radii, the circles are colliding.
+	// Touching circles do not overlap, like in the separating axis test of the collision system.
 	return distance < (c1.Radius + c2.Radius)
 }
 
@@ -554,3 +572,101 @@ func CheckContinuousCircleCircleCollision(startPos1, endPos1 math.Vector, radius
 
 	return true, collisionPoint, normal, collisionTime
 }
+
+// CheckContinuousCircleSegmentCollision performs continuous collision detection between a moving circle
+// and a wall outline segment. This is used to detect collisions between fast-moving objects like bullets and walls.
+//
+// Parameters:
+// - startPos: The starting position of the circle
+// - endPos: The ending position of the circle
+// - radius: The radius of the circle
+// - segment: The wall segment to check for collision with
+//
+// Returns:
+// - bool: True if a collision was detected, false otherwise
+// - math.Vector: The position of the circle at the time of collision (only valid if a collision was detected)
+// - math.Vector: The normal vector of the collision, pointing from the wall to the circle (only valid if a collision was detected)
+// - float64: The time of collision (0-1, where 0 is startPos and 1 is endPos)
+func CheckContinuousCircleSegmentCollision(startPos, endPos math.Vector, radius float64, segment WallSegment) (bool, math.Vector, math.Vector, float64) {
+	// Calculate the movement vector and the segment vector
+	moveX := endPos.X - startPos.X
+	moveY := endPos.Y - startPos.Y
+	segX := segment.B.X - segment.A.X
+	segY := segment.B.Y - segment.A.Y
+	segLengthSq := segX*segX + segY*segY
+
+	// Check if the circle already touches the segment at the start
+	t := 0.0
+	if segLengthSq > 0 {
+		t = stdmath.Max(0, stdmath.Min(1, ((startPos.X-segment.A.X)*segX+(startPos.Y-segment.A.Y)*segY)/segLengthSq))
+	}
+	closest := math.Vector{X: segment.A.X + segX*t, Y: segment.A.Y + segY*t}
+	if distance := math.Distance(startPos, closest); distance <= radius {
+		normal := segment.Normal
+		if distance > 0 {
+			normal = math.Vector{X: (startPos.X - closest.X) / distance, Y: (startPos.Y - closest.Y) / distance}
+		}
+		return true, startPos, normal, 0
+	}
+
+	collisionTime := stdmath.Inf(1)
+	var normal math.Vector
+
+	// The circle hits the side of the segment when its center reaches the line moved out by the radius
+	if segLengthSq > 0 {
+		segLength := stdmath.Sqrt(segLengthSq)
+		sideNormal := math.Vector{X: -segY / segLength, Y: segX / segLength}
+		side := (startPos.X-segment.A.X)*sideNormal.X + (startPos.Y-segment.A.Y)*sideNormal.Y
+		if side < 0 {
+			sideNormal = math.Vector{X: -sideNormal.X, Y: -sideNormal.Y}
+			side = -side
+		}
+
+		approach := moveX*sideNormal.X + moveY*sideNormal.Y
+		if approach < 0 {
+			hitTime := (side - radius) / -approach
+			hitX := startPos.X + moveX*hitTime
+			hitY := startPos.Y + moveY*hitTime
+			along := ((hitX-segment.A.X)*segX + (hitY-segment.A.Y)*segY) / segLengthSq
+			if hitTime >= 0 && hitTime <= 1 && along >= 0 && along <= 1 {
+				collisionTime = hitTime
+				normal = sideNormal
+			}
+		}
+	}
+
+	// The circle hits an end point of the segment
+	// (p + vt)^2 = r^2, where p is the position relative to the end point and v is the movement
+	a := moveX*moveX + moveY*moveY
+	for _, corner := range []math.Vector{segment.A, segment.B} {
+		relX := startPos.X - corner.X
+		relY := startPos.Y - corner.Y
+		b := 2 * (relX*moveX + relY*moveY)
+		c := relX*relX + relY*relY - radius*radius
+
+		discriminant := b*b - 4*a*c
+		if a == 0 || discriminant < 0 {
+			continue
+		}
+
+		hitTime := (-b - stdmath.Sqrt(discriminant)) / (2 * a)
+		if hitTime < 0 || hitTime > 1 || hitTime >= collisionTime {
+			continue
+		}
+		collisionTime = hitTime
+		normal = math.Vector{
+			X: (relX + moveX*hitTime) / radius,
+			Y: (relY + moveY*hitTime) / radius,
+		}
+	}
+
+	if stdmath.IsInf(collisionTime, 1) {
+		return false, math.Vector{}, math.Vector{}, 0
+	}
+
+	collisionPoint := math.Vector{
+		X: startPos.X + moveX*collisionTime,
+		Y: startPos.Y + moveY*collisionTime,
+	}
+	return true, collisionPoint, normal, collisionTime
+}
@@ -3,6 +3,8 @@ package physics
 
 import (
 	"discoveryx/internal/utils/math"
+	"fmt"
+	stdmath "math"
 )
 
 // CollisionManager centralizes collision detection and provides optimized methods
@@ -17,14 +19,40 @@ type CollisionManager struct {
 
 	// Maps wall colliders to their shape IDs
 	wallShapeIDs map[RectCollider]int
+
+	// Maps wall outline segments to their shape IDs
+	wallSegmentIDs map[WallSegment]int
+
+	// Listeners notified about contacts between entities
+	contactListeners []ContactListener
+
+	// Contacts found in the last call to UpdateContacts
+	contacts map[contactPair]ContactEvent
 }
 
 // NewCollisionManager creates a new collision manager with the specified cell size.
 func NewCollisionManager(cellSize float64) *CollisionManager {
+	return newCollisionManager(NewEbitenCollisionSystem(cellSize))
+}
+
+// NewCollisionManagerWithBroadphase creates a new collision manager that finds nearby
+// shapes with the named broadphase (see Broadphases). The grid uses the cell size.
+func NewCollisionManagerWithBroadphase(cellSize float64, broadphase string) (*CollisionManager, error) {
+	newBroadphase, err := NewBroadphase(broadphase, cellSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collision manager: %w", err)
+	}
+	return newCollisionManager(NewEbitenCollisionSystemWithBroadphase(cellSize, newBroadphase)), nil
+}
+
+// newCollisionManager creates a collision manager around a collision system.
+func newCollisionManager(collisionSystem CollisionSystem) *CollisionManager {
 	return &CollisionManager{
-		collisionSystem: NewEbitenCollisionSystem(cellSize),
+		collisionSystem: collisionSystem,
 		entityShapeIDs:  make(map[interface{}]int),
 		wallShapeIDs:    make(map[RectCollider]int),
+		wallSegmentIDs:  make(map[WallSegment]int),
+		contacts:        make(map[contactPair]ContactEvent),
 	}
 }
 
@@ -63,6 +91,42 @@ func (cm *CollisionManager) UpdateEntity(entity interface{}, collider CircleColl
 	cm.collisionSystem.UpdateShape(shapeID, circleShape)
 }
 
+// RegisterEntityPolygon adds an entity to the collision system with a polygon collider.
+// This is used for entities whose outline is not round, like the rotated ship hull.
+func (cm *CollisionManager) RegisterEntityPolygon(entity interface{}, collider PolygonCollider) {
+	// Add the polygon shape to the collision system
+	shapeID := cm.collisionSystem.AddShape(collider.toShape())
+
+	// Store the shape ID for later lookup
+	cm.entityShapeIDs[entity] = shapeID
+}
+
+// UpdateEntityPolygon updates an entity's polygon collider in the collision system.
+func (cm *CollisionManager) UpdateEntityPolygon(entity interface{}, collider PolygonCollider) {
+	// Get the shape ID for this entity
+	shapeID, exists := cm.entityShapeIDs[entity]
+	if !exists {
+		// If the entity doesn't exist, register it
+		cm.RegisterEntityPolygon(entity, collider)
+		return
+	}
+
+	// Update the shape in the collision system
+	cm.collisionSystem.UpdateShape(shapeID, collider.toShape())
+}
+
+// SetEntityLayers sets the collision layers of a registered entity.
+// Entities without layers collide with everything.
+func (cm *CollisionManager) SetEntityLayers(entity interface{}, layers CollisionLayers) {
+	// Get the shape ID for this entity
+	shapeID, exists := cm.entityShapeIDs[entity]
+	if !exists {
+		return
+	}
+
+	cm.collisionSystem.SetLayers(shapeID, layers)
+}
+
 // RemoveEntity removes an entity from the collision system.
 func (cm *CollisionManager) RemoveEntity(entity interface{}) {
 	// Get the shape ID for this entity
@@ -87,22 +151,73 @@ func (cm *CollisionManager) RegisterWall(wall RectCollider) {
 		Height:   wall.Height,
 	}
 
-	// Add the shape to the collision system
+	// Add the shape to the collision system on the wall layer
 	shapeID := cm.collisionSystem.AddShape(aabbShape)
+	cm.collisionSystem.SetLayers(shapeID, NewCollisionLayers(LayerWall))
 
 	// Store the shape ID for later lookup
 	cm.wallShapeIDs[wall] = shapeID
 }
 
+// RemoveWall removes a single wall from the collision system.
+func (cm *CollisionManager) RemoveWall(wall RectCollider) {
+	// Get the shape ID for this wall
+	shapeID, exists := cm.wallShapeIDs[wall]
+	if !exists {
+		return
+	}
+
+	// Remove the shape from the collision system
+	cm.collisionSystem.RemoveShape(shapeID)
+
+	// Remove the wall from our map
+	delete(cm.wallShapeIDs, wall)
+}
+
+// RegisterWallSegment adds a segment of a wall outline to the collision system.
+func (cm *CollisionManager) RegisterWallSegment(segment WallSegment) {
+	// Create a segment shape from the wall segment
+	segmentShape := &SegmentShape{
+		A: segment.A,
+		B: segment.B,
+	}
+
+	// Add the shape to the collision system on the wall layer
+	shapeID := cm.collisionSystem.AddShape(segmentShape)
+	cm.collisionSystem.SetLayers(shapeID, NewCollisionLayers(LayerWall))
+
+	// Store the shape ID for later lookup
+	cm.wallSegmentIDs[segment] = shapeID
+}
+
+// RemoveWallSegment removes a single wall outline segment from the collision system.
+func (cm *CollisionManager) RemoveWallSegment(segment WallSegment) {
+	// Get the shape ID for this segment
+	shapeID, exists := cm.wallSegmentIDs[segment]
+	if !exists {
+		return
+	}
+
+	// Remove the shape from the collision system
+	cm.collisionSystem.RemoveShape(shapeID)
+
+	// Remove the segment from our map
+	delete(cm.wallSegmentIDs, segment)
+}
+
 // ClearWalls removes all walls from the collision system.
 func (cm *CollisionManager) ClearWalls() {
 	// Remove all wall shapes from the collision system
 	for _, shapeID := range cm.wallShapeIDs {
 		cm.collisionSystem.RemoveShape(shapeID)
 	}
+	for _, shapeID := range cm.wallSegmentIDs {
+		cm.collisionSystem.RemoveShape(shapeID)
+	}
 
-	// Clear our map
+	// Clear our maps
 	cm.wallShapeIDs = make(map[RectCollider]int)
+	cm.wallSegmentIDs = make(map[WallSegment]int)
 }
 
 // CheckCollision checks if the specified entity collides with any other entity.
@@ -120,8 +235,8 @@ func (cm *CollisionManager) CheckCollision(entity interface{}, radius float64) (
 			return false
 		}
 
-		// Only check entity-entity collisions (circle-circle)
-		if self.GetType() != ShapeTypeCircle || other.GetType() != ShapeTypeCircle {
+		// Only check entity-entity collisions (circles and polygons)
+		if !isEntityShape(self) || !isEntityShape(other) {
 			return false
 		}
 
@@ -183,10 +298,10 @@ func (cm *CollisionManager) CheckAABBWallCollision(entity interface{}, plannedPo
 	// Add the shape to the collision system temporarily
 	shapeID := cm.collisionSystem.AddShape(aabbShape)
 
-	// Create a filter that only checks AABB-AABB collisions
+	// Create a filter that only checks the entity box against walls
 	filter := func(self, other Shape) bool {
-		// Only check AABB-AABB collisions
-		return self.GetType() == ShapeTypeAABB && other.GetType() == ShapeTypeAABB
+		// Wall rectangles and wall outline segments
+		return self == aabbShape && (other.GetType() == ShapeTypeAABB || other.GetType() == ShapeTypeSegment)
 	}
 
 	// Check for collisions
@@ -286,6 +401,112 @@ func (cm *CollisionManager) GetNearbyWalls(position math.Vector, radius float64)
 	return walls
 }
 
+// Raycast returns the first shape on one of the mask layers that a ray hits, up to maxDistance
+// from the origin. The direction does not need to be normalized. If the shape belongs to an
+// entity, the entity is set in the hit.
+func (cm *CollisionManager) Raycast(origin, direction math.Vector, maxDistance float64, mask CollisionLayer) (RayHit, bool) {
+	hit, found := cm.collisionSystem.Raycast(origin, direction, maxDistance, mask)
+	if !found {
+		return RayHit{}, false
+	}
+
+	// Find the entity associated with the shape
+	for entity, shapeID := range cm.entityShapeIDs {
+		if cm.collisionSystem.(*EbitenCollisionSystem).shapes[shapeID] == hit.Shape {
+			hit.Entity = entity
+			break
+		}
+	}
+
+	return hit, true
+}
+
+// LineOfSight reports whether the straight line between two positions is free of walls.
+func (cm *CollisionManager) LineOfSight(a, b math.Vector) bool {
+	direction := math.Vector{X: b.X - a.X, Y: b.Y - a.Y}
+	distance := stdmath.Sqrt(direction.X*direction.X + direction.Y*direction.Y)
+	if distance == 0 {
+		return true
+	}
+
+	_, blocked := cm.collisionSystem.Raycast(a, direction, distance, LayerWall)
+	return !blocked
+}
+
+// SetEntityTransform moves the shape of a registered entity to a position and, for polygons, a rotation.
+// It returns false if the entity is not registered.
+func (cm *CollisionManager) SetEntityTransform(entity interface{}, position math.Vector, rotation float64) bool {
+	// Get the shape of this entity
+	shapeID, exists := cm.entityShapeIDs[entity]
+	if !exists {
+		return false
+	}
+	shape := cm.collisionSystem.(*EbitenCollisionSystem).shapes[shapeID]
+
+	shape.SetPosition(position)
+	if polygon, ok := shape.(*PolygonShape); ok {
+		polygon.Rotation = rotation
+	}
+	cm.collisionSystem.UpdateShape(shapeID, shape)
+	return true
+}
+
+// maxWallSlides is the number of times an entity may slide along walls in a single movement.
+const maxWallSlides = 3
+
+// MoveEntity moves a registered entity by the movement vector and slides it along the walls it hits,
+// so that it cannot pass through walls even at high speed. It returns the new position of the entity
+// and the walls that were hit. The entity's shape is updated to the new position.
+func (cm *CollisionManager) MoveEntity(entity interface{}, movement math.Vector) (math.Vector, []SweepHit) {
+	// Get the shape of this entity
+	shapeID, exists := cm.entityShapeIDs[entity]
+	if !exists {
+		return math.Vector{}, nil
+	}
+	shape := cm.collisionSystem.(*EbitenCollisionSystem).shapes[shapeID]
+
+	// Move the shape through the walls along the way
+	position, hits := MoveAndSlide(shape, movement, cm.nearbyWallShapes(shape, movement), maxWallSlides)
+
+	// Update the shape in the collision system
+	shape.SetPosition(position)
+	cm.collisionSystem.UpdateShape(shapeID, shape)
+
+	return position, hits
+}
+
+// SweepCircle moves a circle collider by the movement vector and returns the first wall it hits.
+// This is used for small and fast objects like bullets that are not registered as entities.
+func (cm *CollisionManager) SweepCircle(collider CircleCollider, movement math.Vector) (SweepHit, bool) {
+	circleShape := &CircleShape{
+		Position: collider.Position,
+		Radius:   collider.Radius,
+	}
+
+	return SweepShape(circleShape, movement, cm.nearbyWallShapes(circleShape, movement))
+}
+
+// nearbyWallShapes returns the wall shapes that a shape may touch while it moves by the movement vector.
+func (cm *CollisionManager) nearbyWallShapes(shape Shape, movement math.Vector) []Shape {
+	// Everything within reach of the shape along the whole movement
+	start := shape.GetPosition()
+	center := math.Vector{X: start.X + movement.X/2, Y: start.Y + movement.Y/2}
+	radius := shapeExtent(shape) + stdmath.Sqrt(movement.X*movement.X+movement.Y*movement.Y)/2
+
+	var walls []Shape
+	for _, other := range cm.collisionSystem.GetNearbyShapes(center, radius) {
+		if !isEntityShape(other) {
+			walls = append(walls, other)
+		}
+	}
+	return walls
+}
+
+// isEntityShape reports whether a shape belongs to an entity rather than to a wall
+func isEntityShape(shape Shape) bool {
+	return shape.GetType() == ShapeTypeCircle || shape.GetType() == ShapeTypePolygon
+}
+
 // OptimizeWalls simplifies the wall colliders by merging adjacent walls.
 // This is a no-op in the new system as the collision library handles optimization internally.
 func (cm *CollisionManager) OptimizeWalls() {
@@ -3,6 +3,7 @@ package physics
 
 import (
 	"discoveryx/internal/utils/math"
+	stdmath "math"
 )
 
 // Shape is the interface for all collision shapes.
@@ -30,6 +31,9 @@ const (
 	
 	// ShapeTypePolygon represents a polygon collision shape.
 	ShapeTypePolygon
+
+	// ShapeTypeSegment represents a line segment collision shape.
+	ShapeTypeSegment
 )
 
 // CircleShape represents a circular collision shape.
@@ -91,6 +95,67 @@ func (a *AABBShape) GetMax() math.Vector {
 	}
 }
 
+// PolygonShape represents a convex polygon collision shape that can be rotated.
+type PolygonShape struct {
+	Position math.Vector   // Position the polygon rotates around
+	Vertices []math.Vector // Vertices relative to the position, in order around the polygon
+	Rotation float64       // Rotation in radians (clockwise, like the sprites)
+}
+
+// GetPosition returns the position of the polygon.
+func (p *PolygonShape) GetPosition() math.Vector {
+	return p.Position
+}
+
+// SetPosition updates the position of the polygon.
+func (p *PolygonShape) SetPosition(position math.Vector) {
+	p.Position = position
+}
+
+// GetType returns the type of the shape.
+func (p *PolygonShape) GetType() ShapeType {
+	return ShapeTypePolygon
+}
+
+// GetRotatedVertices returns the vertices rotated by the polygon rotation, still relative to the position.
+func (p *PolygonShape) GetRotatedVertices() []math.Vector {
+	cosA := stdmath.Cos(p.Rotation)
+	sinA := stdmath.Sin(p.Rotation)
+
+	vertices := make([]math.Vector, len(p.Vertices))
+	for i, vertex := range p.Vertices {
+		vertices[i] = math.Vector{
+			X: vertex.X*cosA - vertex.Y*sinA,
+			Y: vertex.X*sinA + vertex.Y*cosA,
+		}
+	}
+	return vertices
+}
+
+// SegmentShape represents a line segment collision shape, such as a piece of a wall outline.
+type SegmentShape struct {
+	A math.Vector // First end point
+	B math.Vector // Second end point
+}
+
+// GetPosition returns the midpoint of the segment.
+func (s *SegmentShape) GetPosition() math.Vector {
+	return math.Vector{X: (s.A.X + s.B.X) / 2, Y: (s.A.Y + s.B.Y) / 2}
+}
+
+// SetPosition moves the segment so its midpoint is at the position.
+func (s *SegmentShape) SetPosition(position math.Vector) {
+	current := s.GetPosition()
+	dx, dy := position.X-current.X, position.Y-current.Y
+	s.A = math.Vector{X: s.A.X + dx, Y: s.A.Y + dy}
+	s.B = math.Vector{X: s.B.X + dx, Y: s.B.Y + dy}
+}
+
+// GetType returns the type of the shape.
+func (s *SegmentShape) GetType() ShapeType {
+	return ShapeTypeSegment
+}
+
 // Collision represents a collision between two shapes.
 type Collision struct {
 	// ShapeA is the first shape involved in the collision.
@@ -109,7 +174,30 @@ type Collision struct {
 	Point math.Vector
 }
 
+// RayHit describes the first shape hit by a ray.
+type RayHit struct {
+	// Shape is the shape that was hit.
+	Shape Shape
+
+	// Entity is the entity the shape belongs to, or nil for walls.
+	// It is only set by the CollisionManager.
+	Entity interface{}
+
+	// Layer is the collision layer of the shape that was hit.
+	Layer CollisionLayer
+
+	// Point is where the ray hits the shape.
+	Point math.Vector
+
+	// Normal is the unit normal of the surface that was hit, pointing back along the ray.
+	Normal math.Vector
+
+	// Distance is the distance from the origin of the ray to the hit.
+	Distance float64
+}
+
 // CollisionFilter is a function that determines whether two shapes should collide.
+// It is applied after the collision layers, to the pairs whose layers interact.
 type CollisionFilter func(self, other Shape) bool
 
 // CollisionSystem is the interface for collision detection systems.
@@ -125,6 +213,7 @@ type CollisionSystem interface {
 	UpdateShape(id int, shape Shape)
 	
 	// Resolve checks for collisions and returns a list of collisions.
+	// Each colliding pair is reported once, from the side of the shape the filter accepts.
 	// The filter parameter can be used to selectively enable/disable collisions.
 	Resolve(filter CollisionFilter) ([]Collision, error)
 	
@@ -132,6 +221,17 @@ type CollisionSystem interface {
 	// This is used for continuous collision detection.
 	ResolveWithMovement(dx, dy float64, filter CollisionFilter) ([]Collision, error)
 	
+	// SetLayers sets the collision layers of a shape.
+	// Resolve never reports pairs of shapes whose layers do not interact.
+	SetLayers(id int, layers CollisionLayers)
+
+	// GetLayers returns the collision layers of a shape.
+	GetLayers(id int) CollisionLayers
+
+	// Raycast returns the first shape on one of the mask layers that a ray hits,
+	// up to maxDistance from the origin.
+	Raycast(origin, direction math.Vector, maxDistance float64, mask CollisionLayer) (RayHit, bool)
+
 	// GetNearbyShapes returns all shapes within the specified radius of the position.
 	GetNearbyShapes(position math.Vector, radius float64) []Shape
 }
\ No newline at end of file
@@ -75,9 +75,17 @@ func TestCollisionSystem(t *testing.T) {
 	// Add the shapes to the collision system
 	id3 := cs.AddShape(aabb1)
 	id4 := cs.AddShape(aabb2)
+	if id3 == id4 {
+		t.Fatalf("Expected distinct shape IDs, got %d twice", id3)
+	}
+	
+	// Only check the AABBs, the first circle overlaps them
+	aabbFilter := func(self, other Shape) bool {
+		return self.GetType() == ShapeTypeAABB && other.GetType() == ShapeTypeAABB
+	}
 	
 	// Check for collisions
-	collisions, err = cs.Resolve(nil)
+	collisions, err = cs.Resolve(aabbFilter)
 	if err != nil {
 		t.Fatalf("Error resolving collisions: %v", err)
 	}
@@ -101,7 +109,7 @@ func TestCollisionSystem(t *testing.T) {
 	cs.UpdateShape(id4, aabb2)
 	
 	// Check for collisions again
-	collisions, err = cs.Resolve(nil)
+	collisions, err = cs.Resolve(aabbFilter)
 	if err != nil {
 		t.Fatalf("Error resolving collisions: %v", err)
 	}
@@ -1,6 +1,7 @@
 package physics
 
 import (
+	"discoveryx/internal/core/physics/collisions"
 	"discoveryx/internal/utils/math"
 	"testing"
 )
@@ -36,7 +37,7 @@ func TestCheckCircleCollision(t *testing.T) {
 				Position: math.Vector{X: 10, Y: 0},
 				Radius:   5,
 			},
-			expected: true,
+			expected: false, // Touching is not overlapping, as in collisions.Penetration
 		},
 		{
 			name: "Non-overlapping circles",
@@ -136,3 +137,18 @@ func TestCheckCircleRectCollision(t *testing.T) {
 		})
 	}
 }
+
+// TestTouchingShapesAgree tests that the legacy circle check and the separating axis test
+// of the collision system agree that touching circles do not collide
+func TestTouchingShapesAgree(t *testing.T) {
+	for _, distance := range []float64{9.5, 10, 10.5} {
+		legacy := CheckCircleCollision(
+			CircleCollider{Position: math.Vector{X: 0, Y: 0}, Radius: 5},
+			CircleCollider{Position: math.Vector{X: distance, Y: 0}, Radius: 5},
+		)
+		_, sat := collisions.Penetration(collisions.NewCircle(0, 0, 5), collisions.NewCircle(distance, 0, 5))
+		if legacy != sat {
+			t.Errorf("At distance %v the circle check says %v, the separating axis test %v", distance, legacy, sat)
+		}
+	}
+}
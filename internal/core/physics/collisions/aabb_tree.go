@@ -0,0 +1,312 @@
+package collisions
+
+// AABBTree is a dynamic bounding volume tree. Every object is a leaf whose box is the
+// bounding box of the object enlarged by a margin, and every inner node holds the union
+// of its two children. Objects that move within their enlarged box do not change the
+// tree, and queries only descend into nodes whose box overlaps. It implements Broadphase.
+//
+// Leaves are inserted next to the node that enlarges the tree the least, and the tree
+// is rebalanced with rotations, so its height stays logarithmic in the number of objects.
+type AABBTree struct {
+	margin float64              // Enlargement of the leaf boxes
+	root   *treeNode            // Root of the tree, nil if it is empty
+	leaves map[Object]*treeNode // Leaf of each object
+	nextID int                  // Insertion order of the next leaf, used to report pairs once
+	stack  []*treeNode          // Nodes left to visit, kept between queries to avoid allocations
+}
+
+// treeNode is a node of an AABBTree.
+type treeNode struct {
+	box    AABB      // Enlarged bounds for leaves, union of the children for inner nodes
+	parent *treeNode // nil for the root
+	left   *treeNode // nil for leaves
+	right  *treeNode // nil for leaves
+	height int       // 0 for leaves
+
+	object Object // Object of a leaf
+	bounds AABB   // Exact bounds of the object of a leaf
+	id     int    // Insertion order of a leaf
+}
+
+// isLeaf checks if the node holds an object.
+func (n *treeNode) isLeaf() bool {
+	return n.left == nil
+}
+
+// refit updates the box and height of an inner node from its children.
+func (n *treeNode) refit() {
+	n.box = n.left.box.Union(n.right.box)
+	n.height = 1 + maxInt(n.left.height, n.right.height)
+}
+
+// NewAABBTree creates an empty tree. The margin enlarges the box of every object,
+// so small movements do not need to update the tree. A margin of a few frames of
+// movement of the fastest objects works well.
+func NewAABBTree(margin float64) *AABBTree {
+	return &AABBTree{
+		margin: margin,
+		leaves: make(map[Object]*treeNode),
+	}
+}
+
+// Add adds an object to the tree.
+func (t *AABBTree) Add(obj Object) {
+	if _, exists := t.leaves[obj]; exists {
+		t.Update(obj)
+		return
+	}
+
+	bounds := Bounds(obj)
+	leaf := &treeNode{box: bounds.Grow(t.margin), object: obj, bounds: bounds, id: t.nextID}
+	t.nextID++
+	t.leaves[obj] = leaf
+	t.insertLeaf(leaf)
+}
+
+// Remove removes an object from the tree.
+func (t *AABBTree) Remove(obj Object) {
+	leaf, exists := t.leaves[obj]
+	if !exists {
+		return
+	}
+	delete(t.leaves, obj)
+	t.removeLeaf(leaf)
+}
+
+// Update moves an object to its current bounds. The tree only changes when the
+// object left its enlarged box.
+func (t *AABBTree) Update(obj Object) {
+	leaf, exists := t.leaves[obj]
+	if !exists {
+		t.Add(obj)
+		return
+	}
+
+	leaf.bounds = Bounds(obj)
+	if leaf.box.Contains(leaf.bounds) {
+		return
+	}
+
+	t.removeLeaf(leaf)
+	leaf.box = leaf.bounds.Grow(t.margin)
+	t.insertLeaf(leaf)
+}
+
+// Query returns all objects whose bounding boxes overlap the box.
+func (t *AABBTree) Query(box AABB) []Object {
+	var objects []Object
+	t.visit(box, func(leaf *treeNode) {
+		objects = append(objects, leaf.object)
+	})
+	return objects
+}
+
+// Candidates returns all other objects whose bounding boxes overlap the bounding box of obj.
+func (t *AABBTree) Candidates(obj Object) []Object {
+	box := Bounds(obj)
+	if leaf, exists := t.leaves[obj]; exists {
+		box = leaf.bounds
+	}
+
+	var objects []Object
+	t.visit(box, func(leaf *treeNode) {
+		if leaf.object != obj {
+			objects = append(objects, leaf.object)
+		}
+	})
+	return objects
+}
+
+// Pairs returns every pair of objects whose bounding boxes overlap.
+// Each leaf is queried against the tree and only reports leaves inserted after it.
+func (t *AABBTree) Pairs() [][2]Object {
+	var pairs [][2]Object
+	for _, leaf := range t.leaves {
+		t.visit(leaf.bounds, func(other *treeNode) {
+			if other.id > leaf.id {
+				pairs = append(pairs, [2]Object{leaf.object, other.object})
+			}
+		})
+	}
+	return pairs
+}
+
+// Len returns the number of objects in the tree.
+func (t *AABBTree) Len() int {
+	return len(t.leaves)
+}
+
+// Clear removes all objects from the tree.
+func (t *AABBTree) Clear() {
+	t.root = nil
+	t.leaves = make(map[Object]*treeNode)
+	t.nextID = 0
+}
+
+// visit calls fn for every leaf whose object bounds overlap the box.
+func (t *AABBTree) visit(box AABB, fn func(leaf *treeNode)) {
+	if t.root == nil {
+		return
+	}
+
+	stack := append(t.stack[:0], t.root)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if !node.box.Overlaps(box) {
+			continue
+		}
+		if node.isLeaf() {
+			if node.bounds.Overlaps(box) {
+				fn(node)
+			}
+			continue
+		}
+		stack = append(stack, node.left, node.right)
+	}
+	t.stack = stack
+}
+
+// insertLeaf inserts a leaf next to the node that enlarges the tree the least.
+func (t *AABBTree) insertLeaf(leaf *treeNode) {
+	if t.root == nil {
+		t.root = leaf
+		leaf.parent = nil
+		return
+	}
+
+	// Descend while moving the leaf further down is cheaper than pairing it with the current node
+	sibling := t.root
+	for !sibling.isLeaf() {
+		combined := sibling.box.Union(leaf.box)
+		cost := 2 * combined.Perimeter()
+
+		// Every node above the leaf grows by the same amount
+		inheritance := 2 * (combined.Perimeter() - sibling.box.Perimeter())
+		costLeft := descentCost(sibling.left, leaf.box) + inheritance
+		costRight := descentCost(sibling.right, leaf.box) + inheritance
+
+		if cost < costLeft && cost < costRight {
+			break
+		}
+		if costLeft < costRight {
+			sibling = sibling.left
+		} else {
+			sibling = sibling.right
+		}
+	}
+
+	// Replace the sibling with a new node that holds the sibling and the leaf
+	oldParent := sibling.parent
+	parent := &treeNode{parent: oldParent, left: sibling, right: leaf}
+	parent.refit()
+	sibling.parent = parent
+	leaf.parent = parent
+	t.replaceChild(oldParent, sibling, parent)
+
+	t.rebalance(parent)
+}
+
+// descentCost returns the cost of inserting a box below a child node.
+func descentCost(child *treeNode, box AABB) float64 {
+	combined := child.box.Union(box)
+	if child.isLeaf() {
+		return combined.Perimeter()
+	}
+	return combined.Perimeter() - child.box.Perimeter()
+}
+
+// removeLeaf removes a leaf and replaces its parent with its sibling.
+func (t *AABBTree) removeLeaf(leaf *treeNode) {
+	parent := leaf.parent
+	leaf.parent = nil
+	if parent == nil {
+		t.root = nil
+		return
+	}
+
+	sibling := parent.left
+	if sibling == leaf {
+		sibling = parent.right
+	}
+
+	grandparent := parent.parent
+	sibling.parent = grandparent
+	t.replaceChild(grandparent, parent, sibling)
+
+	t.rebalance(grandparent)
+}
+
+// replaceChild puts a node in the place of a child of parent, or makes it the root if parent is nil.
+func (t *AABBTree) replaceChild(parent, child, node *treeNode) {
+	switch {
+	case parent == nil:
+		t.root = node
+	case parent.left == child:
+		parent.left = node
+	default:
+		parent.right = node
+	}
+}
+
+// rebalance refits and balances all nodes from node up to the root.
+func (t *AABBTree) rebalance(node *treeNode) {
+	for node != nil {
+		node = t.balance(node)
+		node.refit()
+		node = node.parent
+	}
+}
+
+// balance rotates the higher child of an inner node up, if its children differ in
+// height by more than one. It returns the node that is now in the place of node.
+func (t *AABBTree) balance(node *treeNode) *treeNode {
+	diff := node.right.height - node.left.height
+	switch {
+	case diff > 1:
+		return t.rotate(node, node.right)
+	case diff < -1:
+		return t.rotate(node, node.left)
+	default:
+		return node
+	}
+}
+
+// rotate moves the child up of node into the place of node. Node becomes a child of up,
+// in place of the lower child of up, which moves under node in place of up.
+func (t *AABBTree) rotate(node, up *treeNode) *treeNode {
+	keep, move := up.left, up.right
+	if move.height > keep.height {
+		keep, move = move, keep
+	}
+
+	// up takes the place of node
+	up.parent = node.parent
+	t.replaceChild(node.parent, node, up)
+
+	// node and the higher child of up are the new children of up
+	up.left, up.right = node, keep
+	node.parent = up
+	keep.parent = up
+
+	// the lower child of up takes the place of up under node
+	if node.left == up {
+		node.left = move
+	} else {
+		node.right = move
+	}
+	move.parent = node
+
+	node.refit()
+	up.refit()
+	return up
+}
+
+// maxInt returns the larger of two integers.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,88 @@
+package collisions
+
+// Broadphase keeps track of where the objects of a space are, so that only objects
+// whose bounding boxes overlap need to be checked with Collides.
+//
+// Three implementations are available: SpatialHash (a uniform grid), AABBTree (a dynamic
+// bounding volume tree) and SweepAndPrune (objects sorted along the X axis). They differ
+// in the cost of moving objects and of queries, so the best one depends on how many
+// objects move and how they are distributed.
+type Broadphase interface {
+	// Add adds an object at its current bounds.
+	Add(obj Object)
+
+	// Remove removes an object.
+	Remove(obj Object)
+
+	// Update moves an object to its current bounds, after it moved or changed its shape.
+	Update(obj Object)
+
+	// Query returns all objects whose bounds overlap the box.
+	Query(box AABB) []Object
+
+	// Candidates returns all objects other than obj whose bounds overlap the bounds of obj.
+	Candidates(obj Object) []Object
+
+	// Pairs returns every pair of objects whose bounds overlap, each pair once.
+	Pairs() [][2]Object
+
+	// Len returns the number of objects.
+	Len() int
+
+	// Clear removes all objects.
+	Clear()
+}
+
+// AABB is an axis-aligned bounding box.
+type AABB struct {
+	MinX, MinY float64
+	MaxX, MaxY float64
+}
+
+// Overlaps checks if two boxes overlap. Boxes that only touch overlap as well,
+// so touching objects are still passed on to Collides.
+func (b AABB) Overlaps(other AABB) bool {
+	return b.MinX <= other.MaxX && b.MaxX >= other.MinX && b.MinY <= other.MaxY && b.MaxY >= other.MinY
+}
+
+// Contains checks if a box lies completely inside this box.
+func (b AABB) Contains(other AABB) bool {
+	return b.MinX <= other.MinX && b.MinY <= other.MinY && b.MaxX >= other.MaxX && b.MaxY >= other.MaxY
+}
+
+// Union returns the smallest box that contains both boxes.
+func (b AABB) Union(other AABB) AABB {
+	return AABB{
+		MinX: min(b.MinX, other.MinX),
+		MinY: min(b.MinY, other.MinY),
+		MaxX: max(b.MaxX, other.MaxX),
+		MaxY: max(b.MaxY, other.MaxY),
+	}
+}
+
+// Grow returns the box enlarged by a margin on every side.
+func (b AABB) Grow(margin float64) AABB {
+	return AABB{MinX: b.MinX - margin, MinY: b.MinY - margin, MaxX: b.MaxX + margin, MaxY: b.MaxY + margin}
+}
+
+// Perimeter returns the perimeter of the box, used as its cost in the AABB tree.
+func (b AABB) Perimeter() float64 {
+	return 2 * ((b.MaxX - b.MinX) + (b.MaxY - b.MinY))
+}
+
+// Bounds returns the bounding box of an object.
+// Objects of unknown types are treated as a point at their position.
+func Bounds(obj Object) AABB {
+	switch o := obj.(type) {
+	case *Circle:
+		return AABB{MinX: o.X - o.Radius, MinY: o.Y - o.Radius, MaxX: o.X + o.Radius, MaxY: o.Y + o.Radius}
+	case *Rectangle:
+		return AABB{MinX: o.X, MinY: o.Y, MaxX: o.X + o.W, MaxY: o.Y + o.H}
+	case boundedObject:
+		minX, minY, maxX, maxY := o.bounds()
+		return AABB{MinX: minX, MinY: minY, MaxX: maxX, MaxY: maxY}
+	default:
+		x, y := obj.GetX(), obj.GetY()
+		return AABB{MinX: x, MinY: y, MaxX: x, MaxY: y}
+	}
+}
@@ -1,6 +1,8 @@
 // Package collisions provides a minimal implementation of the ebiten-collisions package.
 package collisions
 
+import "math"
+
 // Object is the interface for all collision objects.
 type Object interface {
 	// GetX returns the X coordinate of the object.
@@ -20,9 +22,19 @@ type Object interface {
 }
 
 // SpatialHash represents a spatial partitioning system for efficient collision detection.
+// It divides the world into a uniform grid of cells and stores every object in all cells
+// its bounding box overlaps. It implements Broadphase.
 type SpatialHash struct {
 	cellSize float64
-	cells    map[[2]int][]Object // Key is (cellX, cellY)
+	cells    map[[2]int][]Object  // Key is (cellX, cellY)
+	entries  map[Object]hashEntry // Where each object is stored
+}
+
+// hashEntry is the bounding box and cell range an object was stored with.
+type hashEntry struct {
+	bounds  AABB
+	minCell [2]int
+	maxCell [2]int
 }
 
 // NewSpatialHash creates a new spatial hash with the specified cell size.
@@ -30,197 +42,199 @@ func NewSpatialHash(cellSize float64) *SpatialHash {
 	return &SpatialHash{
 		cellSize: cellSize,
 		cells:    make(map[[2]int][]Object),
+		entries:  make(map[Object]hashEntry),
 	}
 }
 
 // getCellCoords returns the cell coordinates for a given position.
 func (sh *SpatialHash) getCellCoords(x, y float64) [2]int {
-	cellX := int(x / sh.cellSize)
-	cellY := int(y / sh.cellSize)
+	cellX := int(math.Floor(x / sh.cellSize))
+	cellY := int(math.Floor(y / sh.cellSize))
 	return [2]int{cellX, cellY}
 }
 
-// getObjectCells returns all cell coordinates that an object occupies.
-// This handles objects that span multiple cells based on their type.
-func (sh *SpatialHash) getObjectCells(obj Object) [][2]int {
-	var cells [][2]int
+// newEntry returns the cells a bounding box overlaps.
+func (sh *SpatialHash) newEntry(bounds AABB) hashEntry {
+	return hashEntry{
+		bounds:  bounds,
+		minCell: sh.getCellCoords(bounds.MinX, bounds.MinY),
+		maxCell: sh.getCellCoords(bounds.MaxX, bounds.MaxY),
+	}
+}
 
-	switch o := obj.(type) {
-	case *Circle:
-		// For circles, we need to check cells that the circle might overlap
-		// Get the cell coordinates for the top-left and bottom-right corners of the bounding box
-		minX := o.X - o.Radius
-		minY := o.Y - o.Radius
-		maxX := o.X + o.Radius
-		maxY := o.Y + o.Radius
-
-		// Calculate the cell coordinates for the corners
-		minCellX := int(minX / sh.cellSize)
-		minCellY := int(minY / sh.cellSize)
-		maxCellX := int(maxX / sh.cellSize)
-		maxCellY := int(maxY / sh.cellSize)
-
-		// Add all cells in the range
-		for x := minCellX; x <= maxCellX; x++ {
-			for y := minCellY; y <= maxCellY; y++ {
-				cells = append(cells, [2]int{x, y})
-			}
-		}
+// Add adds an object to the cells its bounding box overlaps.
+func (sh *SpatialHash) Add(obj Object) {
+	entry := sh.newEntry(Bounds(obj))
+	sh.entries[obj] = entry
+	sh.addToCells(obj, entry)
+}
 
-	case *Rectangle:
-		// For rectangles, we need to check cells that the rectangle might overlap
-		// Get the cell coordinates for the top-left and bottom-right corners
-		minCellX := int(o.X / sh.cellSize)
-		minCellY := int(o.Y / sh.cellSize)
-		maxCellX := int((o.X + o.W) / sh.cellSize)
-		maxCellY := int((o.Y + o.H) / sh.cellSize)
-
-		// Add all cells in the range
-		for x := minCellX; x <= maxCellX; x++ {
-			for y := minCellY; y <= maxCellY; y++ {
-				cells = append(cells, [2]int{x, y})
-			}
-		}
+// Remove removes an object from the spatial hash.
+func (sh *SpatialHash) Remove(obj Object) {
+	entry, exists := sh.entries[obj]
+	if !exists {
+		return
+	}
+	delete(sh.entries, obj)
+	sh.removeFromCells(obj, entry)
+}
 
-	default:
-		// For other object types, just use the center point
-		cells = append(cells, sh.getCellCoords(obj.GetX(), obj.GetY()))
+// Update moves an object to the cells of its current bounding box.
+// Objects that stay within the same cells are not moved.
+func (sh *SpatialHash) Update(obj Object) {
+	old, exists := sh.entries[obj]
+	if !exists {
+		sh.Add(obj)
+		return
 	}
 
-	return cells
+	entry := sh.newEntry(Bounds(obj))
+	sh.entries[obj] = entry
+
+	// If the cells haven't changed, no need to update
+	if entry.minCell == old.minCell && entry.maxCell == old.maxCell {
+		return
+	}
+
+	sh.removeFromCells(obj, old)
+	sh.addToCells(obj, entry)
 }
 
-// addObject adds an object to the spatial hash.
-func (sh *SpatialHash) addObject(obj Object) {
-	cells := sh.getObjectCells(obj)
-	for _, cell := range cells {
-		sh.cells[cell] = append(sh.cells[cell], obj)
+// addToCells adds an object to all cells of an entry.
+func (sh *SpatialHash) addToCells(obj Object, entry hashEntry) {
+	for x := entry.minCell[0]; x <= entry.maxCell[0]; x++ {
+		for y := entry.minCell[1]; y <= entry.maxCell[1]; y++ {
+			cell := [2]int{x, y}
+			sh.cells[cell] = append(sh.cells[cell], obj)
+		}
 	}
 }
 
-// removeObject removes an object from the spatial hash.
-func (sh *SpatialHash) removeObject(obj Object) {
-	cells := sh.getObjectCells(obj)
-	for _, cell := range cells {
-		objects := sh.cells[cell]
-		for i, o := range objects {
-			if o == obj {
-				// Remove the object from this cell
-				sh.cells[cell] = append(objects[:i], objects[i+1:]...)
-				break
+// removeFromCells removes an object from all cells of an entry.
+func (sh *SpatialHash) removeFromCells(obj Object, entry hashEntry) {
+	for x := entry.minCell[0]; x <= entry.maxCell[0]; x++ {
+		for y := entry.minCell[1]; y <= entry.maxCell[1]; y++ {
+			cell := [2]int{x, y}
+			objects := sh.cells[cell]
+			for i, o := range objects {
+				if o == obj {
+					// Remove the object from this cell, keeping the order of the others
+					sh.cells[cell] = append(objects[:i], objects[i+1:]...)
+					break
+				}
+			}
+			// If the cell is now empty, remove it from the map
+			if len(sh.cells[cell]) == 0 {
+				delete(sh.cells, cell)
 			}
-		}
-		// If the cell is now empty, remove it from the map
-		if len(sh.cells[cell]) == 0 {
-			delete(sh.cells, cell)
 		}
 	}
 }
 
-// updateObject updates an object's position in the spatial hash.
-func (sh *SpatialHash) updateObject(obj Object, oldX, oldY float64) {
-	// Store the original position
-	originalX, originalY := obj.GetX(), obj.GetY()
-
-	// Temporarily set the object's position to the old position
-	obj.SetX(oldX)
-	obj.SetY(oldY)
-
-	// Get the old cells
-	oldCells := sh.getObjectCells(obj)
+// Query returns all objects whose bounding boxes overlap the box.
+func (sh *SpatialHash) Query(box AABB) []Object {
+	return sh.query(box, nil)
+}
 
-	// Restore the object's position
-	obj.SetX(originalX)
-	obj.SetY(originalY)
+// Candidates returns all other objects whose bounding boxes overlap the bounding box of obj.
+func (sh *SpatialHash) Candidates(obj Object) []Object {
+	box := Bounds(obj)
+	if entry, exists := sh.entries[obj]; exists {
+		box = entry.bounds
+	}
+	return sh.query(box, obj)
+}
 
-	// Get the new cells
-	newCells := sh.getObjectCells(obj)
+// query returns all objects except skip whose bounding boxes overlap the box.
+func (sh *SpatialHash) query(box AABB, skip Object) []Object {
+	var objects []Object
+	minCell := sh.getCellCoords(box.MinX, box.MinY)
+	maxCell := sh.getCellCoords(box.MaxX, box.MaxY)
+	single := minCell == maxCell
 
-	// If the cells haven't changed, no need to update
-	if len(oldCells) == len(newCells) {
-		allSame := true
-		for i, oldCell := range oldCells {
-			if oldCell != newCells[i] {
-				allSame = false
-				break
-			}
-		}
-		if allSame {
-			return
-		}
+	// Objects that span multiple cells are only added once
+	var added map[Object]bool
+	if !single {
+		added = make(map[Object]bool)
 	}
 
-	// Remove from old cells
-	for _, cell := range oldCells {
-		for i, o := range sh.cells[cell] {
-			if o == obj {
-				sh.cells[cell] = append(sh.cells[cell][:i], sh.cells[cell][i+1:]...)
-				break
+	for x := minCell[0]; x <= maxCell[0]; x++ {
+		for y := minCell[1]; y <= maxCell[1]; y++ {
+			for _, o := range sh.cells[[2]int{x, y}] {
+				if o == skip || (!single && added[o]) || !sh.entries[o].bounds.Overlaps(box) {
+					continue
+				}
+				objects = append(objects, o)
+				if !single {
+					added[o] = true
+				}
 			}
 		}
-		// If the cell is now empty, remove it from the map
-		if len(sh.cells[cell]) == 0 {
-			delete(sh.cells, cell)
-		}
 	}
 
-	// Add to new cells
-	for _, cell := range newCells {
-		sh.cells[cell] = append(sh.cells[cell], obj)
-	}
-}
-
-// getPotentialCollisions returns all objects that could potentially collide with the given object.
-func (sh *SpatialHash) getPotentialCollisions(obj Object) []Object {
-	var potentialCollisions []Object
-
-	// Get all cells that the object spans
-	objCells := sh.getObjectCells(obj)
-
-	// Create a map to track which objects we've already added
-	// This prevents adding the same object multiple times if it spans multiple cells
-	addedObjects := make(map[Object]bool)
-
-	// Check all cells that the object spans and their neighboring cells
-	for _, cell := range objCells {
-		// Check the cell and all neighboring cells
-		for dx := -1; dx <= 1; dx++ {
-			for dy := -1; dy <= 1; dy++ {
-				neighborCell := [2]int{cell[0] + dx, cell[1] + dy}
-				for _, o := range sh.cells[neighborCell] {
-					// Skip the object itself and objects we've already added
-					if o != obj && !addedObjects[o] {
-						potentialCollisions = append(potentialCollisions, o)
-						addedObjects[o] = true
-					}
+	return objects
+}
+
+// Pairs returns every pair of objects whose bounding boxes overlap.
+// Objects that share several cells are reported only in the cell that contains
+// the corner of their overlap, so every pair is found once.
+func (sh *SpatialHash) Pairs() [][2]Object {
+	var pairs [][2]Object
+	for cell, objects := range sh.cells {
+		for i, a := range objects {
+			boundsA := sh.entries[a].bounds
+			for _, b := range objects[i+1:] {
+				boundsB := sh.entries[b].bounds
+				if !boundsA.Overlaps(boundsB) {
+					continue
+				}
+				if sh.getCellCoords(max(boundsA.MinX, boundsB.MinX), max(boundsA.MinY, boundsB.MinY)) == cell {
+					pairs = append(pairs, [2]Object{a, b})
 				}
 			}
 		}
 	}
+	return pairs
+}
+
+// Len returns the number of objects in the spatial hash.
+func (sh *SpatialHash) Len() int {
+	return len(sh.entries)
+}
 
-	return potentialCollisions
+// Clear removes all objects from the spatial hash.
+func (sh *SpatialHash) Clear() {
+	sh.cells = make(map[[2]int][]Object)
+	sh.entries = make(map[Object]hashEntry)
 }
 
 // Space represents a collision space that contains objects.
 type Space struct {
-	cellSize float64
-	objects  []Object
-	spatialHash *SpatialHash
+	cellSize   float64
+	objects    []Object
+	broadphase Broadphase
 }
 
 // NewSpace creates a new collision space with the specified cell size.
+// Nearby objects are found with a SpatialHash of that cell size.
 func NewSpace(cellSize float64) *Space {
+	return NewSpaceWithBroadphase(cellSize, NewSpatialHash(cellSize))
+}
+
+// NewSpaceWithBroadphase creates a new collision space that finds nearby objects with the given broadphase.
+// Rays are searched in pieces of the cell size.
+func NewSpaceWithBroadphase(cellSize float64, broadphase Broadphase) *Space {
 	return &Space{
-		cellSize: cellSize,
-		objects:  make([]Object, 0),
-		spatialHash: NewSpatialHash(cellSize),
+		cellSize:   cellSize,
+		objects:    make([]Object, 0),
+		broadphase: broadphase,
 	}
 }
 
 // Add adds an object to the space.
 func (s *Space) Add(obj Object) {
 	s.objects = append(s.objects, obj)
-	s.spatialHash.addObject(obj)
+	s.broadphase.Add(obj)
 }
 
 // Remove removes an object from the space.
@@ -228,16 +242,16 @@ func (s *Space) Remove(obj Object) {
 	for i, o := range s.objects {
 		if o == obj {
 			s.objects = append(s.objects[:i], s.objects[i+1:]...)
-			s.spatialHash.removeObject(obj)
+			s.broadphase.Remove(obj)
 			return
 		}
 	}
 }
 
-// UpdateShape updates an object's position in the space.
-// This should be called whenever an object's position changes.
-func (s *Space) UpdateShape(obj Object, oldX, oldY float64) {
-	s.spatialHash.updateObject(obj, oldX, oldY)
+// UpdateShape updates an object in the broadphase of the space.
+// This should be called whenever an object's position or shape changes.
+func (s *Space) UpdateShape(obj Object) {
+	s.broadphase.Update(obj)
 }
 
 // Update updates the space.
@@ -249,8 +263,8 @@ func (s *Space) Update() {
 func (s *Space) GetCollisions(obj Object) []Object {
 	var collisions []Object
 
-	// Get potential collisions from the spatial hash
-	potentialCollisions := s.spatialHash.getPotentialCollisions(obj)
+	// Get potential collisions from the broadphase
+	potentialCollisions := s.broadphase.Candidates(obj)
 
 	// Check actual collisions
 	for _, o := range potentialCollisions {
@@ -262,6 +276,17 @@ func (s *Space) GetCollisions(obj Object) []Object {
 	return collisions
 }
 
+// GetCollidingPairs returns every pair of objects in the space that collide, each pair once.
+func (s *Space) GetCollidingPairs() [][2]Object {
+	var collisions [][2]Object
+	for _, pair := range s.broadphase.Pairs() {
+		if pair[0].Collides(pair[1]) {
+			collisions = append(collisions, pair)
+		}
+	}
+	return collisions
+}
+
 // Circle represents a circular collision object.
 type Circle struct {
 	X      float64
@@ -309,6 +334,9 @@ func (c *Circle) Collides(other Object) bool {
 
 		// If the distance is less than the circle's radius, there is a collision
 		return distance < c.Radius*c.Radius
+	case *Polygon, *Segment:
+		_, colliding := Penetration(c, other)
+		return colliding
 	default:
 		return false
 	}
@@ -369,6 +397,9 @@ func (r *Rectangle) Collides(other Object) bool {
 	case *Rectangle:
 		// Check if the rectangles overlap
 		return r.X < o.X+o.W && r.X+r.W > o.X && r.Y < o.Y+o.H && r.Y+r.H > o.Y
+	case *Polygon, *Segment:
+		_, colliding := Penetration(r, other)
+		return colliding
 	default:
 		return false
 	}
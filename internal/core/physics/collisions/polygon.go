@@ -0,0 +1,143 @@
+package collisions
+
+// Point represents a 2D point of a polygon or segment.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// boundedObject is an object that reports its bounding box to the spatial hash.
+type boundedObject interface {
+	Object
+	bounds() (minX, minY, maxX, maxY float64)
+}
+
+// Polygon represents a convex polygon collision object.
+// The vertices are relative to the position and must be in order around the polygon.
+type Polygon struct {
+	X        float64
+	Y        float64
+	Vertices []Point
+}
+
+// GetX returns the X coordinate of the polygon.
+func (p *Polygon) GetX() float64 {
+	return p.X
+}
+
+// GetY returns the Y coordinate of the polygon.
+func (p *Polygon) GetY() float64 {
+	return p.Y
+}
+
+// SetX sets the X coordinate of the polygon.
+func (p *Polygon) SetX(x float64) {
+	p.X = x
+}
+
+// SetY sets the Y coordinate of the polygon.
+func (p *Polygon) SetY(y float64) {
+	p.Y = y
+}
+
+// Collides checks if this polygon collides with another object.
+func (p *Polygon) Collides(other Object) bool {
+	_, colliding := Penetration(p, other)
+	return colliding
+}
+
+// bounds returns the bounding box of the polygon.
+func (p *Polygon) bounds() (minX, minY, maxX, maxY float64) {
+	return pointBounds(p.points())
+}
+
+// points returns the vertices of the polygon in absolute coordinates.
+func (p *Polygon) points() []Point {
+	points := make([]Point, len(p.Vertices))
+	for i, vertex := range p.Vertices {
+		points[i] = Point{X: p.X + vertex.X, Y: p.Y + vertex.Y}
+	}
+	return points
+}
+
+// NewPolygon creates a new convex polygon with the specified position and vertices relative to it.
+func NewPolygon(x, y float64, vertices []Point) *Polygon {
+	return &Polygon{
+		X:        x,
+		Y:        y,
+		Vertices: vertices,
+	}
+}
+
+// Segment represents a line segment collision object.
+// The position is the midpoint, and the end points are relative to it.
+type Segment struct {
+	X float64
+	Y float64
+	A Point
+	B Point
+}
+
+// GetX returns the X coordinate of the segment.
+func (s *Segment) GetX() float64 {
+	return s.X
+}
+
+// GetY returns the Y coordinate of the segment.
+func (s *Segment) GetY() float64 {
+	return s.Y
+}
+
+// SetX sets the X coordinate of the segment.
+func (s *Segment) SetX(x float64) {
+	s.X = x
+}
+
+// SetY sets the Y coordinate of the segment.
+func (s *Segment) SetY(y float64) {
+	s.Y = y
+}
+
+// Collides checks if this segment collides with another object.
+func (s *Segment) Collides(other Object) bool {
+	_, colliding := Penetration(s, other)
+	return colliding
+}
+
+// bounds returns the bounding box of the segment.
+func (s *Segment) bounds() (minX, minY, maxX, maxY float64) {
+	return pointBounds(s.points())
+}
+
+// points returns the end points of the segment in absolute coordinates.
+func (s *Segment) points() []Point {
+	return []Point{
+		{X: s.X + s.A.X, Y: s.Y + s.A.Y},
+		{X: s.X + s.B.X, Y: s.Y + s.B.Y},
+	}
+}
+
+// NewSegment creates a new line segment between two end points.
+func NewSegment(x1, y1, x2, y2 float64) *Segment {
+	x, y := (x1+x2)/2, (y1+y2)/2
+	return &Segment{
+		X: x,
+		Y: y,
+		A: Point{X: x1 - x, Y: y1 - y},
+		B: Point{X: x2 - x, Y: y2 - y},
+	}
+}
+
+// pointBounds returns the bounding box of a list of points.
+func pointBounds(points []Point) (minX, minY, maxX, maxY float64) {
+	if len(points) == 0 {
+		return 0, 0, 0, 0
+	}
+	minX, minY = points[0].X, points[0].Y
+	maxX, maxY = minX, minY
+	for _, point := range points[1:] {
+		minX, maxX = min(minX, point.X), max(maxX, point.X)
+		minY, maxY = min(minY, point.Y), max(maxY, point.Y)
+	}
+	return minX, minY, maxX, maxY
+}
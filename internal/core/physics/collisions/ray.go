@@ -0,0 +1,109 @@
+package collisions
+
+import (
+	"math"
+	"sort"
+)
+
+// RayHit describes where a ray hits an object.
+type RayHit struct {
+	// Object is the object that was hit
+	Object Object
+
+	// Distance is the distance from the origin of the ray to the hit
+	Distance float64
+
+	// X and Y are the point where the ray hits the object
+	X float64
+	Y float64
+
+	// NormalX and NormalY form the unit normal of the surface that was hit, pointing back along the ray.
+	// Rays that start inside the object have the reversed ray direction as the normal.
+	NormalX float64
+	NormalY float64
+}
+
+// Raycast finds where a ray hits an object. The direction does not need to be normalized.
+// Only hits up to maxDistance from the origin are reported.
+func Raycast(obj Object, originX, originY, dirX, dirY, maxDistance float64) (RayHit, bool) {
+	length := math.Sqrt(dirX*dirX + dirY*dirY)
+	if length == 0 || maxDistance <= 0 {
+		return RayHit{}, false
+	}
+	dirX, dirY = dirX/length, dirY/length
+
+	// A ray is a point that is swept along the direction
+	point := NewCircle(originX, originY, 0)
+	contact, hit := Sweep(point, dirX*maxDistance, dirY*maxDistance, obj)
+	if !hit {
+		return RayHit{}, false
+	}
+
+	result := RayHit{
+		Object:   obj,
+		Distance: contact.Time * maxDistance,
+		NormalX:  contact.NormalX,
+		NormalY:  contact.NormalY,
+	}
+	if contact.Depth > 0 {
+		// Started inside the object
+		result.NormalX, result.NormalY = -dirX, -dirY
+	}
+	result.X = originX + dirX*result.Distance
+	result.Y = originY + dirY*result.Distance
+	return result, true
+}
+
+// GetObjectsAlongRay returns all objects near a ray, up to maxDistance from the origin.
+// The objects are candidates for Raycast; they are not checked for hits.
+func (s *Space) GetObjectsAlongRay(originX, originY, dirX, dirY, maxDistance float64) []Object {
+	length := math.Sqrt(dirX*dirX + dirY*dirY)
+	if length == 0 {
+		return nil
+	}
+	dirX, dirY = dirX/length, dirY/length
+
+	// Query the broadphase with the bounding box of every piece of the ray, so a
+	// long diagonal ray does not collect everything in its overall bounding box
+	var objects []Object
+	added := make(map[Object]bool)
+	pieces := int(math.Ceil(maxDistance / s.cellSize))
+	for piece := 0; piece < pieces; piece++ {
+		from := float64(piece) * s.cellSize
+		to := math.Min(from+s.cellSize, maxDistance)
+		x1, y1 := originX+dirX*from, originY+dirY*from
+		x2, y2 := originX+dirX*to, originY+dirY*to
+
+		box := AABB{MinX: math.Min(x1, x2), MinY: math.Min(y1, y2), MaxX: math.Max(x1, x2), MaxY: math.Max(y1, y2)}
+		for _, o := range s.broadphase.Query(box) {
+			if !added[o] {
+				objects = append(objects, o)
+				added[o] = true
+			}
+		}
+	}
+
+	return objects
+}
+
+// Raycast finds the first object in the space that a ray hits, up to maxDistance from the origin.
+// The filter decides which objects can be hit; a nil filter accepts all objects.
+func (s *Space) Raycast(originX, originY, dirX, dirY, maxDistance float64, filter func(Object) bool) (RayHit, bool) {
+	var hits []RayHit
+	for _, o := range s.GetObjectsAlongRay(originX, originY, dirX, dirY, maxDistance) {
+		if filter != nil && !filter(o) {
+			continue
+		}
+		if hit, ok := Raycast(o, originX, originY, dirX, dirY, maxDistance); ok {
+			hits = append(hits, hit)
+		}
+	}
+
+	if len(hits) == 0 {
+		return RayHit{}, false
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Distance < hits[j].Distance
+	})
+	return hits[0], true
+}
@@ -0,0 +1,176 @@
+package collisions
+
+import "math"
+
+// Contact describes how two overlapping objects touch.
+type Contact struct {
+	// NormalX and NormalY form the unit vector that pushes the first object out of the second.
+	NormalX float64
+	NormalY float64
+
+	// Depth is the distance the first object has to move along the normal to separate.
+	Depth float64
+
+	// PointX and PointY are the deepest point of the first object inside the second.
+	PointX float64
+	PointY float64
+}
+
+// convex is an object in the form used by the separating axis test:
+// the corners of a convex polygon (one for circles, two for segments)
+// and a radius that is added around them.
+type convex struct {
+	points []Point
+	radius float64
+}
+
+// toConvex converts a collision object for the separating axis test.
+func toConvex(obj Object) (convex, bool) {
+	switch o := obj.(type) {
+	case *Circle:
+		return convex{points: []Point{{X: o.X, Y: o.Y}}, radius: o.Radius}, true
+	case *Rectangle:
+		return convex{points: []Point{
+			{X: o.X, Y: o.Y},
+			{X: o.X + o.W, Y: o.Y},
+			{X: o.X + o.W, Y: o.Y + o.H},
+			{X: o.X, Y: o.Y + o.H},
+		}}, true
+	case *Polygon:
+		return convex{points: o.points()}, len(o.Vertices) > 0
+	case *Segment:
+		return convex{points: o.points()}, true
+	default:
+		return convex{}, false
+	}
+}
+
+// Penetration checks two objects for overlap with the separating axis theorem.
+// If they overlap, it returns the contact with the smallest translation that
+// separates the first object from the second. Touching objects do not overlap.
+func Penetration(a, b Object) (Contact, bool) {
+	shapeA, okA := toConvex(a)
+	shapeB, okB := toConvex(b)
+	if !okA || !okB {
+		return Contact{}, false
+	}
+
+	var contact Contact
+	contact.Depth = math.Inf(1)
+
+	for _, axis := range separatingAxes(shapeA, shapeB) {
+		minA, maxA := shapeA.project(axis)
+		minB, maxB := shapeB.project(axis)
+		if maxA <= minB || maxB <= minA {
+			return Contact{}, false
+		}
+
+		// Move the first object forward or backward along the axis, whichever is shorter
+		forward, backward := maxB-minA, maxA-minB
+		if forward < contact.Depth {
+			contact.NormalX, contact.NormalY, contact.Depth = axis.X, axis.Y, forward
+		}
+		if backward < contact.Depth {
+			contact.NormalX, contact.NormalY, contact.Depth = -axis.X, -axis.Y, backward
+		}
+	}
+
+	if math.IsInf(contact.Depth, 1) {
+		return Contact{}, false
+	}
+
+	point := shapeA.support(Point{X: -contact.NormalX, Y: -contact.NormalY})
+	contact.PointX, contact.PointY = point.X, point.Y
+	return contact, true
+}
+
+// separatingAxes returns the unit axes that have to be tested for two convex objects:
+// the edge normals of both, the direction of segments (to separate collinear segments),
+// and for circles the direction to the closest corner of the other object.
+func separatingAxes(a, b convex) []Point {
+	var axes []Point
+	for _, shape := range []convex{a, b} {
+		switch len(shape.points) {
+		case 1:
+		case 2:
+			dx := shape.points[1].X - shape.points[0].X
+			dy := shape.points[1].Y - shape.points[0].Y
+			axes = appendAxis(axes, dx, dy)
+			axes = appendAxis(axes, -dy, dx)
+		default:
+			for i, point := range shape.points {
+				next := shape.points[(i+1)%len(shape.points)]
+				axes = appendAxis(axes, -(next.Y - point.Y), next.X-point.X)
+			}
+		}
+	}
+
+	for _, pair := range [][2]convex{{a, b}, {b, a}} {
+		circle, other := pair[0], pair[1]
+		if len(circle.points) != 1 {
+			continue
+		}
+		closest := other.closestPoint(circle.points[0])
+		axes = appendAxis(axes, closest.X-circle.points[0].X, closest.Y-circle.points[0].Y)
+	}
+
+	return axes
+}
+
+// appendAxis normalizes an axis and appends it, unless it has no length.
+func appendAxis(axes []Point, x, y float64) []Point {
+	length := math.Sqrt(x*x + y*y)
+	if length == 0 {
+		return axes
+	}
+	return append(axes, Point{X: x / length, Y: y / length})
+}
+
+// project returns the interval covered by the object on an axis.
+func (c convex) project(axis Point) (float64, float64) {
+	minimum, maximum := math.Inf(1), math.Inf(-1)
+	for _, point := range c.points {
+		projection := point.X*axis.X + point.Y*axis.Y
+		minimum = min(minimum, projection)
+		maximum = max(maximum, projection)
+	}
+	return minimum - c.radius, maximum + c.radius
+}
+
+// support returns the point of the object that lies farthest in a direction.
+// If an edge faces the direction, the middle of the edge is returned.
+func (c convex) support(direction Point) Point {
+	const epsilon = 1e-9
+
+	best := math.Inf(-1)
+	for _, point := range c.points {
+		best = max(best, point.X*direction.X+point.Y*direction.Y)
+	}
+
+	var sum Point
+	count := 0
+	for _, point := range c.points {
+		if point.X*direction.X+point.Y*direction.Y >= best-epsilon {
+			sum.X += point.X
+			sum.Y += point.Y
+			count++
+		}
+	}
+	return Point{
+		X: sum.X/float64(count) + direction.X*c.radius,
+		Y: sum.Y/float64(count) + direction.Y*c.radius,
+	}
+}
+
+// closestPoint returns the corner of the object closest to a point.
+func (c convex) closestPoint(target Point) Point {
+	closest := c.points[0]
+	closestDistance := math.Inf(1)
+	for _, point := range c.points {
+		dx, dy := point.X-target.X, point.Y-target.Y
+		if distance := dx*dx + dy*dy; distance < closestDistance {
+			closest, closestDistance = point, distance
+		}
+	}
+	return closest
+}
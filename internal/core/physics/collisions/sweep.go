@@ -0,0 +1,173 @@
+package collisions
+
+import (
+	"math"
+	"sort"
+)
+
+// touchEpsilon is the overlap depth below which objects are considered touching.
+// Objects that only touch may move apart or slide along each other.
+const touchEpsilon = 1e-6
+
+// SweepContact describes when a moving object first touches another object.
+type SweepContact struct {
+	// Time is the fraction of the movement (0-1) at which the objects touch.
+	// It is 0 if the objects already overlap at the start.
+	Time float64
+
+	// NormalX and NormalY form the unit vector that points from the second object to the first.
+	NormalX float64
+	NormalY float64
+
+	// Depth is the penetration depth if the objects already overlap at the start.
+	Depth float64
+}
+
+// Sweep moves the first object by (dx, dy) and finds the first time it touches the second object.
+// The objects keep their orientation during the movement.
+//
+// The test works in the space of offsets of the first object: the offsets at which both
+// objects overlap form the convex hull of all corner differences, rounded by the radii.
+// The movement is a ray from the origin, and the impact is where it enters that shape.
+func Sweep(a Object, dx, dy float64, b Object) (SweepContact, bool) {
+	shapeA, okA := toConvex(a)
+	shapeB, okB := toConvex(b)
+	if !okA || !okB {
+		return SweepContact{}, false
+	}
+
+	// Objects that already overlap are hit immediately
+	if contact, overlapping := Penetration(a, b); overlapping && contact.Depth > touchEpsilon {
+		return SweepContact{NormalX: contact.NormalX, NormalY: contact.NormalY, Depth: contact.Depth}, true
+	}
+
+	var differences []Point
+	for _, pointB := range shapeB.points {
+		for _, pointA := range shapeA.points {
+			differences = append(differences, Point{X: pointB.X - pointA.X, Y: pointB.Y - pointA.Y})
+		}
+	}
+	hull := convexHull(differences)
+	radius := shapeA.radius + shapeB.radius
+
+	best := SweepContact{Time: math.Inf(1)}
+
+	// The ray enters through an edge of the hull, moved outwards by the radius
+	center := centroid(hull)
+	edges := len(hull)
+	if edges < 3 {
+		edges-- // A single corner has no edge, and two corners only one
+	}
+	for i := 0; i < edges; i++ {
+		start, end := hull[i], hull[(i+1)%len(hull)]
+		edgeX, edgeY := end.X-start.X, end.Y-start.Y
+		length := math.Sqrt(edgeX*edgeX + edgeY*edgeY)
+
+		// Both sides of a degenerate hull, the outer side of a real one
+		normals := []Point{{X: edgeY / length, Y: -edgeX / length}}
+		if len(hull) == 2 {
+			normals = append(normals, Point{X: -edgeY / length, Y: edgeX / length})
+		} else if (start.X-center.X)*normals[0].X+(start.Y-center.Y)*normals[0].Y < 0 {
+			normals[0] = Point{X: -normals[0].X, Y: -normals[0].Y}
+		}
+
+		for _, normal := range normals {
+			approach := dx*normal.X + dy*normal.Y
+			offset := start.X*normal.X + start.Y*normal.Y + radius
+			if approach >= 0 || offset > touchEpsilon {
+				continue // Moving away from the edge, or already behind it
+			}
+
+			t := math.Max(offset/approach, 0)
+			hitX, hitY := dx*t, dy*t
+			along := ((hitX-start.X)*edgeX + (hitY-start.Y)*edgeY) / (length * length)
+			if t <= 1 && along >= 0 && along <= 1 && t < best.Time {
+				best = SweepContact{Time: t, NormalX: normal.X, NormalY: normal.Y}
+			}
+		}
+	}
+
+	// The ray enters through a rounded corner
+	if radius > 0 {
+		a := dx*dx + dy*dy
+		for _, corner := range hull {
+			b := -2 * (corner.X*dx + corner.Y*dy)
+			c := corner.X*corner.X + corner.Y*corner.Y - radius*radius
+			discriminant := b*b - 4*a*c
+			if a == 0 || discriminant < 0 {
+				continue
+			}
+
+			t := (-b - math.Sqrt(discriminant)) / (2 * a)
+			if t < 0 && c >= 0 {
+				continue // The corner is behind the start
+			}
+			t = math.Max(t, 0)
+			normalX, normalY := dx*t-corner.X, dy*t-corner.Y
+			if length := math.Sqrt(normalX*normalX + normalY*normalY); length > 0 {
+				normalX, normalY = normalX/length, normalY/length
+			}
+			if t <= 1 && t < best.Time && normalX*dx+normalY*dy < 0 {
+				best = SweepContact{Time: t, NormalX: normalX, NormalY: normalY}
+			}
+		}
+	}
+
+	if math.IsInf(best.Time, 1) {
+		return SweepContact{}, false
+	}
+	return best, true
+}
+
+// convexHull returns the corners of the convex hull of the points, in order around the hull.
+// Duplicate points are removed, so the hull may have fewer than three corners.
+func convexHull(points []Point) []Point {
+	sorted := append([]Point(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+
+	unique := sorted[:0]
+	for _, point := range sorted {
+		if len(unique) == 0 || point != unique[len(unique)-1] {
+			unique = append(unique, point)
+		}
+	}
+	if len(unique) < 3 {
+		return unique
+	}
+
+	cross := func(o, a, b Point) float64 {
+		return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+	}
+
+	// Andrew's monotone chain: lower and upper half of the hull
+	hull := make([]Point, 0, 2*len(unique))
+	for _, point := range unique {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], point) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, point)
+	}
+	lower := len(hull) + 1
+	for i := len(unique) - 2; i >= 0; i-- {
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], unique[i]) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, unique[i])
+	}
+	return hull[:len(hull)-1]
+}
+
+// centroid returns the average of the points.
+func centroid(points []Point) Point {
+	var sum Point
+	for _, point := range points {
+		sum.X += point.X
+		sum.Y += point.Y
+	}
+	return Point{X: sum.X / float64(len(points)), Y: sum.Y / float64(len(points))}
+}
@@ -0,0 +1,159 @@
+package collisions
+
+import "sort"
+
+// SweepAndPrune keeps the objects sorted by the left edge of their bounding boxes.
+// Overlapping pairs are found by sweeping along the X axis, and only objects whose
+// boxes overlap on X are compared on Y. After objects moved, the order is repaired with
+// an insertion sort, which is cheap because objects move little between updates.
+// It implements Broadphase.
+type SweepAndPrune struct {
+	entries  []*sapEntry          // Ordered by the left edge of the bounds while sorted is true
+	lookup   map[Object]*sapEntry // Entry of each object
+	sorted   bool                 // Whether entries are in order
+	maxWidth float64              // Width of the widest bounding box, to find where a query starts
+}
+
+// sapEntry is an object with its bounding box.
+type sapEntry struct {
+	object Object
+	bounds AABB
+}
+
+// NewSweepAndPrune creates an empty sweep and prune broadphase.
+func NewSweepAndPrune() *SweepAndPrune {
+	return &SweepAndPrune{
+		lookup: make(map[Object]*sapEntry),
+		sorted: true,
+	}
+}
+
+// Add adds an object.
+func (sp *SweepAndPrune) Add(obj Object) {
+	if _, exists := sp.lookup[obj]; exists {
+		sp.Update(obj)
+		return
+	}
+
+	entry := &sapEntry{object: obj, bounds: Bounds(obj)}
+	sp.lookup[obj] = entry
+	sp.entries = append(sp.entries, entry)
+	sp.sorted = false
+}
+
+// Remove removes an object, keeping the order of the others.
+func (sp *SweepAndPrune) Remove(obj Object) {
+	entry, exists := sp.lookup[obj]
+	if !exists {
+		return
+	}
+	delete(sp.lookup, obj)
+
+	for i, e := range sp.entries {
+		if e == entry {
+			sp.entries = append(sp.entries[:i], sp.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// Update moves an object to its current bounds. The order is repaired before the next query.
+func (sp *SweepAndPrune) Update(obj Object) {
+	entry, exists := sp.lookup[obj]
+	if !exists {
+		sp.Add(obj)
+		return
+	}
+	entry.bounds = Bounds(obj)
+	sp.sorted = false
+}
+
+// Query returns all objects whose bounding boxes overlap the box.
+func (sp *SweepAndPrune) Query(box AABB) []Object {
+	return sp.query(box, nil)
+}
+
+// Candidates returns all other objects whose bounding boxes overlap the bounding box of obj.
+func (sp *SweepAndPrune) Candidates(obj Object) []Object {
+	box := Bounds(obj)
+	if entry, exists := sp.lookup[obj]; exists {
+		box = entry.bounds
+	}
+	return sp.query(box, obj)
+}
+
+// query returns all objects except skip whose bounding boxes overlap the box.
+func (sp *SweepAndPrune) query(box AABB, skip Object) []Object {
+	sp.sort()
+
+	// No box that starts further left than the widest box can reach the query box
+	start := sort.Search(len(sp.entries), func(i int) bool {
+		return sp.entries[i].bounds.MinX >= box.MinX-sp.maxWidth
+	})
+
+	var objects []Object
+	for _, entry := range sp.entries[start:] {
+		if entry.bounds.MinX > box.MaxX {
+			break
+		}
+		if entry.object != skip && entry.bounds.Overlaps(box) {
+			objects = append(objects, entry.object)
+		}
+	}
+	return objects
+}
+
+// Pairs returns every pair of objects whose bounding boxes overlap.
+func (sp *SweepAndPrune) Pairs() [][2]Object {
+	sp.sort()
+
+	var pairs [][2]Object
+	for i, a := range sp.entries {
+		for _, b := range sp.entries[i+1:] {
+			if b.bounds.MinX > a.bounds.MaxX {
+				break // No later box overlaps a on X
+			}
+			if a.bounds.MinY <= b.bounds.MaxY && a.bounds.MaxY >= b.bounds.MinY {
+				pairs = append(pairs, [2]Object{a.object, b.object})
+			}
+		}
+	}
+	return pairs
+}
+
+// Len returns the number of objects.
+func (sp *SweepAndPrune) Len() int {
+	return len(sp.entries)
+}
+
+// Clear removes all objects.
+func (sp *SweepAndPrune) Clear() {
+	sp.entries = nil
+	sp.lookup = make(map[Object]*sapEntry)
+	sp.sorted = true
+	sp.maxWidth = 0
+}
+
+// sort orders the entries by the left edge of their bounds with an insertion sort,
+// which takes linear time when only a few objects changed places.
+func (sp *SweepAndPrune) sort() {
+	if sp.sorted {
+		return
+	}
+
+	sp.maxWidth = 0
+	for i := range sp.entries {
+		entry := sp.entries[i]
+		j := i
+		for j > 0 && sp.entries[j-1].bounds.MinX > entry.bounds.MinX {
+			sp.entries[j] = sp.entries[j-1]
+			j--
+		}
+		sp.entries[j] = entry
+
+		if width := entry.bounds.MaxX - entry.bounds.MinX; width > sp.maxWidth {
+			sp.maxWidth = width
+		}
+	}
+	sp.sorted = true
+}
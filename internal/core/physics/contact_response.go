@@ -0,0 +1,97 @@
+package physics
+
+import (
+	"discoveryx/internal/config"
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+)
+
+// ContactProfile describes how a body responds when it hits a wall.
+// The velocity is split along the wall normal: the speed into the wall bounces back
+// scaled by the restitution, the speed along the wall is slowed down by friction,
+// and the speed into the wall deals damage once it is above a threshold.
+type ContactProfile struct {
+	// Restitution is the part of the speed into the wall that bounces back,
+	// from 0 (stop at the wall and slide along it) to 1 (fully elastic bounce)
+	Restitution float64
+
+	// Friction is the friction coefficient of the wall. The speed along the wall is
+	// reduced by Friction times the change of the speed into the wall, so grazing
+	// hits slide on while head-on hits lose most of their speed.
+	Friction float64
+
+	// DamagePerSpeed is the damage per unit per second of speed into the wall above the threshold
+	DamagePerSpeed float64
+
+	// DamageThreshold is the speed into the wall in units per second that does no damage
+	DamageThreshold float64
+}
+
+// NewContactProfile creates a contact profile from its settings in the config.
+func NewContactProfile(settings config.ContactSettings) ContactProfile {
+	return ContactProfile{
+		Restitution:     settings.Restitution,
+		Friction:        settings.Friction,
+		DamagePerSpeed:  settings.DamagePerSpeed,
+		DamageThreshold: settings.DamageThreshold,
+	}
+}
+
+// ContactResponse is the result of a body hitting a wall.
+type ContactResponse struct {
+	Velocity    math.Vector // Velocity after the impact
+	ImpactSpeed float64     // Speed into the wall in units per second, 0 if the body did not move into it
+	Damage      float64     // Damage of the impact
+}
+
+// Respond returns the velocity of a body after hitting a wall, and the damage of the impact.
+// The normal points from the wall towards the body, like the normals of SweepHit and WallPoint.
+func (p ContactProfile) Respond(velocity, normal math.Vector) ContactResponse {
+	into := velocity.X*normal.X + velocity.Y*normal.Y
+	if into >= 0 {
+		// Moving along the wall or away from it
+		return ContactResponse{Velocity: velocity}
+	}
+	impactSpeed := -into
+
+	// Split the velocity into the part along the normal and the part along the wall
+	normalVelocity := math.Vector{X: normal.X * into, Y: normal.Y * into}
+	tangentVelocity := math.Vector{X: velocity.X - normalVelocity.X, Y: velocity.Y - normalVelocity.Y}
+
+	// Friction takes away speed along the wall in proportion to the change of speed into it
+	tangentSpeed := stdmath.Hypot(tangentVelocity.X, tangentVelocity.Y)
+	if tangentSpeed > 0 {
+		loss := stdmath.Min(tangentSpeed, p.Friction*(1+p.Restitution)*impactSpeed)
+		scale := (tangentSpeed - loss) / tangentSpeed
+		tangentVelocity.X *= scale
+		tangentVelocity.Y *= scale
+	}
+
+	return ContactResponse{
+		Velocity: math.Vector{
+			X: tangentVelocity.X - normalVelocity.X*p.Restitution,
+			Y: tangentVelocity.Y - normalVelocity.Y*p.Restitution,
+		},
+		ImpactSpeed: impactSpeed,
+		Damage:      p.Damage(impactSpeed),
+	}
+}
+
+// Damage returns the damage of an impact with the given speed into the wall in units per second.
+func (p ContactProfile) Damage(impactSpeed float64) float64 {
+	if impactSpeed <= p.DamageThreshold {
+		return 0
+	}
+	return (impactSpeed - p.DamageThreshold) * p.DamagePerSpeed
+}
+
+// SeparationNormal returns the wall normal of a separation vector, as returned by
+// CheckAABBCollisionWithSeparation, so that box contacts can be passed to Respond as well.
+// It returns a zero vector if there is no separation.
+func SeparationNormal(separation math.Vector) math.Vector {
+	length := stdmath.Hypot(separation.X, separation.Y)
+	if length == 0 {
+		return math.Vector{}
+	}
+	return math.Vector{X: separation.X / length, Y: separation.Y / length}
+}
@@ -0,0 +1,43 @@
+package physics
+
+import (
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+	"testing"
+)
+
+// TestContactProfileRespond tests bouncing, friction and impact damage against a wall facing +X
+func TestContactProfileRespond(t *testing.T) {
+	normal := math.Vector{X: 1, Y: 0}
+
+	for _, tc := range []struct {
+		name             string
+		profile          ContactProfile
+		velocity         math.Vector
+		expectedVelocity math.Vector
+		expectedImpact   float64
+		expectedDamage   float64
+	}{
+		{"Slides along without friction", ContactProfile{}, math.Vector{X: -100, Y: 50}, math.Vector{X: 0, Y: 50}, 100, 0},
+		{"Bounces back elastically", ContactProfile{Restitution: 1}, math.Vector{X: -100, Y: 50}, math.Vector{X: 100, Y: 50}, 100, 0},
+		{"Friction slows down the slide", ContactProfile{Friction: 0.2}, math.Vector{X: -100, Y: 50}, math.Vector{X: 0, Y: 30}, 100, 0},
+		{"Friction stops a head-on hit", ContactProfile{Friction: 1}, math.Vector{X: -100, Y: 50}, math.Vector{X: 0, Y: 0}, 100, 0},
+		{"Slow impacts do no damage", ContactProfile{DamagePerSpeed: 0.1, DamageThreshold: 150}, math.Vector{X: -100, Y: 0}, math.Vector{X: 0, Y: 0}, 100, 0},
+		{"Damage grows with the impact speed", ContactProfile{DamagePerSpeed: 0.1, DamageThreshold: 50}, math.Vector{X: -100, Y: 500}, math.Vector{X: 0, Y: 500}, 100, 5},
+		{"Moving away is not a hit", ContactProfile{Restitution: 1, DamagePerSpeed: 1}, math.Vector{X: 100, Y: 50}, math.Vector{X: 100, Y: 50}, 0, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			response := tc.profile.Respond(tc.velocity, normal)
+
+			if stdmath.Abs(response.Velocity.X-tc.expectedVelocity.X) > 1e-9 || stdmath.Abs(response.Velocity.Y-tc.expectedVelocity.Y) > 1e-9 {
+				t.Errorf("Expected velocity %v, got %v", tc.expectedVelocity, response.Velocity)
+			}
+			if stdmath.Abs(response.ImpactSpeed-tc.expectedImpact) > 1e-9 {
+				t.Errorf("Expected impact speed %v, got %v", tc.expectedImpact, response.ImpactSpeed)
+			}
+			if stdmath.Abs(response.Damage-tc.expectedDamage) > 1e-9 {
+				t.Errorf("Expected damage %v, got %v", tc.expectedDamage, response.Damage)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package physics
+
+import (
+	"discoveryx/internal/utils/math"
+	"sort"
+)
+
+// ContactEvent describes a contact between two entities.
+type ContactEvent struct {
+	EntityA interface{}    // First entity of the contact
+	EntityB interface{}    // Second entity of the contact
+	LayerA  CollisionLayer // Layers of the first entity
+	LayerB  CollisionLayer // Layers of the second entity
+
+	Normal math.Vector // Pushes the first entity out of the second, zero when the contact ended
+	Depth  float64     // Penetration depth, zero when the contact ended
+	Point  math.Vector // Deepest point of the first entity inside the second
+}
+
+// Match returns the entities of the contact ordered by layer: the first one is on layerA
+// and the second one is on layerB. It returns false if the contact is not between these layers.
+func (e ContactEvent) Match(layerA, layerB CollisionLayer) (interface{}, interface{}, bool) {
+	switch {
+	case e.LayerA&layerA != 0 && e.LayerB&layerB != 0:
+		return e.EntityA, e.EntityB, true
+	case e.LayerB&layerA != 0 && e.LayerA&layerB != 0:
+		return e.EntityB, e.EntityA, true
+	default:
+		return nil, nil, false
+	}
+}
+
+// ContactListener is notified when entities start touching, keep touching and stop touching.
+// Gameplay systems use it to react to hits instead of checking pairs of entities themselves.
+type ContactListener interface {
+	// ContactEntered is called in the first update in which two entities touch.
+	ContactEntered(event ContactEvent)
+	// ContactStayed is called in every following update in which the entities still touch.
+	ContactStayed(event ContactEvent)
+	// ContactExited is called in the first update in which the entities no longer touch,
+	// or after one of them was removed.
+	ContactExited(event ContactEvent)
+}
+
+// contactPair identifies a contact by the shape IDs of both entities, the smaller one first.
+type contactPair struct {
+	a, b int
+}
+
+// AddContactListener registers a listener for contact events between entities.
+func (cm *CollisionManager) AddContactListener(listener ContactListener) {
+	cm.contactListeners = append(cm.contactListeners, listener)
+}
+
+// UpdateContacts finds all touching entities whose layers interact and notifies the
+// contact listeners. It should be called once per update, after all entities have moved.
+// Events are delivered in a fixed order, so runs with the same seed stay reproducible.
+func (cm *CollisionManager) UpdateContacts() {
+	system := cm.collisionSystem.(*EbitenCollisionSystem)
+
+	// Find the entities and shape IDs of the entity shapes
+	entities := make(map[Shape]interface{}, len(cm.entityShapeIDs))
+	shapeIDs := make(map[Shape]int, len(cm.entityShapeIDs))
+	for entity, id := range cm.entityShapeIDs {
+		shape := system.shapes[id]
+		entities[shape] = entity
+		shapeIDs[shape] = id
+	}
+
+	// Only check entity-entity collisions, the layers are checked by the collision system.
+	// Each pair is seen from the entity that was registered first.
+	collisions, err := cm.collisionSystem.Resolve(func(self, other Shape) bool {
+		return self != other && isEntityShape(self) && isEntityShape(other) && shapeIDs[self] < shapeIDs[other]
+	})
+	if err != nil {
+		return
+	}
+
+	current := make(map[contactPair]ContactEvent)
+	for _, collision := range collisions {
+		idA, okA := shapeIDs[collision.ShapeA]
+		idB, okB := shapeIDs[collision.ShapeB]
+		if !okA || !okB {
+			continue // Not an entity
+		}
+
+		current[contactPair{a: idA, b: idB}] = ContactEvent{
+			EntityA: entities[collision.ShapeA],
+			EntityB: entities[collision.ShapeB],
+			LayerA:  cm.collisionSystem.GetLayers(idA).Layer,
+			LayerB:  cm.collisionSystem.GetLayers(idB).Layer,
+			Normal:  collision.Normal,
+			Depth:   collision.Depth,
+			Point:   collision.Point,
+		}
+	}
+
+	for _, pair := range sortedContactPairs(current) {
+		event := current[pair]
+		if _, existed := cm.contacts[pair]; existed {
+			for _, listener := range cm.contactListeners {
+				listener.ContactStayed(event)
+			}
+		} else {
+			for _, listener := range cm.contactListeners {
+				listener.ContactEntered(event)
+			}
+		}
+	}
+
+	for _, pair := range sortedContactPairs(cm.contacts) {
+		if _, exists := current[pair]; exists {
+			continue
+		}
+
+		// The entities are the same, but they no longer touch
+		event := cm.contacts[pair]
+		event.Normal, event.Depth, event.Point = math.Vector{}, 0, math.Vector{}
+		for _, listener := range cm.contactListeners {
+			listener.ContactExited(event)
+		}
+	}
+
+	cm.contacts = current
+}
+
+// sortedContactPairs returns the pairs of a contact map in a fixed order.
+func sortedContactPairs(contacts map[contactPair]ContactEvent) []contactPair {
+	pairs := make([]contactPair, 0, len(contacts))
+	for pair := range contacts {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].a != pairs[j].a {
+			return pairs[i].a < pairs[j].a
+		}
+		return pairs[i].b < pairs[j].b
+	})
+	return pairs
+}
@@ -0,0 +1,74 @@
+package physics
+
+import (
+	"discoveryx/internal/utils/math"
+	"testing"
+)
+
+// recordingListener records the contact events it receives
+type recordingListener struct {
+	events []string
+}
+
+func (l *recordingListener) ContactEntered(event ContactEvent) {
+	l.events = append(l.events, "enter")
+}
+
+func (l *recordingListener) ContactStayed(event ContactEvent) {
+	l.events = append(l.events, "stay")
+}
+
+func (l *recordingListener) ContactExited(event ContactEvent) {
+	l.events = append(l.events, "exit")
+}
+
+// TestUpdateContacts tests the enter, stay and exit events and that layers filter the contacts
+func TestUpdateContacts(t *testing.T) {
+	cm := NewCollisionManager(100.0)
+	listener := &recordingListener{}
+	cm.AddContactListener(listener)
+
+	player, enemy, enemyBullet := "player", "enemy", "enemy bullet"
+	cm.RegisterEntity(player, CircleCollider{Position: math.Vector{X: 0, Y: 0}, Radius: 10})
+	cm.SetEntityLayers(player, NewCollisionLayers(LayerPlayer))
+	cm.RegisterEntity(enemy, CircleCollider{Position: math.Vector{X: 15, Y: 0}, Radius: 10})
+	cm.SetEntityLayers(enemy, NewCollisionLayers(LayerEnemy))
+
+	// Enemy bullets do not hit enemies, even though they overlap
+	cm.RegisterEntity(enemyBullet, CircleCollider{Position: math.Vector{X: 20, Y: 0}, Radius: 2})
+	cm.SetEntityLayers(enemyBullet, NewCollisionLayers(LayerEnemyBullet))
+
+	var matched bool
+	cm.AddContactListener(&matchListener{match: func(event ContactEvent) {
+		a, b, ok := event.Match(LayerPlayer, LayerEnemy)
+		matched = ok && a == player && b == enemy
+	}})
+
+	cm.UpdateContacts()
+	cm.UpdateContacts()
+	cm.UpdateEntity(enemy, CircleCollider{Position: math.Vector{X: 50, Y: 0}, Radius: 10})
+	cm.UpdateContacts()
+
+	expected := []string{"enter", "stay", "exit"}
+	if len(listener.events) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, listener.events)
+	}
+	for i := range expected {
+		if listener.events[i] != expected[i] {
+			t.Errorf("Expected events %v, got %v", expected, listener.events)
+			break
+		}
+	}
+	if !matched {
+		t.Errorf("Expected the contact to match the player and the enemy layers")
+	}
+}
+
+// matchListener calls a function for every contact event
+type matchListener struct {
+	match func(event ContactEvent)
+}
+
+func (l *matchListener) ContactEntered(event ContactEvent) { l.match(event) }
+func (l *matchListener) ContactStayed(event ContactEvent)  { l.match(event) }
+func (l *matchListener) ContactExited(event ContactEvent)  { l.match(event) }
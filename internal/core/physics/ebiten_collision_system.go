@@ -24,19 +24,39 @@ type EbitenCollisionSystem struct {
 	// Map of shape IDs to collisions.Object for quick lookup
 	objects map[int]collisions.Object
 
+	// Map of collisions.Object to shape IDs, the reverse of objects
+	ids map[collisions.Object]int
+
+	// Map of shape IDs to their collision layers, for shapes that have them set
+	layers map[int]CollisionLayers
+
 	// Spatial partitioning cell size
 	cellSize float64
+
+	// Creates the broadphase of the space, again after Clear
+	newBroadphase func() collisions.Broadphase
 }
 
 // NewEbitenCollisionSystem creates a new collision system using the ebiten-collisions library.
 // The cellSize parameter determines the size of the spatial partitioning cells.
 func NewEbitenCollisionSystem(cellSize float64) *EbitenCollisionSystem {
+	return NewEbitenCollisionSystemWithBroadphase(cellSize, func() collisions.Broadphase {
+		return collisions.NewSpatialHash(cellSize)
+	})
+}
+
+// NewEbitenCollisionSystemWithBroadphase creates a new collision system whose space finds
+// nearby shapes with the broadphase created by newBroadphase (see NewBroadphase).
+func NewEbitenCollisionSystemWithBroadphase(cellSize float64, newBroadphase func() collisions.Broadphase) *EbitenCollisionSystem {
 	return &EbitenCollisionSystem{
-		space:    collisions.NewSpace(cellSize),
-		shapes:   make(map[int]Shape),
-		objects:  make(map[int]collisions.Object),
-		nextID:   1,
-		cellSize: cellSize,
+		space:         collisions.NewSpaceWithBroadphase(cellSize, newBroadphase()),
+		shapes:        make(map[int]Shape),
+		objects:       make(map[int]collisions.Object),
+		ids:           make(map[collisions.Object]int),
+		layers:        make(map[int]CollisionLayers),
+		nextID:        1,
+		cellSize:      cellSize,
+		newBroadphase: newBroadphase,
 	}
 }
 
@@ -46,37 +66,7 @@ func (ecs *EbitenCollisionSystem) AddShape(shape Shape) int {
 	ecs.nextID++
 
 	// Create a collisions.Object based on the shape type
-	var obj collisions.Object
-
-	switch shape.GetType() {
-	case ShapeTypeCircle:
-		circleShape, ok := shape.(*CircleShape)
-		if !ok {
-			panic("Shape with type ShapeTypeCircle is not a *CircleShape")
-		}
-
-		obj = collisions.NewCircle(
-			circleShape.Position.X,
-			circleShape.Position.Y,
-			circleShape.Radius,
-		)
-
-	case ShapeTypeAABB:
-		aabbShape, ok := shape.(*AABBShape)
-		if !ok {
-			panic("Shape with type ShapeTypeAABB is not a *AABBShape")
-		}
-
-		obj = collisions.NewRectangle(
-			aabbShape.Position.X-aabbShape.Width/2,
-			aabbShape.Position.Y-aabbShape.Height/2,
-			aabbShape.Width,
-			aabbShape.Height,
-		)
-
-	default:
-		panic(fmt.Sprintf("Unsupported shape type: %v", shape.GetType()))
-	}
+	obj := newObject(shape)
 
 	// Add the object to the space
 	ecs.space.Add(obj)
@@ -84,6 +74,7 @@ func (ecs *EbitenCollisionSystem) AddShape(shape Shape) int {
 	// Store the shape and object for later lookup
 	ecs.shapes[id] = shape
 	ecs.objects[id] = obj
+	ecs.ids[obj] = id
 
 	return id
 }
@@ -101,6 +92,25 @@ func (ecs *EbitenCollisionSystem) RemoveShape(id int) {
 	// Remove the shape and object from our maps
 	delete(ecs.shapes, id)
 	delete(ecs.objects, id)
+	delete(ecs.ids, obj)
+	delete(ecs.layers, id)
+}
+
+// SetLayers sets the collision layers of a shape.
+func (ecs *EbitenCollisionSystem) SetLayers(id int, layers CollisionLayers) {
+	if _, exists := ecs.objects[id]; !exists {
+		return
+	}
+	ecs.layers[id] = layers
+}
+
+// GetLayers returns the collision layers of a shape.
+// Shapes without layers are on all layers and collide with everything.
+func (ecs *EbitenCollisionSystem) GetLayers(id int) CollisionLayers {
+	if layers, exists := ecs.layers[id]; exists {
+		return layers
+	}
+	return AllLayers
 }
 
 // UpdateShape updates a shape in the collision system.
@@ -111,15 +121,6 @@ func (ecs *EbitenCollisionSystem) UpdateShape(id int, shape Shape) {
 		return
 	}
 
-	// Store the old position for spatial hash update
-	var oldX, oldY float64
-	switch o := obj.(type) {
-	case *collisions.Circle:
-		oldX, oldY = o.X, o.Y
-	case *collisions.Rectangle:
-		oldX, oldY = o.X, o.Y
-	}
-
 	// Update the object based on the shape type
 	switch shape.GetType() {
 	case ShapeTypeCircle:
@@ -153,134 +154,171 @@ func (ecs *EbitenCollisionSystem) UpdateShape(id int, shape Shape) {
 		rect.W = aabbShape.Width
 		rect.H = aabbShape.Height
 
+	case ShapeTypePolygon:
+		polygonShape, ok := shape.(*PolygonShape)
+		if !ok {
+			panic("Shape with type ShapeTypePolygon is not a *PolygonShape")
+		}
+
+		polygon, ok := obj.(*collisions.Polygon)
+		if !ok {
+			panic("Object is not a *collisions.Polygon")
+		}
+
+		polygon.X = polygonShape.Position.X
+		polygon.Y = polygonShape.Position.Y
+		polygon.Vertices = polygonVertices(polygonShape)
+
+	case ShapeTypeSegment:
+		segmentShape, ok := shape.(*SegmentShape)
+		if !ok {
+			panic("Shape with type ShapeTypeSegment is not a *SegmentShape")
+		}
+
+		segment, ok := obj.(*collisions.Segment)
+		if !ok {
+			panic("Object is not a *collisions.Segment")
+		}
+
+		*segment = *collisions.NewSegment(segmentShape.A.X, segmentShape.A.Y, segmentShape.B.X, segmentShape.B.Y)
+
 	default:
 		panic(fmt.Sprintf("Unsupported shape type: %v", shape.GetType()))
 	}
 
-	// Update the spatial hash
-	ecs.space.UpdateShape(obj, oldX, oldY)
+	// Move the object in the broadphase
+	ecs.space.UpdateShape(obj)
 
 	// Update our shape map
 	ecs.shapes[id] = shape
 }
 
 // Resolve checks for collisions and returns a list of collisions.
+// Each colliding pair is reported once: from the side of the first shape of the pair if the filter
+// accepts it, otherwise from the side of the second.
 func (ecs *EbitenCollisionSystem) Resolve(filter CollisionFilter) ([]Collision, error) {
-	// Get all collisions from the space
-	var collisions []Collision
-
-	// Check each object against all other objects
-	for idA, objA := range ecs.objects {
-		shapeA := ecs.shapes[idA]
-
-		// Get all objects that collide with this object
-		for _, other := range ecs.space.GetCollisions(objA) {
-			// Find the shape of the other object
-			var shapeB Shape
-
-			for id, obj := range ecs.objects {
-				if obj == other {
-					shapeB = ecs.shapes[id]
-					break
-				}
-			}
-
-			// Skip if we couldn't find the other object
-			if shapeB == nil {
-				continue
-			}
-
-			// Apply the filter if provided
-			if filter != nil && !filter(shapeA, shapeB) {
-				continue
-			}
-
-			// Create a collision object
-			collision := Collision{
-				ShapeA: shapeA,
-				ShapeB: shapeB,
-			}
-
-			// Calculate the collision normal and depth
-			posA := shapeA.GetPosition()
-			posB := shapeB.GetPosition()
-
-			// Calculate the vector from B to A
-			dx := posA.X - posB.X
-			dy := posA.Y - posB.Y
-
-			// Normalize the vector
-			length := stdmath.Sqrt(dx*dx + dy*dy)
-			if length > 0 {
-				dx /= length
-				dy /= length
-			} else {
-				// If the shapes are at the same position, use a default normal
-				dx = 0
-				dy = -1
-			}
-
-			collision.Normal = math.Vector{X: dx, Y: dy}
-
-			// Calculate the collision depth based on the shape types
-			switch {
-			case shapeA.GetType() == ShapeTypeCircle && shapeB.GetType() == ShapeTypeCircle:
-				// Circle-circle collision
-				circleA := shapeA.(*CircleShape)
-				circleB := shapeB.(*CircleShape)
-
-				// Calculate the distance between the centers
-				distance := math.Distance(posA, posB)
-
-				// The depth is the sum of the radii minus the distance
-				collision.Depth = circleA.Radius + circleB.Radius - distance
-
-				// Calculate the collision point
-				collision.Point = math.Vector{
-					X: posB.X + dx*circleB.Radius,
-					Y: posB.Y + dy*circleB.Radius,
-				}
-
-			case shapeA.GetType() == ShapeTypeAABB && shapeB.GetType() == ShapeTypeAABB:
-				// AABB-AABB collision
-				aabbA := shapeA.(*AABBShape)
-				aabbB := shapeB.(*AABBShape)
-
-				// Calculate the overlap on each axis
-				minA, maxA := aabbA.GetMin(), aabbA.GetMax()
-				minB, maxB := aabbB.GetMin(), aabbB.GetMax()
-
-				overlapX := stdmath.Min(maxA.X, maxB.X) - stdmath.Max(minA.X, minB.X)
-				overlapY := stdmath.Min(maxA.Y, maxB.Y) - stdmath.Max(minA.Y, minB.Y)
-
-				// The depth is the minimum overlap
-				if overlapX < overlapY {
-					collision.Depth = overlapX
-				} else {
-					collision.Depth = overlapY
-				}
-
-				// Calculate the collision point (center of the overlap)
-				collision.Point = math.Vector{
-					X: stdmath.Max(minA.X, minB.X) + overlapX/2,
-					Y: stdmath.Max(minA.Y, minB.Y) + overlapY/2,
-				}
-
-			default:
-				// Mixed shape types - use a simplified approach
-				// This is a simplification and might not be accurate for all cases
-				collision.Depth = 1.0
-				collision.Point = math.Vector{
-					X: (posA.X + posB.X) / 2,
-					Y: (posA.Y + posB.Y) / 2,
-				}
-			}
-
-			collisions = append(collisions, collision)
+	var result []Collision
+
+	// Get the colliding pairs from the space
+	for _, pair := range ecs.space.GetCollidingPairs() {
+		idA, okA := ecs.ids[pair[0]]
+		idB, okB := ecs.ids[pair[1]]
+
+		// Skip objects that are not shapes of the system
+		if !okA || !okB {
+			continue
+		}
+
+		if collision, ok := ecs.collide(idA, idB, filter); ok {
+			result = append(result, collision)
+		} else if collision, ok := ecs.collide(idB, idA, filter); ok {
+			result = append(result, collision)
 		}
 	}
 
-	return collisions, nil
+	return result, nil
+}
+
+// collide returns the collision of shape idA with shape idB, whose objects overlap.
+// It returns false if their layers do not interact or the filter rejects the pair.
+func (ecs *EbitenCollisionSystem) collide(idA, idB int, filter CollisionFilter) (Collision, bool) {
+	shapeA, shapeB := ecs.shapes[idA], ecs.shapes[idB]
+	objA, other := ecs.objects[idA], ecs.objects[idB]
+
+	// Skip pairs on layers that do not collide
+	if !ecs.GetLayers(idA).Interacts(ecs.GetLayers(idB)) {
+		return Collision{}, false
+	}
+
+	// Apply the filter if provided
+	if filter != nil && !filter(shapeA, shapeB) {
+		return Collision{}, false
+	}
+
+	// Create a collision object
+	collision := Collision{
+		ShapeA: shapeA,
+		ShapeB: shapeB,
+	}
+
+	// Calculate the collision normal and depth
+	posA := shapeA.GetPosition()
+	posB := shapeB.GetPosition()
+
+	// Calculate the vector from B to A
+	dx := posA.X - posB.X
+	dy := posA.Y - posB.Y
+
+	// Normalize the vector
+	length := stdmath.Sqrt(dx*dx + dy*dy)
+	if length > 0 {
+		dx /= length
+		dy /= length
+	} else {
+		// If the shapes are at the same position, use a default normal
+		dx = 0
+		dy = -1
+	}
+
+	collision.Normal = math.Vector{X: dx, Y: dy}
+
+	// Calculate the collision depth based on the shape types
+	switch {
+	case shapeA.GetType() == ShapeTypeCircle && shapeB.GetType() == ShapeTypeCircle:
+		// Circle-circle collision
+		circleA := shapeA.(*CircleShape)
+		circleB := shapeB.(*CircleShape)
+
+		// Calculate the distance between the centers
+		distance := math.Distance(posA, posB)
+
+		// The depth is the sum of the radii minus the distance
+		collision.Depth = circleA.Radius + circleB.Radius - distance
+
+		// Calculate the collision point
+		collision.Point = math.Vector{
+			X: posB.X + dx*circleB.Radius,
+			Y: posB.Y + dy*circleB.Radius,
+		}
+
+	case shapeA.GetType() == ShapeTypeAABB && shapeB.GetType() == ShapeTypeAABB:
+		// AABB-AABB collision
+		aabbA := shapeA.(*AABBShape)
+		aabbB := shapeB.(*AABBShape)
+
+		// Calculate the overlap on each axis
+		minA, maxA := aabbA.GetMin(), aabbA.GetMax()
+		minB, maxB := aabbB.GetMin(), aabbB.GetMax()
+
+		overlapX := stdmath.Min(maxA.X, maxB.X) - stdmath.Max(minA.X, minB.X)
+		overlapY := stdmath.Min(maxA.Y, maxB.Y) - stdmath.Max(minA.Y, minB.Y)
+
+		// The depth is the minimum overlap
+		if overlapX < overlapY {
+			collision.Depth = overlapX
+		} else {
+			collision.Depth = overlapY
+		}
+
+		// Calculate the collision point (center of the overlap)
+		collision.Point = math.Vector{
+			X: stdmath.Max(minA.X, minB.X) + overlapX/2,
+			Y: stdmath.Max(minA.Y, minB.Y) + overlapY/2,
+		}
+
+	default:
+		// Mixed shape types, polygons and segments - use the separating axis test
+		contact, colliding := collisions.Penetration(objA, other)
+		if !colliding {
+			return Collision{}, false
+		}
+
+		collision.Normal = math.Vector{X: contact.NormalX, Y: contact.NormalY}
+		collision.Depth = contact.Depth
+		collision.Point = math.Vector{X: contact.PointX, Y: contact.PointY}
+	}
+
+	return collision, true
 }
 
 // ResolveWithMovement checks for collisions with movement and returns a list of collisions.
@@ -314,6 +352,39 @@ func (ecs *EbitenCollisionSystem) ResolveWithMovement(dx, dy float64, filter Col
 	return collisions, nil
 }
 
+// polygonVertices converts the rotated vertices of a polygon shape to collision points.
+func polygonVertices(shape *PolygonShape) []collisions.Point {
+	rotated := shape.GetRotatedVertices()
+	vertices := make([]collisions.Point, len(rotated))
+	for i, vertex := range rotated {
+		vertices[i] = collisions.Point{X: vertex.X, Y: vertex.Y}
+	}
+	return vertices
+}
+
+// Raycast returns the first shape on one of the mask layers that a ray hits,
+// up to maxDistance from the origin. Only the broadphase around the ray is searched.
+func (ecs *EbitenCollisionSystem) Raycast(origin, direction math.Vector, maxDistance float64, mask CollisionLayer) (RayHit, bool) {
+	filter := func(obj collisions.Object) bool {
+		id, exists := ecs.ids[obj]
+		return exists && ecs.GetLayers(id).Layer&mask != 0
+	}
+
+	hit, found := ecs.space.Raycast(origin.X, origin.Y, direction.X, direction.Y, maxDistance, filter)
+	if !found {
+		return RayHit{}, false
+	}
+
+	id := ecs.ids[hit.Object]
+	return RayHit{
+		Shape:    ecs.shapes[id],
+		Layer:    ecs.GetLayers(id).Layer,
+		Point:    math.Vector{X: hit.X, Y: hit.Y},
+		Normal:   math.Vector{X: hit.NormalX, Y: hit.NormalY},
+		Distance: hit.Distance,
+	}, true
+}
+
 // GetNearbyShapes returns all shapes within the specified radius of the position.
 func (ecs *EbitenCollisionSystem) GetNearbyShapes(position math.Vector, radius float64) []Shape {
 	// Create a temporary circle to query the space
@@ -331,11 +402,8 @@ func (ecs *EbitenCollisionSystem) GetNearbyShapes(position math.Vector, radius f
 	// Convert the colliding objects to shapes
 	var shapes []Shape
 	for _, obj := range colliding {
-		for id, o := range ecs.objects {
-			if o == obj {
-				shapes = append(shapes, ecs.shapes[id])
-				break
-			}
+		if id, exists := ecs.ids[obj]; exists {
+			shapes = append(shapes, ecs.shapes[id])
 		}
 	}
 
@@ -345,11 +413,13 @@ func (ecs *EbitenCollisionSystem) GetNearbyShapes(position math.Vector, radius f
 // Clear removes all shapes from the collision system.
 func (ecs *EbitenCollisionSystem) Clear() {
 	// Create a new space
-	ecs.space = collisions.NewSpace(ecs.cellSize)
+	ecs.space = collisions.NewSpaceWithBroadphase(ecs.cellSize, ecs.newBroadphase())
 
 	// Clear our maps
 	ecs.shapes = make(map[int]Shape)
 	ecs.objects = make(map[int]collisions.Object)
+	ecs.ids = make(map[collisions.Object]int)
+	ecs.layers = make(map[int]CollisionLayers)
 
 	// Reset the next ID
 	ecs.nextID = 1
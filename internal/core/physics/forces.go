@@ -0,0 +1,170 @@
+package physics
+
+import (
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+)
+
+// ForceKind is the kind of force a force volume applies
+type ForceKind int
+
+const (
+	// ForceCurrent pushes everything inside in the same direction, like a current or wind
+	ForceCurrent ForceKind = iota
+
+	// ForceGravityWell pulls towards the center, from the full strength at the center to nothing at the edge
+	ForceGravityWell
+
+	// ForceRepulsor pushes away from the center, from the full strength at the center to nothing at the edge
+	ForceRepulsor
+
+	// ForceTurbulence pushes with the full strength in a direction that swirls over space and time
+	ForceTurbulence
+)
+
+// turbulenceScale is the size in units of the swirls of turbulence
+const turbulenceScale = 120.0
+
+// ForceVolume is an area that accelerates the bodies inside it.
+// It is a circle with the radius, or an axis-aligned rectangle if the half size is set.
+type ForceVolume struct {
+	Kind      ForceKind
+	Center    math.Vector // Center of the volume in world coordinates
+	Radius    float64     // Radius of a circular volume
+	HalfSize  math.Vector // Half the width and height of a rectangular volume, zero for circles
+	Strength  float64     // Acceleration in units per second squared
+	Direction math.Vector // Unit direction of currents
+}
+
+// Contains checks if a position lies inside the volume.
+func (v *ForceVolume) Contains(position math.Vector) bool {
+	dx, dy := position.X-v.Center.X, position.Y-v.Center.Y
+	if v.isRectangle() {
+		return stdmath.Abs(dx) <= v.HalfSize.X && stdmath.Abs(dy) <= v.HalfSize.Y
+	}
+	return dx*dx+dy*dy <= v.Radius*v.Radius
+}
+
+// Acceleration returns the acceleration of the volume at a position inside it, at a time in seconds.
+func (v *ForceVolume) Acceleration(position math.Vector, time float64) math.Vector {
+	switch v.Kind {
+	case ForceCurrent:
+		return math.Vector{X: v.Direction.X * v.Strength, Y: v.Direction.Y * v.Strength}
+
+	case ForceGravityWell, ForceRepulsor:
+		dx, dy := v.Center.X-position.X, v.Center.Y-position.Y
+		distance := stdmath.Hypot(dx, dy)
+		if distance == 0 {
+			return math.Vector{}
+		}
+
+		// Linear falloff avoids the endless pull of an inverse square law at the center
+		strength := v.Strength * stdmath.Max(0, 1-distance/v.reach())
+		if v.Kind == ForceRepulsor {
+			strength = -strength
+		}
+		return math.Vector{X: dx / distance * strength, Y: dy / distance * strength}
+
+	case ForceTurbulence:
+		// Two slowly moving waves give a smooth direction that is different everywhere
+		angle := stdmath.Pi * (stdmath.Sin(position.X/turbulenceScale+time*1.3) + stdmath.Cos(position.Y/turbulenceScale-time*0.7))
+		return math.Vector{X: stdmath.Sin(angle) * v.Strength, Y: -stdmath.Cos(angle) * v.Strength}
+	}
+	return math.Vector{}
+}
+
+// reach returns the distance from the center to the edge, used for the falloff of wells and repulsors.
+func (v *ForceVolume) reach() float64 {
+	if v.isRectangle() {
+		return stdmath.Hypot(v.HalfSize.X, v.HalfSize.Y)
+	}
+	return v.Radius
+}
+
+// isRectangle reports whether the volume is a rectangle instead of a circle.
+func (v *ForceVolume) isRectangle() bool {
+	return v.HalfSize.X > 0 && v.HalfSize.Y > 0
+}
+
+// bounds returns the corners of the bounding box of the volume.
+func (v *ForceVolume) bounds() (math.Vector, math.Vector) {
+	extent := math.Vector{X: v.Radius, Y: v.Radius}
+	if v.isRectangle() {
+		extent = v.HalfSize
+	}
+	return math.Vector{X: v.Center.X - extent.X, Y: v.Center.Y - extent.Y},
+		math.Vector{X: v.Center.X + extent.X, Y: v.Center.Y + extent.Y}
+}
+
+// ForceField gives the acceleration that the environment applies to a body at a position.
+// The physics World adds it to all bodies in the same step as their other forces.
+type ForceField interface {
+	Acceleration(position math.Vector, time float64) math.Vector
+}
+
+// ForceVolumes is a ForceField made of force volumes. The volumes are kept in a grid,
+// so only the volumes near a position are checked.
+type ForceVolumes struct {
+	cellSize float64
+	grid     map[gridCell][]*ForceVolume // Volumes overlapping each grid cell
+}
+
+// NewForceVolumes creates an empty set of force volumes with a grid of the given cell size.
+func NewForceVolumes(cellSize float64) *ForceVolumes {
+	return &ForceVolumes{
+		cellSize: cellSize,
+		grid:     make(map[gridCell][]*ForceVolume),
+	}
+}
+
+// Add adds a volume. It must not be changed while it is added.
+func (f *ForceVolumes) Add(volume *ForceVolume) {
+	f.forCells(volume, func(key gridCell) {
+		f.grid[key] = append(f.grid[key], volume)
+	})
+}
+
+// Remove removes a volume that was added before.
+func (f *ForceVolumes) Remove(volume *ForceVolume) {
+	f.forCells(volume, func(key gridCell) {
+		volumes := f.grid[key]
+		for i, v := range volumes {
+			if v == volume {
+				volumes = append(volumes[:i], volumes[i+1:]...)
+				break
+			}
+		}
+		if len(volumes) == 0 {
+			delete(f.grid, key)
+		} else {
+			f.grid[key] = volumes
+		}
+	})
+}
+
+// Acceleration returns the sum of the accelerations of all volumes that contain the position.
+// It implements ForceField.
+func (f *ForceVolumes) Acceleration(position math.Vector, time float64) math.Vector {
+	var total math.Vector
+	key := getCellKey(int(stdmath.Floor(position.X/f.cellSize)), int(stdmath.Floor(position.Y/f.cellSize)))
+	for _, volume := range f.grid[key] {
+		if volume.Contains(position) {
+			acceleration := volume.Acceleration(position, time)
+			total.X += acceleration.X
+			total.Y += acceleration.Y
+		}
+	}
+	return total
+}
+
+// forCells calls fn for every grid cell that the bounding box of a volume overlaps.
+func (f *ForceVolumes) forCells(volume *ForceVolume, fn func(key gridCell)) {
+	minCorner, maxCorner := volume.bounds()
+	minX, minY := int(stdmath.Floor(minCorner.X/f.cellSize)), int(stdmath.Floor(minCorner.Y/f.cellSize))
+	maxX, maxY := int(stdmath.Floor(maxCorner.X/f.cellSize)), int(stdmath.Floor(maxCorner.Y/f.cellSize))
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			fn(getCellKey(x, y))
+		}
+	}
+}
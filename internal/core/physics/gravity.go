@@ -5,7 +5,7 @@
 // The physics system is designed to be:
 // - Lightweight and efficient for mobile devices
 // - Frame-rate independent using deltaTime
-// - Configurable through constants
+// - Configurable through the config profiles
 // - Selective in application (e.g., gravity only affects slow-moving objects)
 //
 // This package works closely with the gameplay systems to create
@@ -13,11 +13,11 @@
 package physics
 
 import (
-	"discoveryx/internal/constants"
+	"discoveryx/internal/config"
 	"discoveryx/internal/utils/math"
 )
 
-// Physics constants are now defined in the constants package
+// Physics values are read from the physics section of the active config
 
 // ApplyGravity applies a gravity force to the given position vector
 // but only if the current velocity is below the LowVelocityThreshold.
@@ -41,12 +41,25 @@ import (
 // Returns:
 //   - The updated position vector after applying gravity
 func ApplyGravity(position math.Vector, velocity float64, deltaTime float64) math.Vector {
+	drift := GravityVelocity(velocity)
+	position.Y += drift.Y * deltaTime
+
+	return position
+}
+
+// GravityVelocity returns the velocity in units per second at which gravity pulls an object
+// that moves with the given velocity magnitude. Like ApplyGravity, it is zero for objects
+// moving at or above the LowVelocityThreshold. Bodies in the physics World add it to their
+// velocity instead of having their position changed directly.
+func GravityVelocity(velocity float64) math.Vector {
+	settings := config.Get().Physics
+
 	// Only apply gravity if velocity is below the threshold
-	if velocity < constants.LowVelocityThreshold {
-		// Apply gravity force in the downward direction (positive Y)
+	if velocity < settings.LowVelocityThreshold {
+		// Pull in the downward direction (positive Y)
 		// Scale by 60.0 to maintain original speed at 60 FPS
-		position.Y += constants.GravityForce * deltaTime * 60.0
+		return math.Vector{X: 0, Y: settings.GravityForce * 60.0}
 	}
 
-	return position
+	return math.Vector{X: 0, Y: 0}
 }
@@ -0,0 +1,80 @@
+package physics
+
+// CollisionLayer is a set of bits that describes what kind of object a shape belongs to.
+// Each layer is a single bit, so layers can be combined into masks.
+type CollisionLayer uint32
+
+const (
+	// LayerPlayer is the layer of the player's ship.
+	LayerPlayer CollisionLayer = 1 << iota
+
+	// LayerEnemy is the layer of enemies.
+	LayerEnemy
+
+	// LayerPlayerBullet is the layer of bullets fired by the player.
+	LayerPlayerBullet
+
+	// LayerEnemyBullet is the layer of bullets fired by enemies.
+	LayerEnemyBullet
+
+	// LayerWall is the layer of the wall outlines and wall colliders.
+	LayerWall
+
+	// LayerPickup is the layer of items the player can collect.
+	LayerPickup
+
+	// LayerDebris is the layer of debris. Debris only bounces off walls,
+	// which MoveEntity does without layers, so it has no contacts.
+	LayerDebris
+
+	// LayerNone is the empty set of layers.
+	LayerNone CollisionLayer = 0
+
+	// LayerAll is the set of all layers.
+	LayerAll CollisionLayer = ^CollisionLayer(0)
+)
+
+// CollisionLayers describes which layers a shape is on and which layers it collides with.
+type CollisionLayers struct {
+	Layer CollisionLayer // Layers the shape is on
+	Mask  CollisionLayer // Layers the shape collides with
+}
+
+// AllLayers are the layers of shapes that never had their layers set.
+// They are on every layer and collide with everything, like before layers existed.
+var AllLayers = CollisionLayers{Layer: LayerAll, Mask: LayerAll}
+
+// NewCollisionLayers creates the layers for a shape on a single layer,
+// colliding with the layers that layer collides with by default.
+func NewCollisionLayers(layer CollisionLayer) CollisionLayers {
+	return CollisionLayers{Layer: layer, Mask: DefaultMask(layer)}
+}
+
+// DefaultMask returns the layers a layer collides with by default.
+// Bullets only hit the other side, and pickups only react to the player.
+func DefaultMask(layer CollisionLayer) CollisionLayer {
+	switch layer {
+	case LayerPlayer:
+		return LayerEnemy | LayerEnemyBullet | LayerWall | LayerPickup
+	case LayerEnemy:
+		return LayerPlayer | LayerPlayerBullet | LayerWall
+	case LayerPlayerBullet:
+		return LayerEnemy | LayerWall
+	case LayerEnemyBullet:
+		return LayerPlayer | LayerWall
+	case LayerWall:
+		return LayerPlayer | LayerEnemy | LayerPlayerBullet | LayerEnemyBullet
+	case LayerPickup:
+		return LayerPlayer
+	case LayerDebris:
+		return LayerNone
+	default:
+		return LayerAll
+	}
+}
+
+// Interacts reports whether two shapes collide.
+// Both shapes have to include the layer of the other in their mask.
+func (l CollisionLayers) Interacts(other CollisionLayers) bool {
+	return l.Mask&other.Layer != 0 && other.Mask&l.Layer != 0
+}
@@ -0,0 +1,83 @@
+package physics
+
+import (
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+	"testing"
+)
+
+// TestResolvePolygonAndSegment tests the contact normal and depth of a rotated polygon against a wall segment
+func TestResolvePolygonAndSegment(t *testing.T) {
+	cs := NewEbitenCollisionSystem(100.0)
+
+	// A square rotated by 45 degrees, with its lower corner 2 units below a horizontal wall
+	square := &PolygonShape{
+		Position: math.Vector{X: 50, Y: 50},
+		Vertices: []math.Vector{{X: -10, Y: -10}, {X: 10, Y: -10}, {X: 10, Y: 10}, {X: -10, Y: 10}},
+		Rotation: stdmath.Pi / 4,
+	}
+	wallY := 50 + 10*stdmath.Sqrt2 - 2
+	wall := &SegmentShape{A: math.Vector{X: 0, Y: wallY}, B: math.Vector{X: 100, Y: wallY}}
+
+	cs.AddShape(square)
+	cs.AddShape(wall)
+
+	collisions, err := cs.Resolve(func(self, other Shape) bool { return self == square })
+	if err != nil {
+		t.Fatalf("Error resolving collisions: %v", err)
+	}
+	if len(collisions) != 1 {
+		t.Fatalf("Expected 1 collision, got %d", len(collisions))
+	}
+
+	// The square is pushed up, out of the wall
+	collision := collisions[0]
+	if stdmath.Abs(collision.Normal.X) > 1e-6 || stdmath.Abs(collision.Normal.Y+1) > 1e-6 {
+		t.Errorf("Expected normal (0, -1), got %v", collision.Normal)
+	}
+	if stdmath.Abs(collision.Depth-2) > 1e-6 {
+		t.Errorf("Expected depth 2, got %v", collision.Depth)
+	}
+
+	// Turned back to an axis-aligned square, it no longer reaches the wall
+	square.Rotation = 0
+	cs.UpdateShape(1, square)
+	collisions, _ = cs.Resolve(nil)
+	if len(collisions) != 0 {
+		t.Errorf("Expected no collisions after rotating the square, got %d", len(collisions))
+	}
+}
+
+// TestCheckContinuousCircleSegmentCollision tests that a fast circle cannot pass through a wall segment
+func TestCheckContinuousCircleSegmentCollision(t *testing.T) {
+	wall := WallSegment{A: math.Vector{X: 0, Y: 0}, B: math.Vector{X: 0, Y: 100}, Normal: math.Vector{X: -1, Y: 0}}
+
+	testCases := []struct {
+		name       string
+		start, end math.Vector
+		expected   bool
+		time       float64
+	}{
+		{name: "Through the side", start: math.Vector{X: -50, Y: 50}, end: math.Vector{X: 50, Y: 50}, expected: true, time: 0.45},
+		{name: "Around the end", start: math.Vector{X: -50, Y: -20}, end: math.Vector{X: 50, Y: -20}, expected: false},
+		{name: "Onto the end point", start: math.Vector{X: 0, Y: -50}, end: math.Vector{X: 0, Y: 50}, expected: true, time: 0.45},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collision, _, normal, time := CheckContinuousCircleSegmentCollision(tc.start, tc.end, 5, wall)
+			if collision != tc.expected {
+				t.Fatalf("Expected collision %v, got %v", tc.expected, collision)
+			}
+			if !collision {
+				return
+			}
+			if stdmath.Abs(time-tc.time) > 1e-6 {
+				t.Errorf("Expected time %v, got %v", tc.time, time)
+			}
+			if dot := normal.X*(tc.start.X-tc.end.X) + normal.Y*(tc.start.Y-tc.end.Y); dot <= 0 {
+				t.Errorf("Expected the normal %v to point back towards the start", normal)
+			}
+		})
+	}
+}
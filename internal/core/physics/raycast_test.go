@@ -0,0 +1,55 @@
+package physics
+
+import (
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+	"testing"
+)
+
+// TestRaycast tests that a ray returns the nearest shape on the mask layers with its hit point and normal
+func TestRaycast(t *testing.T) {
+	cm := NewCollisionManager(100.0)
+
+	// Two walls across the ray, the nearer one in a different cell than the origin
+	near := WallSegment{A: math.Vector{X: -250, Y: -50}, B: math.Vector{X: -250, Y: 50}}
+	far := WallSegment{A: math.Vector{X: -400, Y: -50}, B: math.Vector{X: -400, Y: 50}}
+	cm.RegisterWallSegment(far)
+	cm.RegisterWallSegment(near)
+
+	// An enemy in front of the walls
+	enemy := "enemy"
+	cm.RegisterEntity(enemy, CircleCollider{Position: math.Vector{X: -100, Y: 0}, Radius: 10})
+	cm.SetEntityLayers(enemy, NewCollisionLayers(LayerEnemy))
+
+	hit, found := cm.Raycast(math.Vector{X: 0, Y: 0}, math.Vector{X: -2, Y: 0}, 1000, LayerEnemy|LayerWall)
+	if !found || hit.Entity != enemy || hit.Layer != LayerEnemy {
+		t.Fatalf("Expected to hit the enemy, got %+v", hit)
+	}
+	if stdmath.Abs(hit.Distance-90) > 1e-6 || stdmath.Abs(hit.Point.X+90) > 1e-6 {
+		t.Errorf("Expected a hit at distance 90, got %v at %v", hit.Distance, hit.Point)
+	}
+
+	// Walls only: the enemy is ignored and the nearer wall is hit
+	hit, found = cm.Raycast(math.Vector{X: 0, Y: 0}, math.Vector{X: -1, Y: 0}, 1000, LayerWall)
+	if !found || hit.Entity != nil {
+		t.Fatalf("Expected to hit a wall, got %+v", hit)
+	}
+	if stdmath.Abs(hit.Distance-250) > 1e-6 {
+		t.Errorf("Expected to hit the nearer wall at distance 250, got %v", hit.Distance)
+	}
+	if stdmath.Abs(hit.Normal.X-1) > 1e-6 || stdmath.Abs(hit.Normal.Y) > 1e-6 {
+		t.Errorf("Expected normal (1, 0), got %v", hit.Normal)
+	}
+
+	// Too short to reach the walls
+	if _, found := cm.Raycast(math.Vector{X: 0, Y: 0}, math.Vector{X: -1, Y: 0}, 200, LayerWall); found {
+		t.Errorf("Expected no hit within 200 units")
+	}
+
+	if cm.LineOfSight(math.Vector{X: 0, Y: 0}, math.Vector{X: -300, Y: 0}) {
+		t.Errorf("Expected the wall to block the line of sight")
+	}
+	if !cm.LineOfSight(math.Vector{X: 0, Y: 0}, math.Vector{X: -300, Y: 100}) {
+		t.Errorf("Expected a clear line of sight past the end of the walls")
+	}
+}
@@ -2,17 +2,24 @@ package physics
 
 import (
 	"discoveryx/internal/utils/math"
-	"fmt"
+	stdmath "math"
 )
 
 // SpatialGrid is a simple spatial partitioning system that divides the world into a grid
 // of cells to optimize collision detection. This allows for O(1) lookup of nearby entities
 // instead of checking all entities against each other (O(n²)).
 type SpatialGrid struct {
-	cellSize  float64                      // Size of each grid cell
-	grid      map[string][]interface{}     // Grid cells containing entities
-	positions map[interface{}]math.Vector  // Entity positions for quick lookup
-	cellKeys  map[interface{}]string       // Cell keys for each entity for quick removal
+	cellSize  float64                     // Size of each grid cell
+	grid      map[gridCell][]interface{}  // Grid cells containing entities
+	positions map[interface{}]math.Vector // Entity positions for quick lookup
+	cellKeys  map[interface{}]gridCell    // Cell keys for each entity for quick removal
+	free      [][]interface{}             // Emptied cell slices, reused when a cell is filled again
+}
+
+// gridCell is the key of a grid cell. Using a struct instead of a formatted
+// string keeps lookups free of allocations.
+type gridCell struct {
+	x, y int
 }
 
 // NewSpatialGrid creates a new spatial grid with the specified cell size.
@@ -21,20 +28,27 @@ type SpatialGrid struct {
 func NewSpatialGrid(cellSize float64) *SpatialGrid {
 	return &SpatialGrid{
 		cellSize:  cellSize,
-		grid:      make(map[string][]interface{}),
+		grid:      make(map[gridCell][]interface{}),
 		positions: make(map[interface{}]math.Vector),
-		cellKeys:  make(map[interface{}]string),
+		cellKeys:  make(map[interface{}]gridCell),
 	}
 }
 
-// getCellKey returns a string key for a grid cell based on its coordinates
-func getCellKey(x, y int) string {
-	return fmt.Sprintf("%d,%d", x, y)
+// getCellKey returns the key for a grid cell based on its coordinates
+func getCellKey(x, y int) gridCell {
+	return gridCell{x: x, y: y}
 }
 
-// getCellCoords returns the grid cell coordinates for a world position
+// getCellCoords returns the grid cell coordinates for a world position.
+// Coordinates are floored like worldgen.FloorDiv, so the cells left of and above
+// the origin are as large as all others.
 func (sg *SpatialGrid) getCellCoords(position math.Vector) (int, int) {
-	return int(position.X / sg.cellSize), int(position.Y / sg.cellSize)
+	return sg.cellCoord(position.X), sg.cellCoord(position.Y)
+}
+
+// cellCoord returns the grid cell coordinate of a world coordinate
+func (sg *SpatialGrid) cellCoord(value float64) int {
+	return int(stdmath.Floor(value / sg.cellSize))
 }
 
 // Insert adds an entity to the spatial grid at the specified position
@@ -51,7 +65,12 @@ func (sg *SpatialGrid) Insert(entity interface{}, position math.Vector) {
 
 	// Add the entity to the grid cell
 	if _, exists := sg.grid[cellKey]; !exists {
-		sg.grid[cellKey] = make([]interface{}, 0, 8) // Pre-allocate for efficiency
+		if n := len(sg.free); n > 0 {
+			sg.grid[cellKey] = sg.free[n-1]
+			sg.free = sg.free[:n-1]
+		} else {
+			sg.grid[cellKey] = make([]interface{}, 0, 8) // Pre-allocate for efficiency
+		}
 	}
 	sg.grid[cellKey] = append(sg.grid[cellKey], entity)
 }
@@ -68,15 +87,18 @@ func (sg *SpatialGrid) Remove(entity interface{}) {
 	entities := sg.grid[cellKey]
 	for i, e := range entities {
 		if e == entity {
-			// Remove by swapping with the last element and truncating
+			// Remove by swapping with the last element and truncating. The freed slot is
+			// cleared, so the slice (which may be kept for reuse) does not keep the entity alive.
 			entities[i] = entities[len(entities)-1]
+			entities[len(entities)-1] = nil
 			sg.grid[cellKey] = entities[:len(entities)-1]
 			break
 		}
 	}
 
-	// Clean up empty cells
-	if len(sg.grid[cellKey]) == 0 {
+	// Clean up empty cells, keeping their slice for reuse
+	if remaining := sg.grid[cellKey]; len(remaining) == 0 {
+		sg.free = append(sg.free, remaining[:0])
 		delete(sg.grid, cellKey)
 	}
 
@@ -127,40 +149,34 @@ func (sg *SpatialGrid) QueryNearby(position math.Vector) []interface{} {
 // QueryRadius returns all entities within the specified radius of the position
 func (sg *SpatialGrid) QueryRadius(position math.Vector, radius float64) []interface{} {
 	// Calculate the grid cells that could contain entities within the radius
-	minCellX := int((position.X - radius) / sg.cellSize)
-	maxCellX := int((position.X + radius) / sg.cellSize)
-	minCellY := int((position.Y - radius) / sg.cellSize)
-	maxCellY := int((position.Y + radius) / sg.cellSize)
+	minCellX := sg.cellCoord(position.X - radius)
+	maxCellX := sg.cellCoord(position.X + radius)
+	minCellY := sg.cellCoord(position.Y - radius)
+	maxCellY := sg.cellCoord(position.Y + radius)
 
-	// Collect all entities in those cells
-	var result []interface{}
+	// Collect the entities in those cells that are within the actual distance
+	var filtered []interface{}
 	for cellX := minCellX; cellX <= maxCellX; cellX++ {
 		for cellY := minCellY; cellY <= maxCellY; cellY++ {
-			cellKey := getCellKey(cellX, cellY)
-			if entities, exists := sg.grid[cellKey]; exists {
-				result = append(result, entities...)
+			for _, entity := range sg.grid[getCellKey(cellX, cellY)] {
+				entityPos := sg.positions[entity]
+				dx := entityPos.X - position.X
+				dy := entityPos.Y - position.Y
+				distSquared := dx*dx + dy*dy
+				if distSquared <= radius*radius {
+					filtered = append(filtered, entity)
+				}
 			}
 		}
 	}
 
-	// Filter entities by actual distance
-	var filtered []interface{}
-	for _, entity := range result {
-		entityPos := sg.positions[entity]
-		dx := entityPos.X - position.X
-		dy := entityPos.Y - position.Y
-		distSquared := dx*dx + dy*dy
-		if distSquared <= radius*radius {
-			filtered = append(filtered, entity)
-		}
-	}
-
 	return filtered
 }
 
 // Clear removes all entities from the spatial grid
 func (sg *SpatialGrid) Clear() {
-	sg.grid = make(map[string][]interface{})
+	sg.grid = make(map[gridCell][]interface{})
 	sg.positions = make(map[interface{}]math.Vector)
-	sg.cellKeys = make(map[interface{}]string)
+	sg.cellKeys = make(map[interface{}]gridCell)
+	sg.free = nil
 }
@@ -0,0 +1,108 @@
+package physics
+
+import (
+	"discoveryx/internal/utils/math"
+	"testing"
+)
+
+// newBenchmarkGrid creates a grid with count entities spread over a 5000x5000 area
+func newBenchmarkGrid(count int) (*SpatialGrid, []int) {
+	grid := NewSpatialGrid(100)
+	entities := make([]int, count)
+	for i := range entities {
+		entities[i] = i
+		grid.Insert(&entities[i], math.Vector{X: float64(i*37%5000) - 2500, Y: float64(i*91%5000) - 2500})
+	}
+	return grid, entities
+}
+
+// BenchmarkSpatialGridUpdate measures moving every entity once, as done each frame
+func BenchmarkSpatialGridUpdate(b *testing.B) {
+	grid, entities := newBenchmarkGrid(500)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		offset := float64(i % 100)
+		for j := range entities {
+			grid.Update(&entities[j], math.Vector{X: float64(j*37%5000) - 2500 + offset, Y: float64(j*91%5000) - 2500})
+		}
+	}
+}
+
+// BenchmarkSpatialGridQueryNearby measures looking up the entities near a position
+func BenchmarkSpatialGridQueryNearby(b *testing.B) {
+	grid, _ := newBenchmarkGrid(500)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		grid.QueryNearby(math.Vector{X: float64(i%5000) - 2500, Y: 0})
+	}
+}
+
+// BenchmarkSpatialGridQueryRadius measures looking up the entities within a radius
+func BenchmarkSpatialGridQueryRadius(b *testing.B) {
+	grid, _ := newBenchmarkGrid(500)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		grid.QueryRadius(math.Vector{X: float64(i%5000) - 2500, Y: 0}, 250)
+	}
+}
+
+// TestSpatialGridMoveBetweenCells tests that entities are found in their new cell after moving
+func TestSpatialGridMoveBetweenCells(t *testing.T) {
+	grid := NewSpatialGrid(100)
+	a, b := 1, 2
+
+	grid.Insert(&a, math.Vector{X: 50, Y: 50})
+	grid.Insert(&b, math.Vector{X: 250, Y: 50})
+	grid.Update(&a, math.Vector{X: 260, Y: 60})
+
+	if nearby := grid.QueryNearby(math.Vector{X: 50, Y: 50}); len(nearby) != 0 {
+		t.Errorf("Expected the old cell to be empty, got %v", nearby)
+	}
+	if nearby := grid.QueryNearby(math.Vector{X: 250, Y: 50}); len(nearby) != 2 {
+		t.Errorf("Expected 2 entities in the new cell, got %d", len(nearby))
+	}
+
+	grid.Remove(&b)
+	grid.Insert(&b, math.Vector{X: 50, Y: 50})
+	if found := grid.QueryRadius(math.Vector{X: 50, Y: 50}, 10); len(found) != 1 || found[0] != &b {
+		t.Errorf("Expected only b near the origin cell, got %v", found)
+	}
+}
+
+// TestSpatialGridNegativeCells tests that cells are floored, so positions on both sides of the origin are in different cells
+func TestSpatialGridNegativeCells(t *testing.T) {
+	grid := NewSpatialGrid(100)
+	a, b := 1, 2
+
+	grid.Insert(&a, math.Vector{X: -50, Y: -50})
+	grid.Insert(&b, math.Vector{X: 50, Y: 50})
+	if nearby := grid.QueryNearby(math.Vector{X: -99, Y: -1}); len(nearby) != 1 || nearby[0] != &a {
+		t.Errorf("Expected only a in the cell left of and above the origin, got %v", nearby)
+	}
+	if found := grid.QueryRadius(math.Vector{X: -150, Y: -50}, 101); len(found) != 1 || found[0] != &a {
+		t.Errorf("Expected a within the radius across the cell border, got %v", found)
+	}
+}
+
+// TestSpatialGridReleasesRemoved tests that the slices kept for reuse do not hold on to removed entities
+func TestSpatialGridReleasesRemoved(t *testing.T) {
+	grid := NewSpatialGrid(100)
+	a, b := 1, 2
+
+	grid.Insert(&a, math.Vector{X: 10, Y: 10})
+	grid.Insert(&b, math.Vector{X: 20, Y: 20})
+	grid.Remove(&a)
+	grid.Remove(&b)
+
+	if len(grid.free) != 1 {
+		t.Fatalf("Expected the emptied cell slice to be kept for reuse, got %d", len(grid.free))
+	}
+	for _, entity := range grid.free[0][:cap(grid.free[0])] {
+		if entity != nil {
+			t.Errorf("Expected no entity in the reused slice, got %v", entity)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package physics
+
+import (
+	"discoveryx/internal/core/physics/collisions"
+	"discoveryx/internal/utils/math"
+	"fmt"
+	stdmath "math"
+)
+
+// sweepSkin is the distance a swept shape stops in front of an obstacle.
+// Keeping a small gap means the next sweep starts outside of the obstacle and can slide along it.
+const sweepSkin = 0.01
+
+// SweepHit describes the first obstacle a moving shape runs into.
+type SweepHit struct {
+	// Shape is the obstacle that was hit
+	Shape Shape
+
+	// Time is the fraction of the movement (0-1) that was completed before the hit
+	Time float64
+
+	// Position is where the moving shape stops, just in front of the obstacle
+	Position math.Vector
+
+	// Normal points from the obstacle towards the moving shape
+	Normal math.Vector
+
+	// Slide is the part of the remaining movement that runs along the obstacle
+	Slide math.Vector
+
+	// Depth is the penetration depth if the shape already overlapped the obstacle at the start
+	Depth float64
+
+	// ImpactSpeed is the speed into the obstacle in units per second.
+	// It is set by World for the hits of bodies, and 0 for plain sweeps.
+	ImpactSpeed float64
+}
+
+// SweepShape moves a shape by the movement vector and returns the first obstacle it hits.
+// The shape itself is not modified. If the shape already overlaps an obstacle,
+// the hit has a time of 0 and the penetration depth is set.
+func SweepShape(shape Shape, movement math.Vector, obstacles []Shape) (SweepHit, bool) {
+	return sweepObject(newObject(shape), shape.GetPosition(), movement, obstacles, obstacleObjects(obstacles))
+}
+
+// MoveAndSlide moves a shape by the movement vector through the obstacles.
+// When the shape hits an obstacle, it stops in front of it and continues with the
+// part of the remaining movement that runs along the obstacle, up to maxSlides times.
+// Shapes that start inside an obstacle are pushed out first, even if they do not move.
+// It returns the final position of the shape and all obstacles that were hit.
+// The shape itself is not modified.
+func MoveAndSlide(shape Shape, movement math.Vector, obstacles []Shape, maxSlides int) (math.Vector, []SweepHit) {
+	obj := newObject(shape)
+	objects := obstacleObjects(obstacles)
+	position := shape.GetPosition()
+	remaining := movement
+
+	var hits []SweepHit
+	for i := 0; i <= maxSlides; i++ {
+		hit, found := sweepObject(obj, position, remaining, obstacles, objects)
+		if !found {
+			moveObject(obj, remaining)
+			position = math.Vector{X: position.X + remaining.X, Y: position.Y + remaining.Y}
+			break
+		}
+
+		moveObject(obj, math.Vector{X: hit.Position.X - position.X, Y: hit.Position.Y - position.Y})
+		position = hit.Position
+
+		if hit.Depth > 0 {
+			// Pushed out of the obstacle, now try the same movement again
+			hits = append(hits, hit)
+			continue
+		}
+
+		// Continue with the rest of the movement along the obstacle
+		rest := math.Vector{X: remaining.X * (1 - hit.Time), Y: remaining.Y * (1 - hit.Time)}
+		hit.Slide = slide(rest, hit.Normal)
+		hits = append(hits, hit)
+		remaining = hit.Slide
+		if remaining.X == 0 && remaining.Y == 0 {
+			break
+		}
+	}
+
+	return position, hits
+}
+
+// FindOverlap returns the deepest overlap of a shape with the obstacles.
+// The collision normal pushes the shape out of the obstacle.
+func FindOverlap(shape Shape, obstacles []Shape) (Collision, bool) {
+	obj := newObject(shape)
+
+	var deepest Collision
+	found := false
+	for i, other := range obstacleObjects(obstacles) {
+		contact, overlapping := collisions.Penetration(obj, other)
+		if !overlapping || (found && contact.Depth <= deepest.Depth) {
+			continue
+		}
+
+		deepest = Collision{
+			ShapeA: shape,
+			ShapeB: obstacles[i],
+			Normal: math.Vector{X: contact.NormalX, Y: contact.NormalY},
+			Depth:  contact.Depth,
+			Point:  math.Vector{X: contact.PointX, Y: contact.PointY},
+		}
+		found = true
+	}
+
+	return deepest, found
+}
+
+// CheckPolygonWallOverlap checks if a polygon collider overlaps any of the wall segments.
+// It does not need the walls to be registered, so it can test positions anywhere in the world.
+// It returns the normal that pushes the polygon out of the deepest wall and the penetration depth.
+func CheckPolygonWallOverlap(collider PolygonCollider, segments []WallSegment) (bool, math.Vector, float64) {
+	obstacles := make([]Shape, len(segments))
+	for i, segment := range segments {
+		obstacles[i] = &SegmentShape{A: segment.A, B: segment.B}
+	}
+
+	collision, overlapping := FindOverlap(collider.toShape(), obstacles)
+	return overlapping, collision.Normal, collision.Depth
+}
+
+// sweepObject finds the first obstacle hit by a collision object that is moved from
+// its position by the movement vector.
+func sweepObject(obj collisions.Object, position, movement math.Vector, obstacles []Shape, objects []collisions.Object) (SweepHit, bool) {
+	var best collisions.SweepContact
+	bestIndex := -1
+	for i, other := range objects {
+		contact, hit := collisions.Sweep(obj, movement.X, movement.Y, other)
+		if !hit {
+			continue
+		}
+
+		// Prefer the earliest hit, and the deepest one among overlaps
+		if bestIndex < 0 || contact.Time < best.Time || (contact.Time == best.Time && contact.Depth > best.Depth) {
+			best, bestIndex = contact, i
+		}
+	}
+
+	if bestIndex < 0 {
+		return SweepHit{}, false
+	}
+
+	hit := SweepHit{
+		Shape:  obstacles[bestIndex],
+		Time:   best.Time,
+		Normal: math.Vector{X: best.NormalX, Y: best.NormalY},
+		Depth:  best.Depth,
+	}
+
+	if best.Depth > 0 {
+		// Already overlapping: push the shape out along the normal
+		push := best.Depth + sweepSkin
+		hit.Position = math.Vector{X: position.X + hit.Normal.X*push, Y: position.Y + hit.Normal.Y*push}
+		return hit, true
+	}
+
+	// Stop a little before the point of impact
+	hit.Position = position
+	if distance := stdmath.Sqrt(movement.X*movement.X + movement.Y*movement.Y); distance > 0 {
+		travel := stdmath.Max(best.Time*distance-sweepSkin, 0) / distance
+		hit.Position = math.Vector{X: position.X + movement.X*travel, Y: position.Y + movement.Y*travel}
+	}
+	return hit, true
+}
+
+// slide removes the part of a movement that runs into a surface with the given normal.
+func slide(movement, normal math.Vector) math.Vector {
+	into := movement.X*normal.X + movement.Y*normal.Y
+	if into >= 0 {
+		return movement
+	}
+	return math.Vector{X: movement.X - normal.X*into, Y: movement.Y - normal.Y*into}
+}
+
+// shapeExtent returns the distance from the position of a shape to its farthest point.
+func shapeExtent(shape Shape) float64 {
+	switch s := shape.(type) {
+	case *CircleShape:
+		return s.Radius
+	case *AABBShape:
+		return stdmath.Hypot(s.Width/2, s.Height/2)
+	case *PolygonShape:
+		extent := 0.0
+		for _, vertex := range s.Vertices {
+			extent = stdmath.Max(extent, stdmath.Hypot(vertex.X, vertex.Y))
+		}
+		return extent
+	case *SegmentShape:
+		return math.Distance(s.A, s.B) / 2
+	default:
+		return 0
+	}
+}
+
+// moveObject moves a collision object by an offset.
+func moveObject(obj collisions.Object, offset math.Vector) {
+	obj.SetX(obj.GetX() + offset.X)
+	obj.SetY(obj.GetY() + offset.Y)
+}
+
+// obstacleObjects converts a list of shapes to collision objects.
+func obstacleObjects(obstacles []Shape) []collisions.Object {
+	objects := make([]collisions.Object, len(obstacles))
+	for i, obstacle := range obstacles {
+		objects[i] = newObject(obstacle)
+	}
+	return objects
+}
+
+// newObject creates the collision object for a shape.
+func newObject(shape Shape) collisions.Object {
+	switch shape.GetType() {
+	case ShapeTypeCircle:
+		circleShape, ok := shape.(*CircleShape)
+		if !ok {
+			panic("Shape with type ShapeTypeCircle is not a *CircleShape")
+		}
+
+		return collisions.NewCircle(
+			circleShape.Position.X,
+			circleShape.Position.Y,
+			circleShape.Radius,
+		)
+
+	case ShapeTypeAABB:
+		aabbShape, ok := shape.(*AABBShape)
+		if !ok {
+			panic("Shape with type ShapeTypeAABB is not a *AABBShape")
+		}
+
+		return collisions.NewRectangle(
+			aabbShape.Position.X-aabbShape.Width/2,
+			aabbShape.Position.Y-aabbShape.Height/2,
+			aabbShape.Width,
+			aabbShape.Height,
+		)
+
+	case ShapeTypePolygon:
+		polygonShape, ok := shape.(*PolygonShape)
+		if !ok {
+			panic("Shape with type ShapeTypePolygon is not a *PolygonShape")
+		}
+
+		return collisions.NewPolygon(
+			polygonShape.Position.X,
+			polygonShape.Position.Y,
+			polygonVertices(polygonShape),
+		)
+
+	case ShapeTypeSegment:
+		segmentShape, ok := shape.(*SegmentShape)
+		if !ok {
+			panic("Shape with type ShapeTypeSegment is not a *SegmentShape")
+		}
+
+		return collisions.NewSegment(
+			segmentShape.A.X,
+			segmentShape.A.Y,
+			segmentShape.B.X,
+			segmentShape.B.Y,
+		)
+
+	default:
+		panic(fmt.Sprintf("Unsupported shape type: %v", shape.GetType()))
+	}
+}
@@ -0,0 +1,70 @@
+package physics
+
+import (
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+	"testing"
+)
+
+// TestSweepShapeThinWall tests that a fast polygon stops at a thin wall instead of passing through it
+func TestSweepShapeThinWall(t *testing.T) {
+	triangle := &PolygonShape{
+		Position: math.Vector{X: 0, Y: 0},
+		Vertices: []math.Vector{{X: 0, Y: -5}, {X: 5, Y: 5}, {X: -5, Y: 5}},
+	}
+	wall := &SegmentShape{A: math.Vector{X: 100, Y: -50}, B: math.Vector{X: 100, Y: 50}}
+
+	// Moves 1000 units in a single step, far past the wall
+	hit, found := SweepShape(triangle, math.Vector{X: 1000, Y: 0}, []Shape{wall})
+	if !found {
+		t.Fatalf("Expected the triangle to hit the wall")
+	}
+	if hit.Shape != wall {
+		t.Errorf("Expected the hit shape to be the wall")
+	}
+	if stdmath.Abs(hit.Time-0.095) > 1e-6 {
+		t.Errorf("Expected time of impact 0.095, got %v", hit.Time)
+	}
+	if stdmath.Abs(hit.Normal.X+1) > 1e-6 || stdmath.Abs(hit.Normal.Y) > 1e-6 {
+		t.Errorf("Expected normal (-1, 0), got %v", hit.Normal)
+	}
+	if hit.Position.X > 95 || hit.Position.X < 94.9 {
+		t.Errorf("Expected the triangle to stop just before the wall, got %v", hit.Position)
+	}
+	if triangle.Position.X != 0 {
+		t.Errorf("Expected the swept shape not to be moved, got %v", triangle.Position)
+	}
+}
+
+// TestMoveAndSlide tests that a circle slides along a wall and is pushed out of walls it starts in
+func TestMoveAndSlide(t *testing.T) {
+	floor := &SegmentShape{A: math.Vector{X: -100, Y: 10}, B: math.Vector{X: 100, Y: 10}}
+
+	t.Run("Slide along the floor", func(t *testing.T) {
+		circle := &CircleShape{Position: math.Vector{X: 0, Y: 0}, Radius: 5}
+
+		// Moves diagonally into the floor
+		position, hits := MoveAndSlide(circle, math.Vector{X: 20, Y: 20}, []Shape{floor}, 3)
+		if len(hits) != 1 {
+			t.Fatalf("Expected 1 hit, got %d", len(hits))
+		}
+		if stdmath.Abs(hits[0].Slide.X-15) > 1e-6 || stdmath.Abs(hits[0].Slide.Y) > 1e-6 {
+			t.Errorf("Expected slide (15, 0), got %v", hits[0].Slide)
+		}
+		if stdmath.Abs(position.X-20) > 0.1 || position.Y > 5 || position.Y < 4.9 {
+			t.Errorf("Expected the circle to end at (20, 5) on the floor, got %v", position)
+		}
+	})
+
+	t.Run("Pushed out of the floor", func(t *testing.T) {
+		circle := &CircleShape{Position: math.Vector{X: 0, Y: 8}, Radius: 5}
+
+		position, hits := MoveAndSlide(circle, math.Vector{}, []Shape{floor}, 3)
+		if len(hits) == 0 || hits[0].Depth <= 0 {
+			t.Fatalf("Expected an overlapping hit, got %+v", hits)
+		}
+		if position.Y > 5 || position.Y < 4.9 {
+			t.Errorf("Expected the circle to be pushed up to y=5, got %v", position)
+		}
+	})
+}
@@ -235,3 +235,9 @@ type WallPoint struct {
 	X, Y   float64     // Coordinates
 	Normal math.Vector // Normal vector of the wall (points away from the solid part)
 }
+
+// WallSegment represents a straight piece of a wall outline.
+type WallSegment struct {
+	A, B   math.Vector // End points of the segment
+	Normal math.Vector // Normal vector of the wall (points away from the solid part)
+}
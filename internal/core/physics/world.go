@@ -0,0 +1,251 @@
+package physics
+
+import (
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+)
+
+// Body is the rigid body state of an entity that is moved by the physics world.
+// Velocities are in units per second, so the movement does not depend on the frame rate.
+type Body struct {
+	// Entity is the entity the body belongs to
+	Entity interface{}
+
+	Position        math.Vector // Position in world coordinates
+	Velocity        math.Vector // Velocity in units per second
+	Rotation        float64     // Rotation in radians (clockwise, like the sprites)
+	AngularVelocity float64     // Angular velocity in radians per second
+
+	Mass float64 // Mass for forces; bodies without mass are moved by forces as if they had a mass of 1
+	Drag float64 // Rate per second at which the velocity decays, 0 for none
+
+	// Contact is how the body bounces off, slides along and is damaged by the walls it hits.
+	// The zero profile stops at walls and slides along them without friction.
+	Contact ContactProfile
+
+	// IgnoreForces keeps the force volumes of the world from moving the body, for
+	// entities that are mounted in place. Gravity still applies.
+	IgnoreForces bool
+
+	// CollideWithWalls makes the body stop at walls and slide along them.
+	// The entity must be registered with the collision manager of the world.
+	CollideWithWalls bool
+
+	// Controller is called around every step, for example to steer the body from input
+	Controller BodyController
+
+	force            math.Vector // Force applied until the next step
+	previousPosition math.Vector // Position before the last step, for interpolation
+	previousRotation float64     // Rotation before the last step, for interpolation
+	renderPosition   math.Vector // Position between the last two steps at the time of the last update
+	renderRotation   float64     // Rotation between the last two steps at the time of the last update
+}
+
+// BodyController steers a body and reads back its state.
+// Entities implement it to keep their own state in sync with their body.
+type BodyController interface {
+	// BeforeStep is called before the body is moved, to set its velocity or apply forces.
+	BeforeStep(body *Body, dt float64)
+	// AfterStep is called after the body was moved, with the walls it hit and the speed of each impact.
+	AfterStep(body *Body, hits []SweepHit)
+}
+
+// NewBody creates a body for an entity at a position, with a mass of 1.
+func NewBody(entity interface{}, position math.Vector) *Body {
+	return &Body{
+		Entity:           entity,
+		Position:         position,
+		Mass:             1,
+		previousPosition: position,
+		renderPosition:   position,
+	}
+}
+
+// ApplyForce applies a force to the body until the next step.
+func (b *Body) ApplyForce(force math.Vector) {
+	b.force.X += force.X
+	b.force.Y += force.Y
+}
+
+// ApplyImpulse changes the velocity of the body at once, scaled by its mass.
+func (b *Body) ApplyImpulse(impulse math.Vector) {
+	inverseMass := b.inverseMass()
+	b.Velocity.X += impulse.X * inverseMass
+	b.Velocity.Y += impulse.Y * inverseMass
+}
+
+// SetPosition moves the body without interpolating from its previous position.
+func (b *Body) SetPosition(position math.Vector) {
+	b.Position = position
+	b.previousPosition = position
+	b.renderPosition = position
+}
+
+// RenderPosition returns the position to draw the body at. It lies between the last two steps,
+// so movement looks smooth when the frame rate differs from the step rate.
+func (b *Body) RenderPosition() math.Vector {
+	return b.renderPosition
+}
+
+// RenderRotation returns the rotation to draw the body with, between the last two steps.
+func (b *Body) RenderRotation() float64 {
+	return b.renderRotation
+}
+
+// inverseMass returns the inverse of the mass, treating bodies without mass as having a mass of 1.
+func (b *Body) inverseMass() float64 {
+	if b.Mass <= 0 {
+		return 1
+	}
+	return 1 / b.Mass
+}
+
+// World steps rigid bodies at a fixed rate.
+// The time of each update is collected in an accumulator and spent in steps of the same length,
+// so the simulation behaves the same at every frame rate. Positions for drawing are interpolated
+// between the last two steps.
+type World struct {
+	// Gravity is the acceleration applied to all bodies, in units per second squared
+	Gravity math.Vector
+
+	// Forces is the environment, like currents and gravity wells, that accelerates the
+	// bodies depending on where they are. It may be nil.
+	Forces ForceField
+
+	timestep         float64           // Length of a step in seconds
+	maxSteps         int               // Steps after which an update drops the remaining time
+	accumulator      float64           // Time that has not been stepped yet
+	time             float64           // Time that has been stepped, for forces that change over time
+	bodies           []*Body           // Bodies in the order they were added
+	collisionManager *CollisionManager // Used for bodies that collide with walls, may be nil
+}
+
+// NewWorld creates a physics world that steps stepsPerSecond times per second,
+// at most maxSteps times per update. Bodies that collide with walls use the collision manager.
+func NewWorld(stepsPerSecond, maxSteps int, collisionManager *CollisionManager) *World {
+	return &World{
+		timestep:         1 / float64(stepsPerSecond),
+		maxSteps:         maxSteps,
+		collisionManager: collisionManager,
+	}
+}
+
+// AddBody adds a body to the world.
+func (w *World) AddBody(body *Body) {
+	w.bodies = append(w.bodies, body)
+}
+
+// RemoveBody removes a body from the world.
+func (w *World) RemoveBody(body *Body) {
+	for i, b := range w.bodies {
+		if b == body {
+			w.bodies = append(w.bodies[:i], w.bodies[i+1:]...)
+			return
+		}
+	}
+}
+
+// Timestep returns the length of a step in seconds.
+func (w *World) Timestep() float64 {
+	return w.timestep
+}
+
+// Update advances the world by the elapsed time and returns the number of steps taken.
+// Time left over for less than a full step is kept for the next update.
+func (w *World) Update(deltaTime float64) int {
+	w.accumulator += deltaTime
+
+	steps := 0
+	for w.accumulator >= w.timestep && steps < w.maxSteps {
+		w.step(w.timestep)
+		w.accumulator -= w.timestep
+		steps++
+	}
+
+	// Drop the time that could not be stepped, instead of falling further behind
+	if w.accumulator >= w.timestep {
+		w.accumulator = 0
+	}
+
+	// Interpolate between the last two steps for drawing
+	alpha := w.accumulator / w.timestep
+	for _, body := range w.bodies {
+		body.renderPosition = math.Vector{
+			X: body.previousPosition.X + (body.Position.X-body.previousPosition.X)*alpha,
+			Y: body.previousPosition.Y + (body.Position.Y-body.previousPosition.Y)*alpha,
+		}
+		body.renderRotation = body.previousRotation + (body.Rotation-body.previousRotation)*alpha
+	}
+
+	return steps
+}
+
+// step moves all bodies by one step.
+func (w *World) step(dt float64) {
+	for _, body := range w.bodies {
+		body.previousPosition = body.Position
+		body.previousRotation = body.Rotation
+
+		if body.Controller != nil {
+			body.Controller.BeforeStep(body, dt)
+		}
+
+		// Accumulate the forces on the body with the gravity and the environment
+		acceleration := w.Gravity
+		if w.Forces != nil && !body.IgnoreForces {
+			environment := w.Forces.Acceleration(body.Position, w.time)
+			acceleration.X += environment.X
+			acceleration.Y += environment.Y
+		}
+
+		// Semi-implicit Euler: the new velocity moves the body
+		inverseMass := body.inverseMass()
+		body.Velocity.X += (body.force.X*inverseMass + acceleration.X) * dt
+		body.Velocity.Y += (body.force.Y*inverseMass + acceleration.Y) * dt
+		body.force = math.Vector{}
+
+		if body.Drag > 0 {
+			damping := stdmath.Exp(-body.Drag * dt)
+			body.Velocity.X *= damping
+			body.Velocity.Y *= damping
+			body.AngularVelocity *= damping
+		}
+
+		body.Rotation += body.AngularVelocity * dt
+
+		movement := math.Vector{X: body.Velocity.X * dt, Y: body.Velocity.Y * dt}
+		hits := w.move(body, movement)
+
+		if body.Controller != nil {
+			body.Controller.AfterStep(body, hits)
+		}
+	}
+	w.time += dt
+}
+
+// move moves a body and, if it collides with walls, responds to them with the contact profile of the body.
+func (w *World) move(body *Body, movement math.Vector) []SweepHit {
+	if !body.CollideWithWalls || w.collisionManager == nil || (movement.X == 0 && movement.Y == 0) {
+		body.Position.X += movement.X
+		body.Position.Y += movement.Y
+		return nil
+	}
+
+	if !w.collisionManager.SetEntityTransform(body.Entity, body.Position, body.Rotation) {
+		// Not registered, so there is nothing to collide with
+		body.Position.X += movement.X
+		body.Position.Y += movement.Y
+		return nil
+	}
+	position, hits := w.collisionManager.MoveEntity(body.Entity, movement)
+	body.Position = position
+
+	// Bounce off or slide along each wall, and record how hard it was hit
+	for i, hit := range hits {
+		response := body.Contact.Respond(body.Velocity, hit.Normal)
+		body.Velocity = response.Velocity
+		hits[i].ImpactSpeed = response.ImpactSpeed
+	}
+
+	return hits
+}
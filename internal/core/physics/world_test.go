@@ -0,0 +1,139 @@
+package physics
+
+import (
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+	"testing"
+)
+
+// TestWorldFrameRateIndependence tests that bodies end up at the same place at different frame rates
+func TestWorldFrameRateIndependence(t *testing.T) {
+	run := func(frameTime float64, frames int) math.Vector {
+		world := NewWorld(64, 5, nil)
+		world.Gravity = math.Vector{X: 0, Y: 50}
+
+		body := NewBody("body", math.Vector{X: 0, Y: 0})
+		body.Velocity = math.Vector{X: 100, Y: 0}
+		body.Drag = 0.5
+		world.AddBody(body)
+
+		for i := 0; i < frames; i++ {
+			world.Update(frameTime)
+		}
+		return body.Position
+	}
+
+	slow := run(1.0/32, 32)
+	fast := run(1.0/128, 128)
+	if stdmath.Abs(slow.X-fast.X) > 1e-9 || stdmath.Abs(slow.Y-fast.Y) > 1e-9 {
+		t.Errorf("Expected the same position at 32 and 128 frames per second, got %v and %v", slow, fast)
+	}
+}
+
+// TestWorldInterpolation tests the accumulator, the step limit and the render position between steps
+func TestWorldInterpolation(t *testing.T) {
+	world := NewWorld(10, 5, nil)
+	body := NewBody("body", math.Vector{X: 0, Y: 0})
+	body.Velocity = math.Vector{X: 10, Y: 0}
+	world.AddBody(body)
+
+	// A step moves the body by 1, the remaining half step is interpolated
+	if steps := world.Update(0.15); steps != 1 {
+		t.Fatalf("Expected 1 step, got %d", steps)
+	}
+	if stdmath.Abs(body.Position.X-1) > 1e-9 {
+		t.Errorf("Expected the body at x=1, got %v", body.Position.X)
+	}
+	if stdmath.Abs(body.RenderPosition().X-0.5) > 1e-9 {
+		t.Errorf("Expected to draw the body at x=0.5, got %v", body.RenderPosition().X)
+	}
+
+	// A long frame is limited to the maximum number of steps
+	if steps := world.Update(10); steps != 5 {
+		t.Errorf("Expected 5 steps, got %d", steps)
+	}
+}
+
+// TestWorldRestitution tests that bodies slide along walls or bounce off them
+func TestWorldRestitution(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		restitution float64
+		expectedX   float64
+	}{
+		{"Stops at the wall", 0, 0},
+		{"Bounces back", 1, -200},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cm := NewCollisionManager(100.0)
+			cm.RegisterWallSegment(WallSegment{A: math.Vector{X: 20, Y: -100}, B: math.Vector{X: 20, Y: 100}})
+
+			entity := "ball"
+			cm.RegisterEntity(entity, CircleCollider{Position: math.Vector{X: 0, Y: 0}, Radius: 5})
+
+			world := NewWorld(10, 5, cm)
+			body := NewBody(entity, math.Vector{X: 0, Y: 0})
+			body.Velocity = math.Vector{X: 200, Y: 0}
+			body.Contact.Restitution = tc.restitution
+			body.CollideWithWalls = true
+			world.AddBody(body)
+
+			world.Update(0.1)
+
+			if body.Position.X > 15+1e-6 {
+				t.Errorf("Expected the body to stop at the wall, got x=%v", body.Position.X)
+			}
+			if stdmath.Abs(body.Velocity.X-tc.expectedX) > 1e-6 {
+				t.Errorf("Expected velocity %v, got %v", tc.expectedX, body.Velocity.X)
+			}
+		})
+	}
+}
+
+// TestWorldForceField tests that force volumes accelerate the bodies inside them and no others
+func TestWorldForceField(t *testing.T) {
+	forces := NewForceVolumes(100)
+	forces.Add(&ForceVolume{
+		Kind:      ForceCurrent,
+		Center:    math.Vector{X: 0, Y: 0},
+		HalfSize:  math.Vector{X: 150, Y: 50},
+		Strength:  100,
+		Direction: math.Vector{X: 1, Y: 0},
+	})
+	well := &ForceVolume{Kind: ForceGravityWell, Center: math.Vector{X: 0, Y: 500}, Radius: 100, Strength: 100}
+	forces.Add(well)
+
+	world := NewWorld(60, 30, nil)
+	world.Forces = forces
+
+	inCurrent := NewBody("current", math.Vector{X: -100, Y: 0})
+	nearWell := NewBody("well", math.Vector{X: 50, Y: 500})
+	outside := NewBody("outside", math.Vector{X: 0, Y: 300})
+	mounted := NewBody("mounted", math.Vector{X: 100, Y: 0})
+	mounted.IgnoreForces = true
+	for _, body := range []*Body{inCurrent, nearWell, outside, mounted} {
+		world.AddBody(body)
+	}
+
+	world.Update(0.5)
+
+	// Constant acceleration for half a second
+	if stdmath.Abs(inCurrent.Velocity.X-50) > 1e-6 || inCurrent.Velocity.Y != 0 {
+		t.Errorf("Expected the current to speed the body up to (50, 0), got %v", inCurrent.Velocity)
+	}
+	if nearWell.Velocity.X >= 0 {
+		t.Errorf("Expected the gravity well to pull the body towards its center, got %v", nearWell.Velocity)
+	}
+	if outside.Velocity.X != 0 || outside.Velocity.Y != 0 {
+		t.Errorf("Expected no force outside of the volumes, got %v", outside.Velocity)
+	}
+	if mounted.Velocity.X != 0 || mounted.Position.X != 100 {
+		t.Errorf("Expected the current to leave a body that ignores forces in place, got %v at %v", mounted.Velocity, mounted.Position)
+	}
+
+	// Removed volumes no longer act
+	forces.Remove(well)
+	if acceleration := forces.Acceleration(math.Vector{X: 50, Y: 500}, 0); acceleration.X != 0 || acceleration.Y != 0 {
+		t.Errorf("Expected no force after removing the well, got %v", acceleration)
+	}
+}
@@ -0,0 +1,72 @@
+package worldgen
+
+import (
+	"discoveryx/internal/utils/math"
+	"math/rand"
+)
+
+// Arena constants
+const (
+	arenaSpacing    = 4    // Minimum distance in cells between two arenas
+	arenaConnectors = 4    // Connectors of a junction large enough for an arena
+	arenaGateInset  = 10.0 // Distance of the gates from the edges of the arena cell
+)
+
+// markArenas marks cells of the world map as boss arenas. Dead-ends and junctions with a
+// connector on every side qualify; cells of the main path do not, so a boss never blocks
+// the loop. The candidates are taken in a random order from the generator's random stream,
+// keeping the arenas apart, so the same seed marks the same cells.
+func markArenas(worldMap *WorldMap, count int, rng *rand.Rand) {
+	var candidates []*WorldCell
+	for _, cell := range sortedCells(worldMap) {
+		if cell.IsMainPath || cell.Snippet == nil || len(cell.Snippet.Connectors) == 0 {
+			continue
+		}
+		if cell.Snippet.GetType() == SnippetTypeDeadEnd || len(cell.Snippet.Connectors) >= arenaConnectors {
+			candidates = append(candidates, cell)
+		}
+	}
+	rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	var arenas []*WorldCell
+	for _, cell := range candidates {
+		if len(arenas) >= count {
+			break
+		}
+		apart := true
+		for _, arena := range arenas {
+			apart = apart && abs(arena.X-cell.X)+abs(arena.Y-cell.Y) >= arenaSpacing
+		}
+		if apart {
+			cell.IsArena = true
+			arenas = append(arenas, cell)
+		}
+	}
+}
+
+// ArenaGates returns the walls that close the openings of an arena cell, in world coordinates.
+// Each side with a connector is closed along its whole length, just inside the cell, and the
+// normals point into the arena.
+func (c *WorldCell) ArenaGates() []WallSegment {
+	left := float64(c.X*CellSize) + arenaGateInset
+	top := float64(c.Y*CellSize) + arenaGateInset
+	right := float64((c.X+1)*CellSize) - arenaGateInset
+	bottom := float64((c.Y+1)*CellSize) - arenaGateInset
+
+	var gates []WallSegment
+	for _, connector := range c.GetRotatedConnectors() {
+		switch connector {
+		case ConnectorTop:
+			gates = append(gates, WallSegment{A: math.Vector{X: left, Y: top}, B: math.Vector{X: right, Y: top}, Normal: math.Vector{Y: 1}})
+		case ConnectorRight:
+			gates = append(gates, WallSegment{A: math.Vector{X: right, Y: top}, B: math.Vector{X: right, Y: bottom}, Normal: math.Vector{X: -1}})
+		case ConnectorBottom:
+			gates = append(gates, WallSegment{A: math.Vector{X: right, Y: bottom}, B: math.Vector{X: left, Y: bottom}, Normal: math.Vector{Y: -1}})
+		case ConnectorLeft:
+			gates = append(gates, WallSegment{A: math.Vector{X: left, Y: bottom}, B: math.Vector{X: left, Y: top}, Normal: math.Vector{X: 1}})
+		}
+	}
+	return gates
+}
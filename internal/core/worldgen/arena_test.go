@@ -0,0 +1,70 @@
+package worldgen
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestMarkArenas tests that arenas are dead-ends or large junctions off the main path, kept apart and picked by the seed
+func TestMarkArenas(t *testing.T) {
+	deadEnd := &WorldSnippet{Filename: "dead-end.png", Connectors: []SnippetConnector{ConnectorLeft}}
+	cross := &WorldSnippet{Filename: "cross.png", Connectors: []SnippetConnector{ConnectorTop, ConnectorRight, ConnectorBottom, ConnectorLeft}}
+
+	build := func() *WorldMap {
+		worldMap := newLoopWorld()
+		worldMap.AddCell(&WorldCell{X: 4, Y: 0, Snippet: deadEnd})
+		worldMap.AddCell(&WorldCell{X: 4, Y: 1, Snippet: cross})
+		worldMap.AddCell(&WorldCell{X: -3, Y: 0, Snippet: deadEnd, Rotation: 180})
+		worldMap.AddCell(&WorldCell{X: 2, Y: 5, Snippet: cross, IsMainPath: true})
+		return worldMap
+	}
+	arenas := func(worldMap *WorldMap) []CellCoord {
+		var coords []CellCoord
+		for _, cell := range sortedCells(worldMap) {
+			if cell.IsArena {
+				coords = append(coords, CellCoord{X: cell.X, Y: cell.Y})
+			}
+		}
+		return coords
+	}
+
+	first := build()
+	markArenas(first, 3, rand.New(rand.NewSource(7)))
+	marked := arenas(first)
+
+	// Of the two candidates at x=4 only one fits, as they are next to each other
+	if len(marked) != 2 {
+		t.Fatalf("Expected 2 arenas, got %v", marked)
+	}
+	for _, coord := range marked {
+		cell := first.GetCell(coord.X, coord.Y)
+		if cell.IsMainPath || cell.Snippet.GetType() == SnippetTypePath || len(cell.Snippet.Connectors) == 0 {
+			t.Errorf("Expected arenas on dead-ends and junctions off the main path, got %v", coord)
+		}
+	}
+
+	second := build()
+	markArenas(second, 3, rand.New(rand.NewSource(7)))
+	if again := arenas(second); len(again) != len(marked) || again[0] != marked[0] || again[1] != marked[1] {
+		t.Errorf("Expected the same arenas for the same seed, got %v and %v", marked, again)
+	}
+}
+
+// TestArenaGates tests that an arena is closed on every side with a connector, with the normals pointing inside
+func TestArenaGates(t *testing.T) {
+	cell := &WorldCell{X: 1, Y: -1, Snippet: &WorldSnippet{Connectors: []SnippetConnector{ConnectorLeft, ConnectorTop}}, Rotation: 90}
+
+	gates := cell.ArenaGates()
+	if len(gates) != 2 {
+		t.Fatalf("Expected a gate per connector, got %d", len(gates))
+	}
+
+	centerX, centerY := float64(cell.X*CellSize+CellSize/2), float64(cell.Y*CellSize+CellSize/2)
+	for _, gate := range gates {
+		// The center of the cell lies on the side of the gate its normal points to
+		toCenterX, toCenterY := centerX-gate.A.X, centerY-gate.A.Y
+		if toCenterX*gate.Normal.X+toCenterY*gate.Normal.Y <= 0 {
+			t.Errorf("Expected the normal of gate %+v to point into the arena", gate)
+		}
+	}
+}
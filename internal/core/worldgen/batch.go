@@ -0,0 +1,229 @@
+package worldgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// BatchReport aggregates the results of generating many worlds with consecutive seeds
+type BatchReport struct {
+	Config    *WorldGenConfig  `json:"config"`     // Configuration used for every world (Seed is the first seed)
+	Worlds    int              `json:"worlds"`     // Number of worlds attempted
+	Failures  int              `json:"failures"`   // Worlds for which GenerateWorld returned an error
+	Invalid   int              `json:"invalid"`    // Generated worlds with at least one violation
+	Errors    map[string]int   `json:"errors"`     // Number of failures per error message
+	ErrorSeed map[string]int64 `json:"error_seed"` // First seed that failed with each error message
+
+	InvalidWorldsByInvariant map[Invariant]int     `json:"invalid_worlds_by_invariant"` // Number of worlds violating each invariant
+	InvalidSeedsByInvariant  map[Invariant][]int64 `json:"invalid_seeds_by_invariant"`  // Seeds violating each invariant
+
+	MainPathLength Distribution `json:"main_path_length"`
+	Cells          Distribution `json:"cells"`
+	BranchCells    Distribution `json:"branch_cells"`
+	Branches       Distribution `json:"branches"`
+	DeadEnds       Distribution `json:"dead_ends"`
+	DeadEndRatio   Distribution `json:"dead_end_ratio"` // Fraction of branches ending in a dead-end, per world
+
+	SnippetUsage map[string]int      `json:"snippet_usage"` // Total cells per snippet filename, including unused snippets
+	TypeUsage    map[SnippetType]int `json:"type_usage"`    // Total open cells per snippet type
+}
+
+// RunBatch generates count worlds with the seeds config.Seed, config.Seed+1, ...,
+// validates each of them and aggregates their statistics.
+// The config itself is not modified.
+func (g *WorldGenerator) RunBatch(config *WorldGenConfig, count int) *BatchReport {
+	report := &BatchReport{
+		Config:                   config,
+		Worlds:                   count,
+		Errors:                   make(map[string]int),
+		ErrorSeed:                make(map[string]int64),
+		InvalidWorldsByInvariant: make(map[Invariant]int),
+		InvalidSeedsByInvariant:  make(map[Invariant][]int64),
+		SnippetUsage:             make(map[string]int),
+		TypeUsage:                make(map[SnippetType]int),
+	}
+
+	var mainPathLengths, cells, branchCells, branches, deadEnds, deadEndRatios []float64
+
+	for i := 0; i < count; i++ {
+		runConfig := *config
+		runConfig.Seed = config.Seed + int64(i)
+
+		worldMap, err := g.GenerateWorld(&runConfig)
+		if err != nil {
+			report.Failures++
+			message := err.Error()
+			if report.Errors[message] == 0 {
+				report.ErrorSeed[message] = runConfig.Seed
+			}
+			report.Errors[message]++
+			continue
+		}
+
+		// Count every invariant once per world
+		violated := make(map[Invariant]bool)
+		for _, violation := range ValidateWorldMap(worldMap, &runConfig) {
+			violated[violation.Invariant] = true
+		}
+		if len(violated) > 0 {
+			report.Invalid++
+		}
+		for _, invariant := range Invariants {
+			if violated[invariant] {
+				report.InvalidWorldsByInvariant[invariant]++
+				report.InvalidSeedsByInvariant[invariant] = append(report.InvalidSeedsByInvariant[invariant], runConfig.Seed)
+			}
+		}
+
+		stats := ComputeWorldStats(worldMap)
+		mainPathLengths = append(mainPathLengths, float64(stats.MainPathLength))
+		cells = append(cells, float64(stats.Cells))
+		branchCells = append(branchCells, float64(stats.BranchCells))
+		branches = append(branches, float64(stats.Branches))
+		deadEnds = append(deadEnds, float64(stats.DeadEnds))
+		deadEndRatios = append(deadEndRatios, stats.DeadEndRatio())
+		for name, used := range stats.SnippetUsage {
+			report.SnippetUsage[name] += used
+		}
+		for snippetType, used := range stats.TypeUsage {
+			report.TypeUsage[snippetType] += used
+		}
+	}
+
+	// List snippets that were never placed as well, since those are what weights are tuned for
+	for _, snippet := range g.Registry.Ordered {
+		if _, exists := report.SnippetUsage[snippet.Filename]; !exists {
+			report.SnippetUsage[snippet.Filename] = 0
+		}
+	}
+
+	report.MainPathLength = NewDistribution(mainPathLengths, true)
+	report.Cells = NewDistribution(cells, false)
+	report.BranchCells = NewDistribution(branchCells, false)
+	report.Branches = NewDistribution(branches, true)
+	report.DeadEnds = NewDistribution(deadEnds, true)
+	report.DeadEndRatio = NewDistribution(deadEndRatios, false)
+
+	return report
+}
+
+// FailureRate returns the fraction of worlds that failed to generate
+func (r *BatchReport) FailureRate() float64 {
+	return rate(r.Failures, r.Worlds)
+}
+
+// InvalidRate returns the fraction of generated worlds that violate an invariant
+func (r *BatchReport) InvalidRate() float64 {
+	return rate(r.Invalid, r.Worlds-r.Failures)
+}
+
+// OK reports whether every world was generated and passed validation
+func (r *BatchReport) OK() bool {
+	return r.Failures == 0 && r.Invalid == 0
+}
+
+// WriteJSON writes the report as indented JSON to the writer
+func (r *BatchReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode batch report: %w", err)
+	}
+	return nil
+}
+
+// WriteText writes a human readable summary of the report to the writer
+func (r *BatchReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	generated := r.Worlds - r.Failures
+
+	fmt.Fprintf(tw, "worlds\t%d\t(seeds %d to %d)\n", r.Worlds, r.Config.Seed, r.Config.Seed+int64(r.Worlds)-1)
+	fmt.Fprintf(tw, "generation failures\t%d\t%.1f%%\n", r.Failures, 100*r.FailureRate())
+	fmt.Fprintf(tw, "invalid worlds\t%d\t%.1f%%\n", r.Invalid, 100*r.InvalidRate())
+
+	for _, message := range sortedByCount(r.Errors) {
+		fmt.Fprintf(tw, "  error\t%d\t%s (first seed %d)\n", r.Errors[message], message, r.ErrorSeed[message])
+	}
+	for _, invariant := range Invariants {
+		seeds := r.InvalidSeedsByInvariant[invariant]
+		if len(seeds) == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "  %s\t%d\t%.1f%% (first seed %d)\n", invariant, len(seeds), 100*rate(len(seeds), generated), seeds[0])
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "metric\tmin\tmedian\tmean\tp90\tmax")
+	for _, metric := range []struct {
+		name string
+		d    Distribution
+	}{
+		{"main path length", r.MainPathLength},
+		{"cells", r.Cells},
+		{"branch cells", r.BranchCells},
+		{"branches", r.Branches},
+		{"dead-ends", r.DeadEnds},
+		{"dead-end ratio", r.DeadEndRatio},
+	} {
+		fmt.Fprintf(tw, "%s\t%.4g\t%.4g\t%.4g\t%.4g\t%.4g\n", metric.name, metric.d.Min, metric.d.Median, metric.d.Mean, metric.d.P90, metric.d.Max)
+	}
+
+	if len(r.MainPathLength.Histogram) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "main path length\tworlds")
+		lengths := make([]int, 0, len(r.MainPathLength.Histogram))
+		for length := range r.MainPathLength.Histogram {
+			lengths = append(lengths, length)
+		}
+		sort.Ints(lengths)
+		for _, length := range lengths {
+			worlds := r.MainPathLength.Histogram[length]
+			fmt.Fprintf(tw, "%d\t%d\t%s\n", length, worlds, strings.Repeat("#", (worlds*40+generated-1)/max(generated, 1)))
+		}
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "snippet type\tcells\tshare")
+	totalOpen := 0
+	for _, used := range r.TypeUsage {
+		totalOpen += used
+	}
+	for _, snippetType := range []SnippetType{SnippetTypePath, SnippetTypeJunction, SnippetTypeDeadEnd} {
+		fmt.Fprintf(tw, "%s\t%d\t%.1f%%\n", snippetType, r.TypeUsage[snippetType], 100*rate(r.TypeUsage[snippetType], totalOpen))
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "snippet\tcells\tper world")
+	for _, name := range sortedByCount(r.SnippetUsage) {
+		fmt.Fprintf(tw, "%s\t%d\t%.2f\n", name, r.SnippetUsage[name], float64(r.SnippetUsage[name])/float64(max(generated, 1)))
+	}
+
+	return tw.Flush()
+}
+
+// rate returns part/total, or 0 if total is 0
+func rate(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
+
+// sortedByCount returns the keys of a count map, most frequent first and then by name
+func sortedByCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -0,0 +1,140 @@
+package worldgen
+
+import (
+	"math/rand"
+	"path"
+	"sort"
+)
+
+// BiomeMode selects how the world map is partitioned into biomes
+type BiomeMode string
+
+const (
+	// BiomeModeMainPath splits the main path into equal consecutive sections, one per biome.
+	// Branches belong to the biome of the main path cell they start from.
+	BiomeModeMainPath BiomeMode = "main-path"
+
+	// BiomeModeBranch keeps the main path in the first biome and assigns each branch
+	// one of the other biomes. Sub-branches belong to the biome of their branch.
+	BiomeModeBranch BiomeMode = "branch"
+)
+
+// Biome describes a themed region of the world.
+// Every field is optional: empty values fall back to the global settings.
+type Biome struct {
+	Name               string              `json:"name"`                 // Unique name of the biome
+	Snippets           []string            `json:"snippets"`             // Snippet filename patterns (e.g. "Worldgen_l*", "Synth_*"), empty for all snippets
+	SnippetTypeWeights map[SnippetType]int `json:"snippet_type_weights"` // Relative weights for snippet types, nil for the global weights
+	Background         string              `json:"background"`           // Asset path of the background image, empty for the default background
+	EnemyTypes         []string            `json:"enemy_types"`          // Enemy types spawned in this biome, empty for the default roster
+	LightRadius        float64             `json:"light_radius"`         // Radius of the player light as a fraction of the screen width, 0 for the default
+}
+
+// AllowsSnippet reports whether the snippet may be placed in this biome
+func (b *Biome) AllowsSnippet(snippet *WorldSnippet) bool {
+	if len(b.Snippets) == 0 {
+		return true
+	}
+	for _, pattern := range b.Snippets {
+		if matched, _ := path.Match(pattern, snippet.Filename); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// mainPathBiome returns the biome of the main path cell at the given index
+func (c *WorldGenConfig) mainPathBiome(index, pathLength int) *Biome {
+	if len(c.Biomes) == 0 {
+		return nil
+	}
+	if c.BiomeMode == BiomeModeBranch || pathLength <= 0 {
+		return &c.Biomes[0]
+	}
+
+	biomeIndex := index * len(c.Biomes) / pathLength
+	if biomeIndex >= len(c.Biomes) {
+		biomeIndex = len(c.Biomes) - 1
+	}
+	return &c.Biomes[biomeIndex]
+}
+
+// branchBiome returns the biome of a new branch cell grown from fromCell.
+// In branch mode, a branch that starts at the main path gets a random biome
+// other than the main path biome. The random generator is only used in that
+// case, so worlds without biomes are generated exactly as before.
+func (c *WorldGenConfig) branchBiome(fromCell *WorldCell, rng *rand.Rand) *Biome {
+	if len(c.Biomes) == 0 {
+		return nil
+	}
+	if c.BiomeMode == BiomeModeBranch && fromCell.IsMainPath && len(c.Biomes) > 1 {
+		return &c.Biomes[1+rng.Intn(len(c.Biomes)-1)]
+	}
+	return fromCell.Biome
+}
+
+// typeWeights returns the snippet type weights to use in the biome
+func (c *WorldGenConfig) typeWeights(biome *Biome) map[SnippetType]int {
+	if biome != nil && biome.SnippetTypeWeights != nil {
+		return biome.SnippetTypeWeights
+	}
+	return c.SnippetTypeWeights
+}
+
+// biomeSnippets filters snippets down to those allowed in the biome.
+// If the biome allows none of them, all snippets are returned, so a restrictive
+// biome can never make world generation fail.
+func biomeSnippets(snippets []*WorldSnippet, biome *Biome) []*WorldSnippet {
+	if biome == nil || len(biome.Snippets) == 0 {
+		return snippets
+	}
+
+	filtered := make([]*WorldSnippet, 0, len(snippets))
+	for _, snippet := range snippets {
+		if biome.AllowsSnippet(snippet) {
+			filtered = append(filtered, snippet)
+		}
+	}
+
+	if len(filtered) == 0 {
+		return snippets
+	}
+	return filtered
+}
+
+// assignMissingBiomes gives every cell without a biome the biome of the nearest cell
+// that has one. This covers the cells added while post-processing (borders, fillers
+// and repairs). Cells are visited in a fixed order so the result is deterministic.
+func assignMissingBiomes(worldMap *WorldMap) {
+	// Start from all cells that already have a biome, sorted by row and column
+	queue := make([]*WorldCell, 0, len(worldMap.Cells))
+	for _, cell := range worldMap.Cells {
+		if cell.Biome != nil {
+			queue = append(queue, cell)
+		}
+	}
+	if len(queue) == 0 {
+		return
+	}
+	sort.Slice(queue, func(i, j int) bool {
+		if queue[i].Y != queue[j].Y {
+			return queue[i].Y < queue[j].Y
+		}
+		return queue[i].X < queue[j].X
+	})
+
+	// Breadth-first search spreads each biome to its unassigned neighbours
+	for len(queue) > 0 {
+		cell := queue[0]
+		queue = queue[1:]
+
+		for _, dir := range getDirections() {
+			neighbour := worldMap.GetCell(cell.X+dir[0], cell.Y+dir[1])
+			if neighbour == nil || neighbour.Biome != nil {
+				continue
+			}
+			neighbour.Biome = cell.Biome
+			queue = append(queue, neighbour)
+		}
+	}
+}
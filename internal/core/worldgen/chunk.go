@@ -1,18 +1,25 @@
 package worldgen
 
 import (
-	"fmt"
+	"discoveryx/internal/constants"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 // ChunkSize defines the number of cells in a chunk (width and height)
-const ChunkSize = 4
+const ChunkSize = constants.WorldChunkSize
+
+// ChunkCoord identifies a chunk in the chunk grid
+type ChunkCoord struct {
+	X, Y int
+}
 
 // WorldChunk represents a chunk of the world map containing multiple cells
 type WorldChunk struct {
-	X, Y     int                    // Chunk coordinates
-	Cells    map[string]*WorldCell  // Map of cells in this chunk by local coordinates
-	IsLoaded bool                   // Whether this chunk is currently loaded
+	X, Y     int          // Chunk coordinates
+	Cells    []*WorldCell // Cells in this chunk, in the order they were added
+	IsLoaded bool         // Whether this chunk is currently loaded
+
+	grid [ChunkSize * ChunkSize]*WorldCell // Cells by local coordinates, for allocation free lookups
 }
 
 // NewWorldChunk creates a new empty world chunk at the specified coordinates
@@ -20,31 +27,51 @@ func NewWorldChunk(x, y int) *WorldChunk {
 	return &WorldChunk{
 		X:        x,
 		Y:        y,
-		Cells:    make(map[string]*WorldCell),
+		Cells:    make([]*WorldCell, 0, ChunkSize*ChunkSize),
 		IsLoaded: false,
 	}
 }
 
-// GetKey returns a unique string key for this chunk based on its coordinates
-func (c *WorldChunk) GetKey() string {
-	return fmt.Sprintf("%d,%d", c.X, c.Y)
+// GetKey returns the unique key of this chunk based on its coordinates
+func (c *WorldChunk) GetKey() ChunkCoord {
+	return ChunkCoord{X: c.X, Y: c.Y}
 }
 
-// AddCell adds a cell to this chunk
+// AddCell adds a cell to this chunk, replacing any cell at the same position.
+// Cells outside the chunk are ignored.
 func (c *WorldChunk) AddCell(cell *WorldCell) {
 	// Calculate local coordinates within the chunk
 	localX := cell.X - c.X*ChunkSize
 	localY := cell.Y - c.Y*ChunkSize
+	if !isLocalCell(localX, localY) {
+		return
+	}
 
-	// Add to cells map using local coordinates as key
-	key := fmt.Sprintf("%d,%d", localX, localY)
-	c.Cells[key] = cell
+	index := localY*ChunkSize + localX
+	if previous := c.grid[index]; previous != nil {
+		for i, existing := range c.Cells {
+			if existing == previous {
+				c.Cells[i] = cell
+				break
+			}
+		}
+	} else {
+		c.Cells = append(c.Cells, cell)
+	}
+	c.grid[index] = cell
 }
 
 // GetCell returns the cell at the specified local coordinates, or nil if no cell exists there
 func (c *WorldChunk) GetCell(localX, localY int) *WorldCell {
-	key := fmt.Sprintf("%d,%d", localX, localY)
-	return c.Cells[key]
+	if !isLocalCell(localX, localY) {
+		return nil
+	}
+	return c.grid[localY*ChunkSize+localX]
+}
+
+// isLocalCell reports whether local coordinates are inside a chunk
+func isLocalCell(localX, localY int) bool {
+	return localX >= 0 && localX < ChunkSize && localY >= 0 && localY < ChunkSize
 }
 
 // GetCellCount returns the number of cells in this chunk
@@ -70,6 +97,11 @@ func (c *WorldChunk) Draw(screen *ebiten.Image, worldX, worldY int, cellSize int
 
 	// Draw each cell in the chunk
 	for _, cell := range c.Cells {
+		// Skip cells whose image is not available
+		if cell.Snippet == nil || cell.Snippet.Image == nil {
+			continue
+		}
+
 		// Calculate local coordinates within the chunk
 		localX := cell.X - c.X*ChunkSize
 		localY := cell.Y - c.Y*ChunkSize
@@ -0,0 +1,235 @@
+package worldgen
+
+import (
+	"discoveryx/internal/assets"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"sort"
+)
+
+// CellExport is the serializable form of a WorldCell
+type CellExport struct {
+	X           int    `json:"x"`
+	Y           int    `json:"y"`
+	Snippet     string `json:"snippet"`
+	Rotation    int    `json:"rotation"`
+	Connectors  []int  `json:"connectors"` // Connectors after rotation
+	IsMainPath  bool   `json:"is_main_path"`
+	BranchDepth int    `json:"branch_depth"`
+	Biome       string `json:"biome,omitempty"` // Name of the biome, empty if the world has no biomes
+}
+
+// WorldMapExport is the serializable form of a WorldMap.
+// Cells are sorted by row and column so that two exports of the same world
+// produce byte-identical JSON, which makes layouts easy to diff.
+type WorldMapExport struct {
+	Config   *WorldGenConfig `json:"config,omitempty"` // Configuration used to generate the world
+	MinX     int             `json:"min_x"`            // Smallest cell X coordinate
+	MinY     int             `json:"min_y"`            // Smallest cell Y coordinate
+	MaxX     int             `json:"max_x"`            // Largest cell X coordinate
+	MaxY     int             `json:"max_y"`            // Largest cell Y coordinate
+	MainPath [][2]int        `json:"main_path"`        // Main path cell coordinates in path order
+	Cells    []CellExport    `json:"cells"`
+}
+
+// Export converts the world map into its serializable form.
+// The config is embedded so that the export can be replayed later; it may be nil.
+func (m *WorldMap) Export(config *WorldGenConfig) *WorldMapExport {
+	export := &WorldMapExport{
+		Config:   config,
+		MainPath: make([][2]int, 0, len(m.MainPathCells)),
+		Cells:    make([]CellExport, 0, len(m.Cells)),
+	}
+
+	if len(m.Cells) > 0 {
+		export.MinX, export.MinY, export.MaxX, export.MaxY = findWorldBoundaries(m)
+	}
+
+	for _, cell := range m.MainPathCells {
+		export.MainPath = append(export.MainPath, [2]int{cell.X, cell.Y})
+	}
+
+	for _, cell := range m.Cells {
+		cellExport := CellExport{
+			X:           cell.X,
+			Y:           cell.Y,
+			Rotation:    cell.Rotation,
+			Connectors:  []int{},
+			IsMainPath:  cell.IsMainPath,
+			BranchDepth: cell.BranchDepth,
+		}
+
+		if cell.Biome != nil {
+			cellExport.Biome = cell.Biome.Name
+		}
+
+		if cell.Snippet != nil {
+			cellExport.Snippet = cell.Snippet.Filename
+			for _, conn := range cell.GetRotatedConnectors() {
+				cellExport.Connectors = append(cellExport.Connectors, int(conn))
+			}
+			sort.Ints(cellExport.Connectors)
+		}
+
+		export.Cells = append(export.Cells, cellExport)
+	}
+
+	sort.Slice(export.Cells, func(i, j int) bool {
+		if export.Cells[i].Y != export.Cells[j].Y {
+			return export.Cells[i].Y < export.Cells[j].Y
+		}
+		return export.Cells[i].X < export.Cells[j].X
+	})
+
+	return export
+}
+
+// WriteJSON writes the world map export as indented JSON to the writer
+func (e *WorldMapExport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(e); err != nil {
+		return fmt.Errorf("failed to encode world map: %w", err)
+	}
+	return nil
+}
+
+// Colors used by RenderOverview
+var (
+	overviewRockColor     = color.RGBA{110, 110, 110, 255} // Rock pixels
+	overviewEmptyColor    = color.RGBA{20, 20, 20, 255}    // Air in empty snippets
+	overviewMainPathColor = color.RGBA{60, 90, 160, 255}   // Air in main path cells
+	overviewBranchColor   = color.RGBA{60, 140, 80, 255}   // Air in branch cells
+	overviewDeadEndColor  = color.RGBA{160, 70, 60, 255}   // Air in dead-end cells
+)
+
+// RenderOverview renders a downscaled overview image of the world map.
+// Every cell is drawn as a tileSize x tileSize square. Snippet images are decoded
+// directly from the embedded assets (without ebiten), so this works headless.
+// Synthesized snippets are drawn from their rock mask.
+// Rock is drawn grey, and the open cave area is tinted by cell role:
+// blue for the main path, green for branches, red for dead-ends.
+func RenderOverview(m *WorldMap, tileSize int) (*image.RGBA, error) {
+	if tileSize <= 0 {
+		return nil, fmt.Errorf("tile size must be positive, got %d", tileSize)
+	}
+	if len(m.Cells) == 0 {
+		return nil, fmt.Errorf("world map has no cells")
+	}
+
+	minX, minY, maxX, maxY := findWorldBoundaries(m)
+	width := (maxX - minX + 1) * tileSize
+	height := (maxY - minY + 1) * tileSize
+	overview := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	// Downscaled rock masks, keyed by snippet filename
+	masks := make(map[string][]bool)
+
+	for _, cell := range m.Cells {
+		if cell.Snippet == nil {
+			continue
+		}
+
+		mask, exists := masks[cell.Snippet.Filename]
+		if !exists {
+			var err error
+			if cell.Snippet.Mask != nil {
+				mask = sampleRockMask(cell.Snippet.Mask, tileSize)
+			} else {
+				mask, err = loadRockMask(cell.Snippet.Filename, tileSize)
+			}
+			if err != nil {
+				return nil, err
+			}
+			masks[cell.Snippet.Filename] = mask
+		}
+
+		airColor := overviewEmptyColor
+		switch {
+		case cell.IsMainPath:
+			airColor = overviewMainPathColor
+		case cell.Snippet.GetType() == SnippetTypeDeadEnd && len(cell.Snippet.Connectors) > 0:
+			airColor = overviewDeadEndColor
+		case len(cell.Snippet.Connectors) > 0:
+			airColor = overviewBranchColor
+		}
+
+		originX := (cell.X - minX) * tileSize
+		originY := (cell.Y - minY) * tileSize
+
+		for dy := 0; dy < tileSize; dy++ {
+			for dx := 0; dx < tileSize; dx++ {
+				sx, sy := rotateTileCoords(dx, dy, tileSize, cell.Rotation)
+				if mask[sy*tileSize+sx] {
+					overview.SetRGBA(originX+dx, originY+dy, overviewRockColor)
+				} else {
+					overview.SetRGBA(originX+dx, originY+dy, airColor)
+				}
+			}
+		}
+	}
+
+	return overview, nil
+}
+
+// loadRockMask decodes a snippet image and samples it down to a tileSize x tileSize
+// mask where true marks rock (non-transparent) pixels
+func loadRockMask(filename string, tileSize int) ([]bool, error) {
+	file, err := assets.Assets.Open(SnippetImagePath(filename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snippet image %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode snippet image %s: %w", filename, err)
+	}
+
+	bounds := img.Bounds()
+	mask := make([]bool, tileSize*tileSize)
+	for y := 0; y < tileSize; y++ {
+		for x := 0; x < tileSize; x++ {
+			// Sample the center of the source area covered by this tile pixel
+			srcX := bounds.Min.X + (2*x+1)*bounds.Dx()/(2*tileSize)
+			srcY := bounds.Min.Y + (2*y+1)*bounds.Dy()/(2*tileSize)
+			_, _, _, a := img.At(srcX, srcY).RGBA()
+			mask[y*tileSize+x] = a > 0
+		}
+	}
+
+	return mask, nil
+}
+
+// sampleRockMask samples the rock mask of a synthesized snippet down to a
+// tileSize x tileSize mask, like loadRockMask does for authored snippets
+func sampleRockMask(rockMask *RockMask, tileSize int) []bool {
+	mask := make([]bool, tileSize*tileSize)
+	for y := 0; y < tileSize; y++ {
+		for x := 0; x < tileSize; x++ {
+			srcX := (2*x + 1) * CellSize / (2 * tileSize)
+			srcY := (2*y + 1) * CellSize / (2 * tileSize)
+			mask[y*tileSize+x] = rockMask.IsRock(srcX, srcY)
+		}
+	}
+	return mask
+}
+
+// rotateTileCoords maps a destination pixel of a rotated tile back to the source pixel.
+// Rotations are clockwise, matching the rotation applied by WorldChunk.Draw and
+// WorldCell.GetRotatedConnectors.
+func rotateTileCoords(x, y, size, rotation int) (int, int) {
+	switch rotation {
+	case 90:
+		return y, size - 1 - x
+	case 180:
+		return size - 1 - x, size - 1 - y
+	case 270:
+		return size - 1 - y, x
+	default:
+		return x, y
+	}
+}
@@ -0,0 +1,95 @@
+package worldgen
+
+import (
+	"fmt"
+	stdmath "math"
+)
+
+// ForceVolumeType names the kind of force a force volume applies
+type ForceVolumeType string
+
+const (
+	ForceCurrent     ForceVolumeType = "current"      // Constant push in one direction, for currents and wind zones
+	ForceGravityWell ForceVolumeType = "gravity_well" // Pull towards the center, strongest at the center
+	ForceRepulsor    ForceVolumeType = "repulsor"     // Push away from the center, strongest at the center
+	ForceTurbulence  ForceVolumeType = "turbulence"   // Push whose direction swirls over space and time
+)
+
+// ForceVolumeTypes lists all force volume types
+var ForceVolumeTypes = []ForceVolumeType{ForceCurrent, ForceGravityWell, ForceRepulsor, ForceTurbulence}
+
+// ForceVolume is an area of a snippet that pushes the player, bullets and enemies inside it.
+// Level designers declare them in the forces list of the snippet metadata JSON, in pixel
+// coordinates of the unrotated snippet image. A volume is a circle with the radius, or a
+// rectangle of width by height if both are set.
+type ForceVolume struct {
+	Type      ForceVolumeType `json:"type"`
+	X         float64         `json:"x"`                   // Center of the volume
+	Y         float64         `json:"y"`                   // Center of the volume
+	Radius    float64         `json:"radius,omitempty"`    // Radius of a circular volume
+	Width     float64         `json:"width,omitempty"`     // Width of a rectangular volume
+	Height    float64         `json:"height,omitempty"`    // Height of a rectangular volume
+	Strength  float64         `json:"strength"`            // Acceleration in units per second squared
+	Direction float64         `json:"direction,omitempty"` // Direction of currents in degrees (0 = up, clockwise)
+}
+
+// IsRectangle reports whether the volume is a rectangle instead of a circle
+func (v ForceVolume) IsRectangle() bool {
+	return v.Width > 0 && v.Height > 0
+}
+
+// validate checks that the volume has a known type and a size
+func (v ForceVolume) validate() error {
+	known := false
+	for _, t := range ForceVolumeTypes {
+		known = known || v.Type == t
+	}
+	if !known {
+		return fmt.Errorf("unknown type %q, expected one of %v", v.Type, ForceVolumeTypes)
+	}
+	if !v.IsRectangle() && v.Radius <= 0 {
+		return fmt.Errorf("needs a radius or a width and height greater than 0")
+	}
+	if stdmath.IsNaN(v.Strength) || stdmath.IsInf(v.Strength, 0) {
+		return fmt.Errorf("strength must be a number, got %v", v.Strength)
+	}
+	return nil
+}
+
+// rotated returns the volume rotated around the snippet center by a multiple of 90 degrees,
+// like the cell rotates its snippet
+func (v ForceVolume) rotated(rotation int) ForceVolume {
+	angle := float64(rotation) * (stdmath.Pi / 180.0)
+	cosA := stdmath.Cos(angle)
+	sinA := stdmath.Sin(angle)
+	center := float64(CellSize) / 2
+
+	relX := v.X - center
+	relY := v.Y - center
+	v.X = relX*cosA - relY*sinA + center
+	v.Y = relX*sinA + relY*cosA + center
+	v.Direction = stdmath.Mod(v.Direction+float64(rotation), 360)
+
+	// Rectangles stay axis-aligned, with their sides swapped on quarter turns
+	if (rotation/90)%2 != 0 {
+		v.Width, v.Height = v.Height, v.Width
+	}
+	return v
+}
+
+// GetForceVolumesInWorldCoordinates returns the force volumes of the cell's snippet,
+// rotated with the cell and moved to world coordinates
+func (cell *WorldCell) GetForceVolumesInWorldCoordinates() []ForceVolume {
+	if cell.Snippet == nil || len(cell.Snippet.Forces) == 0 {
+		return nil
+	}
+
+	volumes := make([]ForceVolume, len(cell.Snippet.Forces))
+	for i, volume := range cell.Snippet.Forces {
+		volume = volume.rotated(cell.Rotation)
+		volume.X += float64(cell.X * CellSize)
+		volume.Y += float64(cell.Y * CellSize)
+		volumes[i] = volume
+	}
+	return volumes
+}
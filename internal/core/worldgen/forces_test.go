@@ -0,0 +1,103 @@
+package worldgen
+
+import (
+	"encoding/json"
+	stdmath "math"
+	"testing"
+)
+
+// forcesMetadata is snippet metadata declaring one force volume of each type
+const forcesMetadata = `{
+  "filename": "Forces.png",
+  "connectors": [270, 90],
+  "weight": 10,
+  "forces": [
+    {"type": "current", "x": 500, "y": 500, "width": 800, "height": 200, "strength": 150, "direction": 90},
+    {"type": "gravity_well", "x": 450, "y": 500, "radius": 150, "strength": 250},
+    {"type": "repulsor", "x": 700, "y": 450, "radius": 150, "strength": 300},
+    {"type": "turbulence", "x": 500, "y": 500, "width": 600, "height": 200, "strength": 200}
+  ]
+}`
+
+// TestParseForceMetadata tests that force volumes declared in snippet metadata are read and valid
+func TestParseForceMetadata(t *testing.T) {
+	var metadata SnippetMetadata
+	if err := json.Unmarshal([]byte(forcesMetadata), &metadata); err != nil {
+		t.Fatalf("Failed to parse the metadata: %v", err)
+	}
+
+	expected := []ForceVolumeType{ForceCurrent, ForceGravityWell, ForceRepulsor, ForceTurbulence}
+	if len(metadata.Forces) != len(expected) {
+		t.Fatalf("Expected %d force volumes, got %d", len(expected), len(metadata.Forces))
+	}
+	for i, volume := range metadata.Forces {
+		if volume.Type != expected[i] {
+			t.Errorf("Expected volume %d to be a %s, got %s", i, expected[i], volume.Type)
+		}
+		if err := volume.validate(); err != nil {
+			t.Errorf("Expected volume %d to be valid, got %v", i, err)
+		}
+	}
+
+	current := metadata.Forces[0]
+	if current.Width != 800 || current.Height != 200 || current.Strength != 150 || current.Direction != 90 {
+		t.Errorf("Expected the current to keep its size, strength and direction, got %+v", current)
+	}
+	if well := metadata.Forces[1]; well.Radius != 150 || well.X != 450 {
+		t.Errorf("Expected the gravity well at x 450 with radius 150, got %+v", well)
+	}
+}
+
+// TestForceVolumesInWorldCoordinates tests that force volumes turn and move with their cell
+func TestForceVolumesInWorldCoordinates(t *testing.T) {
+	snippet := &WorldSnippet{
+		Filename: "forces.png",
+		Forces: []ForceVolume{
+			{Type: ForceCurrent, X: 500, Y: 200, Width: 400, Height: 100, Strength: 100, Direction: 0},
+		},
+	}
+	cell := &WorldCell{X: 2, Y: -1, Snippet: snippet, Rotation: 90}
+
+	volumes := cell.GetForceVolumesInWorldCoordinates()
+	if len(volumes) != 1 {
+		t.Fatalf("Expected 1 force volume, got %d", len(volumes))
+	}
+	volume := volumes[0]
+
+	// A quarter turn clockwise moves the top center of the snippet to the right center
+	if stdmath.Abs(volume.X-(2*CellSize+800)) > 1e-9 || stdmath.Abs(volume.Y-(-CellSize+500)) > 1e-9 {
+		t.Errorf("Expected the volume at (%d, %d), got (%v, %v)", 2*CellSize+800, -CellSize+500, volume.X, volume.Y)
+	}
+	if volume.Width != 100 || volume.Height != 400 {
+		t.Errorf("Expected the sides to be swapped to 100x400, got %vx%v", volume.Width, volume.Height)
+	}
+	if volume.Direction != 90 {
+		t.Errorf("Expected the current to point right (90), got %v", volume.Direction)
+	}
+
+	// The snippet itself keeps its unrotated volumes
+	if snippet.Forces[0].X != 500 || snippet.Forces[0].Direction != 0 {
+		t.Errorf("Expected the snippet volumes to stay unchanged, got %+v", snippet.Forces[0])
+	}
+}
+
+// TestForceVolumeValidation tests that force volumes without a known type or a size are rejected
+func TestForceVolumeValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		volume ForceVolume
+		valid  bool
+	}{
+		{"Circle", ForceVolume{Type: ForceGravityWell, Radius: 100, Strength: 200}, true},
+		{"Rectangle", ForceVolume{Type: ForceCurrent, Width: 100, Height: 50, Strength: 200}, true},
+		{"Unknown type", ForceVolume{Type: "vortex", Radius: 100}, false},
+		{"No size", ForceVolume{Type: ForceRepulsor, Strength: 200}, false},
+		{"Half a rectangle", ForceVolume{Type: ForceTurbulence, Width: 100, Strength: 200}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.volume.validate(); (err == nil) != tc.valid {
+				t.Errorf("Expected valid=%v, got error %v", tc.valid, err)
+			}
+		})
+	}
+}
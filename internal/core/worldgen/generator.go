@@ -1,39 +1,87 @@
 package worldgen
 
 import (
+	"discoveryx/internal/config"
+	"discoveryx/internal/utils/random"
 	"fmt"
 	"math/rand"
-	"time"
 )
 
 // WorldGenConfig represents the configuration for world generation
 type WorldGenConfig struct {
-	MainPathMinLength    int                 // Minimum number of snippets in main path
-	MainPathMaxLength    int                 // Maximum number of snippets in main path
-	BranchProbability    float64             // Probability of creating a branch (0.0-1.0)
-	BranchMaxDepth       int                 // Maximum branch depth from main path
-	DeadEndProbability   float64             // Probability of branch ending in dead-end
-	SnippetTypeWeights   map[SnippetType]int // Relative weights for snippet types
-	SubBranchProbability float64             // Probability of branches from branches
-	Seed                 int64               // Random seed for reproducible generation
+	MainPathMinLength    int                 `json:"main_path_min_length"`   // Minimum number of snippets in main path
+	MainPathMaxLength    int                 `json:"main_path_max_length"`   // Maximum number of snippets in main path
+	BranchProbability    float64             `json:"branch_probability"`     // Probability of creating a branch (0.0-1.0)
+	BranchMaxDepth       int                 `json:"branch_max_depth"`       // Maximum branch depth from main path
+	DeadEndProbability   float64             `json:"dead_end_probability"`   // Probability of branch ending in dead-end
+	SnippetTypeWeights   map[SnippetType]int `json:"snippet_type_weights"`   // Relative weights for snippet types
+	SubBranchProbability float64             `json:"sub_branch_probability"` // Probability of branches from branches
+	Seed                 int64               `json:"seed"`                   // Random seed for reproducible generation
+	Synthesis            SynthesisConfig     `json:"synthesis"`              // Procedural snippets mixed into the authored set
+	BiomeMode            BiomeMode           `json:"biome_mode"`             // How the world is partitioned into biomes
+	Biomes               []Biome             `json:"biomes"`                 // Themed regions of the world (empty for a single global region)
+	Infinite             bool                `json:"infinite"`               // Grow the world around the generated loop as the player explores (see FrontierGrower)
+	ArenaCount           int                 `json:"arena_count"`            // Dead-ends and large junctions marked as boss arenas
 }
 
 // DefaultWorldGenConfig returns a default configuration for world generation
 func DefaultWorldGenConfig() *WorldGenConfig {
+	return NewWorldGenConfig(config.Defaults().WorldGen)
+}
+
+// NewWorldGenConfig creates a world generation configuration from loaded settings.
+// The seed is the world generation stream of a fresh run seed; callers that need
+// reproducible worlds should overwrite it with the stream of their run seed.
+func NewWorldGenConfig(settings config.WorldGenSettings) *WorldGenConfig {
+	weights := make(map[SnippetType]int, len(settings.SnippetTypeWeights))
+	for snippetType, weight := range settings.SnippetTypeWeights {
+		weights[SnippetType(snippetType)] = weight
+	}
+
 	return &WorldGenConfig{
-		MainPathMinLength:  20,
-		MainPathMaxLength:  40,
-		BranchProbability:  0.3,
-		BranchMaxDepth:     5,
-		DeadEndProbability: 0.5,
-		SnippetTypeWeights: map[SnippetType]int{
-			SnippetTypePath:     10, // Higher weight for path segments
-			SnippetTypeJunction: 5,  // Medium weight for junctions
-			SnippetTypeDeadEnd:  2,  // Lower weight for dead-ends
+		MainPathMinLength:    settings.MainPathMinLength,
+		MainPathMaxLength:    settings.MainPathMaxLength,
+		BranchProbability:    settings.BranchProbability,
+		BranchMaxDepth:       settings.BranchMaxDepth,
+		DeadEndProbability:   settings.DeadEndProbability,
+		SnippetTypeWeights:   weights,
+		SubBranchProbability: settings.SubBranchProbability,
+		Seed:                 random.NewSeed().Derive(random.StreamWorldGen),
+		Synthesis: SynthesisConfig{
+			VariantsPerLayout:   settings.Synthesis.VariantsPerLayout,
+			Weight:              settings.Synthesis.Weight,
+			FillProbability:     settings.Synthesis.FillProbability,
+			SmoothingIterations: settings.Synthesis.SmoothingIterations,
 		},
-		SubBranchProbability: 0.2,
-		Seed:                 time.Now().UnixNano(),
+		BiomeMode:  BiomeMode(settings.BiomeMode),
+		Biomes:     newBiomes(settings.Biomes),
+		Infinite:   settings.Infinite,
+		ArenaCount: settings.ArenaCount,
+	}
+}
+
+// newBiomes converts the configured biomes
+func newBiomes(settings []config.BiomeSettings) []Biome {
+	biomes := make([]Biome, 0, len(settings))
+	for _, biome := range settings {
+		var weights map[SnippetType]int
+		if biome.SnippetTypeWeights != nil {
+			weights = make(map[SnippetType]int, len(biome.SnippetTypeWeights))
+			for snippetType, weight := range biome.SnippetTypeWeights {
+				weights[SnippetType(snippetType)] = weight
+			}
+		}
+
+		biomes = append(biomes, Biome{
+			Name:               biome.Name,
+			Snippets:           append([]string(nil), biome.Snippets...),
+			SnippetTypeWeights: weights,
+			Background:         biome.Background,
+			EnemyTypes:         append([]string(nil), biome.EnemyTypes...),
+			LightRadius:        biome.LightRadius,
+		})
 	}
+	return biomes
 }
 
 // GenerateWorld generates a new world map based on the provided configuration
@@ -41,6 +89,10 @@ func (g *WorldGenerator) GenerateWorld(config *WorldGenConfig) (*WorldMap, error
 	// Initialize random number generator with seed
 	rng := rand.New(rand.NewSource(config.Seed))
 
+	// Mix synthesized snippets into the authored set (or remove them if disabled).
+	// They are derived from the world seed, so the same seed yields the same world.
+	g.Registry.ensureSynthesizedSnippets(config.Synthesis, config.Seed)
+
 	// Create a new world map
 	worldMap := NewWorldMap()
 
@@ -62,6 +114,12 @@ func (g *WorldGenerator) GenerateWorld(config *WorldGenConfig) (*WorldMap, error
 		return nil, fmt.Errorf("failed to post-process world map: %w", err)
 	}
 
+	// Cells added during post-processing join the biome of their neighbours
+	assignMissingBiomes(worldMap)
+
+	// Mark the cells where bosses wait
+	markArenas(worldMap, config.ArenaCount, rng)
+
 	return worldMap, nil
 }
 
@@ -77,7 +135,7 @@ func (g *WorldGenerator) generateMainPath(worldMap *WorldMap, config *WorldGenCo
 	// Start with the first cell at (0,0)
 	// For simplicity, we'll use a snippet with at least two connectors
 	var startSnippet *WorldSnippet
-	for _, snippet := range g.Registry.Snippets {
+	for _, snippet := range g.Registry.Ordered {
 		if len(snippet.Connectors) >= 2 {
 			startSnippet = snippet
 			break
@@ -221,10 +279,12 @@ func (g *WorldGenerator) generateMainPath(worldMap *WorldMap, config *WorldGenCo
 
 	// Now generate the actual path following the planned positions
 	currentX, currentY = 0, 0 // Reset to start position
+	startCell.Biome = config.mainPathBiome(0, len(plannedPath))
 
 	for i := 1; i < len(plannedPath); i++ {
 		nextX, nextY := plannedPath[i][0], plannedPath[i][1]
 		dx, dy := nextX-currentX, nextY-currentY
+		biome := config.mainPathBiome(i, len(plannedPath))
 
 		// Determine the connector direction from the new cell back to the current cell
 		var toConnector SnippetConnector
@@ -239,10 +299,11 @@ func (g *WorldGenerator) generateMainPath(worldMap *WorldMap, config *WorldGenCo
 		}
 
 		// Find a snippet that has the required connector
-		snippets := g.Registry.GetSnippetsByConnector(toConnector)
-		if len(snippets) == 0 {
+		allSnippets := g.Registry.GetSnippetsByConnector(toConnector)
+		if len(allSnippets) == 0 {
 			return fmt.Errorf("no snippets found with connector %d", toConnector)
 		}
+		snippets := biomeSnippets(allSnippets, biome)
 
 		// For the last cell, we need a snippet with exactly two connectors:
 		// one connecting to the previous cell and one connecting to the start cell
@@ -259,58 +320,18 @@ func (g *WorldGenerator) generateMainPath(worldMap *WorldMap, config *WorldGenCo
 				toStartConnector = ConnectorRight
 			}
 
-			// Filter snippets to only include those with both required connectors
-			// Consider rotation when checking for connectors
-			var filteredSnippets []*WorldSnippet
-
-			// Create a map to store the correct rotation for each snippet
-			snippetRotations := make(map[*WorldSnippet]int)
-
-			for _, s := range snippets {
-				// Check if this snippet can have both connectors after rotation
-				canHaveBothConnectors := false
-
-				// Try all possible rotations (0, 90, 180, 270)
-				for rotation := 0; rotation < 360; rotation += 90 {
-					// Create a temporary cell with this snippet and rotation
-					tempCell := &WorldCell{
-						Snippet:  s,
-						Rotation: rotation,
-					}
-
-					// Get the rotated connectors
-					rotatedConnectors := tempCell.GetRotatedConnectors()
-
-					// Check if the rotated connectors include both required connectors
-					hasToConnector := false
-					hasToStartConnector := false
-
-					for _, conn := range rotatedConnectors {
-						if conn == toConnector {
-							hasToConnector = true
-						}
-						if conn == toStartConnector {
-							hasToStartConnector = true
-						}
-					}
-
-					if hasToConnector && hasToStartConnector {
-						canHaveBothConnectors = true
-						snippetRotations[s] = rotation // Store the correct rotation
-						break
-					}
-				}
-
-				if canHaveBothConnectors {
-					filteredSnippets = append(filteredSnippets, s)
-				}
+			// Filter snippets to only include those with both required connectors,
+			// falling back to snippets outside of the biome if it has none
+			filteredSnippets, snippetRotations := closingSnippets(snippets, toConnector, toStartConnector)
+			if len(filteredSnippets) == 0 {
+				filteredSnippets, snippetRotations = closingSnippets(allSnippets, toConnector, toStartConnector)
 			}
 
 			if len(filteredSnippets) > 0 {
 				snippets = filteredSnippets
 
 				// Choose a snippet based on weight and type
-				snippet := SelectWeightedSnippetWithTypeWeights(snippets, config.SnippetTypeWeights, rng)
+				snippet := SelectWeightedSnippetWithTypeWeights(snippets, config.typeWeights(biome), rng)
 
 				// Use the stored rotation for this snippet
 				rotation := snippetRotations[snippet]
@@ -323,6 +344,7 @@ func (g *WorldGenerator) generateMainPath(worldMap *WorldMap, config *WorldGenCo
 					Rotation:    rotation,
 					IsMainPath:  true,
 					BranchDepth: 0,
+					Biome:       biome,
 				}
 
 				worldMap.AddCell(newCell)
@@ -338,7 +360,7 @@ func (g *WorldGenerator) generateMainPath(worldMap *WorldMap, config *WorldGenCo
 		}
 
 		// Choose a snippet based on weight and type
-		snippet := SelectWeightedSnippetWithTypeWeights(snippets, config.SnippetTypeWeights, rng)
+		snippet := SelectWeightedSnippetWithTypeWeights(snippets, config.typeWeights(biome), rng)
 
 		// Determine the rotation needed
 		rotation := 0
@@ -362,6 +384,7 @@ func (g *WorldGenerator) generateMainPath(worldMap *WorldMap, config *WorldGenCo
 			Rotation:    rotation,
 			IsMainPath:  true,
 			BranchDepth: 0,
+			Biome:       biome,
 		}
 
 		worldMap.AddCell(newCell)
@@ -436,6 +459,57 @@ func (g *WorldGenerator) generateMainPath(worldMap *WorldMap, config *WorldGenCo
 	return nil
 }
 
+// closingSnippets returns the snippets that can have both required connectors after
+// rotation, together with the rotation that achieves it for each snippet
+func closingSnippets(snippets []*WorldSnippet, toConnector, toStartConnector SnippetConnector) ([]*WorldSnippet, map[*WorldSnippet]int) {
+	var filteredSnippets []*WorldSnippet
+
+	// Create a map to store the correct rotation for each snippet
+	snippetRotations := make(map[*WorldSnippet]int)
+
+	for _, s := range snippets {
+		// Check if this snippet can have both connectors after rotation
+		canHaveBothConnectors := false
+
+		// Try all possible rotations (0, 90, 180, 270)
+		for rotation := 0; rotation < 360; rotation += 90 {
+			// Create a temporary cell with this snippet and rotation
+			tempCell := &WorldCell{
+				Snippet:  s,
+				Rotation: rotation,
+			}
+
+			// Get the rotated connectors
+			rotatedConnectors := tempCell.GetRotatedConnectors()
+
+			// Check if the rotated connectors include both required connectors
+			hasToConnector := false
+			hasToStartConnector := false
+
+			for _, conn := range rotatedConnectors {
+				if conn == toConnector {
+					hasToConnector = true
+				}
+				if conn == toStartConnector {
+					hasToStartConnector = true
+				}
+			}
+
+			if hasToConnector && hasToStartConnector {
+				canHaveBothConnectors = true
+				snippetRotations[s] = rotation // Store the correct rotation
+				break
+			}
+		}
+
+		if canHaveBothConnectors {
+			filteredSnippets = append(filteredSnippets, s)
+		}
+	}
+
+	return filteredSnippets, snippetRotations
+}
+
 // generateBranches generates branches from the main path
 func (g *WorldGenerator) generateBranches(worldMap *WorldMap, config *WorldGenConfig, rng *rand.Rand) error {
 	// For each cell in the main path
@@ -489,6 +563,9 @@ func (g *WorldGenerator) generateBranch(worldMap *WorldMap, fromCell *WorldCell,
 	// Determine if this branch should be a dead-end
 	isDeadEnd := rng.Float64() < config.DeadEndProbability
 
+	// Determine the biome of the new cell
+	biome := config.branchBiome(fromCell, rng)
+
 	// We don't need to determine the connector from the current cell
 	// as we're only concerned with the connector on the new cell
 	// that connects back to the current cell
@@ -510,6 +587,7 @@ func (g *WorldGenerator) generateBranch(worldMap *WorldMap, fromCell *WorldCell,
 	if len(snippets) == 0 {
 		return fmt.Errorf("no snippets found with connector %d", toConnector)
 	}
+	snippets = biomeSnippets(snippets, biome)
 
 	// Filter snippets based on whether this should be a dead-end
 	var filteredSnippets []*WorldSnippet
@@ -540,7 +618,7 @@ func (g *WorldGenerator) generateBranch(worldMap *WorldMap, fromCell *WorldCell,
 	}
 
 	// Choose a snippet based on weight and type
-	snippet := SelectWeightedSnippetWithTypeWeights(filteredSnippets, config.SnippetTypeWeights, rng)
+	snippet := SelectWeightedSnippetWithTypeWeights(filteredSnippets, config.typeWeights(biome), rng)
 
 	// Determine the rotation needed
 	rotation := 0
@@ -562,6 +640,7 @@ func (g *WorldGenerator) generateBranch(worldMap *WorldMap, fromCell *WorldCell,
 		Rotation:    rotation,
 		IsMainPath:  false,
 		BranchDepth: depth,
+		Biome:       biome,
 	}
 
 	worldMap.AddCell(newCell)
@@ -715,6 +794,10 @@ func (g *WorldGenerator) generateDeadEnd(worldMap *WorldMap, fromCell *WorldCell
 		return fmt.Errorf("no snippets found with connector %d", toConnector)
 	}
 
+	// Dead-ends belong to the biome of the cell they are attached to
+	biome := fromCell.Biome
+	snippets = biomeSnippets(snippets, biome)
+
 	// Try to find a dead-end snippet (only one connector)
 	var deadEndSnippets []*WorldSnippet
 	for _, s := range snippets {
@@ -728,12 +811,12 @@ func (g *WorldGenerator) generateDeadEnd(worldMap *WorldMap, fromCell *WorldCell
 
 	// Prefer a dead-end snippet, but use any snippet if necessary
 	if len(deadEndSnippets) > 0 {
-		snippet = SelectWeightedSnippetWithTypeWeights(deadEndSnippets, config.SnippetTypeWeights, rng)
+		snippet = SelectWeightedSnippetWithTypeWeights(deadEndSnippets, config.typeWeights(biome), rng)
 		isDeadEnd = true
 	} else {
 		// If no dead-end snippets are available, use any snippet with the required connector
 		// We'll ensure all other connectors are connected to something
-		snippet = SelectWeightedSnippetWithTypeWeights(snippets, config.SnippetTypeWeights, rng)
+		snippet = SelectWeightedSnippetWithTypeWeights(snippets, config.typeWeights(biome), rng)
 		isDeadEnd = false
 	}
 
@@ -757,6 +840,7 @@ func (g *WorldGenerator) generateDeadEnd(worldMap *WorldMap, fromCell *WorldCell
 		Rotation:    rotation,
 		IsMainPath:  false,
 		BranchDepth: fromCell.BranchDepth + 1,
+		Biome:       biome,
 	}
 
 	worldMap.AddCell(newCell)
@@ -801,7 +885,7 @@ func (g *WorldGenerator) generateDeadEnd(worldMap *WorldMap, fromCell *WorldCell
 			if newCell.BranchDepth < config.BranchMaxDepth {
 				// Try to create a dead-end with a single-connector snippet
 				deadEndSnippets := make([]*WorldSnippet, 0)
-				for _, s := range g.Registry.GetSnippetsByConnector((conn + 180) % 360) {
+				for _, s := range biomeSnippets(g.Registry.GetSnippetsByConnector((conn+180)%360), biome) {
 					if len(s.Connectors) == 1 {
 						deadEndSnippets = append(deadEndSnippets, s)
 					}
@@ -810,7 +894,7 @@ func (g *WorldGenerator) generateDeadEnd(worldMap *WorldMap, fromCell *WorldCell
 				// If we have dead-end snippets, use one of them
 				if len(deadEndSnippets) > 0 {
 					// Create a temporary cell with a dead-end snippet
-					deadEndSnippet := SelectWeightedSnippetWithTypeWeights(deadEndSnippets, config.SnippetTypeWeights, rng)
+					deadEndSnippet := SelectWeightedSnippetWithTypeWeights(deadEndSnippets, config.typeWeights(biome), rng)
 					rotation := 0
 					for _, c := range deadEndSnippet.Connectors {
 						if c == (conn+180)%360 {
@@ -829,6 +913,7 @@ func (g *WorldGenerator) generateDeadEnd(worldMap *WorldMap, fromCell *WorldCell
 						Rotation:    rotation,
 						IsMainPath:  false,
 						BranchDepth: newCell.BranchDepth + 1,
+						Biome:       biome,
 					}
 
 					worldMap.AddCell(deadEndCell)
@@ -1197,7 +1282,7 @@ func (g *WorldGenerator) postProcessWorldMap(worldMap *WorldMap, rng *rand.Rand)
 	// This satisfies requirement 8: At least 2 other empty snippets must be adjacent to each empty snippet
 
 	// First, create a map to track which empty snippets need additional adjacent empty snippets
-	emptySnippetsNeedingAdjacent := make(map[string]bool)
+	emptySnippetsNeedingAdjacent := make(map[CellCoord]bool)
 
 	// Identify all empty snippets that need additional adjacent empty snippets
 	for y := minY + 2; y <= maxY-2; y++ {
@@ -1234,8 +1319,7 @@ func (g *WorldGenerator) postProcessWorldMap(worldMap *WorldMap, rng *rand.Rand)
 
 			// If this empty snippet has fewer than 2 adjacent empty snippets, mark it
 			if adjacentEmptyCount < 2 {
-				key := fmt.Sprintf("%d,%d", x, y)
-				emptySnippetsNeedingAdjacent[key] = true
+				emptySnippetsNeedingAdjacent[CellCoord{X: x, Y: y}] = true
 			}
 		}
 	}
@@ -1244,8 +1328,7 @@ func (g *WorldGenerator) postProcessWorldMap(worldMap *WorldMap, rng *rand.Rand)
 	// We do this in a separate pass to avoid conflicts between different empty snippets
 	for y := minY + 2; y <= maxY-2; y++ {
 		for x := minX + 2; x <= maxX-2; x++ {
-			key := fmt.Sprintf("%d,%d", x, y)
-			if !emptySnippetsNeedingAdjacent[key] {
+			if !emptySnippetsNeedingAdjacent[CellCoord{X: x, Y: y}] {
 				continue
 			}
 
@@ -1653,7 +1736,7 @@ func (g *WorldGenerator) postProcessWorldMap(worldMap *WorldMap, rng *rand.Rand)
 						// with a snippet that doesn't have this connector
 						// First, find all snippets that don't have this connector
 						var alternativeSnippets []*WorldSnippet
-						for _, s := range g.Registry.Snippets {
+						for _, s := range g.Registry.Ordered {
 							hasConnector := false
 							for _, c := range s.Connectors {
 								if c == conn {
@@ -1746,7 +1829,7 @@ func (g *WorldGenerator) postProcessWorldMap(worldMap *WorldMap, rng *rand.Rand)
 						// with a snippet that doesn't have this connector
 						// First, find all snippets that don't have this connector
 						var alternativeSnippets []*WorldSnippet
-						for _, s := range g.Registry.Snippets {
+						for _, s := range g.Registry.Ordered {
 							hasConnector := false
 							for _, c := range s.Connectors {
 								if c == conn {
@@ -1894,7 +1977,7 @@ func (g *WorldGenerator) postProcessWorldMap(worldMap *WorldMap, rng *rand.Rand)
 							if isConnectedToOthers {
 								// Find all snippets that don't have this connector
 								var alternativeSnippets []*WorldSnippet
-								for _, s := range g.Registry.Snippets {
+								for _, s := range g.Registry.Ordered {
 									hasConnector := false
 									for _, c := range s.Connectors {
 										if c == conn {
@@ -1926,7 +2009,7 @@ func (g *WorldGenerator) postProcessWorldMap(worldMap *WorldMap, rng *rand.Rand)
 							// with a snippet that doesn't have this connector
 							// First, find all snippets that don't have this connector
 							var alternativeSnippets []*WorldSnippet
-							for _, s := range g.Registry.Snippets {
+							for _, s := range g.Registry.Ordered {
 								hasConnector := false
 								for _, c := range s.Connectors {
 									if c == conn {
@@ -2042,7 +2125,7 @@ func (g *WorldGenerator) verifyAllPathsConnectedToMainPath(worldMap *WorldMap, e
 	}
 
 	// Create a map to track visited cells
-	visited := make(map[string]bool)
+	visited := make(map[CellCoord]bool)
 
 	// Start with all main path cells
 	queue := make([]*WorldCell, 0, len(worldMap.MainPathCells))
@@ -2075,7 +2158,7 @@ func (g *WorldGenerator) verifyAllPathsConnectedToMainPath(worldMap *WorldMap, e
 			nx, ny := cell.X+dir[0], cell.Y+dir[1]
 
 			// Skip if we've already visited this cell
-			adjacentKey := fmt.Sprintf("%d,%d", nx, ny)
+			adjacentKey := CellCoord{X: nx, Y: ny}
 			if visited[adjacentKey] {
 				continue
 			}
@@ -0,0 +1,515 @@
+package worldgen
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// RegionSize defines the width and height in cells of the regions an infinite world grows by.
+// It is a multiple of ChunkSize, so every grown region fills whole chunks.
+const RegionSize = 2 * ChunkSize
+
+// regionNodes is the number of junction nodes per region side (see regionLayout)
+const regionNodes = RegionSize / 2
+
+// RegionCoord identifies a region in the region grid
+type RegionCoord struct {
+	X, Y int
+}
+
+// FrontierGrower extends a generated world with new regions as the player approaches its edge.
+//
+// The finite world produced by GenerateWorld stays in the middle as the core. Everything
+// around it is split into regions of RegionSize x RegionSize cells. The layout of each region
+// only depends on the seed and the region coordinates, so the world grows identically no
+// matter in which order the player explores it.
+//
+// Inside a region, open cells sit on a lattice: junction nodes on cells with even
+// coordinates, joined by corridor cells in between. Cells with two odd coordinates are
+// always empty, so two open cells are only ever adjacent if they are meant to be connected.
+// Every open cell gets exactly the connectors towards its open neighbours, which keeps
+// requirement 2 (connectors are always matched) and requirement 7 (sides without a
+// connector face an empty snippet) valid while the world grows.
+type FrontierGrower struct {
+	generator    *WorldGenerator
+	config       *WorldGenConfig
+	worldMap     *WorldMap
+	emptySnippet *WorldSnippet
+
+	coreMin, coreMax RegionCoord // Regions covered by the core world
+
+	exits   map[regionEdge]int            // Gate positions on the edges between the core and the regions around it
+	layouts map[RegionCoord]*regionLayout // Layouts of regions, computed on demand
+	grown   map[RegionCoord]bool          // Regions whose cells were added to the world map
+}
+
+// regionEdge identifies the edge between a region and its right (east) or lower (south) neighbour
+type regionEdge struct {
+	X, Y  int
+	South bool
+}
+
+// regionLayout holds the open cells of one region
+type regionLayout struct {
+	open [RegionSize * RegionSize]bool
+}
+
+// NewFrontierGrower prepares the world map for growing.
+// The cells around the core are filled with empty snippets up to the region borders, and
+// one corridor per side is carved from the core to the regions around it.
+func NewFrontierGrower(generator *WorldGenerator, config *WorldGenConfig, worldMap *WorldMap) (*FrontierGrower, error) {
+	g := &FrontierGrower{
+		generator:    generator,
+		config:       config,
+		worldMap:     worldMap,
+		emptySnippet: generator.Registry.GetSnippet("Worldgen_x.png"),
+		exits:        make(map[regionEdge]int),
+		layouts:      make(map[RegionCoord]*regionLayout),
+		grown:        make(map[RegionCoord]bool),
+	}
+	if g.emptySnippet == nil {
+		return nil, fmt.Errorf("empty snippet 'Worldgen_x.png' not found")
+	}
+
+	// Every open cell has 1 to 3 connectors, so all these layouts must be available
+	for _, connectors := range [][]SnippetConnector{
+		{ConnectorLeft},
+		{ConnectorLeft, ConnectorRight},
+		{ConnectorLeft, ConnectorTop},
+		{ConnectorLeft, ConnectorTop, ConnectorBottom},
+	} {
+		if len(g.snippetsFor(connectorMask(connectors))) == 0 {
+			return nil, fmt.Errorf("no snippet with %d connectors for layout %v", len(connectors), connectors)
+		}
+	}
+
+	// Keep a margin of 3 cells around the open cells of the core, so the exit corridors can turn
+	minX, minY, maxX, maxY := 0, 0, 0, 0
+	first := true
+	for _, cell := range worldMap.Cells {
+		if isEmptyCell(cell) || cell.Snippet == nil {
+			continue
+		}
+		if first || cell.X < minX {
+			minX = cell.X
+		}
+		if first || cell.Y < minY {
+			minY = cell.Y
+		}
+		if first || cell.X > maxX {
+			maxX = cell.X
+		}
+		if first || cell.Y > maxY {
+			maxY = cell.Y
+		}
+		first = false
+	}
+	if first {
+		return nil, fmt.Errorf("world has no open cells to grow from")
+	}
+	g.coreMin = RegionCoord{X: FloorDiv(minX-3, RegionSize), Y: FloorDiv(minY-3, RegionSize)}
+	g.coreMax = RegionCoord{X: FloorDiv(maxX+3, RegionSize), Y: FloorDiv(maxY+3, RegionSize)}
+
+	// Fill the core regions, so the core ends on region borders like every other region
+	for y := g.coreMin.Y * RegionSize; y < (g.coreMax.Y+1)*RegionSize; y++ {
+		for x := g.coreMin.X * RegionSize; x < (g.coreMax.X+1)*RegionSize; x++ {
+			if !worldMap.HasCell(x, y) {
+				worldMap.AddCell(&WorldCell{X: x, Y: y, Snippet: g.emptySnippet})
+			}
+		}
+	}
+
+	rng := rand.New(rand.NewSource(mixSeed(config.Seed, 0, 0, 0)))
+	for _, dir := range validationDirections {
+		g.carveExit(dir.dx, dir.dy, rng)
+	}
+
+	// Cells added around the core join the biome of their neighbours
+	assignMissingBiomes(worldMap)
+
+	return g, nil
+}
+
+// IsGrown reports whether the cells of a region are part of the world map.
+// The core regions count as grown.
+func (g *FrontierGrower) IsGrown(region RegionCoord) bool {
+	return g.isCore(region) || g.grown[region]
+}
+
+// GrowArea grows all regions that overlap the given cell rectangle and
+// returns the cells that were added to the world map
+func (g *FrontierGrower) GrowArea(minCellX, minCellY, maxCellX, maxCellY int) []*WorldCell {
+	var added []*WorldCell
+	for ry := FloorDiv(minCellY, RegionSize); ry <= FloorDiv(maxCellY, RegionSize); ry++ {
+		for rx := FloorDiv(minCellX, RegionSize); rx <= FloorDiv(maxCellX, RegionSize); rx++ {
+			added = append(added, g.GrowRegion(RegionCoord{X: rx, Y: ry})...)
+		}
+	}
+	return added
+}
+
+// GrowRegion adds the cells of a region to the world map and returns them.
+// Regions that were already grown are left untouched.
+func (g *FrontierGrower) GrowRegion(region RegionCoord) []*WorldCell {
+	if g.IsGrown(region) {
+		return nil
+	}
+	g.grown[region] = true
+
+	rng := rand.New(rand.NewSource(mixSeed(g.config.Seed, int64(region.X), int64(region.Y), 2)))
+	biome := g.regionBiome(region)
+
+	cells := make([]*WorldCell, 0, RegionSize*RegionSize)
+	for localY := 0; localY < RegionSize; localY++ {
+		for localX := 0; localX < RegionSize; localX++ {
+			x, y := region.X*RegionSize+localX, region.Y*RegionSize+localY
+			cell := &WorldCell{X: x, Y: y, Snippet: g.emptySnippet, BranchDepth: 1, Biome: biome}
+
+			if g.isOpen(x, y) {
+				cell.Snippet, cell.Rotation = g.pickSnippet(g.openMask(x, y), biome, rng)
+			}
+
+			g.worldMap.AddCell(cell)
+			cells = append(cells, cell)
+		}
+	}
+
+	return cells
+}
+
+// carveExit connects the core to the regions on one side with a corridor.
+// It starts at the open core cell furthest towards that side whose way out is free.
+// The corridor is bent once if needed, so it leaves the core on the lattice of the
+// regions around it. Sides without a suitable cell get no exit.
+func (g *FrontierGrower) carveExit(dx, dy int, rng *rand.Rand) {
+	// Perpendicular direction and the coordinate of the last core cell in the direction
+	px, py := abs(dy), abs(dx)
+	var boundary int
+	switch {
+	case dx > 0:
+		boundary = (g.coreMax.X+1)*RegionSize - 1
+	case dx < 0:
+		boundary = g.coreMin.X * RegionSize
+	case dy > 0:
+		boundary = (g.coreMax.Y+1)*RegionSize - 1
+	default:
+		boundary = g.coreMin.Y * RegionSize
+	}
+	axis := func(x, y int) int { return x*px + y*py }
+
+	var start *WorldCell
+	for _, cell := range sortedCells(g.worldMap) {
+		if isEmptyCell(cell) || cell.Snippet == nil || len(cell.Snippet.Connectors) >= 3 {
+			continue
+		}
+		if g.exitBlocked(cell, dx, dy, px, py, boundary) {
+			continue
+		}
+		if start == nil || cell.X*dx+cell.Y*dy > start.X*dx+start.Y*dy {
+			start = cell
+		}
+	}
+	if start == nil {
+		return
+	}
+
+	// Walk to the boundary, moving one cell sideways after two steps if the cell is off the lattice
+	var corridor []*WorldCell
+	x, y := start.X, start.Y
+	shift := axis(x, y)%2 != 0
+	for step := 1; x*dx+y*dy < boundary*(dx+dy); step++ {
+		x, y = x+dx, y+dy
+		corridor = append(corridor, g.worldMap.GetCell(x, y))
+		if step == 2 && shift {
+			x, y = x+px, y+py
+			corridor = append(corridor, g.worldMap.GetCell(x, y))
+		}
+	}
+
+	// Open the gate on the region edge the corridor ends at
+	end := corridor[len(corridor)-1]
+	inside := RegionCoord{X: FloorDiv(end.X, RegionSize), Y: FloorDiv(end.Y, RegionSize)}
+	outside := RegionCoord{X: FloorDiv(end.X+dx, RegionSize), Y: FloorDiv(end.Y+dy, RegionSize)}
+	g.exits[regionEdgeBetween(inside, outside)] = (axis(end.X, end.Y) - axis(inside.X, inside.Y)*RegionSize) / 2
+
+	// Mark the corridor cells as open first, so their connectors can be derived from their neighbours
+	for _, cell := range corridor {
+		cell.Snippet = nil
+	}
+	start.Snippet, start.Rotation = g.pickSnippet(connectorMask(start.GetRotatedConnectors())|connectorBit(directionConnector(dx, dy)), start.Biome, rng)
+	for _, cell := range corridor {
+		cell.Snippet, cell.Rotation = g.pickSnippet(g.openMask(cell.X, cell.Y), cell.Biome, rng)
+	}
+}
+
+// exitBlocked reports whether a corridor from the cell towards the boundary would touch other
+// open cells. The corridor may bend by one cell, so a band of 4 cells is checked.
+func (g *FrontierGrower) exitBlocked(cell *WorldCell, dx, dy, px, py, boundary int) bool {
+	if connectorSet(cell)[directionConnector(dx, dy)] {
+		return true
+	}
+	for offset := -1; offset <= 2; offset++ {
+		x, y := cell.X+dx+px*offset, cell.Y+dy+py*offset
+		for ; x*dx+y*dy <= boundary*(dx+dy); x, y = x+dx, y+dy {
+			if neighbour := g.worldMap.GetCell(x, y); neighbour != nil && !isEmptyCell(neighbour) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// openMask returns the connector mask of an open cell, with a connector towards every open neighbour
+func (g *FrontierGrower) openMask(x, y int) int {
+	mask := 0
+	for _, dir := range validationDirections {
+		if g.isOpen(x+dir.dx, y+dir.dy) {
+			mask |= connectorBit(dir.connector)
+		}
+	}
+	return mask
+}
+
+// isOpen reports whether the cell at the given coordinates holds, or will hold, a snippet with connectors
+func (g *FrontierGrower) isOpen(x, y int) bool {
+	region := RegionCoord{X: FloorDiv(x, RegionSize), Y: FloorDiv(y, RegionSize)}
+	if g.isCore(region) {
+		cell := g.worldMap.GetCell(x, y)
+		// Corridor cells have no snippet while they are being carved
+		return cell != nil && (cell.Snippet == nil || len(cell.Snippet.Connectors) > 0)
+	}
+
+	localX, localY := x-region.X*RegionSize, y-region.Y*RegionSize
+	return g.layout(region).open[localY*RegionSize+localX]
+}
+
+// isCore reports whether a region is covered by the core world
+func (g *FrontierGrower) isCore(region RegionCoord) bool {
+	return region.X >= g.coreMin.X && region.X <= g.coreMax.X && region.Y >= g.coreMin.Y && region.Y <= g.coreMax.Y
+}
+
+// gate returns the node position of the gate on a region edge, or false if the edge is closed.
+// Edges between two grown regions always have a gate; edges at the core only at the exits.
+func (g *FrontierGrower) gate(edge regionEdge) (int, bool) {
+	other := RegionCoord{X: edge.X + 1, Y: edge.Y}
+	if edge.South {
+		other = RegionCoord{X: edge.X, Y: edge.Y + 1}
+	}
+	if g.isCore(RegionCoord{X: edge.X, Y: edge.Y}) || g.isCore(other) {
+		position, exists := g.exits[edge]
+		return position, exists
+	}
+
+	south := int64(0)
+	if edge.South {
+		south = 1
+	}
+	return int(uint64(mixSeed(g.config.Seed, int64(edge.X), int64(edge.Y), 3+south)) % regionNodes), true
+}
+
+// layout returns the layout of a region, computing it on first use
+func (g *FrontierGrower) layout(region RegionCoord) *regionLayout {
+	if layout, exists := g.layouts[region]; exists {
+		return layout
+	}
+	layout := g.generateLayout(region)
+	g.layouts[region] = layout
+	return layout
+}
+
+// generateLayout lays out the open cells of a region.
+// A random spanning tree joins the junction nodes of the region, starting at one of its
+// gates. Nodes keep at most 3 connections, since there is no snippet with 4 connectors.
+// Afterwards, dead-ends are kept with the configured dead-end probability.
+func (g *FrontierGrower) generateLayout(region RegionCoord) *regionLayout {
+	rng := rand.New(rand.NewSource(mixSeed(g.config.Seed, int64(region.X), int64(region.Y), 1)))
+	layout := &regionLayout{}
+
+	type node struct{ i, j int }
+	degree := make(map[node]int)
+	gates := make(map[node]bool)
+	var gateOrder []node
+	addGate := func(n node) {
+		degree[n]++
+		if !gates[n] {
+			gates[n] = true
+			gateOrder = append(gateOrder, n)
+		}
+	}
+	open := func(localX, localY int) {
+		layout.open[localY*RegionSize+localX] = true
+	}
+
+	// Gates to the left and top neighbours end on nodes of this region,
+	// gates to the right and bottom neighbours start with a corridor cell of this region
+	if j, exists := g.gate(regionEdge{X: region.X - 1, Y: region.Y}); exists {
+		addGate(node{0, j})
+	}
+	if i, exists := g.gate(regionEdge{X: region.X, Y: region.Y - 1, South: true}); exists {
+		addGate(node{i, 0})
+	}
+	if j, exists := g.gate(regionEdge{X: region.X, Y: region.Y}); exists {
+		addGate(node{regionNodes - 1, j})
+		open(RegionSize-1, 2*j)
+	}
+	if i, exists := g.gate(regionEdge{X: region.X, Y: region.Y, South: true}); exists {
+		addGate(node{i, regionNodes - 1})
+		open(2*i, RegionSize-1)
+	}
+
+	start := node{rng.Intn(regionNodes), rng.Intn(regionNodes)}
+	if len(gateOrder) > 0 {
+		start = gateOrder[0]
+	}
+
+	// Randomized depth-first search over the nodes
+	visited := map[node]bool{start: true}
+	links := make(map[node][]node)
+	var visit func(current node)
+	visit = func(current node) {
+		for _, k := range rng.Perm(4) {
+			dir := validationDirections[k]
+			next := node{current.i + dir.dx, current.j + dir.dy}
+			if next.i < 0 || next.i >= regionNodes || next.j < 0 || next.j >= regionNodes || visited[next] {
+				continue
+			}
+			if degree[current] >= 3 || degree[next] >= 3 {
+				continue
+			}
+			visited[next] = true
+			degree[current]++
+			degree[next]++
+			links[current] = append(links[current], next)
+			links[next] = append(links[next], current)
+			visit(next)
+		}
+	}
+	visit(start)
+
+	// Prune dead-ends that are not kept
+	removed := make(map[node]bool)
+	for j := 0; j < regionNodes; j++ {
+		for i := 0; i < regionNodes; i++ {
+			n := node{i, j}
+			if !visited[n] || gates[n] || len(links[n]) != 1 {
+				continue
+			}
+			parent := links[n][0]
+			if removed[parent] || degree[parent] <= 1 || rng.Float64() < g.config.DeadEndProbability {
+				continue
+			}
+			removed[n] = true
+			degree[parent]--
+		}
+	}
+
+	for n := range visited {
+		if removed[n] {
+			continue
+		}
+		open(2*n.i, 2*n.j)
+		for _, other := range links[n] {
+			if !removed[other] {
+				open(n.i+other.i, n.j+other.j)
+			}
+		}
+	}
+
+	return layout
+}
+
+// regionBiome returns the biome of a grown region, or nil if the world has no biomes
+func (g *FrontierGrower) regionBiome(region RegionCoord) *Biome {
+	if len(g.config.Biomes) == 0 {
+		return nil
+	}
+	index := uint64(mixSeed(g.config.Seed, int64(region.X), int64(region.Y), 5)) % uint64(len(g.config.Biomes))
+	return &g.config.Biomes[index]
+}
+
+// pickSnippet selects a snippet and rotation that has exactly the connectors in the mask
+func (g *FrontierGrower) pickSnippet(mask int, biome *Biome, rng *rand.Rand) (*WorldSnippet, int) {
+	if mask == 0 {
+		return g.emptySnippet, 0
+	}
+
+	candidates := biomeSnippets(g.snippetsFor(mask), biome)
+	snippet := SelectWeightedSnippetWithTypeWeights(candidates, g.config.typeWeights(biome), rng)
+
+	var rotations []int
+	for rotation := 0; rotation < 360; rotation += 90 {
+		if rotatedMask(snippet, rotation) == mask {
+			rotations = append(rotations, rotation)
+		}
+	}
+	return snippet, rotations[rng.Intn(len(rotations))]
+}
+
+// snippetsFor returns the snippets that have exactly the connectors in the mask after some rotation
+func (g *FrontierGrower) snippetsFor(mask int) []*WorldSnippet {
+	var snippets []*WorldSnippet
+	for _, snippet := range g.generator.Registry.Ordered {
+		for rotation := 0; rotation < 360; rotation += 90 {
+			if rotatedMask(snippet, rotation) == mask {
+				snippets = append(snippets, snippet)
+				break
+			}
+		}
+	}
+	return snippets
+}
+
+// regionEdgeBetween returns the edge between two adjacent regions
+func regionEdgeBetween(a, b RegionCoord) regionEdge {
+	if b.X < a.X || b.Y < a.Y {
+		a, b = b, a
+	}
+	return regionEdge{X: a.X, Y: a.Y, South: b.Y > a.Y}
+}
+
+// connectorBit returns the bit of a connector in a connector mask
+func connectorBit(connector SnippetConnector) int {
+	return 1 << (connector / 90)
+}
+
+// connectorMask returns the mask of a list of connectors
+func connectorMask(connectors []SnippetConnector) int {
+	mask := 0
+	for _, connector := range connectors {
+		mask |= connectorBit(connector)
+	}
+	return mask
+}
+
+// rotatedMask returns the connector mask of a snippet placed with the given rotation
+func rotatedMask(snippet *WorldSnippet, rotation int) int {
+	mask := 0
+	for _, connector := range snippet.Connectors {
+		mask |= connectorBit((connector + SnippetConnector(rotation)) % 360)
+	}
+	return mask
+}
+
+// directionConnector returns the connector facing the neighbour in the given direction
+func directionConnector(dx, dy int) SnippetConnector {
+	for _, dir := range validationDirections {
+		if dir.dx == dx && dir.dy == dy {
+			return dir.connector
+		}
+	}
+	return ConnectorTop
+}
+
+// mixSeed derives an independent seed from a world seed and a few values (splitmix64 finalizer)
+func mixSeed(seed, a, b, c int64) int64 {
+	mixed := uint64(seed)
+	for _, value := range []int64{a, b, c} {
+		mixed = mixed*0x9e3779b97f4a7c15 + uint64(value)
+		mixed ^= mixed >> 30
+		mixed *= 0xbf58476d1ce4e5b9
+		mixed ^= mixed >> 27
+		mixed *= 0x94d049bb133111eb
+		mixed ^= mixed >> 31
+	}
+	return int64(mixed)
+}
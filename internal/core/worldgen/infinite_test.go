@@ -0,0 +1,73 @@
+package worldgen
+
+import (
+	"testing"
+)
+
+// newTestFrontierGrower prepares the loop world of newLoopWorld for growing,
+// with one snippet per connector layout
+func newTestFrontierGrower(t *testing.T, seed int64) (*FrontierGrower, *WorldMap) {
+	t.Helper()
+
+	registry := NewSnippetRegistry()
+	for _, snippet := range []*WorldSnippet{
+		{Filename: "Worldgen_x.png", Weight: 10},
+		{Filename: "Worldgen_l.png", Weight: 10, Connectors: []SnippetConnector{ConnectorLeft}},
+		{Filename: "Worldgen_lr.png", Weight: 10, Connectors: []SnippetConnector{ConnectorLeft, ConnectorRight}},
+		{Filename: "Worldgen_lu.png", Weight: 10, Connectors: []SnippetConnector{ConnectorLeft, ConnectorBottom}},
+		{Filename: "Worldgen_lou.png", Weight: 10, Connectors: []SnippetConnector{ConnectorLeft, ConnectorTop, ConnectorBottom}},
+	} {
+		registry.addSnippet(snippet)
+	}
+
+	worldMap := newLoopWorld()
+	grower, err := NewFrontierGrower(&WorldGenerator{Registry: registry}, &WorldGenConfig{Seed: seed, DeadEndProbability: 0.5}, worldMap)
+	if err != nil {
+		t.Fatalf("Failed to create frontier grower: %v", err)
+	}
+	return grower, worldMap
+}
+
+// TestFrontierGrowerKeepsInvariants tests that grown regions keep connectors matched,
+// closed sides next to empty snippets, and every open cell reachable
+func TestFrontierGrowerKeepsInvariants(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		grower, worldMap := newTestFrontierGrower(t, seed)
+		grower.GrowArea(-3*RegionSize, -3*RegionSize, 4*RegionSize-1, 4*RegionSize-1)
+
+		for _, violation := range ValidateWorldMap(worldMap, nil) {
+			switch violation.Invariant {
+			case InvariantNoGaps:
+				// Open cells on the edge of the grown area face regions that do not exist yet
+				if abs(violation.X) < 3*RegionSize && abs(violation.Y) < 3*RegionSize {
+					t.Errorf("Seed %d: %v", seed, violation)
+				}
+			case InvariantConnectorsMatch, InvariantEmptySides, InvariantReachable, InvariantBranchEnds:
+				t.Errorf("Seed %d: %v", seed, violation)
+			}
+		}
+	}
+}
+
+// TestFrontierGrowerIsDeterministic tests that the world does not depend on the order regions are grown in
+func TestFrontierGrowerIsDeterministic(t *testing.T) {
+	forward, forwardMap := newTestFrontierGrower(t, 7)
+	backward, backwardMap := newTestFrontierGrower(t, 7)
+
+	for ry := -3; ry <= 3; ry++ {
+		for rx := -3; rx <= 3; rx++ {
+			forward.GrowRegion(RegionCoord{X: rx, Y: ry})
+			backward.GrowRegion(RegionCoord{X: -rx, Y: -ry})
+		}
+	}
+
+	if len(forwardMap.Cells) != len(backwardMap.Cells) {
+		t.Fatalf("Expected %d cells, got %d", len(forwardMap.Cells), len(backwardMap.Cells))
+	}
+	for key, cell := range forwardMap.Cells {
+		other := backwardMap.Cells[key]
+		if other == nil || other.Snippet.Filename != cell.Snippet.Filename || other.Rotation != cell.Rotation {
+			t.Fatalf("Cell %v differs: %+v and %+v", key, cell, other)
+		}
+	}
+}
@@ -0,0 +1,417 @@
+package worldgen
+
+import (
+	"container/heap"
+	"discoveryx/internal/utils/math"
+	"fmt"
+	stdmath "math"
+)
+
+// Navigation constants
+const (
+	NavTileSize     = 25                     // Size of a navigation tile in world units
+	NavTilesPerCell = CellSize / NavTileSize // Navigation tiles per side of a cell
+
+	navSamples       = 2               // Rock samples per navigation tile side
+	navMaxRouteCells = 4096            // Cells the route search may visit before it gives up
+	navCacheSize     = 1024            // Cached routes and paths before the caches are cleared
+	navPortalReach   = 2 * NavTileSize // Distance from an opening at which a way heads into the next cell
+)
+
+// NavTile is the position of a navigation tile in the grid of a cell
+type NavTile struct {
+	X, Y int
+}
+
+// navDirections are the neighbors of a cell or tile, in the order the searches visit them
+var navDirections = [4]NavTile{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+
+// NavGrid is the open space of a cell on a grid of navigation tiles. A tile is open when
+// it and the tiles next to it are free of rock, so a way along the centers of open tiles
+// keeps its distance from the walls.
+type NavGrid struct {
+	open []bool // NavTilesPerCell*NavTilesPerCell tiles, row by row
+}
+
+// Open reports whether a tile is inside the grid and open
+func (g *NavGrid) Open(tile NavTile) bool {
+	if tile.X < 0 || tile.X >= NavTilesPerCell || tile.Y < 0 || tile.Y >= NavTilesPerCell {
+		return false
+	}
+	return g.open[tile.Y*NavTilesPerCell+tile.X]
+}
+
+// clear checks that the straight line between the centers of two tiles only crosses open tiles
+func (g *NavGrid) clear(from, to NavTile) bool {
+	steps := 2 * max(abs(to.X-from.X), abs(to.Y-from.Y))
+	for i := 1; i < steps; i++ {
+		t := float64(i) / float64(steps)
+		tile := NavTile{
+			X: int(stdmath.Round(float64(from.X) + float64(to.X-from.X)*t)),
+			Y: int(stdmath.Round(float64(from.Y) + float64(to.Y-from.Y)*t)),
+		}
+		if !g.Open(tile) {
+			return false
+		}
+	}
+	return true
+}
+
+// navGridKey identifies the navigation grid of a snippet placed with a rotation
+type navGridKey struct {
+	snippet  *WorldSnippet
+	rotation int
+}
+
+// newNavGrid samples the navigation grid of a snippet placed with a rotation. Grids are
+// sampled from the snippet files instead of the loaded images, so they do not depend on streaming.
+func newNavGrid(snippet *WorldSnippet, rotation int) (*NavGrid, error) {
+	size := NavTilesPerCell * navSamples
+	var mask []bool
+	if snippet.Mask != nil {
+		mask = sampleRockMask(snippet.Mask, size)
+	} else {
+		var err error
+		if mask, err = loadRockMask(snippet.Filename, size); err != nil {
+			return nil, err
+		}
+	}
+
+	// A tile is rock if one of its samples is, after rotating the samples like the snippet
+	rock := make([]bool, NavTilesPerCell*NavTilesPerCell)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			srcX, srcY := rotateTileCoords(x, y, size, rotation)
+			if mask[srcY*size+srcX] {
+				rock[(y/navSamples)*NavTilesPerCell+x/navSamples] = true
+			}
+		}
+	}
+
+	// Keep a tile of clearance along the walls. Tiles outside of the cell belong to the
+	// neighboring cell, so the openings at the edges stay open.
+	grid := &NavGrid{open: make([]bool, len(rock))}
+	for ty := 0; ty < NavTilesPerCell; ty++ {
+		for tx := 0; tx < NavTilesPerCell; tx++ {
+			open := !rock[ty*NavTilesPerCell+tx]
+			for _, d := range navDirections {
+				nx, ny := tx+d.X, ty+d.Y
+				if open && nx >= 0 && nx < NavTilesPerCell && ny >= 0 && ny < NavTilesPerCell {
+					open = !rock[ny*NavTilesPerCell+nx]
+				}
+			}
+			grid.open[ty*NavTilesPerCell+tx] = open
+		}
+	}
+	return grid, nil
+}
+
+// cellRouteKey identifies a cached route between two cells
+type cellRouteKey struct {
+	from, to CellCoord
+}
+
+// tilePathKey identifies a cached path between two tiles of a cell
+type tilePathKey struct {
+	cell     CellCoord
+	from, to NavTile
+}
+
+// Navigation answers how to get from one place of the world to another. Routes between
+// cells are found with A* on the cell graph of the world map, where neighboring cells are
+// linked by a pair of matching connectors, like the validator checks them. Within a cell,
+// paths are found with A* on the navigation grid sampled from the pixels of its snippet.
+//
+// Routes and paths are cached. Navigation implements ChunkListener: loading a chunk
+// builds the navigation grids of its cells, so queries never read snippet files, and
+// drops the cached routes, since an infinite world adds cells to the map as it grows.
+// Streaming a chunk drops the cached paths through its cells. Grids are kept, as there
+// is at most one per snippet of the registry and rotation.
+type Navigation struct {
+	worldMap *WorldMap
+	grids    map[navGridKey]*NavGrid      // Navigation grids of the snippets of the loaded cells
+	routes   map[cellRouteKey][]CellCoord // Cached cell routes, nil for cells that are not connected
+	paths    map[tilePathKey][]NavTile    // Cached tile paths, nil for tiles that are not connected
+}
+
+// NewNavigation creates the navigation for the cells of a world map
+func NewNavigation(worldMap *WorldMap) *Navigation {
+	return &Navigation{
+		worldMap: worldMap,
+		grids:    make(map[navGridKey]*NavGrid),
+		routes:   make(map[cellRouteKey][]CellCoord),
+		paths:    make(map[tilePathKey][]NavTile),
+	}
+}
+
+// ChunkLoaded builds the navigation grids of the cells of the chunk and drops the cached
+// routes, which may lead through the new cells now. It implements ChunkListener.
+func (n *Navigation) ChunkLoaded(chunk *WorldChunk) {
+	for _, cell := range chunk.Cells {
+		if cell.Snippet == nil {
+			continue
+		}
+		key := navGridKey{snippet: cell.Snippet, rotation: cell.Rotation}
+		if _, exists := n.grids[key]; exists {
+			continue
+		}
+		grid, err := newNavGrid(cell.Snippet, cell.Rotation)
+		if err != nil {
+			fmt.Printf("Warning: no navigation grid for cell (%d,%d): %v\n", cell.X, cell.Y, err)
+			continue
+		}
+		n.grids[key] = grid
+	}
+
+	clear(n.routes)
+	n.invalidateCells(chunk)
+}
+
+// ChunkUnloaded drops the cached paths through the cells of the chunk.
+// It implements ChunkListener.
+func (n *Navigation) ChunkUnloaded(chunk *WorldChunk) {
+	n.invalidateCells(chunk)
+}
+
+// invalidateCells drops the cached paths within the cells of a chunk
+func (n *Navigation) invalidateCells(chunk *WorldChunk) {
+	for key := range n.paths {
+		if FloorDiv(key.cell.X, ChunkSize) == chunk.X && FloorDiv(key.cell.Y, ChunkSize) == chunk.Y {
+			delete(n.paths, key)
+		}
+	}
+}
+
+// Grid returns the navigation grid of a cell, false if the chunk of the cell was not
+// loaded yet or its snippet could not be read. The grid must not be modified.
+func (n *Navigation) Grid(cell *WorldCell) (*NavGrid, bool) {
+	grid, exists := n.grids[navGridKey{snippet: cell.Snippet, rotation: cell.Rotation}]
+	return grid, exists
+}
+
+// CellRoute returns the cells from one cell to another through linked cells, both included.
+// It returns nil if the cells are not connected.
+func (n *Navigation) CellRoute(from, to CellCoord) []CellCoord {
+	key := cellRouteKey{from: from, to: to}
+	if route, exists := n.routes[key]; exists {
+		return route
+	}
+	if n.worldMap.GetCell(from.X, from.Y) == nil || n.worldMap.GetCell(to.X, to.Y) == nil {
+		return nil
+	}
+
+	route := aStar(from, to, func(coord CellCoord) []CellCoord {
+		cell := n.worldMap.GetCell(coord.X, coord.Y)
+		var linked []CellCoord
+		for _, d := range navDirections {
+			neighbor := n.worldMap.GetCell(coord.X+d.X, coord.Y+d.Y)
+			if neighbor != nil && cellsConnected(cell, neighbor) {
+				linked = append(linked, neighbor.GetKey())
+			}
+		}
+		return linked
+	}, func(coord CellCoord) int {
+		return abs(to.X-coord.X) + abs(to.Y-coord.Y)
+	}, navMaxRouteCells)
+
+	if len(n.routes) >= navCacheSize {
+		clear(n.routes)
+	}
+	n.routes[key] = route
+	return route
+}
+
+// TilePath returns the tiles from one tile of a cell to another through open tiles, both
+// included. The end tiles may be closed, so a way out is found from close to a wall.
+// It returns nil if there is no way or the cell has no navigation grid.
+func (n *Navigation) TilePath(cell *WorldCell, from, to NavTile) []NavTile {
+	key := tilePathKey{cell: cell.GetKey(), from: from, to: to}
+	if path, exists := n.paths[key]; exists {
+		return path
+	}
+	grid, exists := n.Grid(cell)
+	if !exists {
+		return nil
+	}
+
+	path := aStar(from, to, func(tile NavTile) []NavTile {
+		var open []NavTile
+		for _, d := range navDirections {
+			next := NavTile{X: tile.X + d.X, Y: tile.Y + d.Y}
+			if grid.Open(next) || next == to {
+				open = append(open, next)
+			}
+		}
+		return open
+	}, func(tile NavTile) int {
+		return abs(to.X-tile.X) + abs(to.Y-tile.Y)
+	}, NavTilesPerCell*NavTilesPerCell)
+
+	if len(n.paths) >= navCacheSize {
+		clear(n.paths)
+	}
+	n.paths[key] = path
+	return path
+}
+
+// Route returns the way from one position to another through the openings of the cells
+// on the route, ending at the goal. It is coarse, for markers and the minimap; within the
+// cells the way may bend around walls. It returns false if the cells are not connected.
+func (n *Navigation) Route(from, to math.Vector) ([]math.Vector, bool) {
+	route := n.CellRoute(cellCoordAt(from), cellCoordAt(to))
+	if route == nil {
+		return nil, false
+	}
+
+	way := make([]math.Vector, 0, len(route))
+	for i := 1; i < len(route); i++ {
+		way = append(way, opening(route[i-1], route[i]))
+	}
+	return append(way, to), true
+}
+
+// NextWaypoint returns the position to move to next on the way from one position to another.
+// In another cell, the way leads through the opening towards the next cell on the route;
+// within a cell it follows the open tiles, skipping ahead as far as a straight line is clear.
+// It returns false if no way is known.
+func (n *Navigation) NextWaypoint(from, to math.Vector) (math.Vector, bool) {
+	fromCoord, toCoord := cellCoordAt(from), cellCoordAt(to)
+	cell := n.worldMap.GetCell(fromCoord.X, fromCoord.Y)
+	if cell == nil || cell.Snippet == nil {
+		return to, false
+	}
+
+	goal := to
+	if fromCoord != toCoord {
+		route := n.CellRoute(fromCoord, toCoord)
+		if len(route) < 2 {
+			return to, false
+		}
+
+		// Head into the next cell once close to the opening, and for the opening otherwise
+		next := route[1]
+		dx, dy := float64(next.X-fromCoord.X), float64(next.Y-fromCoord.Y)
+		gate := opening(fromCoord, next)
+		if math.Distance(from, gate) <= navPortalReach {
+			return math.Vector{X: gate.X + dx*navPortalReach, Y: gate.Y + dy*navPortalReach}, true
+		}
+		goal = math.Vector{X: gate.X - dx*NavTileSize/2, Y: gate.Y - dy*NavTileSize/2}
+	}
+
+	// Without a grid, head straight for the goal
+	grid, exists := n.Grid(cell)
+	if !exists {
+		return goal, true
+	}
+	start, end := tileAt(cell, from), tileAt(cell, goal)
+	path := n.TilePath(cell, start, end)
+	if path == nil {
+		return to, false
+	}
+
+	// Skip ahead to the farthest tile in a straight line
+	waypoint := len(path) - 1
+	for waypoint > 1 && !grid.clear(start, path[waypoint]) {
+		waypoint--
+	}
+	if path[waypoint] == end {
+		return goal, true
+	}
+	return math.Vector{
+		X: float64(cell.X*CellSize + path[waypoint].X*NavTileSize + NavTileSize/2),
+		Y: float64(cell.Y*CellSize + path[waypoint].Y*NavTileSize + NavTileSize/2),
+	}, true
+}
+
+// opening returns the middle of the edge between two neighboring cells, where snippets have
+// the openings of their connectors
+func opening(from, to CellCoord) math.Vector {
+	return math.Vector{
+		X: (float64(from.X) + 0.5 + float64(to.X-from.X)/2) * CellSize,
+		Y: (float64(from.Y) + 0.5 + float64(to.Y-from.Y)/2) * CellSize,
+	}
+}
+
+// cellCoordAt returns the coordinates of the cell that contains a position
+func cellCoordAt(position math.Vector) CellCoord {
+	return CellCoord{
+		X: FloorDiv(int(stdmath.Floor(position.X)), CellSize),
+		Y: FloorDiv(int(stdmath.Floor(position.Y)), CellSize),
+	}
+}
+
+// tileAt returns the navigation tile of a cell that contains a position, clamped to the cell
+func tileAt(cell *WorldCell, position math.Vector) NavTile {
+	tx := int(stdmath.Floor(position.X-float64(cell.X*CellSize))) / NavTileSize
+	ty := int(stdmath.Floor(position.Y-float64(cell.Y*CellSize))) / NavTileSize
+	return NavTile{X: min(max(tx, 0), NavTilesPerCell-1), Y: min(max(ty, 0), NavTilesPerCell-1)}
+}
+
+// aStar finds the shortest way from start to goal in a graph with unit steps.
+// Ties are broken by the order in which nodes were found, so the result only depends on
+// the order of the neighbors. It gives up after expanding the maximum number of nodes
+// and returns nil if there is no way.
+func aStar[T comparable](start, goal T, neighbors func(T) []T, estimate func(T) int, maxExpanded int) []T {
+	previous := map[T]T{start: start}
+	cost := map[T]int{start: 0}
+	queue := &aStarQueue[T]{{node: start, score: estimate(start)}}
+	found := 0
+
+	for expanded := 0; queue.Len() > 0 && expanded < maxExpanded; expanded++ {
+		entry := heap.Pop(queue).(aStarEntry[T])
+		if entry.node == goal {
+			path := []T{goal}
+			for node := goal; node != start; {
+				node = previous[node]
+				path = append(path, node)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path
+		}
+		if entry.cost > cost[entry.node] {
+			continue // Found a shorter way to this node after it was queued
+		}
+
+		for _, next := range neighbors(entry.node) {
+			nextCost := entry.cost + 1
+			if known, exists := cost[next]; exists && known <= nextCost {
+				continue
+			}
+			cost[next] = nextCost
+			previous[next] = entry.node
+			found++
+			heap.Push(queue, aStarEntry[T]{node: next, cost: nextCost, score: nextCost + estimate(next), order: found})
+		}
+	}
+	return nil
+}
+
+// aStarEntry is a node waiting in the A* queue
+type aStarEntry[T comparable] struct {
+	node  T
+	cost  int // Steps from the start
+	score int // Steps from the start plus the estimate to the goal
+	order int // Order in which the node was found
+}
+
+// aStarQueue orders the entries by score, then by the order they were found.
+// It implements heap.Interface.
+type aStarQueue[T comparable] []aStarEntry[T]
+
+func (q aStarQueue[T]) Len() int { return len(q) }
+func (q aStarQueue[T]) Less(i, j int) bool {
+	if q[i].score != q[j].score {
+		return q[i].score < q[j].score
+	}
+	return q[i].order < q[j].order
+}
+func (q aStarQueue[T]) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *aStarQueue[T]) Push(x any)   { *q = append(*q, x.(aStarEntry[T])) }
+func (q *aStarQueue[T]) Pop() any {
+	old := *q
+	entry := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return entry
+}
@@ -0,0 +1,201 @@
+package worldgen
+
+import (
+	"discoveryx/internal/utils/math"
+	"testing"
+)
+
+// maskCell creates a cell with a synthesized snippet whose rock is given on a 20x20 grid
+func maskCell(x, y int, connectors []SnippetConnector, isRock func(gx, gy int) bool) *WorldCell {
+	mask := &RockMask{Size: 20, Values: make([]float64, 400)}
+	for gy := 0; gy < mask.Size; gy++ {
+		for gx := 0; gx < mask.Size; gx++ {
+			if isRock(gx, gy) {
+				mask.Values[gy*mask.Size+gx] = 1
+			}
+		}
+	}
+	return &WorldCell{X: x, Y: y, Snippet: &WorldSnippet{Connectors: connectors, Mask: mask, Synthesized: true}}
+}
+
+// tunnel is rock everywhere but a horizontal tunnel through the middle of the cell
+func tunnel(gx, gy int) bool {
+	return gy < 8 || gy > 11
+}
+
+// pillar is a room with a pillar hanging from the ceiling in the middle
+func pillar(gx, gy int) bool {
+	border := gx == 0 || gx == 19 || gy == 0 || gy == 19
+	return border || (gx >= 9 && gx <= 10 && gy <= 13)
+}
+
+// loadNavigation creates the navigation of a world map and loads the chunks of all its cells
+func loadNavigation(worldMap *WorldMap) *Navigation {
+	navigation := NewNavigation(worldMap)
+	chunks := make(map[ChunkCoord]*WorldChunk)
+	for _, cell := range worldMap.Cells {
+		key := ChunkCoord{X: FloorDiv(cell.X, ChunkSize), Y: FloorDiv(cell.Y, ChunkSize)}
+		if chunks[key] == nil {
+			chunks[key] = NewWorldChunk(key.X, key.Y)
+		}
+		chunks[key].AddCell(cell)
+	}
+	for _, chunk := range chunks {
+		navigation.ChunkLoaded(chunk)
+	}
+	return navigation
+}
+
+// follow moves from a position along the waypoints of the navigation until it reaches the goal.
+// It fails the test if a step crosses rock or the goal is not reached.
+func follow(t *testing.T, navigation *Navigation, worldMap *WorldMap, from, to math.Vector) {
+	t.Helper()
+	position := from
+	for step := 0; step < 100; step++ {
+		waypoint, found := navigation.NextWaypoint(position, to)
+		if !found {
+			t.Fatalf("Expected a way from %v to %v", position, to)
+		}
+
+		// The straight line to the waypoint must stay in the air
+		for i := 0; i <= 100; i++ {
+			point := math.Vector{X: position.X + (waypoint.X-position.X)*float64(i)/100, Y: position.Y + (waypoint.Y-position.Y)*float64(i)/100}
+			coord := cellCoordAt(point)
+			cell := worldMap.GetCell(coord.X, coord.Y)
+			if cell == nil || cell.Snippet.Mask.IsRock(int(point.X)-coord.X*CellSize, int(point.Y)-coord.Y*CellSize) {
+				t.Fatalf("Step from %v to %v crosses rock at %v", position, waypoint, point)
+			}
+		}
+
+		position = waypoint
+		if position == to {
+			return
+		}
+	}
+	t.Fatalf("Expected to reach %v, stopped at %v", to, position)
+}
+
+// TestCellRoute tests that routes only lead through cells with openings into each other
+func TestCellRoute(t *testing.T) {
+	leftRight := []SnippetConnector{ConnectorLeft, ConnectorRight}
+	worldMap := NewWorldMap()
+	worldMap.AddCell(maskCell(0, 0, []SnippetConnector{ConnectorRight}, tunnel))
+	worldMap.AddCell(maskCell(1, 0, leftRight, tunnel))
+	worldMap.AddCell(maskCell(2, 0, []SnippetConnector{ConnectorLeft}, tunnel))
+	worldMap.AddCell(maskCell(1, 1, leftRight, tunnel)) // Next to the tunnel, but without an opening into it
+	navigation := loadNavigation(worldMap)
+
+	route := navigation.CellRoute(CellCoord{X: 0, Y: 0}, CellCoord{X: 2, Y: 0})
+	expected := []CellCoord{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}}
+	if len(route) != len(expected) {
+		t.Fatalf("Expected route %v, got %v", expected, route)
+	}
+	for i := range expected {
+		if route[i] != expected[i] {
+			t.Fatalf("Expected route %v, got %v", expected, route)
+		}
+	}
+
+	if route := navigation.CellRoute(CellCoord{X: 1, Y: 0}, CellCoord{X: 1, Y: 1}); route != nil {
+		t.Errorf("Expected no route into a cell without an opening, got %v", route)
+	}
+}
+
+// TestNavigationAvoidsRock tests that the waypoints lead around rock within a cell
+func TestNavigationAvoidsRock(t *testing.T) {
+	worldMap := NewWorldMap()
+	worldMap.AddCell(maskCell(0, 0, []SnippetConnector{ConnectorTop}, pillar))
+	navigation := loadNavigation(worldMap)
+
+	from, to := math.Vector{X: 200, Y: 300}, math.Vector{X: 800, Y: 300}
+	if waypoint, _ := navigation.NextWaypoint(from, to); waypoint == to {
+		t.Errorf("Expected a waypoint around the pillar, got the goal")
+	}
+	follow(t, navigation, worldMap, from, to)
+}
+
+// TestNavigationCrossesCells tests that the waypoints lead through the openings of several cells
+func TestNavigationCrossesCells(t *testing.T) {
+	leftRight := []SnippetConnector{ConnectorLeft, ConnectorRight}
+	worldMap := NewWorldMap()
+	worldMap.AddCell(maskCell(0, 0, []SnippetConnector{ConnectorRight}, tunnel))
+	worldMap.AddCell(maskCell(1, 0, leftRight, tunnel))
+	worldMap.AddCell(maskCell(2, 0, []SnippetConnector{ConnectorLeft}, tunnel))
+	navigation := loadNavigation(worldMap)
+
+	follow(t, navigation, worldMap, math.Vector{X: 300, Y: 500}, math.Vector{X: 2700, Y: 500})
+}
+
+// TestNavigationInvalidatesStreamedChunks tests that streaming drops the cached paths of a chunk
+// and that routes find cells that were added to the map after the last search
+func TestNavigationInvalidatesStreamedChunks(t *testing.T) {
+	worldMap := NewWorldMap()
+	worldMap.AddCell(maskCell(0, 0, []SnippetConnector{ConnectorRight}, tunnel))
+	worldMap.AddCell(maskCell(2, 0, []SnippetConnector{ConnectorLeft}, tunnel))
+	navigation := loadNavigation(worldMap)
+
+	from, to := CellCoord{X: 0, Y: 0}, CellCoord{X: 2, Y: 0}
+	if route := navigation.CellRoute(from, to); route != nil {
+		t.Fatalf("Expected no route past a missing cell, got %v", route)
+	}
+	if path := navigation.TilePath(worldMap.GetCell(0, 0), NavTile{X: 2, Y: 20}, NavTile{X: 30, Y: 20}); path == nil {
+		t.Fatalf("Expected a path through the tunnel")
+	}
+
+	navigation.ChunkUnloaded(&WorldChunk{X: 0, Y: 0})
+	if len(navigation.paths) != 0 {
+		t.Errorf("Expected the paths of the unloaded chunk to be dropped, %d left", len(navigation.paths))
+	}
+
+	// The world grows; the cached result holds until the chunk with the new cell is loaded
+	worldMap.AddCell(maskCell(1, 0, []SnippetConnector{ConnectorLeft, ConnectorRight}, tunnel))
+	if route := navigation.CellRoute(from, to); route != nil {
+		t.Errorf("Expected the cached result before loading, got %v", route)
+	}
+	navigation.ChunkLoaded(&WorldChunk{X: 0, Y: 0})
+	if route := navigation.CellRoute(from, to); len(route) != 3 {
+		t.Errorf("Expected a route through 3 cells after loading, got %v", route)
+	}
+}
+
+// TestNavGridRotation tests that the navigation grid is rotated like the snippet
+func TestNavGridRotation(t *testing.T) {
+	cell := maskCell(0, 0, []SnippetConnector{ConnectorLeft, ConnectorRight}, tunnel)
+	cell.Rotation = 90
+	worldMap := NewWorldMap()
+	worldMap.AddCell(cell)
+	grid, exists := loadNavigation(worldMap).Grid(cell)
+	if !exists {
+		t.Fatal("Expected a grid for the loaded cell")
+	}
+	if !grid.Open(NavTile{X: 20, Y: 2}) || grid.Open(NavTile{X: 2, Y: 20}) {
+		t.Errorf("Expected the tunnel to run from top to bottom after a rotation by 90 degrees")
+	}
+}
+
+// TestNavigationBuildsGridsOnLoad tests that grids are built when their chunk loads, and
+// that cells without one are crossed in a straight line
+func TestNavigationBuildsGridsOnLoad(t *testing.T) {
+	worldMap := NewWorldMap()
+	cell := maskCell(0, 0, []SnippetConnector{ConnectorTop}, pillar)
+	worldMap.AddCell(cell)
+	navigation := NewNavigation(worldMap)
+
+	from, to := math.Vector{X: 200, Y: 300}, math.Vector{X: 800, Y: 300}
+	if _, exists := navigation.Grid(cell); exists {
+		t.Fatal("Expected no grid before the chunk is loaded")
+	}
+	if waypoint, found := navigation.NextWaypoint(from, to); !found || waypoint != to {
+		t.Errorf("Expected to head straight for the goal without a grid, got %v", waypoint)
+	}
+
+	chunk := NewWorldChunk(0, 0)
+	chunk.AddCell(cell)
+	navigation.ChunkLoaded(chunk)
+	if _, exists := navigation.Grid(cell); !exists {
+		t.Fatal("Expected a grid after the chunk is loaded")
+	}
+	if waypoint, _ := navigation.NextWaypoint(from, to); waypoint == to {
+		t.Errorf("Expected a waypoint around the pillar, got the goal")
+	}
+}
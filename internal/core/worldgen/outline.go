@@ -0,0 +1,367 @@
+package worldgen
+
+import (
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+	"sync"
+)
+
+// outlineTolerance is the maximum distance in pixels between a simplified wall
+// outline and the traced pixel contour
+const outlineTolerance = 1.5
+
+// WallSegment is a straight piece of a wall outline
+type WallSegment struct {
+	A, B   math.Vector // End points, in outline order
+	Normal math.Vector // Unit normal of the wall (points away from the rock)
+}
+
+// WallOutline is a simplified contour along the border between rock and air.
+// Walking from one point to the next, the rock is always on the same side, so
+// all segment normals point into the air. Outlines that reach the border of a
+// snippet are open, because the wall continues in the neighbouring cell.
+type WallOutline struct {
+	Points   []math.Vector // Corner points of the outline
+	Closed   bool          // Whether the last point connects back to the first
+	Segments []WallSegment // Segments between the points, with their normals
+}
+
+// outlineKey identifies the outlines of a snippet placed with a rotation
+type outlineKey struct {
+	snippet  *WorldSnippet
+	rotation int
+}
+
+// outlineCache stores the rotated outlines of each snippet, so cells that share a
+// snippet and rotation do not transform the outlines again
+var outlineCache = struct {
+	sync.RWMutex
+	data map[outlineKey][]WallOutline
+}{
+	data: make(map[outlineKey][]WallOutline),
+}
+
+// GetWallOutlinesInWorldCoordinates returns the wall outlines of a cell in world coordinates
+func (cell *WorldCell) GetWallOutlinesInWorldCoordinates() []WallOutline {
+	if cell.Snippet == nil || len(cell.Snippet.Outlines) == 0 {
+		return nil
+	}
+
+	rotated := rotatedOutlines(cell.Snippet, cell.Rotation)
+	offset := math.Vector{X: float64(cell.X * CellSize), Y: float64(cell.Y * CellSize)}
+
+	worldOutlines := make([]WallOutline, len(rotated))
+	for i, outline := range rotated {
+		worldOutlines[i] = outline.translated(offset)
+	}
+	return worldOutlines
+}
+
+// rotatedOutlines returns the outlines of a snippet rotated around the snippet center.
+// The result is cached per snippet and rotation and must not be modified.
+func rotatedOutlines(snippet *WorldSnippet, rotation int) []WallOutline {
+	key := outlineKey{snippet: snippet, rotation: rotation}
+
+	outlineCache.RLock()
+	cached, exists := outlineCache.data[key]
+	outlineCache.RUnlock()
+	if exists {
+		return cached
+	}
+
+	angle := float64(rotation) * (stdmath.Pi / 180.0)
+	cosA := stdmath.Cos(angle)
+	sinA := stdmath.Sin(angle)
+	center := float64(CellSize) / 2
+
+	rotate := func(point math.Vector) math.Vector {
+		relX := point.X - center
+		relY := point.Y - center
+		return math.Vector{X: relX*cosA - relY*sinA + center, Y: relX*sinA + relY*cosA + center}
+	}
+
+	outlines := make([]WallOutline, len(snippet.Outlines))
+	for i, outline := range snippet.Outlines {
+		points := make([]math.Vector, len(outline.Points))
+		for j, point := range outline.Points {
+			points[j] = rotate(point)
+		}
+		outlines[i] = newWallOutline(points, outline.Closed)
+	}
+
+	outlineCache.Lock()
+	outlineCache.data[key] = outlines
+	outlineCache.Unlock()
+
+	return outlines
+}
+
+// translated returns a copy of the outline moved by the offset
+func (o WallOutline) translated(offset math.Vector) WallOutline {
+	moved := WallOutline{
+		Points:   make([]math.Vector, len(o.Points)),
+		Closed:   o.Closed,
+		Segments: make([]WallSegment, len(o.Segments)),
+	}
+	for i, point := range o.Points {
+		moved.Points[i] = math.Vector{X: point.X + offset.X, Y: point.Y + offset.Y}
+	}
+	for i, segment := range o.Segments {
+		moved.Segments[i] = WallSegment{
+			A:      math.Vector{X: segment.A.X + offset.X, Y: segment.A.Y + offset.Y},
+			B:      math.Vector{X: segment.B.X + offset.X, Y: segment.B.Y + offset.Y},
+			Normal: segment.Normal,
+		}
+	}
+	return moved
+}
+
+// newWallOutline creates an outline from its points and computes the segment normals
+func newWallOutline(points []math.Vector, closed bool) WallOutline {
+	outline := WallOutline{Points: points, Closed: closed}
+
+	count := len(points) - 1
+	if closed {
+		count = len(points)
+	}
+	for i := 0; i < count; i++ {
+		a, b := points[i], points[(i+1)%len(points)]
+		length := math.Distance(a, b)
+		if length == 0 {
+			continue
+		}
+		// Outlines keep the rock on the right of the walking direction (y points down)
+		outline.Segments = append(outline.Segments, WallSegment{
+			A:      a,
+			B:      b,
+			Normal: math.Vector{X: (b.Y - a.Y) / length, Y: -(b.X - a.X) / length},
+		})
+	}
+	return outline
+}
+
+// traceOutlines extracts simplified wall outlines from a width x height area.
+// The contours are traced with marching squares over the pixel centers and then
+// simplified with the Douglas-Peucker algorithm. Like detectWalls, the outlines
+// use pixel centers at integer coordinates.
+func traceOutlines(width, height int, isRock func(x, y int) bool) []WallOutline {
+	// Rock grid with a one pixel rock frame, so every traced contour is closed
+	gridWidth := width + 2
+	rock := make([]bool, gridWidth*(height+2))
+	for y := -1; y <= height; y++ {
+		for x := -1; x <= width; x++ {
+			rock[(y+1)*gridWidth+x+1] = x < 0 || x >= width || y < 0 || y >= height || isRock(x, y)
+		}
+	}
+	rockAt := func(x, y int) bool {
+		return rock[(y+1)*gridWidth+x+1]
+	}
+
+	// Contour pieces of all squares, from start to end point. Points are stored
+	// in half pixels, so the edge midpoints of the squares are integers.
+	next := make(map[[2]int][2]int)
+	var starts [][2]int
+
+	for y := -1; y < height; y++ {
+		for x := -1; x < width; x++ {
+			// Corners of the square: top left, top right, bottom right, bottom left
+			corners := [4][2]int{{x, y}, {x + 1, y}, {x + 1, y + 1}, {x, y + 1}}
+			var cornerRock [4]bool
+			for i, corner := range corners {
+				cornerRock[i] = rockAt(corner[0], corner[1])
+			}
+
+			// Midpoints of the square edges that separate rock from air (top, right, bottom, left)
+			var crossed [][2]int
+			for i := range corners {
+				if cornerRock[i] != cornerRock[(i+1)%4] {
+					a, b := corners[i], corners[(i+1)%4]
+					crossed = append(crossed, [2]int{a[0] + b[0], a[1] + b[1]})
+				}
+			}
+
+			var pieces [][2][2]int
+			switch len(crossed) {
+			case 2:
+				pieces = [][2][2]int{{crossed[0], crossed[1]}}
+			case 4:
+				// Saddle: cut off the rock corners, so the air stays connected
+				if cornerRock[0] {
+					pieces = [][2][2]int{{crossed[0], crossed[3]}, {crossed[1], crossed[2]}}
+				} else {
+					pieces = [][2][2]int{{crossed[0], crossed[1]}, {crossed[2], crossed[3]}}
+				}
+			}
+
+			for _, piece := range pieces {
+				start, end := orientPiece(piece[0], piece[1], corners, cornerRock)
+				next[start] = end
+				starts = append(starts, start)
+			}
+		}
+	}
+
+	var outlines []WallOutline
+	visited := make(map[[2]int]bool, len(next))
+	for _, start := range starts {
+		if visited[start] {
+			continue
+		}
+
+		// Follow the contour until it is closed
+		var loop [][2]int
+		for point := start; !visited[point]; point = next[point] {
+			visited[point] = true
+			loop = append(loop, point)
+		}
+
+		for _, run := range cutAtBorder(loop, width, height) {
+			points := make([]math.Vector, len(run.points))
+			for i, point := range run.points {
+				points[i] = math.Vector{X: float64(point[0]) / 2, Y: float64(point[1]) / 2}
+			}
+
+			if run.closed {
+				points = simplifyLoop(points, outlineTolerance)
+				if len(points) < 3 {
+					continue
+				}
+			} else {
+				points = simplifyPolyline(points, outlineTolerance)
+			}
+			outlines = append(outlines, newWallOutline(points, run.closed))
+		}
+	}
+
+	return outlines
+}
+
+// orientPiece orders the end points of a contour piece so the rock lies on its right.
+// The side is decided by the square corner closest to the piece.
+func orientPiece(a, b [2]int, corners [4][2]int, cornerRock [4]bool) ([2]int, [2]int) {
+	// Midpoint and normal of the piece, in quarter pixels
+	midX, midY := a[0]+b[0], a[1]+b[1]
+	normalX, normalY := b[1]-a[1], -(b[0] - a[0])
+
+	closest, closestDistance := 0, -1
+	for i, corner := range corners {
+		dx, dy := corner[0]*4-midX, corner[1]*4-midY
+		if distance := dx*dx + dy*dy; closestDistance < 0 || distance < closestDistance {
+			closest, closestDistance = i, distance
+		}
+	}
+
+	// The normal must point away from rock corners and towards air corners
+	toCorner := (corners[closest][0]*4-midX)*normalX + (corners[closest][1]*4-midY)*normalY
+	if (toCorner > 0) == cornerRock[closest] {
+		return b, a
+	}
+	return a, b
+}
+
+// contourRun is a part of a traced contour, in half pixels
+type contourRun struct {
+	points [][2]int
+	closed bool
+}
+
+// cutAtBorder removes the contour pieces that run along the rock frame around the
+// area. A contour that touches the frame is split into open runs.
+func cutAtBorder(loop [][2]int, width, height int) []contourRun {
+	onBorder := func(point [2]int) bool {
+		return point[0] == -1 || point[0] == 2*width-1 || point[1] == -1 || point[1] == 2*height-1
+	}
+
+	// A piece is dropped if both of its end points lie on the frame
+	keep := make([]bool, len(loop))
+	firstDropped := -1
+	for i := range loop {
+		keep[i] = !onBorder(loop[i]) || !onBorder(loop[(i+1)%len(loop)])
+		if !keep[i] && firstDropped < 0 {
+			firstDropped = i
+		}
+	}
+	if firstDropped < 0 {
+		return []contourRun{{points: loop, closed: true}}
+	}
+
+	var runs []contourRun
+	var current [][2]int
+	for step := 1; step <= len(loop); step++ {
+		i := (firstDropped + step) % len(loop)
+		if !keep[i] {
+			if len(current) >= 2 {
+				runs = append(runs, contourRun{points: current})
+			}
+			current = nil
+			continue
+		}
+		if len(current) == 0 {
+			current = append(current, loop[i])
+		}
+		current = append(current, loop[(i+1)%len(loop)])
+	}
+	return runs
+}
+
+// simplifyLoop simplifies a closed contour. The loop is split at the point
+// farthest from its first point, and both halves are simplified separately.
+func simplifyLoop(points []math.Vector, tolerance float64) []math.Vector {
+	if len(points) < 3 {
+		return points
+	}
+
+	farthest, farthestDistance := 0, 0.0
+	for i, point := range points {
+		if distance := math.Distance(points[0], point); distance > farthestDistance {
+			farthest, farthestDistance = i, distance
+		}
+	}
+
+	closedPoints := append(append([]math.Vector{}, points...), points[0])
+	first := simplifyPolyline(closedPoints[:farthest+1], tolerance)
+	second := simplifyPolyline(closedPoints[farthest:], tolerance)
+
+	// Both halves share the farthest point, and the second one ends at the first point again
+	simplified := append(first[:len(first):len(first)], second[1:len(second)-1]...)
+	return simplified
+}
+
+// simplifyPolyline simplifies an open polyline with the Douglas-Peucker algorithm.
+// The end points are always kept.
+func simplifyPolyline(points []math.Vector, tolerance float64) []math.Vector {
+	if len(points) < 3 {
+		return points
+	}
+
+	first, last := points[0], points[len(points)-1]
+	index, maxDistance := 0, 0.0
+	for i := 1; i < len(points)-1; i++ {
+		if distance := pointSegmentDistance(points[i], first, last); distance > maxDistance {
+			index, maxDistance = i, distance
+		}
+	}
+
+	if maxDistance <= tolerance {
+		return []math.Vector{first, last}
+	}
+
+	left := simplifyPolyline(points[:index+1], tolerance)
+	right := simplifyPolyline(points[index:], tolerance)
+
+	simplified := append([]math.Vector{}, left[:len(left)-1]...)
+	return append(simplified, right...)
+}
+
+// pointSegmentDistance returns the distance between a point and the segment from a to b
+func pointSegmentDistance(point, a, b math.Vector) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	lengthSq := dx*dx + dy*dy
+	if lengthSq == 0 {
+		return math.Distance(point, a)
+	}
+
+	t := ((point.X-a.X)*dx + (point.Y-a.Y)*dy) / lengthSq
+	t = stdmath.Max(0, stdmath.Min(1, t))
+	return math.Distance(point, math.Vector{X: a.X + dx*t, Y: a.Y + dy*t})
+}
@@ -0,0 +1,93 @@
+package worldgen
+
+import (
+	"discoveryx/internal/utils/math"
+	stdmath "math"
+	"testing"
+)
+
+// TestTraceOutlinesRoom tests that an air room inside rock becomes one closed outline with normals into the room
+func TestTraceOutlinesRoom(t *testing.T) {
+	outlines := traceOutlines(40, 40, func(x, y int) bool {
+		return x < 10 || x >= 30 || y < 10 || y >= 30
+	})
+
+	if len(outlines) != 1 {
+		t.Fatalf("Expected 1 outline, got %d", len(outlines))
+	}
+	outline := outlines[0]
+	if !outline.Closed {
+		t.Errorf("Expected a closed outline")
+	}
+	// Four walls plus the cut corners of the marching squares
+	if len(outline.Segments) > 8 {
+		t.Errorf("Expected a simplified outline, got %d segments", len(outline.Segments))
+	}
+
+	center := math.Vector{X: 19.5, Y: 19.5}
+	for _, segment := range outline.Segments {
+		middle := math.Vector{X: (segment.A.X + segment.B.X) / 2, Y: (segment.A.Y + segment.B.Y) / 2}
+		toCenter := math.Vector{X: center.X - middle.X, Y: center.Y - middle.Y}
+		if segment.Normal.X*toCenter.X+segment.Normal.Y*toCenter.Y <= 0 {
+			t.Errorf("Normal %v of segment %v-%v does not point into the room", segment.Normal, segment.A, segment.B)
+		}
+	}
+}
+
+// TestTraceOutlinesDiagonalWall tests that a staircase of pixels becomes a single straight segment
+// and that walls reaching the border of the area stay open
+func TestTraceOutlinesDiagonalWall(t *testing.T) {
+	outlines := traceOutlines(50, 50, func(x, y int) bool {
+		return y > x
+	})
+
+	if len(outlines) != 1 {
+		t.Fatalf("Expected 1 outline, got %d", len(outlines))
+	}
+	outline := outlines[0]
+	if outline.Closed {
+		t.Errorf("Expected an open outline")
+	}
+	if len(outline.Segments) != 1 {
+		t.Fatalf("Expected 1 segment, got %d", len(outline.Segments))
+	}
+
+	// The rock is below the diagonal, so the normal points up and to the right
+	normal := outline.Segments[0].Normal
+	expected := 1 / stdmath.Sqrt2
+	if stdmath.Abs(normal.X-expected) > 0.01 || stdmath.Abs(normal.Y+expected) > 0.01 {
+		t.Errorf("Expected normal (%.2f, %.2f), got %v", expected, -expected, normal)
+	}
+}
+
+// TestRotatedOutlines tests that rotated outlines are cached and keep their normals pointing into the air
+func TestRotatedOutlines(t *testing.T) {
+	// Rock on the left half of the snippet: the wall normal points right
+	snippet := &WorldSnippet{Filename: "half.png"}
+	snippet.Outlines = traceOutlines(CellSize, CellSize, func(x, y int) bool {
+		return x < CellSize/2
+	})
+
+	rotated := rotatedOutlines(snippet, 90)
+	if len(rotated) != 1 || len(rotated[0].Segments) != 1 {
+		t.Fatalf("Expected a single wall segment, got %+v", rotated)
+	}
+	if &rotatedOutlines(snippet, 90)[0] != &rotated[0] {
+		t.Errorf("Expected the rotated outlines to be cached")
+	}
+
+	// Rotated by 90 degrees clockwise the rock is on the top, so the normal points down
+	normal := rotated[0].Segments[0].Normal
+	if stdmath.Abs(normal.X) > 1e-9 || stdmath.Abs(normal.Y-1) > 1e-9 {
+		t.Errorf("Expected normal (0, 1), got %v", normal)
+	}
+
+	cell := &WorldCell{X: -1, Y: 2, Snippet: snippet, Rotation: 90}
+	segment := cell.GetWallOutlinesInWorldCoordinates()[0].Segments[0]
+	if stdmath.Abs(segment.A.Y-2.5*CellSize) > 1 || stdmath.Abs(segment.B.Y-2.5*CellSize) > 1 {
+		t.Errorf("Expected a horizontal wall through the middle of the cell, got %v-%v", segment.A, segment.B)
+	}
+	if stdmath.Min(segment.A.X, segment.B.X) > -CellSize+1 || stdmath.Max(segment.A.X, segment.B.X) < -1 {
+		t.Errorf("Expected the wall to span the cell, got %v-%v", segment.A, segment.B)
+	}
+}
@@ -37,8 +37,14 @@ type WorldSnippet struct {
 	Filename   string             // The filename of the snippet image
 	Connectors []SnippetConnector // The connectors this snippet has
 	Weight     int                // The relative probability weight for selection
-	Image      *ebiten.Image      // The loaded image
+	Image      *ebiten.Image      // The loaded image (nil while freed by chunk streaming)
 	Walls      []WallPoint        // The wall points detected in this snippet
+	Outlines   []WallOutline      // The simplified wall outlines of this snippet (unrotated)
+	Forces     []ForceVolume      // The force volumes declared in the metadata (unrotated)
+
+	// Procedurally synthesized snippets (see SnippetSynthesizer)
+	Mask        *RockMask // Rock layout (nil for authored snippets)
+	Synthesized bool      // Whether the snippet was synthesized instead of loaded from a file
 }
 
 // GetType returns the type of the snippet based on the number of connectors
@@ -55,15 +61,21 @@ func (s *WorldSnippet) GetType() SnippetType {
 
 // SnippetMetadata represents the JSON structure of a snippet metadata file
 type SnippetMetadata struct {
-	Filename   string `json:"filename"`
-	Connectors []int  `json:"connectors"`
-	Weight     int    `json:"weight"`
+	Filename   string        `json:"filename"`
+	Connectors []int         `json:"connectors"`
+	Weight     int           `json:"weight"`
+	Forces     []ForceVolume `json:"forces,omitempty"`
 }
 
 // SnippetRegistry manages all available world snippets and provides methods to access them
 type SnippetRegistry struct {
-	Snippets    map[string]*WorldSnippet                // Map of snippets by filename
-	ByConnector map[SnippetConnector][]*WorldSnippet    // Map of snippets by connector
+	Snippets    map[string]*WorldSnippet             // Map of snippets by filename
+	ByConnector map[SnippetConnector][]*WorldSnippet // Map of snippets by connector
+	Ordered     []*WorldSnippet                      // Snippets in load order, for deterministic iteration
+
+	imagesLoaded    bool            // Whether snippet images are loaded (false for headless registries)
+	synthesisConfig SynthesisConfig // Config of the current synthesized snippets
+	synthesisSeed   int64           // Seed of the current synthesized snippets
 }
 
 // NewSnippetRegistry creates a new empty snippet registry
@@ -71,17 +83,33 @@ func NewSnippetRegistry() *SnippetRegistry {
 	return &SnippetRegistry{
 		Snippets:    make(map[string]*WorldSnippet),
 		ByConnector: make(map[SnippetConnector][]*WorldSnippet),
+		Ordered:     make([]*WorldSnippet, 0),
 	}
 }
 
 // LoadSnippets loads all snippet metadata and images from the specified directory
 func (r *SnippetRegistry) LoadSnippets(metadataDir, imageDir string) error {
+	return r.loadSnippets(metadataDir, true)
+}
+
+// LoadSnippetMetadata loads only the snippet metadata from the specified directory.
+// Images are not decoded and walls are not detected, so the registry can be used
+// for headless world generation (e.g. the cmd/worldgen tool) where no graphics
+// context is available.
+func (r *SnippetRegistry) LoadSnippetMetadata(metadataDir string) error {
+	return r.loadSnippets(metadataDir, false)
+}
+
+// loadSnippets loads all snippet metadata and, if requested, their images
+func (r *SnippetRegistry) loadSnippets(metadataDir string, loadImages bool) error {
 	// Load metadata from JSON files
 	metadataFiles, err := assets.Assets.ReadDir(metadataDir)
 	if err != nil {
 		return fmt.Errorf("failed to read metadata directory: %w", err)
 	}
 
+	r.imagesLoaded = loadImages
+
 	// Process each metadata file
 	for _, metadataFile := range metadataFiles {
 		if filepath.Ext(metadataFile.Name()) != ".json" {
@@ -95,11 +123,19 @@ func (r *SnippetRegistry) LoadSnippets(metadataDir, imageDir string) error {
 			return fmt.Errorf("failed to load metadata from %s: %w", metadataPath, err)
 		}
 
+		// Reject force volumes that could not be applied
+		for i, volume := range metadata.Forces {
+			if err := volume.validate(); err != nil {
+				return fmt.Errorf("invalid force volume %d in %s: %w", i, metadataPath, err)
+			}
+		}
+
 		// Create WorldSnippet from metadata
 		snippet := &WorldSnippet{
 			Filename:   metadata.Filename,
 			Weight:     metadata.Weight,
 			Connectors: make([]SnippetConnector, len(metadata.Connectors)),
+			Forces:     metadata.Forces,
 		}
 
 		// Convert connector integers to SnippetConnector type
@@ -107,10 +143,11 @@ func (r *SnippetRegistry) LoadSnippets(metadataDir, imageDir string) error {
 			snippet.Connectors[i] = SnippetConnector(conn)
 		}
 
-		// Load the image
-		imagePath := filepath.Join("images/gameScene/World", metadata.Filename)
-		img := assets.GetImage(imagePath)
-		snippet.Image = img
+		// Load the image. It is not taken from the shared asset cache, so chunk
+		// streaming can free it while no loaded chunk uses the snippet.
+		if loadImages {
+			snippet.Image = assets.LoadImage(SnippetImagePath(metadata.Filename))
+		}
 
 		// Add to registry
 		r.addSnippet(snippet)
@@ -119,6 +156,11 @@ func (r *SnippetRegistry) LoadSnippets(metadataDir, imageDir string) error {
 	return nil
 }
 
+// SnippetImagePath returns the asset path of a snippet image
+func SnippetImagePath(filename string) string {
+	return "images/gameScene/World/" + filename
+}
+
 // loadMetadata loads a single metadata file
 func (r *SnippetRegistry) loadMetadata(path string) (*SnippetMetadata, error) {
 	// Read the metadata file
@@ -141,14 +183,19 @@ func (r *SnippetRegistry) loadMetadata(path string) (*SnippetMetadata, error) {
 func (r *SnippetRegistry) addSnippet(snippet *WorldSnippet) {
 	// Add to main map
 	r.Snippets[snippet.Filename] = snippet
+	r.Ordered = append(r.Ordered, snippet)
 
 	// Index by connector
 	for _, conn := range snippet.Connectors {
 		r.ByConnector[conn] = append(r.ByConnector[conn], snippet)
 	}
 
-	// Detect walls in the snippet
-	snippet.Walls = DetectWallsInSnippet(snippet)
+	// Detect walls in the snippet (only possible once the image is loaded).
+	// Synthesized snippets already come with their walls.
+	if snippet.Image != nil && snippet.Walls == nil {
+		snippet.Walls = DetectWallsInSnippet(snippet)
+		snippet.Outlines = DetectWallOutlinesInSnippet(snippet)
+	}
 }
 
 // GetSnippetsByConnector returns all snippets that have the specified connector
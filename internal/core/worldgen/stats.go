@@ -0,0 +1,149 @@
+package worldgen
+
+import (
+	"math"
+	"sort"
+)
+
+// WorldStats summarizes the layout of a single generated world
+type WorldStats struct {
+	Cells           int                 `json:"cells"`             // Total number of cells, including empty ones
+	OpenCells       int                 `json:"open_cells"`        // Cells holding a snippet with connectors
+	MainPathLength  int                 `json:"main_path_length"`  // Number of main path cells
+	BranchCells     int                 `json:"branch_cells"`      // Open cells that are not on the main path
+	Branches        int                 `json:"branches"`          // Connected groups of branch cells
+	DeadEndBranches int                 `json:"dead_end_branches"` // Branches that contain a dead-end snippet
+	DeadEnds        int                 `json:"dead_ends"`         // Number of dead-end snippets
+	SnippetUsage    map[string]int      `json:"snippet_usage"`     // Number of cells per snippet filename
+	TypeUsage       map[SnippetType]int `json:"type_usage"`        // Number of open cells per snippet type
+}
+
+// DeadEndRatio returns the fraction of branches that end in a dead-end
+func (s *WorldStats) DeadEndRatio() float64 {
+	if s.Branches == 0 {
+		return 0
+	}
+	return float64(s.DeadEndBranches) / float64(s.Branches)
+}
+
+// ComputeWorldStats collects the statistics of a generated world
+func ComputeWorldStats(worldMap *WorldMap) *WorldStats {
+	stats := &WorldStats{
+		Cells:          len(worldMap.Cells),
+		MainPathLength: len(worldMap.MainPathCells),
+		SnippetUsage:   make(map[string]int),
+		TypeUsage:      make(map[SnippetType]int),
+	}
+
+	branchCells := make([]*WorldCell, 0)
+	for _, cell := range sortedCells(worldMap) {
+		if cell.Snippet == nil {
+			continue
+		}
+		stats.SnippetUsage[cell.Snippet.Filename]++
+		if isEmptyCell(cell) {
+			continue
+		}
+
+		stats.OpenCells++
+		stats.TypeUsage[cell.Snippet.GetType()]++
+		if cell.Snippet.GetType() == SnippetTypeDeadEnd {
+			stats.DeadEnds++
+		}
+		if !cell.IsMainPath {
+			branchCells = append(branchCells, cell)
+		}
+	}
+	stats.BranchCells = len(branchCells)
+
+	// Group connected branch cells into branches
+	visited := make(map[*WorldCell]bool, len(branchCells))
+	for _, start := range branchCells {
+		if visited[start] {
+			continue
+		}
+
+		stats.Branches++
+		hasDeadEnd := false
+		visited[start] = true
+		queue := []*WorldCell{start}
+		for len(queue) > 0 {
+			cell := queue[0]
+			queue = queue[1:]
+
+			if cell.Snippet.GetType() == SnippetTypeDeadEnd {
+				hasDeadEnd = true
+			}
+
+			for _, dir := range validationDirections {
+				neighbour := worldMap.GetCell(cell.X+dir.dx, cell.Y+dir.dy)
+				if neighbour == nil || visited[neighbour] || neighbour.IsMainPath || isEmptyCell(neighbour) {
+					continue
+				}
+				if !cellsConnected(cell, neighbour) {
+					continue
+				}
+				visited[neighbour] = true
+				queue = append(queue, neighbour)
+			}
+		}
+
+		if hasDeadEnd {
+			stats.DeadEndBranches++
+		}
+	}
+
+	return stats
+}
+
+// Distribution summarizes a series of values
+type Distribution struct {
+	Count     int         `json:"count"`
+	Min       float64     `json:"min"`
+	Max       float64     `json:"max"`
+	Mean      float64     `json:"mean"`
+	Median    float64     `json:"median"`
+	P90       float64     `json:"p90"`                 // 90th percentile
+	Histogram map[int]int `json:"histogram,omitempty"` // Number of occurrences per value, for integer series
+}
+
+// NewDistribution summarizes the values. If withHistogram is set, the values are
+// rounded to integers and counted per value.
+func NewDistribution(values []float64, withHistogram bool) Distribution {
+	d := Distribution{Count: len(values)}
+	if len(values) == 0 {
+		return d
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, value := range sorted {
+		sum += value
+	}
+
+	d.Min = sorted[0]
+	d.Max = sorted[len(sorted)-1]
+	d.Mean = sum / float64(len(sorted))
+	d.Median = percentile(sorted, 0.5)
+	d.P90 = percentile(sorted, 0.9)
+
+	if withHistogram {
+		d.Histogram = make(map[int]int)
+		for _, value := range sorted {
+			d.Histogram[int(math.Round(value))]++
+		}
+	}
+
+	return d
+}
+
+// percentile returns the value at the given fraction of a sorted series (nearest rank)
+func percentile(sorted []float64, fraction float64) float64 {
+	rank := int(math.Ceil(fraction*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
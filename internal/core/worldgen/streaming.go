@@ -0,0 +1,219 @@
+package worldgen
+
+import (
+	"discoveryx/internal/assets"
+	"discoveryx/internal/config"
+	"github.com/hajimehoshi/ebiten/v2"
+	"sort"
+)
+
+// StreamingConfig configures how chunks are streamed in and out as the player moves
+type StreamingConfig struct {
+	ImageBudgetBytes int64 // Snippet image memory kept for chunks that are not loaded (0 frees it immediately)
+	UnloadMargin     int   // Chunks beyond VisibilityRadius before a loaded chunk is unloaded
+}
+
+// NewStreamingConfig creates a streaming configuration from the streaming section of the game config
+func NewStreamingConfig(settings config.StreamingSettings) StreamingConfig {
+	return StreamingConfig{
+		ImageBudgetBytes: int64(settings.ImageBudgetMB * 1024 * 1024),
+		UnloadMargin:     settings.UnloadMargin,
+	}
+}
+
+// ChunkListener is notified when chunks are loaded and unloaded.
+// Gameplay systems use it to create and free the data they keep per chunk,
+// such as wall colliders and enemies.
+type ChunkListener interface {
+	// ChunkLoaded is called after a chunk was loaded. Its snippet images are available.
+	ChunkLoaded(chunk *WorldChunk)
+	// ChunkUnloaded is called before a chunk is unloaded, while its snippet images are still available.
+	ChunkUnloaded(chunk *WorldChunk)
+}
+
+// AddChunkListener registers a listener for chunk load and unload events.
+// The listener is immediately notified about all chunks that are already loaded.
+func (w *GeneratedWorld) AddChunkListener(listener ChunkListener) {
+	w.listeners = append(w.listeners, listener)
+	for _, chunk := range w.loaded {
+		listener.ChunkLoaded(chunk)
+	}
+}
+
+// EnableStreaming makes the world free snippet images of chunks that leave the
+// visibility radius and reload them when the chunks come back into view.
+// Without streaming, all snippet images stay in memory for the whole run.
+func (w *GeneratedWorld) EnableStreaming(streaming StreamingConfig) {
+	w.streaming = streaming
+	w.images = newSnippetImageCache(streaming.ImageBudgetBytes)
+
+	// Images decoded by the registry count against the budget until they are used
+	for _, snippet := range w.generator.Registry.Ordered {
+		w.images.adopt(snippet)
+	}
+	for _, chunk := range w.loaded {
+		w.images.acquireChunk(chunk)
+	}
+	w.images.trim()
+}
+
+// ResidentImageBytes returns the memory used by snippet images that are currently
+// decoded, or 0 if streaming is not enabled
+func (w *GeneratedWorld) ResidentImageBytes() int64 {
+	if w.images == nil {
+		return 0
+	}
+	return w.images.resident
+}
+
+// loadChunk marks a chunk as loaded, makes its snippet images available and notifies the listeners
+func (w *GeneratedWorld) loadChunk(chunk *WorldChunk) {
+	if w.images != nil {
+		w.images.acquireChunk(chunk)
+	}
+	chunk.Load()
+	w.loaded = append(w.loaded, chunk)
+
+	for _, listener := range w.listeners {
+		listener.ChunkLoaded(chunk)
+	}
+}
+
+// unloadChunk notifies the listeners, marks the chunk as unloaded and releases its snippet images.
+// The chunk must be removed from w.loaded by the caller.
+func (w *GeneratedWorld) unloadChunk(chunk *WorldChunk) {
+	for _, listener := range w.listeners {
+		listener.ChunkUnloaded(chunk)
+	}
+
+	chunk.Unload()
+	if w.images != nil {
+		w.images.releaseChunk(chunk)
+	}
+}
+
+// snippetImageCache reference counts the snippet images used by loaded chunks.
+// Images that are no longer used stay decoded while they fit into the budget,
+// so walking back and forth over a chunk border does not decode them again.
+// When the budget is exceeded, the least recently used images are freed.
+type snippetImageCache struct {
+	budget   int64                         // Bytes of unused images to keep
+	resident int64                         // Bytes of all decoded images
+	entries  map[*WorldSnippet]*imageEntry // Decoded images by snippet
+	clock    int64                         // Incremented on every release, for LRU ordering
+}
+
+// imageEntry tracks a single decoded snippet image
+type imageEntry struct {
+	refs     int   // Number of loaded cells using the image
+	bytes    int64 // Memory used by the image
+	lastUsed int64 // Clock value of the last release
+}
+
+// newSnippetImageCache creates an empty image cache with the given budget for unused images
+func newSnippetImageCache(budget int64) *snippetImageCache {
+	return &snippetImageCache{
+		budget:  budget,
+		entries: make(map[*WorldSnippet]*imageEntry),
+	}
+}
+
+// adopt starts tracking an image that was decoded outside of the cache
+func (c *snippetImageCache) adopt(snippet *WorldSnippet) {
+	if snippet.Image == nil || c.entries[snippet] != nil {
+		return
+	}
+	entry := &imageEntry{bytes: imageBytes(snippet.Image)}
+	c.entries[snippet] = entry
+	c.resident += entry.bytes
+}
+
+// acquireChunk makes the images of all cells in the chunk available
+func (c *snippetImageCache) acquireChunk(chunk *WorldChunk) {
+	for _, cell := range chunk.Cells {
+		if cell.Snippet != nil {
+			c.acquire(cell.Snippet)
+		}
+	}
+}
+
+// releaseChunk releases the images of all cells in the chunk and frees unused images over budget
+func (c *snippetImageCache) releaseChunk(chunk *WorldChunk) {
+	for _, cell := range chunk.Cells {
+		if cell.Snippet != nil {
+			c.release(cell.Snippet)
+		}
+	}
+	c.trim()
+}
+
+// acquire decodes the image of a snippet if necessary and marks it as used
+func (c *snippetImageCache) acquire(snippet *WorldSnippet) {
+	entry := c.entries[snippet]
+	if entry == nil {
+		if snippet.Image == nil {
+			snippet.Image = loadSnippetImage(snippet)
+		}
+		entry = &imageEntry{bytes: imageBytes(snippet.Image)}
+		c.entries[snippet] = entry
+		c.resident += entry.bytes
+	}
+	entry.refs++
+}
+
+// release marks one use of a snippet image as finished
+func (c *snippetImageCache) release(snippet *WorldSnippet) {
+	entry := c.entries[snippet]
+	if entry == nil || entry.refs == 0 {
+		return
+	}
+	entry.refs--
+	c.clock++
+	entry.lastUsed = c.clock
+}
+
+// trim frees the least recently used unused images until the unused images fit into the budget
+func (c *snippetImageCache) trim() {
+	var unused []*WorldSnippet
+	var unusedBytes int64
+	for snippet, entry := range c.entries {
+		if entry.refs == 0 {
+			unused = append(unused, snippet)
+			unusedBytes += entry.bytes
+		}
+	}
+	if unusedBytes <= c.budget {
+		return
+	}
+
+	sort.Slice(unused, func(i, j int) bool {
+		return c.entries[unused[i]].lastUsed < c.entries[unused[j]].lastUsed
+	})
+	for _, snippet := range unused {
+		if unusedBytes <= c.budget {
+			break
+		}
+		entry := c.entries[snippet]
+		unusedBytes -= entry.bytes
+		c.resident -= entry.bytes
+		delete(c.entries, snippet)
+
+		snippet.Image.Deallocate()
+		snippet.Image = nil
+	}
+}
+
+// loadSnippetImage decodes the image of a snippet again after it was freed.
+// Authored snippets are read from the assets, synthesized snippets are painted from their rock mask.
+func loadSnippetImage(snippet *WorldSnippet) *ebiten.Image {
+	if snippet.Synthesized {
+		return ebiten.NewImageFromImage(renderRockMask(snippet.Mask))
+	}
+	return assets.LoadImage(SnippetImagePath(snippet.Filename))
+}
+
+// imageBytes returns the memory used by an RGBA image
+func imageBytes(image *ebiten.Image) int64 {
+	bounds := image.Bounds()
+	return int64(bounds.Dx()) * int64(bounds.Dy()) * 4
+}
@@ -0,0 +1,507 @@
+package worldgen
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"image"
+	"image/color"
+	stdmath "math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Grid layout of synthesized snippets
+const (
+	synthGridSize          = 50                       // Number of grid points per snippet side
+	synthGridSpacing       = CellSize / synthGridSize // Pixels between two grid points
+	synthFrameWidth        = 2                        // Grid points along the snippet edge that always stay rock
+	synthOpeningHalfWidth  = 100                      // Half width of a connector opening in pixels
+	synthTunnelRadius      = 4                        // Radius of the carved tunnels in grid points
+	synthTunnelWobble      = 6                        // Maximum sideways drift of a tunnel in grid points
+	synthRoomMinRadius     = 5                        // Minimum radius of the central room in grid points
+	synthRoomMaxRadius     = 8                        // Maximum radius of the central room in grid points
+	synthSnippetNamePrefix = "Synth_"                 // Filename prefix of synthesized snippets
+)
+
+// Colors used to paint synthesized snippet images.
+// They mirror the dark brown rock of the authored snippets, which fades to black
+// away from the cave walls.
+var (
+	synthRockEdgeColor = color.RGBA{50, 18, 0, 255} // Rock directly at a wall
+	synthRockDeepColor = color.RGBA{0, 0, 0, 255}   // Rock far away from any wall
+)
+
+// SynthesisConfig configures the procedural synthesis of world snippets.
+// Synthesized snippets are generated for every connector layout of the authored
+// snippets and are selected alongside them by weight.
+type SynthesisConfig struct {
+	VariantsPerLayout   int     `json:"variants_per_layout"`  // Synthesized snippets per connector layout (0 disables synthesis)
+	Weight              int     `json:"weight"`               // Selection weight of each synthesized snippet (authored snippets mostly use 10)
+	FillProbability     float64 `json:"fill_probability"`     // Initial probability of a grid point being rock (0.0-1.0)
+	SmoothingIterations int     `json:"smoothing_iterations"` // Number of cellular automaton passes
+}
+
+// RockMask is the rock layout of a synthesized snippet on a coarse grid.
+// Values range from 0 (air) to 1 (rock), and a point is rock from 0.5 upwards.
+// IsRock interpolates between grid points, so the cave outline is smooth at
+// pixel resolution.
+type RockMask struct {
+	Size   int       // Number of grid points per side
+	Values []float64 // Size*Size values, row by row
+}
+
+// IsRock reports whether the snippet pixel at (x, y) is rock.
+// Coordinates are in unrotated snippet space, from 0 to CellSize-1.
+func (m *RockMask) IsRock(x, y int) bool {
+	return m.sample(x, y) >= 0.5
+}
+
+// sample returns the bilinearly interpolated mask value at a snippet pixel
+func (m *RockMask) sample(x, y int) float64 {
+	spacing := float64(CellSize) / float64(m.Size)
+
+	// Grid coordinates of the pixel center, clamped to the grid
+	gx := clampFloat((float64(x)+0.5)/spacing-0.5, 0, float64(m.Size-1))
+	gy := clampFloat((float64(y)+0.5)/spacing-0.5, 0, float64(m.Size-1))
+
+	x0, y0 := int(gx), int(gy)
+	x1, y1 := minInt(x0+1, m.Size-1), minInt(y0+1, m.Size-1)
+	fx, fy := gx-float64(x0), gy-float64(y0)
+
+	top := m.Values[y0*m.Size+x0]*(1-fx) + m.Values[y0*m.Size+x1]*fx
+	bottom := m.Values[y1*m.Size+x0]*(1-fx) + m.Values[y1*m.Size+x1]*fx
+
+	return top*(1-fy) + bottom*fy
+}
+
+// SnippetSynthesizer creates cave snippets procedurally with a cellular automaton.
+// Every snippet has openings at the edge midpoints of its connectors, tunnels from
+// each opening to a central room, and solid rock along all other edges, so it fits
+// into the world exactly like an authored snippet with the same connectors.
+type SnippetSynthesizer struct {
+	config     SynthesisConfig
+	seed       int64
+	withImages bool
+}
+
+// NewSnippetSynthesizer creates a synthesizer.
+// If withImages is false, only the rock mask is generated, which is enough for
+// headless world generation and overview rendering.
+func NewSnippetSynthesizer(config SynthesisConfig, seed int64, withImages bool) *SnippetSynthesizer {
+	return &SnippetSynthesizer{
+		config:     config,
+		seed:       seed,
+		withImages: withImages,
+	}
+}
+
+// Synthesize creates one snippet with the given connectors.
+// The same connectors, variant and seed always produce the same snippet.
+func (s *SnippetSynthesizer) Synthesize(connectors []SnippetConnector, variant int) *WorldSnippet {
+	rng := rand.New(rand.NewSource(s.snippetSeed(connectors, variant)))
+
+	mask := s.generateMask(connectors, rng)
+
+	snippet := &WorldSnippet{
+		Filename:    fmt.Sprintf("%s%s_%d", synthSnippetNamePrefix, connectorLayoutName(connectors), variant),
+		Connectors:  append([]SnippetConnector(nil), connectors...),
+		Weight:      s.config.Weight,
+		Mask:        mask,
+		Synthesized: true,
+	}
+
+	if s.withImages {
+		img := renderRockMask(mask)
+		snippet.Image = ebiten.NewImageFromImage(img)
+		isRock := func(x, y int) bool {
+			return img.Pix[img.PixOffset(x, y)+3] > 0
+		}
+		snippet.Walls = detectWalls(CellSize, CellSize, isRock)
+		snippet.Outlines = traceOutlines(CellSize, CellSize, isRock)
+	}
+
+	return snippet
+}
+
+// snippetSeed derives the random seed of a single snippet from the synthesizer seed
+func (s *SnippetSynthesizer) snippetSeed(connectors []SnippetConnector, variant int) int64 {
+	seed := uint64(s.seed)
+	for _, conn := range connectors {
+		seed = seed*31 + uint64(conn) + 1
+	}
+	seed = seed*31 + uint64(variant)
+
+	// Mix the bits so neighbouring variants get unrelated sequences (splitmix64 finalizer)
+	seed ^= seed >> 30
+	seed *= 0xbf58476d1ce4e5b9
+	seed ^= seed >> 27
+	seed *= 0x94d049bb133111eb
+	seed ^= seed >> 31
+
+	return int64(seed)
+}
+
+// generateMask runs the cellular automaton and returns the resulting rock mask
+func (s *SnippetSynthesizer) generateMask(connectors []SnippetConnector, rng *rand.Rand) *RockMask {
+	size := synthGridSize
+
+	// Cells that must stay air (openings, tunnels, room) or rock (frame)
+	forceAir := make([]bool, size*size)
+	forceRock := make([]bool, size*size)
+
+	// Solid frame along all edges
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if x < synthFrameWidth || y < synthFrameWidth || x >= size-synthFrameWidth || y >= size-synthFrameWidth {
+				forceRock[y*size+x] = true
+			}
+		}
+	}
+
+	// Central room
+	center := size / 2
+	roomRadius := synthRoomMinRadius + rng.Intn(synthRoomMaxRadius-synthRoomMinRadius+1)
+	carveDisk(forceAir, size, center, center, roomRadius)
+
+	// Openings and tunnels for every connector
+	for _, conn := range connectors {
+		s.carveTunnel(forceAir, forceRock, size, conn, rng)
+	}
+
+	// Random initial fill
+	rock := make([]bool, size*size)
+	for i := range rock {
+		rock[i] = rng.Float64() < s.config.FillProbability
+	}
+	applyForcedCells(rock, forceAir, forceRock)
+
+	// Smooth the noise into caves
+	for i := 0; i < s.config.SmoothingIterations; i++ {
+		rock = smoothCaveStep(rock, size)
+		applyForcedCells(rock, forceAir, forceRock)
+	}
+
+	// Fill air pockets that cannot be reached from the room
+	fillUnreachableAir(rock, size, center, center)
+
+	return &RockMask{
+		Size:   size,
+		Values: blurCaveGrid(rock, size),
+	}
+}
+
+// carveTunnel carves the opening of a connector and a wobbly tunnel from it to the room
+func (s *SnippetSynthesizer) carveTunnel(forceAir, forceRock []bool, size int, conn SnippetConnector, rng *rand.Rand) {
+	center := size / 2
+
+	// Opening on the edge: grid points whose pixel position lies within the opening
+	for i := 0; i < size; i++ {
+		pixel := i*synthGridSpacing + synthGridSpacing/2
+		if abs(pixel-CellSize/2) > synthOpeningHalfWidth {
+			continue
+		}
+		for depth := 0; depth < synthFrameWidth; depth++ {
+			x, y := connectorGridPoint(conn, size, depth, i)
+			forceRock[y*size+x] = false
+			forceAir[y*size+x] = true
+		}
+	}
+
+	// Walk from the edge to the center, drifting sideways
+	offset := 0
+	for depth := 0; depth <= center; depth++ {
+		// Keep the tunnel aligned with the opening near the edge and the room near the center
+		if depth > synthFrameWidth+2 && depth < center-synthRoomMinRadius {
+			offset += rng.Intn(3) - 1
+			if offset > synthTunnelWobble {
+				offset = synthTunnelWobble
+			} else if offset < -synthTunnelWobble {
+				offset = -synthTunnelWobble
+			}
+		} else if depth >= center-synthRoomMinRadius && offset != 0 {
+			if offset > 0 {
+				offset--
+			} else {
+				offset++
+			}
+		}
+
+		x, y := connectorGridPoint(conn, size, depth, center+offset)
+		carveDisk(forceAir, size, x, y, synthTunnelRadius)
+	}
+}
+
+// connectorGridPoint returns the grid point at the given depth from the edge of a
+// connector, and at the given position along that edge
+func connectorGridPoint(conn SnippetConnector, size, depth, along int) (int, int) {
+	switch conn {
+	case ConnectorTop:
+		return along, depth
+	case ConnectorRight:
+		return size - 1 - depth, along
+	case ConnectorBottom:
+		return along, size - 1 - depth
+	default: // ConnectorLeft
+		return depth, along
+	}
+}
+
+// carveDisk marks all grid points within radius of (cx, cy) as air.
+// The outermost row and column are never carved, so the frame stays intact
+// outside of the openings.
+func carveDisk(forceAir []bool, size, cx, cy, radius int) {
+	for y := cy - radius; y <= cy+radius; y++ {
+		for x := cx - radius; x <= cx+radius; x++ {
+			if x < synthFrameWidth || y < synthFrameWidth || x >= size-synthFrameWidth || y >= size-synthFrameWidth {
+				continue
+			}
+			dx, dy := x-cx, y-cy
+			if dx*dx+dy*dy <= radius*radius {
+				forceAir[y*size+x] = true
+			}
+		}
+	}
+}
+
+// applyForcedCells overrides the grid with the forced air and rock cells
+func applyForcedCells(rock, forceAir, forceRock []bool) {
+	for i := range rock {
+		if forceAir[i] {
+			rock[i] = false
+		} else if forceRock[i] {
+			rock[i] = true
+		}
+	}
+}
+
+// smoothCaveStep runs one cellular automaton pass.
+// A cell becomes rock if at least 5 of its 8 neighbours are rock, and stays rock
+// if at least 4 are. Neighbours outside the grid count as rock.
+func smoothCaveStep(rock []bool, size int) []bool {
+	next := make([]bool, len(rock))
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			neighbours := 0
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					if dx == 0 && dy == 0 {
+						continue
+					}
+					nx, ny := x+dx, y+dy
+					if nx < 0 || ny < 0 || nx >= size || ny >= size || rock[ny*size+nx] {
+						neighbours++
+					}
+				}
+			}
+
+			if rock[y*size+x] {
+				next[y*size+x] = neighbours >= 4
+			} else {
+				next[y*size+x] = neighbours >= 5
+			}
+		}
+	}
+
+	return next
+}
+
+// fillUnreachableAir turns every air cell that is not 4-connected to (startX, startY) into rock
+func fillUnreachableAir(rock []bool, size, startX, startY int) {
+	reached := make([]bool, len(rock))
+	queue := [][2]int{{startX, startY}}
+	reached[startY*size+startX] = true
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dir := range getDirections() {
+			nx, ny := current[0]+dir[0], current[1]+dir[1]
+			if nx < 0 || ny < 0 || nx >= size || ny >= size {
+				continue
+			}
+			idx := ny*size + nx
+			if rock[idx] || reached[idx] {
+				continue
+			}
+			reached[idx] = true
+			queue = append(queue, [2]int{nx, ny})
+		}
+	}
+
+	for i := range rock {
+		if !rock[i] && !reached[i] {
+			rock[i] = true
+		}
+	}
+}
+
+// blurCaveGrid converts the rock grid into mask values with a 3x3 box blur.
+// This rounds off the staircase outline of the grid and removes single rock
+// cells. Grid points outside the grid count as rock, like in smoothCaveStep.
+func blurCaveGrid(rock []bool, size int) []float64 {
+	values := make([]float64, size*size)
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			sum := 0.0
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					nx, ny := x+dx, y+dy
+					if nx < 0 || ny < 0 || nx >= size || ny >= size || rock[ny*size+nx] {
+						sum++
+					}
+				}
+			}
+			values[y*size+x] = sum / 9
+		}
+	}
+
+	return values
+}
+
+// renderRockMask paints a snippet image from a rock mask.
+// Air is transparent, and rock fades from brown at the walls to black.
+func renderRockMask(mask *RockMask) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, CellSize, CellSize))
+
+	for y := 0; y < CellSize; y++ {
+		for x := 0; x < CellSize; x++ {
+			value := mask.sample(x, y)
+			if value < 0.5 {
+				continue
+			}
+
+			// 0 at the wall, 1 deep inside the rock
+			depth := (value - 0.5) * 2
+			img.SetRGBA(x, y, color.RGBA{
+				R: lerpUint8(synthRockEdgeColor.R, synthRockDeepColor.R, depth),
+				G: lerpUint8(synthRockEdgeColor.G, synthRockDeepColor.G, depth),
+				B: lerpUint8(synthRockEdgeColor.B, synthRockDeepColor.B, depth),
+				A: 255,
+			})
+		}
+	}
+
+	return img
+}
+
+// connectorLayoutName names a connector layout like the authored snippet files:
+// o (top), r (right), u (bottom) and l (left), in the order of the connectors
+func connectorLayoutName(connectors []SnippetConnector) string {
+	var name strings.Builder
+	for _, conn := range connectors {
+		switch conn {
+		case ConnectorTop:
+			name.WriteString("o")
+		case ConnectorRight:
+			name.WriteString("r")
+		case ConnectorBottom:
+			name.WriteString("u")
+		case ConnectorLeft:
+			name.WriteString("l")
+		}
+	}
+	return name.String()
+}
+
+// connectorLayouts returns the distinct connector layouts of the authored snippets
+// in load order. Snippets without connectors (empty snippets) are skipped.
+func (r *SnippetRegistry) connectorLayouts() [][]SnippetConnector {
+	layouts := make([][]SnippetConnector, 0)
+	seen := make(map[string]bool)
+
+	for _, snippet := range r.Ordered {
+		if snippet.Synthesized || len(snippet.Connectors) == 0 {
+			continue
+		}
+
+		sorted := append([]SnippetConnector(nil), snippet.Connectors...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		key := fmt.Sprint(sorted)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		layouts = append(layouts, snippet.Connectors)
+	}
+
+	return layouts
+}
+
+// SetSynthesizedSnippets replaces all synthesized snippets in the registry with a
+// new set generated from the config and seed. A config with no variants only removes
+// the previously synthesized snippets.
+func (r *SnippetRegistry) SetSynthesizedSnippets(config SynthesisConfig, seed int64) {
+	r.removeSynthesizedSnippets()
+
+	if config.VariantsPerLayout <= 0 {
+		return
+	}
+
+	synthesizer := NewSnippetSynthesizer(config, seed, r.imagesLoaded)
+	for _, connectors := range r.connectorLayouts() {
+		for variant := 0; variant < config.VariantsPerLayout; variant++ {
+			r.addSnippet(synthesizer.Synthesize(connectors, variant))
+		}
+	}
+
+	r.synthesisConfig = config
+	r.synthesisSeed = seed
+}
+
+// removeSynthesizedSnippets removes all synthesized snippets from the registry
+func (r *SnippetRegistry) removeSynthesizedSnippets() {
+	ordered := make([]*WorldSnippet, 0, len(r.Ordered))
+	for _, snippet := range r.Ordered {
+		if snippet.Synthesized {
+			delete(r.Snippets, snippet.Filename)
+			continue
+		}
+		ordered = append(ordered, snippet)
+	}
+	r.Ordered = ordered
+
+	for conn, snippets := range r.ByConnector {
+		kept := make([]*WorldSnippet, 0, len(snippets))
+		for _, snippet := range snippets {
+			if !snippet.Synthesized {
+				kept = append(kept, snippet)
+			}
+		}
+		r.ByConnector[conn] = kept
+	}
+
+	r.synthesisConfig = SynthesisConfig{}
+	r.synthesisSeed = 0
+}
+
+// ensureSynthesizedSnippets synthesizes snippets for a world generation run,
+// reusing the current set if it was created with the same config and seed
+func (r *SnippetRegistry) ensureSynthesizedSnippets(config SynthesisConfig, seed int64) {
+	if config == r.synthesisConfig && seed == r.synthesisSeed {
+		return
+	}
+	r.SetSynthesizedSnippets(config, seed)
+}
+
+// clampFloat limits a value to the range [min, max]
+func clampFloat(value, min, max float64) float64 {
+	return stdmath.Max(min, stdmath.Min(max, value))
+}
+
+// minInt returns the smaller of two integers
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// lerpUint8 interpolates linearly between two color channels
+func lerpUint8(from, to uint8, t float64) uint8 {
+	t = clampFloat(t, 0, 1)
+	return uint8(float64(from) + (float64(to)-float64(from))*t + 0.5)
+}
@@ -0,0 +1,99 @@
+package worldgen
+
+import (
+	"testing"
+)
+
+// edgePixel returns the snippet pixel on the edge of a connector at a distance along that edge
+func edgePixel(conn SnippetConnector, along int) (int, int) {
+	switch conn {
+	case ConnectorTop:
+		return along, 0
+	case ConnectorRight:
+		return CellSize - 1, along
+	case ConnectorBottom:
+		return along, CellSize - 1
+	default: // ConnectorLeft
+		return 0, along
+	}
+}
+
+// TestSynthesizeIsDeterministic tests that the same seed, connectors and variant produce the same snippet
+func TestSynthesizeIsDeterministic(t *testing.T) {
+	config := SynthesisConfig{VariantsPerLayout: 2, Weight: 5, FillProbability: 0.55, SmoothingIterations: 4}
+	connectors := []SnippetConnector{ConnectorTop, ConnectorLeft}
+
+	first := NewSnippetSynthesizer(config, 42, false).Synthesize(connectors, 1)
+	again := NewSnippetSynthesizer(config, 42, false).Synthesize(connectors, 1)
+	if first.Filename != again.Filename {
+		t.Errorf("Expected the same name, got %s and %s", first.Filename, again.Filename)
+	}
+	for i := range first.Mask.Values {
+		if first.Mask.Values[i] != again.Mask.Values[i] {
+			t.Fatalf("Expected identical masks, first difference at grid point %d", i)
+		}
+	}
+
+	differs := func(other *WorldSnippet) bool {
+		for i := range first.Mask.Values {
+			if first.Mask.Values[i] != other.Mask.Values[i] {
+				return true
+			}
+		}
+		return false
+	}
+	if !differs(NewSnippetSynthesizer(config, 43, false).Synthesize(connectors, 1)) {
+		t.Error("Expected another seed to produce another snippet")
+	}
+	if !differs(NewSnippetSynthesizer(config, 42, false).Synthesize(connectors, 2)) {
+		t.Error("Expected another variant to produce another snippet")
+	}
+}
+
+// TestSynthesizeOpenings tests that every snippet is open at the edge midpoints of its connectors
+// and closed along all other edges, so it tiles with its neighbours like an authored snippet
+func TestSynthesizeOpenings(t *testing.T) {
+	config := SynthesisConfig{VariantsPerLayout: 1, Weight: 5, FillProbability: 0.55, SmoothingIterations: 4}
+	all := []SnippetConnector{ConnectorTop, ConnectorRight, ConnectorBottom, ConnectorLeft}
+
+	for _, connectors := range [][]SnippetConnector{
+		{ConnectorTop},
+		{ConnectorRight, ConnectorLeft},
+		{ConnectorTop, ConnectorRight, ConnectorBottom},
+		all,
+	} {
+		t.Run(connectorLayoutName(connectors), func(t *testing.T) {
+			for variant := 0; variant < 3; variant++ {
+				snippet := NewSnippetSynthesizer(config, int64(variant)*7919, false).Synthesize(connectors, variant)
+				if len(snippet.Connectors) != len(connectors) {
+					t.Fatalf("Expected the requested connectors %v, got %v", connectors, snippet.Connectors)
+				}
+
+				open := make(map[SnippetConnector]bool)
+				for _, conn := range connectors {
+					open[conn] = true
+				}
+
+				// The middle of the opening is air on the edge itself; other edges are rock along their length
+				inner := synthOpeningHalfWidth - synthGridSpacing
+				for _, conn := range all {
+					for along := 0; along < CellSize; along += synthGridSpacing / 2 {
+						x, y := edgePixel(conn, along)
+						inOpening := abs(along-CellSize/2) <= inner
+						switch {
+						case open[conn] && inOpening && snippet.Mask.IsRock(x, y):
+							t.Fatalf("Variant %d: expected the opening of connector %d to be air at (%d, %d)", variant, conn, x, y)
+						case !open[conn] && !snippet.Mask.IsRock(x, y):
+							t.Fatalf("Variant %d: expected the edge without connector %d to be rock at (%d, %d)", variant, conn, x, y)
+						}
+					}
+				}
+
+				// Each opening leads into the cave: the room in the center is air
+				if snippet.Mask.IsRock(CellSize/2, CellSize/2) {
+					t.Errorf("Variant %d: expected the room in the center to be air", variant)
+				}
+			}
+		})
+	}
+}
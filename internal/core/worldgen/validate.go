@@ -0,0 +1,256 @@
+package worldgen
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Invariant names a property that every generated world must have.
+// The checks in ValidateWorldMap only look at the finished WorldMap and share no
+// code with the generator, so a generator bug cannot hide its own violations.
+type Invariant string
+
+const (
+	InvariantNoGaps          Invariant = "no-gaps"          // Requirement 1: open cells are surrounded by cells on all four sides
+	InvariantConnectorsMatch Invariant = "connectors-match" // Requirement 2: every connector faces a matching connector
+	InvariantMainPathLoop    Invariant = "main-path-loop"   // Requirement 3: the main path is a closed loop of connected cells
+	InvariantMainPathLength  Invariant = "main-path-length" // The main path length is within the configured bounds
+	InvariantReachable       Invariant = "reachable"        // Requirement 5: every open cell can be reached from the main path
+	InvariantBranchEnds      Invariant = "branch-ends"      // Requirement 6: dead-end snippets are only used off the main path
+	InvariantEmptySides      Invariant = "empty-sides"      // Requirement 7: sides without a connector face an empty snippet
+	InvariantEmptyNeighbours Invariant = "empty-neighbours" // Requirement 8: empty snippets have at least 2 empty neighbours
+)
+
+// Invariants lists all invariants in the order they are checked
+var Invariants = []Invariant{
+	InvariantNoGaps,
+	InvariantConnectorsMatch,
+	InvariantMainPathLoop,
+	InvariantMainPathLength,
+	InvariantReachable,
+	InvariantBranchEnds,
+	InvariantEmptySides,
+	InvariantEmptyNeighbours,
+}
+
+// Violation describes a single broken invariant
+type Violation struct {
+	Invariant Invariant `json:"invariant"`
+	X         int       `json:"x"` // Cell coordinates where the violation was found
+	Y         int       `json:"y"`
+	Message   string    `json:"message"`
+}
+
+// String returns a readable description of the violation
+func (v Violation) String() string {
+	return fmt.Sprintf("%s at %d,%d: %s", v.Invariant, v.X, v.Y, v.Message)
+}
+
+// validationDirections lists the four neighbours of a cell and the connector facing each of them
+var validationDirections = []struct {
+	dx, dy    int
+	connector SnippetConnector
+}{
+	{0, -1, ConnectorTop},
+	{1, 0, ConnectorRight},
+	{0, 1, ConnectorBottom},
+	{-1, 0, ConnectorLeft},
+}
+
+// ValidateWorldMap checks a generated world against all invariants and returns the
+// violations sorted by invariant and position. The config is only used for the
+// main path length bounds; it may be nil to skip that check.
+func ValidateWorldMap(worldMap *WorldMap, config *WorldGenConfig) []Violation {
+	v := &worldValidator{worldMap: worldMap}
+
+	cells := sortedCells(worldMap)
+	for _, cell := range cells {
+		v.checkCell(cell)
+	}
+	v.checkMainPath(config)
+	v.checkReachable(cells)
+
+	order := make(map[Invariant]int, len(Invariants))
+	for i, invariant := range Invariants {
+		order[invariant] = i
+	}
+	sort.SliceStable(v.violations, func(i, j int) bool {
+		return order[v.violations[i].Invariant] < order[v.violations[j].Invariant]
+	})
+
+	return v.violations
+}
+
+// worldValidator collects the violations of a single world
+type worldValidator struct {
+	worldMap   *WorldMap
+	violations []Violation
+}
+
+// report records a violation
+func (v *worldValidator) report(invariant Invariant, x, y int, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{
+		Invariant: invariant,
+		X:         x,
+		Y:         y,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+// checkCell checks the invariants that only depend on a cell and its neighbours
+func (v *worldValidator) checkCell(cell *WorldCell) {
+	if cell.Snippet == nil {
+		v.report(InvariantNoGaps, cell.X, cell.Y, "cell has no snippet")
+		return
+	}
+
+	connectors := connectorSet(cell)
+
+	// Empty snippets need at least 2 empty neighbours
+	if len(connectors) == 0 {
+		emptyNeighbours := 0
+		for _, dir := range validationDirections {
+			if neighbour := v.worldMap.GetCell(cell.X+dir.dx, cell.Y+dir.dy); isEmptyCell(neighbour) {
+				emptyNeighbours++
+			}
+		}
+		if emptyNeighbours < 2 {
+			v.report(InvariantEmptyNeighbours, cell.X, cell.Y, "empty snippet has %d empty neighbours", emptyNeighbours)
+		}
+		return
+	}
+
+	if cell.IsMainPath && len(connectors) == 1 {
+		v.report(InvariantBranchEnds, cell.X, cell.Y, "dead-end snippet %s on the main path", cell.Snippet.Filename)
+	}
+
+	for _, dir := range validationDirections {
+		neighbour := v.worldMap.GetCell(cell.X+dir.dx, cell.Y+dir.dy)
+		if neighbour == nil || neighbour.Snippet == nil {
+			v.report(InvariantNoGaps, cell.X, cell.Y, "no cell on side %d", dir.connector)
+			continue
+		}
+
+		opposite := (dir.connector + 180) % 360
+		if connectors[dir.connector] {
+			if !connectorSet(neighbour)[opposite] {
+				v.report(InvariantConnectorsMatch, cell.X, cell.Y, "connector %d faces %s without connector %d",
+					dir.connector, neighbour.Snippet.Filename, opposite)
+			}
+		} else if !isEmptyCell(neighbour) {
+			v.report(InvariantEmptySides, cell.X, cell.Y, "side %d without connector faces %s",
+				dir.connector, neighbour.Snippet.Filename)
+		}
+	}
+}
+
+// checkMainPath checks that the main path cells form a closed loop in path order
+func (v *worldValidator) checkMainPath(config *WorldGenConfig) {
+	path := v.worldMap.MainPathCells
+	if len(path) == 0 {
+		v.report(InvariantMainPathLoop, 0, 0, "world has no main path")
+		return
+	}
+
+	if config != nil && (len(path) < config.MainPathMinLength || len(path) > config.MainPathMaxLength) {
+		v.report(InvariantMainPathLength, path[0].X, path[0].Y, "main path has %d cells, expected %d to %d",
+			len(path), config.MainPathMinLength, config.MainPathMaxLength)
+	}
+
+	seen := make(map[[2]int]bool, len(path))
+	for i, cell := range path {
+		position := [2]int{cell.X, cell.Y}
+		if seen[position] {
+			v.report(InvariantMainPathLoop, cell.X, cell.Y, "main path visits the cell twice")
+		}
+		seen[position] = true
+
+		if v.worldMap.GetCell(cell.X, cell.Y) != cell {
+			v.report(InvariantMainPathLoop, cell.X, cell.Y, "main path cell is not part of the map")
+			continue
+		}
+		if !cell.IsMainPath {
+			v.report(InvariantMainPathLoop, cell.X, cell.Y, "main path cell is not marked as main path")
+		}
+
+		next := path[(i+1)%len(path)]
+		if !cellsConnected(cell, next) {
+			v.report(InvariantMainPathLoop, cell.X, cell.Y, "not connected to the next main path cell at %d,%d", next.X, next.Y)
+		}
+	}
+}
+
+// checkReachable checks that every open cell can be reached from the main path
+// by moving through pairs of matching connectors
+func (v *worldValidator) checkReachable(cells []*WorldCell) {
+	visited := make(map[*WorldCell]bool, len(cells))
+	queue := make([]*WorldCell, 0, len(cells))
+	for _, cell := range v.worldMap.MainPathCells {
+		if v.worldMap.GetCell(cell.X, cell.Y) == cell && !visited[cell] {
+			visited[cell] = true
+			queue = append(queue, cell)
+		}
+	}
+
+	for len(queue) > 0 {
+		cell := queue[0]
+		queue = queue[1:]
+
+		for _, dir := range validationDirections {
+			neighbour := v.worldMap.GetCell(cell.X+dir.dx, cell.Y+dir.dy)
+			if neighbour == nil || visited[neighbour] || !cellsConnected(cell, neighbour) {
+				continue
+			}
+			visited[neighbour] = true
+			queue = append(queue, neighbour)
+		}
+	}
+
+	for _, cell := range cells {
+		if !isEmptyCell(cell) && !visited[cell] {
+			v.report(InvariantReachable, cell.X, cell.Y, "%s is not reachable from the main path", cell.Snippet.Filename)
+		}
+	}
+}
+
+// connectorSet returns the rotated connectors of a cell as a set
+func connectorSet(cell *WorldCell) map[SnippetConnector]bool {
+	set := make(map[SnippetConnector]bool, 4)
+	for _, conn := range cell.Snippet.Connectors {
+		set[(conn+SnippetConnector(cell.Rotation))%360] = true
+	}
+	return set
+}
+
+// cellsConnected reports whether two adjacent cells are joined by a pair of matching connectors
+func cellsConnected(a, b *WorldCell) bool {
+	if a.Snippet == nil || b.Snippet == nil {
+		return false
+	}
+	for _, dir := range validationDirections {
+		if a.X+dir.dx == b.X && a.Y+dir.dy == b.Y {
+			return connectorSet(a)[dir.connector] && connectorSet(b)[(dir.connector+180)%360]
+		}
+	}
+	return false
+}
+
+// isEmptyCell reports whether the cell holds a snippet without connectors
+func isEmptyCell(cell *WorldCell) bool {
+	return cell != nil && cell.Snippet != nil && len(cell.Snippet.Connectors) == 0
+}
+
+// sortedCells returns the cells of the map sorted by row and column
+func sortedCells(worldMap *WorldMap) []*WorldCell {
+	cells := make([]*WorldCell, 0, len(worldMap.Cells))
+	for _, cell := range worldMap.Cells {
+		cells = append(cells, cell)
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Y != cells[j].Y {
+			return cells[i].Y < cells[j].Y
+		}
+		return cells[i].X < cells[j].X
+	})
+	return cells
+}
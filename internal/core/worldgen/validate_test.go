@@ -0,0 +1,97 @@
+package worldgen
+
+import (
+	"testing"
+)
+
+// newLoopWorld builds a 2x2 main path loop surrounded by a 2 cell wide border of empty snippets
+func newLoopWorld() *WorldMap {
+	corner := &WorldSnippet{Filename: "corner.png", Connectors: []SnippetConnector{ConnectorLeft, ConnectorBottom}}
+	empty := &WorldSnippet{Filename: "empty.png"}
+
+	worldMap := NewWorldMap()
+	worldMap.AddCell(&WorldCell{X: 0, Y: 0, Snippet: corner, Rotation: 270, IsMainPath: true})
+	worldMap.AddCell(&WorldCell{X: 1, Y: 0, Snippet: corner, Rotation: 0, IsMainPath: true})
+	worldMap.AddCell(&WorldCell{X: 1, Y: 1, Snippet: corner, Rotation: 90, IsMainPath: true})
+	worldMap.AddCell(&WorldCell{X: 0, Y: 1, Snippet: corner, Rotation: 180, IsMainPath: true})
+
+	for y := -2; y <= 3; y++ {
+		for x := -2; x <= 3; x++ {
+			if !worldMap.HasCell(x, y) {
+				worldMap.AddCell(&WorldCell{X: x, Y: y, Snippet: empty})
+			}
+		}
+	}
+	return worldMap
+}
+
+// TestValidateWorldMapAcceptsValidWorld tests that a well-formed world has no violations
+func TestValidateWorldMapAcceptsValidWorld(t *testing.T) {
+	config := &WorldGenConfig{MainPathMinLength: 4, MainPathMaxLength: 4}
+
+	if violations := ValidateWorldMap(newLoopWorld(), config); len(violations) != 0 {
+		t.Fatalf("Expected no violations, got %v", violations)
+	}
+}
+
+// TestValidateWorldMapReportsViolations tests that broken worlds are reported with the right invariant
+func TestValidateWorldMapReportsViolations(t *testing.T) {
+	testCases := []struct {
+		name      string
+		modify    func(worldMap *WorldMap)
+		invariant Invariant
+	}{
+		{
+			name:      "Rotated corner",
+			modify:    func(worldMap *WorldMap) { worldMap.GetCell(1, 1).Rotation = 0 },
+			invariant: InvariantConnectorsMatch,
+		},
+		{
+			name:      "Missing border",
+			modify:    func(worldMap *WorldMap) { delete(worldMap.Cells, CellCoord{X: -1, Y: 0}) },
+			invariant: InvariantNoGaps,
+		},
+		{
+			name: "Broken loop order",
+			modify: func(worldMap *WorldMap) {
+				worldMap.MainPathCells[1], worldMap.MainPathCells[2] = worldMap.MainPathCells[2], worldMap.MainPathCells[1]
+			},
+			invariant: InvariantMainPathLoop,
+		},
+		{
+			name: "Open cell next to a wall",
+			modify: func(worldMap *WorldMap) {
+				worldMap.GetCell(-1, 0).Snippet = &WorldSnippet{Filename: "dead-end.png", Connectors: []SnippetConnector{ConnectorTop}}
+			},
+			invariant: InvariantEmptySides,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			worldMap := newLoopWorld()
+			tc.modify(worldMap)
+
+			for _, violation := range ValidateWorldMap(worldMap, nil) {
+				if violation.Invariant == tc.invariant {
+					return
+				}
+			}
+			t.Errorf("Expected a %s violation", tc.invariant)
+		})
+	}
+}
+
+// TestComputeWorldStats tests counting branches and dead-ends
+func TestComputeWorldStats(t *testing.T) {
+	worldMap := newLoopWorld()
+	worldMap.Cells[CellCoord{X: -1, Y: 0}] = &WorldCell{X: -1, Y: 0, Snippet: &WorldSnippet{Filename: "dead-end.png", Connectors: []SnippetConnector{ConnectorRight}}}
+
+	stats := ComputeWorldStats(worldMap)
+	if stats.MainPathLength != 4 || stats.Branches != 1 || stats.DeadEndBranches != 1 || stats.DeadEnds != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if stats.SnippetUsage["corner.png"] != 4 {
+		t.Errorf("Expected 4 corner cells, got %d", stats.SnippetUsage["corner.png"])
+	}
+}
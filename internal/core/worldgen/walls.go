@@ -13,17 +13,34 @@ type WallPoint struct {
 	Normal math.Vector // Normal vector of the wall (points away from the rock)
 }
 
+// snippetWalls holds the walls detected in a snippet image
+type snippetWalls struct {
+	points   []WallPoint   // Wall points between rock and air pixels
+	outlines []WallOutline // Simplified wall outlines
+}
+
 // wallCache stores detected walls for snippets to avoid reprocessing unchanged snippets
 var wallCache = struct {
 	sync.RWMutex
-	data map[string][]WallPoint
+	data map[string]snippetWalls
 }{
-	data: make(map[string][]WallPoint),
+	data: make(map[string]snippetWalls),
 }
 
 // DetectWallsInSnippet detects all wall points within a snippet
 // If the snippet hasn't changed (based on filename), it returns cached wall data
 func DetectWallsInSnippet(snippet *WorldSnippet) []WallPoint {
+	return detectSnippetWalls(snippet).points
+}
+
+// DetectWallOutlinesInSnippet detects the simplified wall outlines of a snippet
+// If the snippet hasn't changed (based on filename), it returns cached wall data
+func DetectWallOutlinesInSnippet(snippet *WorldSnippet) []WallOutline {
+	return detectSnippetWalls(snippet).outlines
+}
+
+// detectSnippetWalls detects the wall points and outlines of a snippet image, or returns them from the cache
+func detectSnippetWalls(snippet *WorldSnippet) snippetWalls {
 	// Check if we have this snippet's walls in the cache
 	wallCache.RLock()
 	cachedWalls, exists := wallCache.data[snippet.Filename]
@@ -35,8 +52,6 @@ func DetectWallsInSnippet(snippet *WorldSnippet) []WallPoint {
 	}
 
 	// Otherwise, detect walls
-	walls := []WallPoint{}
-
 	// Get image dimensions
 	width, height := snippet.Image.Bounds().Dx(), snippet.Image.Bounds().Dy()
 
@@ -49,6 +64,29 @@ func DetectWallsInSnippet(snippet *WorldSnippet) []WallPoint {
 	imgData := make([]byte, width*height*4)
 	rgba.ReadPixels(imgData)
 
+	// A pixel is rock if it is not transparent (alpha is the 4th byte in RGBA format)
+	isRock := func(x, y int) bool {
+		return imgData[(y*width+x)*4+3] > 0
+	}
+	walls := snippetWalls{
+		points:   detectWalls(width, height, isRock),
+		outlines: traceOutlines(width, height, isRock),
+	}
+
+	// Store the detected walls in the cache
+	wallCache.Lock()
+	wallCache.data[snippet.Filename] = walls
+	wallCache.Unlock()
+
+	return walls
+}
+
+// detectWalls finds all wall points in a width x height area.
+// A wall point lies between a rock pixel and a neighbouring air pixel, and its
+// normal points from the rock into the air.
+func detectWalls(width, height int, isRock func(x, y int) bool) []WallPoint {
+	walls := []WallPoint{}
+
 	// Directions for neighboring pixels (top, right, bottom, left)
 	directions := [][2]int{
 		{0, -1}, // Top
@@ -60,52 +98,32 @@ func DetectWallsInSnippet(snippet *WorldSnippet) []WallPoint {
 	// Check each pixel
 	for y := 0; y < height; y++ {
 		for x := 0; x < width; x++ {
-			// Get alpha value of current pixel (4th byte in RGBA format)
-			idx := (y*width + x) * 4
-			alpha := imgData[idx+3]
-
-			// If pixel is not transparent (rock)
-			if alpha > 0 {
-				// Check all neighboring pixels
-				for _, dir := range directions {
-					nx, ny := x+dir[0], y+dir[1]
-
-					// Check if neighbor is within image bounds
-					if nx >= 0 && nx < width && ny >= 0 && ny < height {
-						// Get alpha value of neighboring pixel
-						nIdx := (ny*width + nx) * 4
-						nAlpha := imgData[nIdx+3]
-
-						// If neighbor is transparent, we found a wall
-						if nAlpha == 0 {
-							// Calculate wall position (between the two pixels)
-							wallX := float64(x) + float64(dir[0])*0.5
-							wallY := float64(y) + float64(dir[1])*0.5
-
-							// Calculate normal vector (points away from rock)
-							normal := math.Vector{
-								X: float64(dir[0]),
-								Y: float64(dir[1]),
-							}
-
-							// Add wall point
-							walls = append(walls, WallPoint{
-								X:      wallX,
-								Y:      wallY,
-								Normal: normal,
-							})
-						}
-					}
+			if !isRock(x, y) {
+				continue
+			}
+
+			// Check all neighboring pixels
+			for _, dir := range directions {
+				nx, ny := x+dir[0], y+dir[1]
+
+				// Neighbors outside the area are ignored, and rock neighbors are no wall
+				if nx < 0 || nx >= width || ny < 0 || ny >= height || isRock(nx, ny) {
+					continue
 				}
+
+				// The wall lies between the two pixels, and its normal points away from the rock
+				walls = append(walls, WallPoint{
+					X: float64(x) + float64(dir[0])*0.5,
+					Y: float64(y) + float64(dir[1])*0.5,
+					Normal: math.Vector{
+						X: float64(dir[0]),
+						Y: float64(dir[1]),
+					},
+				})
 			}
- 	}
+		}
 	}
 
-	// Store the detected walls in the cache
-	wallCache.Lock()
-	wallCache.data[snippet.Filename] = walls
-	wallCache.Unlock()
-
 	return walls
 }
 
@@ -23,6 +23,7 @@
 package worldgen
 
 import (
+	"discoveryx/internal/constants"
 	"discoveryx/internal/core/ecs"
 	"fmt"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -32,7 +33,7 @@ import (
 // This constant determines the granularity of the world grid.
 // Larger values create bigger cells, reducing the number of cells needed
 // to represent the world but potentially reducing detail.
-const CellSize = 1000
+const CellSize = constants.WorldCellSize
 
 // VisibilityRadius defines how many chunks around the player should be visible.
 // This constant controls the draw distance and memory usage:
@@ -40,7 +41,18 @@ const CellSize = 1000
 // - Lower values improve performance but limit visibility
 // The value represents the number of chunks in each direction (a radius),
 // so the actual visible area is a square with sides of (2*VisibilityRadius+1) chunks.
-const VisibilityRadius = 4
+const VisibilityRadius = constants.WorldVisibilityRadius
+
+// FloorDiv divides a by b and rounds towards negative infinity.
+// World, cell and chunk coordinates can be negative, and plain integer division
+// would map e.g. cells -3..3 all to chunk 0. FloorDiv maps cells -4..-1 to chunk -1.
+func FloorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
 
 // GeneratedWorld implements the ecs.World interface and provides access to the generated world map.
 // It serves as the central manager for the procedurally generated game world,
@@ -49,8 +61,8 @@ const VisibilityRadius = 4
 //
 // The GeneratedWorld uses a multi-level coordinate system:
 // - World coordinates: Raw pixel positions in the game world
-// - Cell coordinates: World coordinates divided by CellSize
-// - Chunk coordinates: Cell coordinates divided by ChunkSize
+// - Cell coordinates: World coordinates divided by CellSize (rounded down, see FloorDiv)
+// - Chunk coordinates: Cell coordinates divided by ChunkSize (rounded down, see FloorDiv)
 // - Local coordinates: Cell positions within a specific chunk
 //
 // This chunking approach optimizes memory usage and rendering performance
@@ -61,11 +73,20 @@ type GeneratedWorld struct {
 	height      int
 	matchScreen bool
 	worldMap    *WorldMap
-	chunks      map[string]*WorldChunk
+	chunks      map[ChunkCoord]*WorldChunk
+	loaded      []*WorldChunk // Chunks that are currently loaded
 	generator   *WorldGenerator
 	config      *WorldGenConfig
 	playerX     float64
 	playerY     float64
+
+	// Endless growth of infinite worlds, nil for finite worlds
+	frontier *FrontierGrower
+
+	// Chunk streaming (see EnableStreaming)
+	listeners []ChunkListener    // Notified when chunks are loaded and unloaded
+	streaming StreamingConfig    // Streaming settings, zero until streaming is enabled
+	images    *snippetImageCache // Reference counted snippet images, nil until streaming is enabled
 }
 
 // NewGeneratedWorld creates a new generated world with the specified dimensions.
@@ -94,7 +115,7 @@ func NewGeneratedWorld(width, height int, generator *WorldGenerator, config *Wor
 		matchScreen: false,
 		generator:   generator,
 		config:      config,
-		chunks:      make(map[string]*WorldChunk),
+		chunks:      make(map[ChunkCoord]*WorldChunk),
 		playerX:     0,
 		playerY:     0,
 	}
@@ -104,6 +125,13 @@ func NewGeneratedWorld(width, height int, generator *WorldGenerator, config *Wor
 		return nil, err
 	}
 
+	if config.Infinite {
+		world.frontier, err = NewFrontierGrower(generator, config, world.worldMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare infinite world: %w", err)
+		}
+	}
+
 	// Set player position to the middle of the main path
 	if len(world.worldMap.MainPathCells) > 0 {
 		mainPathCell := world.worldMap.MainPathCells[len(world.worldMap.MainPathCells)/2]
@@ -129,43 +157,61 @@ func (w *GeneratedWorld) GenerateNewWorld() error {
 
 // organizeChunks groups cells into manageable chunks for efficient rendering
 func (w *GeneratedWorld) organizeChunks() {
-	w.chunks = make(map[string]*WorldChunk)
+	w.chunks = make(map[ChunkCoord]*WorldChunk)
+	w.loaded = w.loaded[:0]
 
 	for _, cell := range w.worldMap.Cells {
-		chunkX := cell.X / ChunkSize
-		chunkY := cell.Y / ChunkSize
-
-		chunkKey := getChunkKey(chunkX, chunkY)
-		chunk, exists := w.chunks[chunkKey]
-		if !exists {
-			chunk = NewWorldChunk(chunkX, chunkY)
-			w.chunks[chunkKey] = chunk
-		}
+		w.addCellToChunk(cell)
+	}
+}
+
+// addCellToChunk adds a cell to the chunk that contains it, creating the chunk if needed
+func (w *GeneratedWorld) addCellToChunk(cell *WorldCell) {
+	chunkX := FloorDiv(cell.X, ChunkSize)
+	chunkY := FloorDiv(cell.Y, ChunkSize)
 
-		chunk.AddCell(cell)
+	chunkKey := ChunkCoord{X: chunkX, Y: chunkY}
+	chunk, exists := w.chunks[chunkKey]
+	if !exists {
+		chunk = NewWorldChunk(chunkX, chunkY)
+		w.chunks[chunkKey] = chunk
 	}
+
+	chunk.AddCell(cell)
 }
 
-// getChunkKey returns a unique string key for a chunk based on its coordinates
-func getChunkKey(x, y int) string {
-	return fmt.Sprintf("%d,%d", x, y)
+// growFrontier grows an infinite world far enough that every chunk within the
+// visibility radius of the player chunk exists
+func (w *GeneratedWorld) growFrontier(playerChunkX, playerChunkY int) {
+	if w.frontier == nil {
+		return
+	}
+
+	cells := w.frontier.GrowArea(
+		(playerChunkX-VisibilityRadius)*ChunkSize,
+		(playerChunkY-VisibilityRadius)*ChunkSize,
+		(playerChunkX+VisibilityRadius+1)*ChunkSize-1,
+		(playerChunkY+VisibilityRadius+1)*ChunkSize-1,
+	)
+	for _, cell := range cells {
+		w.addCellToChunk(cell)
+	}
 }
 
 // GetChunk returns the chunk at the specified chunk coordinates, or nil if no chunk exists there
 func (w *GeneratedWorld) GetChunk(chunkX, chunkY int) *WorldChunk {
-	key := getChunkKey(chunkX, chunkY)
-	return w.chunks[key]
+	return w.chunks[ChunkCoord{X: chunkX, Y: chunkY}]
 }
 
 // GetChunkAt returns the chunk that contains the specified world coordinates, or nil if no chunk exists there
 func (w *GeneratedWorld) GetChunkAt(worldX, worldY int) *WorldChunk {
 	// Convert world coordinates to cell coordinates
-	cellX := worldX / CellSize
-	cellY := worldY / CellSize
+	cellX := FloorDiv(worldX, CellSize)
+	cellY := FloorDiv(worldY, CellSize)
 
 	// Convert cell coordinates to chunk coordinates
-	chunkX := cellX / ChunkSize
-	chunkY := cellY / ChunkSize
+	chunkX := FloorDiv(cellX, ChunkSize)
+	chunkY := FloorDiv(cellY, ChunkSize)
 
 	return w.GetChunk(chunkX, chunkY)
 }
@@ -173,32 +219,51 @@ func (w *GeneratedWorld) GetChunkAt(worldX, worldY int) *WorldChunk {
 // UpdateVisibleChunks loads chunks within the visibility radius of the player.
 // This method is a core part of the dynamic loading system that optimizes
 // memory usage and rendering performance by:
-// 1. Unloading all currently loaded chunks
+// 1. Unloading chunks beyond the visibility radius plus the unload margin
 // 2. Calculating which chunks are within the visibility radius of the player
-// 3. Loading only those chunks that should be visible
+// 3. Loading those chunks that are not loaded yet
 //
 // This approach ensures that:
 // - Only relevant parts of the world consume memory
 // - Rendering is focused on visible areas
 // - The game can support very large worlds without performance issues
+// - Moving back and forth over a chunk border does not reload chunks every frame
+//
+// In infinite worlds, the world is grown first so that every chunk in the
+// visibility radius exists.
+//
+// Chunk listeners are notified about every chunk that is loaded or unloaded.
 //
 // This method is automatically called when the player's position changes
 // and should be called manually if the visibility radius is modified.
 func (w *GeneratedWorld) UpdateVisibleChunks() {
-	playerCellX := int(w.playerX) / CellSize
-	playerCellY := int(w.playerY) / CellSize
-	playerChunkX := playerCellX / ChunkSize
-	playerChunkY := playerCellY / ChunkSize
-
-	for _, chunk := range w.chunks {
-		chunk.Unload()
+	playerCellX := FloorDiv(int(w.playerX), CellSize)
+	playerCellY := FloorDiv(int(w.playerY), CellSize)
+	playerChunkX := FloorDiv(playerCellX, ChunkSize)
+	playerChunkY := FloorDiv(playerCellY, ChunkSize)
+
+	unloadRadius := VisibilityRadius + w.streaming.UnloadMargin
+	kept := w.loaded[:0]
+	for _, chunk := range w.loaded {
+		if abs(chunk.X-playerChunkX) > unloadRadius || abs(chunk.Y-playerChunkY) > unloadRadius {
+			w.unloadChunk(chunk)
+			continue
+		}
+		kept = append(kept, chunk)
+	}
+	// Clear the tail so unloaded chunks are not referenced by the slice
+	for i := len(kept); i < len(w.loaded); i++ {
+		w.loaded[i] = nil
 	}
+	w.loaded = kept
+
+	w.growFrontier(playerChunkX, playerChunkY)
 
 	for y := playerChunkY - VisibilityRadius; y <= playerChunkY+VisibilityRadius; y++ {
 		for x := playerChunkX - VisibilityRadius; x <= playerChunkX+VisibilityRadius; x++ {
 			chunk := w.GetChunk(x, y)
-			if chunk != nil {
-				chunk.Load()
+			if chunk != nil && !chunk.IsLoaded {
+				w.loadChunk(chunk)
 			}
 		}
 	}
@@ -270,12 +335,12 @@ func (w *GeneratedWorld) SetMatchScreen(match bool) {
 // all cells sequentially.
 func (w *GeneratedWorld) GetCellAt(worldX, worldY int) *WorldCell {
 	// Convert world coordinates to cell coordinates
-	cellX := worldX / CellSize
-	cellY := worldY / CellSize
+	cellX := FloorDiv(worldX, CellSize)
+	cellY := FloorDiv(worldY, CellSize)
 
 	// Get the chunk that contains this cell
-	chunkX := cellX / ChunkSize
-	chunkY := cellY / ChunkSize
+	chunkX := FloorDiv(cellX, ChunkSize)
+	chunkY := FloorDiv(cellY, ChunkSize)
 	chunk := w.GetChunk(chunkX, chunkY)
 
 	if chunk == nil || !chunk.IsLoaded {
@@ -298,6 +363,16 @@ func (w *GeneratedWorld) GetSnippetAt(worldX, worldY int) *WorldSnippet {
 	return cell.Snippet
 }
 
+// GetBiomeAt returns the biome at the specified world coordinates.
+// It returns nil if the world has no biomes or no cell is loaded there.
+func (w *GeneratedWorld) GetBiomeAt(worldX, worldY int) *Biome {
+	cell := w.GetCellAt(worldX, worldY)
+	if cell == nil {
+		return nil
+	}
+	return cell.Biome
+}
+
 // Draw renders all loaded chunks with the given camera offset.
 // This method is responsible for visualizing the world by:
 // 1. Calculating the screen position for each loaded chunk
@@ -316,7 +391,7 @@ func (w *GeneratedWorld) Draw(screen *ebiten.Image, offsetX, offsetY float64) {
 	centerX := float64(w.width) / 2
 	centerY := float64(w.height) / 2
 
-	for _, chunk := range w.chunks {
+	for _, chunk := range w.loaded {
 		if chunk.IsLoaded {
 			worldX := centerX + float64(chunk.X*ChunkSize*CellSize) + offsetX
 			worldY := centerY + float64(chunk.Y*ChunkSize*CellSize) + offsetY
@@ -0,0 +1,149 @@
+package worldgen
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"testing"
+)
+
+// newBenchmarkWorld creates a size x size world of empty cells centred on the origin
+func newBenchmarkWorld(size int) *GeneratedWorld {
+	empty := &WorldSnippet{Filename: "empty.png"}
+	worldMap := NewWorldMap()
+	for y := -size / 2; y < size/2; y++ {
+		for x := -size / 2; x < size/2; x++ {
+			worldMap.AddCell(&WorldCell{X: x, Y: y, Snippet: empty})
+		}
+	}
+
+	world := &GeneratedWorld{worldMap: worldMap}
+	world.organizeChunks()
+	world.SetPlayerPosition(CellSize/2, CellSize/2)
+	return world
+}
+
+// BenchmarkWorldMapGetCell measures a cell lookup in the world map, as done by the generator
+func BenchmarkWorldMapGetCell(b *testing.B) {
+	worldMap := newBenchmarkWorld(40).GetWorldMap()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if worldMap.GetCell(i%40-20, i/40%40-20) == nil {
+			b.Fatal("Expected a cell")
+		}
+	}
+}
+
+// BenchmarkFrameLookups measures the world lookups of one frame: moving the player,
+// reading the biome and checking the cells around the player for wall collisions
+func BenchmarkFrameLookups(b *testing.B) {
+	world := newBenchmarkWorld(40)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		x := float64(i%2000) - 1000
+		world.SetPlayerPosition(x, x)
+		world.GetBiomeAt(int(x), int(x))
+		for cellY := -1; cellY <= 1; cellY++ {
+			for cellX := -1; cellX <= 1; cellX++ {
+				world.GetCellAt(int(x)+cellX*CellSize, int(x)+cellY*CellSize)
+			}
+		}
+	}
+}
+
+// TestGetCellAtNegativeCoordinates tests that cells left of and above the origin are found
+func TestGetCellAtNegativeCoordinates(t *testing.T) {
+	world := newBenchmarkWorld(8)
+
+	testCases := []struct {
+		worldX, worldY int
+		cellX, cellY   int
+	}{
+		{worldX: 500, worldY: 500, cellX: 0, cellY: 0},
+		{worldX: -1, worldY: 500, cellX: -1, cellY: 0},
+		{worldX: -999, worldY: -1001, cellX: -1, cellY: -2},
+		{worldX: -4000, worldY: 3999, cellX: -4, cellY: 3},
+	}
+
+	for _, tc := range testCases {
+		cell := world.GetCellAt(tc.worldX, tc.worldY)
+		if cell == nil || cell.X != tc.cellX || cell.Y != tc.cellY {
+			t.Errorf("GetCellAt(%d, %d) = %+v, expected cell %d,%d", tc.worldX, tc.worldY, cell, tc.cellX, tc.cellY)
+		}
+	}
+}
+
+// chunkEventRecorder records chunk load and unload events
+type chunkEventRecorder struct {
+	loaded, unloaded []ChunkCoord
+}
+
+func (r *chunkEventRecorder) ChunkLoaded(chunk *WorldChunk) {
+	r.loaded = append(r.loaded, chunk.GetKey())
+}
+
+func (r *chunkEventRecorder) ChunkUnloaded(chunk *WorldChunk) {
+	r.unloaded = append(r.unloaded, chunk.GetKey())
+}
+
+// TestUpdateVisibleChunksStreamsChunks tests that only chunks entering and leaving
+// the visibility radius are loaded and unloaded, with the unload margin as hysteresis
+func TestUpdateVisibleChunksStreamsChunks(t *testing.T) {
+	world := newBenchmarkWorld(80)
+	world.streaming.UnloadMargin = 1
+
+	recorder := &chunkEventRecorder{}
+	world.AddChunkListener(recorder)
+	if len(recorder.loaded) != (2*VisibilityRadius+1)*(2*VisibilityRadius+1) {
+		t.Fatalf("Expected the listener to be told about all loaded chunks, got %d", len(recorder.loaded))
+	}
+
+	chunkWidth := float64(ChunkSize * CellSize)
+	steps := []struct {
+		name             string
+		chunkX           float64
+		loaded, unloaded int
+	}{
+		{name: "One chunk right", chunkX: 1, loaded: 2*VisibilityRadius + 1, unloaded: 0},
+		{name: "Back to the start", chunkX: 0, loaded: 0, unloaded: 0},
+		{name: "Two chunks right", chunkX: 2, loaded: 2*VisibilityRadius + 1, unloaded: 2*VisibilityRadius + 1},
+	}
+
+	for _, step := range steps {
+		recorder.loaded, recorder.unloaded = nil, nil
+		world.SetPlayerPosition(step.chunkX*chunkWidth+CellSize/2, CellSize/2)
+
+		if len(recorder.loaded) != step.loaded || len(recorder.unloaded) != step.unloaded {
+			t.Errorf("%s: expected %d loaded and %d unloaded chunks, got %d and %d",
+				step.name, step.loaded, step.unloaded, len(recorder.loaded), len(recorder.unloaded))
+		}
+		for _, key := range recorder.unloaded {
+			if world.GetChunk(key.X, key.Y).IsLoaded {
+				t.Errorf("%s: chunk %v is still loaded", step.name, key)
+			}
+		}
+	}
+}
+
+// TestSnippetImageCacheEvictsLeastRecentlyUsed tests that unused images over budget are freed oldest first
+func TestSnippetImageCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	first := &WorldSnippet{Filename: "first.png", Image: ebiten.NewImage(10, 10)}
+	second := &WorldSnippet{Filename: "second.png", Image: ebiten.NewImage(10, 10)}
+
+	cache := newSnippetImageCache(10 * 10 * 4)
+	cache.acquire(first)
+	cache.acquire(second)
+	cache.release(first)
+	cache.release(second)
+	cache.trim()
+
+	if first.Image != nil {
+		t.Error("Expected the least recently used image to be freed")
+	}
+	if second.Image == nil {
+		t.Error("Expected the most recently used image to stay within the budget")
+	}
+	if cache.resident != 10*10*4 {
+		t.Errorf("Expected %d resident bytes, got %d", 10*10*4, cache.resident)
+	}
+}
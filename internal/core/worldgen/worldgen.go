@@ -29,6 +29,25 @@ func NewWorldGenerator() (*WorldGenerator, error) {
 	}, nil
 }
 
+// NewHeadlessWorldGenerator creates a world generator whose registry only holds snippet
+// metadata. It does not decode snippet images or detect walls, so it can run without a
+// graphics context. The generated WorldMap is identical to the one produced by
+// NewWorldGenerator for the same configuration.
+func NewHeadlessWorldGenerator() (*WorldGenerator, error) {
+	registry := NewSnippetRegistry()
+
+	metadataDir := filepath.Join("images", "gameScene", "World", "metadata")
+
+	err := registry.LoadSnippetMetadata(metadataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load world snippet metadata: %w", err)
+	}
+
+	return &WorldGenerator{
+		Registry: registry,
+	}, nil
+}
+
 // Initialize initializes the world generator with the specified metadata and image directories
 func (g *WorldGenerator) Initialize(metadataDir, imageDir string) error {
 	// Create a new registry
@@ -1,9 +1,5 @@
 package worldgen
 
-import (
-	"fmt"
-)
-
 // WorldCell represents a single cell in the world map
 type WorldCell struct {
 	X, Y        int           // The coordinates of the cell in the world grid
@@ -11,11 +7,19 @@ type WorldCell struct {
 	Rotation    int           // The rotation of the snippet in degrees (0, 90, 180, 270)
 	IsMainPath  bool          // Whether this cell is part of the main path
 	BranchDepth int           // The depth of this cell in a branch (0 for main path)
+	Biome       *Biome        // The biome this cell belongs to (nil if the world has no biomes)
+	IsArena     bool          // Whether a boss waits in this cell (see markArenas)
+}
+
+// CellCoord identifies a cell in the world grid.
+// It is used as a map key instead of a formatted string, so lookups do not allocate.
+type CellCoord struct {
+	X, Y int
 }
 
-// GetKey returns a unique string key for this cell based on its coordinates
-func (c *WorldCell) GetKey() string {
-	return fmt.Sprintf("%d,%d", c.X, c.Y)
+// GetKey returns the unique key of this cell based on its coordinates
+func (c *WorldCell) GetKey() CellCoord {
+	return CellCoord{X: c.X, Y: c.Y}
 }
 
 // GetRotatedConnectors returns the snippet connectors adjusted for cell rotation
@@ -33,15 +37,15 @@ func (c *WorldCell) GetRotatedConnectors() []SnippetConnector {
 
 // WorldMap represents the generated world map
 type WorldMap struct {
-	Cells         map[string]*WorldCell // Map of cells by coordinates (key: "x,y")
-	MainPathCells []*WorldCell          // List of cells that form the main path
-	BranchCells   []*WorldCell          // List of cells that form branches
+	Cells         map[CellCoord]*WorldCell // Map of cells by coordinates
+	MainPathCells []*WorldCell             // List of cells that form the main path
+	BranchCells   []*WorldCell             // List of cells that form branches
 }
 
 // NewWorldMap creates a new empty world map
 func NewWorldMap() *WorldMap {
 	return &WorldMap{
-		Cells:         make(map[string]*WorldCell),
+		Cells:         make(map[CellCoord]*WorldCell),
 		MainPathCells: make([]*WorldCell, 0),
 		BranchCells:   make([]*WorldCell, 0),
 	}
@@ -49,8 +53,7 @@ func NewWorldMap() *WorldMap {
 
 // AddCell adds a cell to the world map
 func (m *WorldMap) AddCell(cell *WorldCell) {
-	key := cell.GetKey()
-	m.Cells[key] = cell
+	m.Cells[cell.GetKey()] = cell
 
 	if cell.IsMainPath {
 		m.MainPathCells = append(m.MainPathCells, cell)
@@ -61,14 +64,12 @@ func (m *WorldMap) AddCell(cell *WorldCell) {
 
 // GetCell returns the cell at the specified coordinates, or nil if no cell exists there
 func (m *WorldMap) GetCell(x, y int) *WorldCell {
-	key := fmt.Sprintf("%d,%d", x, y)
-	return m.Cells[key]
+	return m.Cells[CellCoord{X: x, Y: y}]
 }
 
 // HasCell returns true if there is a cell at the specified coordinates
 func (m *WorldMap) HasCell(x, y int) bool {
-	key := fmt.Sprintf("%d,%d", x, y)
-	_, exists := m.Cells[key]
+	_, exists := m.Cells[CellCoord{X: x, Y: y}]
 	return exists
 }
 
@@ -0,0 +1,50 @@
+package shaders
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// FlashShader blends a sprite towards white while keeping its shape.
+// It is used to flash enemies briefly when they are hit.
+//
+// Usage:
+//
+//	fs, _ := shaders.NewFlashShader()
+//	op := &ebiten.DrawRectShaderOptions{}
+//	op.Images[0] = sprite
+//	op.Uniforms = map[string]any{
+//	    "Amount": float32(0.8), // 0 draws the sprite unchanged, 1 draws it all white
+//	}
+//	screen.DrawRectShader(sprite.Bounds().Dx(), sprite.Bounds().Dy(), fs.Shader(), op)
+type FlashShader struct {
+	shader *ebiten.Shader
+}
+
+// NewFlashShader compiles and returns a new flash shader.
+func NewFlashShader() (*FlashShader, error) {
+	s, err := ebiten.NewShader([]byte(flashShaderSrc))
+	if err != nil {
+		return nil, err
+	}
+	return &FlashShader{shader: s}, nil
+}
+
+// Shader returns the underlying ebiten.Shader.
+func (f *FlashShader) Shader() *ebiten.Shader {
+	return f.shader
+}
+
+const flashShaderSrc = `//kage:unit pixels
+package main
+
+var Amount float
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+    col := imageSrc0At(texCoord)
+
+    // Colors are premultiplied, so white at the alpha of the pixel is vec3(col.a)
+    col.rgb = mix(col.rgb, vec3(col.a), Amount)
+
+    return col * color
+}
+`
@@ -2,8 +2,10 @@ package scenes
 
 import (
 	"discoveryx/internal/assets"
+	"discoveryx/internal/config"
 	"discoveryx/internal/constants"
 	"discoveryx/internal/core/gameplay/enemies"
+	"discoveryx/internal/core/gameplay/pickups"
 	"discoveryx/internal/core/gameplay/player"
 	"discoveryx/internal/core/gameplay/projectiles"
 	"discoveryx/internal/core/physics"
@@ -11,11 +13,13 @@ import (
 	"discoveryx/internal/input"
 	"discoveryx/internal/rendering/shaders"
 	"discoveryx/internal/utils/math"
+	"discoveryx/internal/utils/random"
 	"fmt"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"image/color"
 	stdmath "math"
+	"math/rand"
 )
 
 // GameScene represents the main gameplay scene with player, enemies, and world
@@ -26,8 +30,34 @@ type GameScene struct {
 	enemies           []*enemies.Enemy
 	brightnessShader  *shaders.BrightnessShader
 	bullets           []*projectiles.Bullet
+	spentBullets      map[*projectiles.Bullet]bool // Bullets that hit something in the current update
+	debris            []*projectiles.Debris        // Pieces of destroyed enemies bouncing around the walls
+	enemyShots        []*projectiles.Bullet        // Bullets fired by enemies during the physics steps, added after them
+	damageNumbers     *enemies.DamageNumbers       // Damage of recent hits rising from the enemies, nil if disabled
 	timeSinceLastShot float64
+	score             int                       // Score of the destroyed enemies
 	collisionManager  *physics.CollisionManager // Manages all collision detection
+	physicsWorld      *physics.World            // Moves the player, bullets and enemies at a fixed rate
+	forceVolumes      *physics.ForceVolumes     // Currents, gravity wells and other forces of the loaded chunks
+
+	// Per chunk state that is created and freed as chunks are streamed in and out
+	enemyStore  *enemies.EnemyStore                            // Spawns, saves and restores the enemies of each chunk
+	director    *enemies.Director                              // Sends waves of enemies into loaded chunks, nil if disabled
+	lastHealth  float64                                        // Health of the player in the last update, to report damage to the director
+	navigation  *worldgen.Navigation                           // Finds the way through the cave, with grids built and paths cached per loaded chunk
+	chunkWalls  map[worldgen.ChunkCoord][]physics.WallSegment  // Wall outline segments registered for each loaded chunk
+	chunkForces map[worldgen.ChunkCoord][]*physics.ForceVolume // Force volumes added for each loaded chunk
+
+	// Boss arenas: a boss waits in each loaded arena cell until the player enters, then the arena is closed until it is destroyed
+	bosses         map[worldgen.CellCoord]*enemies.Enemy // Bosses of the loaded arena cells
+	defeatedArenas map[worldgen.CellCoord]bool           // Arenas whose boss was destroyed in this run
+	lockedArena    *worldgen.WorldCell                   // Arena the player is locked into, nil if none
+	arenaGates     []physics.WallSegment                 // Walls closing the openings of the locked arena
+	rewards        []*pickups.Reward                     // Rewards dropped by destroyed bosses
+
+	// Deterministic randomness for the run
+	seed        random.Seed // Root seed of this run
+	gameplayRng *rand.Rand  // Random stream for gameplay decisions, derived from the seed
 
 	// Screen shake effect for visual feedback
 	shakeTimer     float64 // Time remaining for screen shake effect
@@ -35,44 +65,116 @@ type GameScene struct {
 	shakeFrequency float64 // Shake frequency in cycles per second
 	totalTime      float64 // Total elapsed time for time-based effects
 
-	// Collision resolution improvement
-	lastCollisionNormal math.Vector // Tracks the last collision normal to prevent oscillation
+	// Biome the player is currently in, used for the background and lighting
+	currentBiome *worldgen.Biome // nil if the world has no biomes
+	lightRadius  float64         // Current light radius as a fraction of the screen width
 }
 
+// defaultLightRadius is the light radius (as a fraction of the screen width)
+// outside of biomes that set their own
+const defaultLightRadius = 0.75
+
+// lightRadiusSmoothing is how quickly the light radius adapts when entering a new biome
+const lightRadiusSmoothing = 0.03
+
+// Debris that bursts out of destroyed enemies
+const (
+	enemyDebrisCount = 8     // Pieces per destroyed enemy
+	enemyDebrisSpeed = 180.0 // Speed of the fastest pieces in units per second
+)
+
+// arenaLockMargin is how far inside an arena cell the player has to be before the arena is closed,
+// so the gates never close on the hull
+const arenaLockMargin = 150.0
+
+// gateThickness is the width in pixels of the drawn arena gates
+const gateThickness = 4.0
+
+// gateColor is the color of the drawn arena gates
+var gateColor = color.RGBA{R: 255, G: 80, B: 60, A: 220}
+
+// gateImage is a white pixel that is stretched along the arena gates when they are drawn
+var gateImage *ebiten.Image
+
 // NewGameScene creates a new game scene with the provided player
 func NewGameScene(player *player.Player) *GameScene {
-	// Create a new collision manager with a cell size of 100 units
+	physicsSettings := config.Get().Physics
+
+	// Create a new collision manager with a cell size of 100 units and the configured broadphase
 	// This value can be tuned based on the typical size and distribution of entities
-	collisionManager := physics.NewCollisionManager(100.0)
+	collisionManager, err := physics.NewCollisionManagerWithBroadphase(100.0, physicsSettings.Broadphase)
+	if err != nil {
+		// The config is validated, so this only happens if the physics package lacks a broadphase
+		fmt.Printf("%v, using the grid\n", err)
+		collisionManager = physics.NewCollisionManager(100.0)
+	}
+
+	// Bodies are stepped at the configured fixed rate, whatever the frame rate
+	physicsWorld := physics.NewWorld(physicsSettings.StepsPerSecond, physicsSettings.MaxStepsPerUpdate, collisionManager)
+
+	// The force volumes of the snippets push the bodies around; a grid cell per world cell
+	forceVolumes := physics.NewForceVolumes(worldgen.CellSize)
+	physicsWorld.Forces = forceVolumes
 
 	return &GameScene{
 		player:            player,
 		cameraPosition:    math.Vector{X: 0, Y: 0},
 		timeSinceLastShot: 0,
 		collisionManager:  collisionManager,
+		physicsWorld:      physicsWorld,
+		forceVolumes:      forceVolumes,
+		spentBullets:      make(map[*projectiles.Bullet]bool),
+		chunkWalls:        make(map[worldgen.ChunkCoord][]physics.WallSegment),
+		chunkForces:       make(map[worldgen.ChunkCoord][]*physics.ForceVolume),
+		bosses:            make(map[worldgen.CellCoord]*enemies.Enemy),
+		defeatedArenas:    make(map[worldgen.CellCoord]bool),
 
 		// Initialize screen shake effect fields
 		shakeTimer:     0,
 		shakeAmplitude: 0,
 		shakeFrequency: 10.0, // 10 cycles per second
 		totalTime:      0,
+
+		lightRadius: defaultLightRadius,
 	}
 }
 
 // Initialize sets up the game scene with world generation, shaders, and enemy placement
 func (s *GameScene) Initialize(state *State) error {
+	// Pick the run seed: the requested one, or a fresh one for this run
+	s.seed = state.Seed
+	if s.seed == 0 {
+		s.seed = random.NewSeed()
+	}
+	s.gameplayRng = s.seed.NewRand(random.StreamGameplay)
+
+	if constants.DebugLogging {
+		fmt.Printf("Starting run with seed %s\n", s.seed)
+	}
+
 	generator, err := worldgen.NewWorldGenerator()
 	if err != nil {
 		return err
 	}
 
-	config := worldgen.DefaultWorldGenConfig()
+	worldConfig := worldgen.NewWorldGenConfig(config.Get().WorldGen)
+	worldConfig.Seed = s.seed.Derive(random.StreamWorldGen)
+
+	// Fail early on biome backgrounds that do not exist, instead of when the player enters the biome
+	for _, biome := range worldConfig.Biomes {
+		if biome.Background == "" {
+			continue
+		}
+		if _, err := assets.Assets.Open(biome.Background); err != nil {
+			return fmt.Errorf("background of biome %s: %w", biome.Name, err)
+		}
+	}
 
 	s.generatedWorld, err = worldgen.NewGeneratedWorld(
 		state.World.GetWidth(),
 		state.World.GetHeight(),
 		generator,
-		config,
+		worldConfig,
 	)
 	if err != nil {
 		return err
@@ -83,8 +185,28 @@ func (s *GameScene) Initialize(state *State) error {
 		return err
 	}
 
-	objectTypes := []string{"enemy_1"}
-	s.enemies = enemies.SpawnObjectsOnWalls(s.generatedWorld, objectTypes, 1.0, 32.0)
+	// Free snippet images of chunks out of view and reload them when they come back
+	s.generatedWorld.EnableStreaming(worldgen.NewStreamingConfig(config.Get().Streaming))
+
+	// Enemies are spawned per chunk when the chunk is first loaded
+	spawner := enemies.NewSpawner()
+	spawner.Config.Seed = s.seed.Derive(random.StreamEnemySpawn)
+	spawner.Config.MinDistanceBetweenEnemies = 32.0
+	s.enemyStore = enemies.NewEnemyStore(spawner, enemies.ArchetypeNames(), 1.0)
+	s.navigation = worldgen.NewNavigation(s.generatedWorld.GetWorldMap())
+
+	// The director adds waves of enemies over the run, on top of the enemies of each chunk
+	s.director = nil
+	if directorSettings := config.Get().Enemies.Director; directorSettings.Enabled {
+		s.director = enemies.NewDirector(directorSettings, spawner, s.enemyStore,
+			enemies.ArchetypeNames(), s.seed.Derive(random.StreamDirector))
+	}
+
+	// Hits on enemies show their damage if enabled; health bars and hit flashes are drawn by the enemies
+	s.damageNumbers = nil
+	if feedback := config.Get().Enemies.Feedback; feedback.DamageNumbers {
+		s.damageNumbers = enemies.NewDamageNumbers(feedback)
+	}
 
 	// Position the player on the main path first
 	if len(s.generatedWorld.GetWorldMap().MainPathCells) > 0 {
@@ -101,8 +223,10 @@ func (s *GameScene) Initialize(state *State) error {
 		s.player.SetPosition(mainPathPos)
 	}
 
-	// Register walls with the collision manager after positioning the player
-	s.registerWalls()
+	// Register walls and enemies of the loaded chunks with the collision manager,
+	// and keep them and the navigation grids and paths in sync with the chunks that are streamed in and out
+	s.generatedWorld.AddChunkListener(&gameChunkListener{scene: s})
+	s.generatedWorld.AddChunkListener(s.navigation)
 
 	// Now try to find a better position for the player if needed
 	if len(s.generatedWorld.GetWorldMap().MainPathCells) > 0 {
@@ -137,10 +261,8 @@ func (s *GameScene) Initialize(state *State) error {
 				Y: float64(mainPathCell.Y*worldgen.CellSize + worldgen.CellSize/2),
 			}
 
-			// Check if this position collides with any walls using world data
-			collision, _, _ := s.CheckWallCollisionUsingWorldData(s.player, mainPathPos)
-
-			if !collision {
+			// Check if the hull would overlap any walls at this position
+			if !s.overlapsWallAt(mainPathPos) {
 				// Found a valid position
 				s.player.SetPosition(mainPathPos)
 				validPosition = true
@@ -151,608 +273,500 @@ func (s *GameScene) Initialize(state *State) error {
 	}
 
 	// Register the player with the collision manager
-	s.collisionManager.RegisterEntity(s.player, s.player.GetCollider())
+	s.collisionManager.RegisterEntityPolygon(s.player, s.player.GetHullCollider())
+	s.collisionManager.SetEntityLayers(s.player, physics.NewCollisionLayers(physics.LayerPlayer))
+	s.physicsWorld.AddBody(s.player.NewBody())
 
-	// Register enemies with the collision manager
-	for _, enemy := range s.enemies {
-		s.collisionManager.RegisterEntity(enemy, enemy.GetCollider())
-	}
+	// Apply the damage of hits between the player, enemies and bullets
+	s.collisionManager.AddContactListener(&gameContactListener{scene: s})
 
 	return nil
 }
 
-// registerWalls extracts wall points from the generated world, converts them to wall colliders,
-// and registers them with the collision manager.
-func (s *GameScene) registerWalls() {
-	// Clear existing walls
-	s.collisionManager.ClearWalls()
+// Score returns the score of the enemies destroyed in the current run.
+func (s *GameScene) Score() int {
+	return s.score
+}
+
+// Seed returns the root seed of the current run.
+// Starting a new run with the same seed reproduces it exactly.
+func (s *GameScene) Seed() random.Seed {
+	return s.seed
+}
+
+// gameChunkListener keeps the collision manager and the enemies of the scene
+// in sync with the chunks that are loaded around the player
+type gameChunkListener struct {
+	scene *GameScene
+}
 
-	// Create a wall collider generator with a minimum wall size of 10 units
-	wallGenerator := physics.NewWallColliderGenerator(10.0)
+// ChunkLoaded registers the walls and force volumes of the chunk, spawns or restores its enemies
+// and spawns the bosses of its arenas that have not been defeated
+func (l *gameChunkListener) ChunkLoaded(chunk *worldgen.WorldChunk) {
+	s := l.scene
 
-	// Get player position in cell coordinates
-	playerPos := s.player.GetPosition()
-	playerCellX := int(playerPos.X) / worldgen.CellSize
-	playerCellY := int(playerPos.Y) / worldgen.CellSize
+	walls := chunkWallSegments(chunk)
+	for _, segment := range walls {
+		s.collisionManager.RegisterWallSegment(segment)
+	}
+	s.chunkWalls[chunk.GetKey()] = walls
 
-	// Convert player cell coordinates to chunk coordinates
-	playerChunkX := playerCellX / worldgen.ChunkSize
-	playerChunkY := playerCellY / worldgen.ChunkSize
+	forces := chunkForceVolumes(chunk)
+	for _, volume := range forces {
+		s.forceVolumes.Add(volume)
+	}
+	s.chunkForces[chunk.GetKey()] = forces
 
-	// Use the same visibility radius as the world generator
-	chunkRadius := worldgen.VisibilityRadius
+	for _, enemy := range s.enemyStore.Load(s.generatedWorld, chunk) {
+		s.addEnemy(enemy)
+	}
 
-	// Process all chunks in the visibility radius
-	for chunkY := playerChunkY - chunkRadius; chunkY <= playerChunkY+chunkRadius; chunkY++ {
-		for chunkX := playerChunkX - chunkRadius; chunkX <= playerChunkX+chunkRadius; chunkX++ {
-			// Get the chunk at these coordinates
-			chunk := s.generatedWorld.GetChunk(chunkX, chunkY)
-			if chunk == nil || !chunk.IsLoaded {
-				continue
-			}
+	for _, cell := range chunk.Cells {
+		key := worldgen.CellCoord{X: cell.X, Y: cell.Y}
+		if !cell.IsArena || cell.Snippet == nil || s.defeatedArenas[key] || s.bosses[key] != nil {
+			continue
+		}
+		definition := enemies.BossForArena(s.seed.Derive(random.StreamBoss), key)
+		if definition == nil {
+			continue
+		}
+		boss := enemies.NewBoss(definition, cell)
+		s.bosses[key] = boss
+		s.addEnemy(boss)
+	}
+}
 
-			// Process all cells in this chunk
-			for _, cell := range chunk.Cells {
-				if cell == nil || cell.Snippet == nil {
-					continue
-				}
+// ChunkUnloaded removes the walls and force volumes of the chunk and saves its enemies.
+// Bosses are not saved: a boss that is streamed out before it is destroyed recovers fully.
+func (l *gameChunkListener) ChunkUnloaded(chunk *worldgen.WorldChunk) {
+	s := l.scene
 
-				// Get wall points in world coordinates
-				wallPoints := cell.GetWallsInWorldCoordinates()
-				if len(wallPoints) == 0 {
-					continue
-				}
+	for _, segment := range s.chunkWalls[chunk.GetKey()] {
+		s.collisionManager.RemoveWallSegment(segment)
+	}
+	delete(s.chunkWalls, chunk.GetKey())
 
-				// Convert wall points to physics.WallPoint
-				physicsWallPoints := make([]physics.WallPoint, len(wallPoints))
-				for i, wp := range wallPoints {
-					physicsWallPoints[i] = physics.WallPoint{
-						X:      wp.X,
-						Y:      wp.Y,
-						Normal: wp.Normal,
-					}
-				}
+	for _, volume := range s.chunkForces[chunk.GetKey()] {
+		s.forceVolumes.Remove(volume)
+	}
+	delete(s.chunkForces, chunk.GetKey())
 
-				// Generate wall colliders
-				wallColliders := wallGenerator.GenerateWallColliders(physicsWallPoints, float64(worldgen.CellSize))
+	remaining, unloaded := s.enemyStore.Unload(chunk, s.enemies)
+	for _, enemy := range unloaded {
+		s.removeEnemy(enemy)
+	}
+	s.enemies = remaining
 
-				// Register wall colliders with the collision manager
-				for _, collider := range wallColliders {
-					s.collisionManager.RegisterWall(collider)
-				}
+	for _, cell := range chunk.Cells {
+		key := worldgen.CellCoord{X: cell.X, Y: cell.Y}
+		boss := s.bosses[key]
+		if boss == nil {
+			continue
+		}
+		if s.lockedArena == cell {
+			s.unlockArena()
+		}
+		s.removeEnemy(boss)
+		delete(s.bosses, key)
+
+		active := s.enemies[:0]
+		for _, enemy := range s.enemies {
+			if enemy != boss {
+				active = append(active, enemy)
 			}
 		}
+		s.enemies = active
 	}
-
-	// Optimize walls to reduce the number of colliders
-	s.collisionManager.OptimizeWalls()
 }
 
-// abs returns the absolute value of a float64
-func abs(x float64) float64 {
-	if x < 0 {
-		return -x
-	}
-	return x
+// gameEnemyEnvironment lets the brains of the enemies see the player and shoot at it.
+// It implements enemies.Environment.
+type gameEnemyEnvironment struct {
+	scene *GameScene
 }
 
-// CheckWallCollisionUsingWorldData performs collision detection between an entity and walls using world generation data.
-// This is more efficient than using physics queries as it directly uses the tile information.
-func (s *GameScene) CheckWallCollisionUsingWorldData(entity interface{}, plannedPos math.Vector) (bool, math.Vector, bool) {
-	// Check if the entity implements GetAABBCollider
-	type aabbGetter interface {
-		GetAABBCollider() physics.AABBCollider
-	}
+// enemyBulletOffset is the distance from the enemy center at which its bullets spawn
+const enemyBulletOffset = 10.0
 
-	aabbEntity, ok := entity.(aabbGetter)
-	if !ok {
-		return false, math.Vector{}, false
-	}
+// Target returns the position of the player
+func (e *gameEnemyEnvironment) Target() (math.Vector, bool) {
+	return e.scene.player.GetPosition(), true
+}
 
-	// Get the entity's AABB collider
-	entityCollider := aabbEntity.GetAABBCollider()
+// LineOfSight checks that no wall lies between two positions
+func (e *gameEnemyEnvironment) LineOfSight(from, to math.Vector) bool {
+	return e.scene.collisionManager.LineOfSight(from, to)
+}
 
-	// Calculate the entity's bounds
-	minX := plannedPos.X - entityCollider.Width/2
-	maxX := plannedPos.X + entityCollider.Width/2
-	minY := plannedPos.Y - entityCollider.Height/2
-	maxY := plannedPos.Y + entityCollider.Height/2
+// Fire queues a bullet from the edge of the enemy with the damage and ricochet of its weapon; it is added after the physics steps
+func (e *gameEnemyEnvironment) Fire(enemy *enemies.Enemy, rotation float64) {
+	position := math.Vector{
+		X: enemy.Position.X + stdmath.Sin(rotation)*enemyBulletOffset,
+		Y: enemy.Position.Y - stdmath.Cos(rotation)*enemyBulletOffset,
+	}
+	bullet := projectiles.NewLinearBullet(position, rotation, assets.EnemyBullet, false)
+	if enemy.Archetype != nil && enemy.Archetype.Weapon != nil {
+		if enemy.Archetype.Weapon.Damage > 0 {
+			bullet.Damage = enemy.Archetype.Weapon.Damage
+		}
+		bullet.Bounces = enemy.Archetype.Weapon.Bounces
+	}
+	e.scene.enemyShots = append(e.scene.enemyShots, bullet)
+}
 
-	// Get the cells that the entity overlaps with
-	cellMinX := int(minX) / worldgen.CellSize
-	cellMaxX := int(maxX) / worldgen.CellSize
-	cellMinY := int(minY) / worldgen.CellSize
-	cellMaxY := int(maxY) / worldgen.CellSize
+// NextWaypoint returns the next position on the way through the cave between two positions
+func (e *gameEnemyEnvironment) NextWaypoint(from, to math.Vector) (math.Vector, bool) {
+	return e.scene.navigation.NextWaypoint(from, to)
+}
 
-	// Check each cell for wall collisions
-	collision := false
-	smallestDepth := 1000000.0
-	var separationVector math.Vector
-	var isXAxis bool
-	var selectedNormal math.Vector
+// chunkWallSegments collects the wall outline segments of all cells in a chunk
+// in world coordinates
+func chunkWallSegments(chunk *worldgen.WorldChunk) []physics.WallSegment {
+	var segments []physics.WallSegment
+	for _, cell := range chunk.Cells {
+		if cell == nil || cell.Snippet == nil {
+			continue
+		}
 
-	for cellY := cellMinY; cellY <= cellMaxY; cellY++ {
-		for cellX := cellMinX; cellX <= cellMaxX; cellX++ {
-			// Get the cell at these coordinates
-			cell := s.generatedWorld.GetCellAt(cellX*worldgen.CellSize, cellY*worldgen.CellSize)
-			if cell == nil || cell.Snippet == nil {
-				continue
+		// Convert the outline segments to physics.WallSegment
+		for _, outline := range cell.GetWallOutlinesInWorldCoordinates() {
+			for _, segment := range outline.Segments {
+				segments = append(segments, physics.WallSegment{
+					A:      segment.A,
+					B:      segment.B,
+					Normal: segment.Normal,
+				})
 			}
+		}
+	}
 
-			// Get wall points in world coordinates
-			wallPoints := cell.GetWallsInWorldCoordinates()
-			if len(wallPoints) == 0 {
-				continue
-			}
+	return segments
+}
 
-			// Check for collisions with each wall point
-			for _, wallPoint := range wallPoints {
-				// Calculate the distance from the wall point to the entity's AABB
-				// We need to check if the wall point is inside or close to the entity's AABB
-
-				// Calculate the closest point on the AABB to the wall point
-				// Clamp X value between minX and maxX
-				closestX := wallPoint.X
-				if closestX < minX {
-					closestX = minX
-				} else if closestX > maxX {
-					closestX = maxX
-				}
+// forceKinds maps the force volume types of the snippet metadata to the physics force kinds
+var forceKinds = map[worldgen.ForceVolumeType]physics.ForceKind{
+	worldgen.ForceCurrent:     physics.ForceCurrent,
+	worldgen.ForceGravityWell: physics.ForceGravityWell,
+	worldgen.ForceRepulsor:    physics.ForceRepulsor,
+	worldgen.ForceTurbulence:  physics.ForceTurbulence,
+}
 
-				// Clamp Y value between minY and maxY
-				closestY := wallPoint.Y
-				if closestY < minY {
-					closestY = minY
-				} else if closestY > maxY {
-					closestY = maxY
-				}
+// chunkForceVolumes collects the force volumes of all cells in a chunk in world coordinates
+func chunkForceVolumes(chunk *worldgen.WorldChunk) []*physics.ForceVolume {
+	var volumes []*physics.ForceVolume
+	for _, cell := range chunk.Cells {
+		if cell == nil || cell.Snippet == nil {
+			continue
+		}
 
-				// Calculate the distance from the wall point to the closest point on the AABB
-				distX := wallPoint.X - closestX
-				distY := wallPoint.Y - closestY
-
-				// If the wall point is inside or very close to the AABB, we have a collision
-				// We use a small threshold to account for floating point errors
-				// Reduced threshold to prevent detecting barely touching edges as deep collisions
-				threshold := 0.5 // 0.5 pixel threshold (reduced from 1.0)
-				if (distX*distX + distY*distY) <= threshold*threshold {
-					collision = true
-
-					// Calculate the penetration depth
-					depth := 0.0
-					normal := wallPoint.Normal
-					isCollisionXAxis := false
-
-					// Calculate penetration depth based on the normal direction
-					if abs(normal.X) > abs(normal.Y) {
-						// X-axis collision
-						if normal.X > 0 {
-							depth = maxX - wallPoint.X
-						} else {
-							depth = wallPoint.X - minX
-						}
-						isCollisionXAxis = true
-					} else {
-						// Y-axis collision
-						if normal.Y > 0 {
-							depth = maxY - wallPoint.Y
-						} else {
-							depth = wallPoint.Y - minY
-						}
-						isCollisionXAxis = false
-					}
-
-					// Check if this normal is opposite to the last collision normal
-					dotProduct := normal.X * s.lastCollisionNormal.X + normal.Y * s.lastCollisionNormal.Y
-					isOppositeToLast := dotProduct < -0.7 // Threshold for considering normals as opposite (roughly 135 degrees)
-
-					// If this is the smallest penetration depth so far and not opposite to the last normal
-					// or if it's significantly smaller than the current smallest depth, update the separation vector
-					if (depth < smallestDepth && (!isOppositeToLast || s.lastCollisionNormal.X == 0 && s.lastCollisionNormal.Y == 0)) || 
-					   (depth < smallestDepth * 0.8) { // If depth is at least 20% smaller, use it even if opposite
-						smallestDepth = depth
-						separationVector = math.Vector{
-							X: normal.X * depth,
-							Y: normal.Y * depth,
-						}
-						isXAxis = isCollisionXAxis
-						selectedNormal = normal // Store the selected normal for later use
-					}
-				}
+		// Convert the volumes to physics.ForceVolume; the direction is 0 = up, clockwise
+		for _, volume := range cell.GetForceVolumesInWorldCoordinates() {
+			direction := volume.Direction * stdmath.Pi / 180.0
+			forceVolume := &physics.ForceVolume{
+				Kind:      forceKinds[volume.Type],
+				Center:    math.Vector{X: volume.X, Y: volume.Y},
+				Radius:    volume.Radius,
+				Strength:  volume.Strength,
+				Direction: math.Vector{X: stdmath.Sin(direction), Y: -stdmath.Cos(direction)},
+			}
+			if volume.IsRectangle() {
+				forceVolume.HalfSize = math.Vector{X: volume.Width / 2, Y: volume.Height / 2}
 			}
+			volumes = append(volumes, forceVolume)
 		}
 	}
 
-	// If a collision was detected, update the lastCollisionNormal field
-	if collision {
-		s.lastCollisionNormal = selectedNormal
-	}
+	return volumes
+}
 
-	return collision, separationVector, isXAxis
+// gameContactListener applies the damage of hits between the player, enemies and bullets.
+// Touching counts every update, so hits that happen while the player is invincible
+// still count once the invincibility is over.
+type gameContactListener struct {
+	scene *GameScene
 }
 
-// Update handles the game logic and camera movement for the scene
-func (s *GameScene) Update(state *State) error {
-	// Update total elapsed time
-	s.totalTime += state.DeltaTime
+// ContactEntered applies the damage of a new hit
+func (l *gameContactListener) ContactEntered(event physics.ContactEvent) {
+	l.hit(event)
+}
 
-	if s.generatedWorld == nil {
-		if err := s.Initialize(state); err != nil {
-			return err
-		}
-	}
+// ContactStayed applies the damage of a hit that is still going on
+func (l *gameContactListener) ContactStayed(event physics.ContactEvent) {
+	l.hit(event)
+}
 
-	// Check if player's health is zero and redirect to start screen
-	if s.player.GetHealth() <= 0 {
-		state.SceneManager.GoToScene(NewStartScene())
-		return nil
-	}
+// ContactExited does nothing, separating entities do not deal damage
+func (l *gameContactListener) ContactExited(event physics.ContactEvent) {}
 
-	// Get the player's current position before updating
-	currentPosition := s.player.GetPosition()
+// hit applies the damage of a contact between the player, an enemy or a bullet
+func (l *gameContactListener) hit(event physics.ContactEvent) {
+	s := l.scene
 
-	// Update player's state (input, rotation, etc.) but don't apply movement yet
-	if err := s.player.Update(state.Input, state.DeltaTime); err != nil {
-		return err
+	// The player collects the rewards it touches
+	if pickup, _, ok := event.Match(physics.LayerPickup, physics.LayerPlayer); ok {
+		reward := pickup.(*pickups.Reward)
+		if reward.Collect() {
+			s.player.Heal(reward.Health)
+			s.score += reward.Score
+		}
+		return
 	}
 
-	// Get the player's updated position after input processing
-	updatedPosition := s.player.GetPosition()
-
-	// Check for wall collisions using AABB collision detection
-	// First check X-axis movement
-	plannedXPosition := math.Vector{
-		X: updatedPosition.X,
-		Y: currentPosition.Y,
+	// Player bullets damage enemies
+	if bullet, target, ok := event.Match(physics.LayerPlayerBullet, physics.LayerEnemy); ok {
+		b := bullet.(*projectiles.Bullet)
+		if !s.spentBullets[b] {
+			enemy := target.(*enemies.Enemy)
+			if s.damageNumbers != nil && !enemy.IsDying {
+				s.damageNumbers.Add(enemy.Position, b.Damage)
+			}
+			if enemy.TakeDamage(b.Damage) {
+				s.spawnDebris(enemy.Position)
+				if enemy.Archetype != nil {
+					s.score += enemy.Archetype.Score
+				}
+				if s.director != nil {
+					s.director.EnemyKilled()
+				}
+			}
+			s.spentBullets[b] = true
+		}
+		return
 	}
 
-	collisionX, separationVectorX, _ := s.CheckWallCollisionUsingWorldData(s.player, plannedXPosition)
+	// Invincibility protects the player from everything else
+	if s.player.IsInvincible() {
+		return
+	}
 
-	// Apply X-axis movement if no collision
-	if collisionX {
-		// Collision on X-axis, keep the player at the wall edge
-		s.player.SetPosition(math.Vector{
-			X: plannedXPosition.X + separationVectorX.X,
-			Y: currentPosition.Y,
-		})
-	} else {
-		// No collision on X-axis, allow movement
-		s.player.SetPosition(math.Vector{
-			X: updatedPosition.X,
-			Y: currentPosition.Y,
-		})
+	// Enemy bullets damage the player
+	if bullet, _, ok := event.Match(physics.LayerEnemyBullet, physics.LayerPlayer); ok {
+		b := bullet.(*projectiles.Bullet)
+		if !s.spentBullets[b] {
+			s.player.TakeDamage(b.Damage)
+			s.spentBullets[b] = true
+		}
+		return
 	}
 
-	// Now check Y-axis movement
-	currentXPosition := s.player.GetPosition().X
-	plannedYPosition := math.Vector{
-		X: currentXPosition,
-		Y: updatedPosition.Y,
+	// Touching an enemy damages the player
+	if _, _, ok := event.Match(physics.LayerEnemy, physics.LayerPlayer); ok {
+		s.player.TakeDamage(player.EnemyCollisionDamage)
 	}
+}
 
-	collisionY, separationVectorY, _ := s.CheckWallCollisionUsingWorldData(s.player, plannedYPosition)
+// overlapsWallAt checks if the player's hull would overlap a wall at the position.
+// The walls are taken from the world data, so positions in chunks that are not loaded can be tested too.
+func (s *GameScene) overlapsWallAt(position math.Vector) bool {
+	hull := s.player.GetHullCollider()
+	hull.Position = position
 
-	// Apply Y-axis movement if no collision
-	if collisionY {
-		// Collision on Y-axis, keep the player at the wall edge
-		s.player.SetPosition(math.Vector{
-			X: currentXPosition,
-			Y: plannedYPosition.Y + separationVectorY.Y,
-		})
-	} else {
-		// No collision on Y-axis, allow movement
-		s.player.SetPosition(math.Vector{
-			X: currentXPosition,
-			Y: updatedPosition.Y,
-		})
-	}
-
-	// Final check to ensure player is not inside a wall
-	// This handles cases where minimal overlaps might still occur due to player hitbox size
-	// We limit the number of iterations to prevent infinite loops when player is stuck between two walls
-	const maxIterations = 3 // Maximum number of collision resolution attempts
-	const minPositionChange = 0.01 // Minimum position change to continue iterations (in units)
-	const oscillationTolerance = 0.5 // Tolerance for detecting oscillation (in units)
-	var iterations int = 0
-	var finalPosition math.Vector
-	var finalCollision bool
-	var separationVector math.Vector
-	var previousPosition math.Vector
-	var positionHistory []math.Vector // Track position history to detect oscillation
-
-	// Try to resolve collisions up to maxIterations times
-	for iterations < maxIterations {
-		finalPosition = s.player.GetPosition()
-		finalCollision, separationVector, _ = s.CheckWallCollisionUsingWorldData(s.player, finalPosition)
-
-		if !finalCollision {
-			// No collision, we're done
-			break
-		}
-
-		// Calculate the normal vector (normalized direction of the separation vector)
-		magnitude := stdmath.Sqrt(separationVector.X*separationVector.X + separationVector.Y*separationVector.Y)
-		if magnitude <= 0 {
-			// Invalid magnitude, can't resolve
-			break
-		}
-
-		normalVector := math.Vector{
-			X: separationVector.X / magnitude,
-			Y: separationVector.Y / magnitude,
-		}
-
-		// Store the current position before correction
-		previousPosition = finalPosition
-
-		// Player is still inside a wall, push them out using ResolveCollision
-		// Use the magnitude of the separation vector as the depth
-		correctedPosition := physics.ResolveCollision(finalPosition, normalVector, magnitude)
-		s.player.SetPosition(correctedPosition)
-
-		// Calculate how much the position changed
-		positionChange := stdmath.Sqrt(
-			stdmath.Pow(correctedPosition.X-previousPosition.X, 2) +
-			stdmath.Pow(correctedPosition.Y-previousPosition.Y, 2))
-
-		// Debug output to help diagnose collision issues
-		if constants.DebugPlayerWallCollision {
-			fmt.Printf("Collision resolved (iteration %d): Player moved from (%.4f, %.4f) to (%.4f, %.4f), change: %.4f\n", 
-				iterations+1, previousPosition.X, previousPosition.Y, correctedPosition.X, correctedPosition.Y, positionChange)
-		}
-
-		// Add current position to history
-		positionHistory = append(positionHistory, correctedPosition)
-
-		// Check for oscillation patterns
-		if len(positionHistory) >= 3 {
-			// Check for specific oscillation pattern (alternating between two positions)
-			oscillationDetected := false
-
-			if len(positionHistory) >= 4 {
-				// Check if we're alternating between two positions (A-B-A pattern)
-				// Get the last three positions
-				posA := positionHistory[len(positionHistory)-3]
-				posB := positionHistory[len(positionHistory)-2]
-				posC := positionHistory[len(positionHistory)-1]
-
-				// Calculate distances
-				distAC := stdmath.Sqrt(
-					stdmath.Pow(posA.X-posC.X, 2) +
-					stdmath.Pow(posA.Y-posC.Y, 2))
-
-				// If positions A and C are very close, we might be oscillating
-				if distAC < oscillationTolerance {
-					oscillationDetected = true
-					if constants.DebugPlayerWallCollision {
-						fmt.Printf("Oscillation pattern detected: Positions (%.4f, %.4f) and (%.4f, %.4f) are repeating\n",
-							posA.X, posA.Y, posB.X, posB.Y)
-					}
-				}
-			}
+	// Only the cells the hull can reach have to be checked
+	reach := 0.0
+	for _, vertex := range hull.Vertices {
+		reach = stdmath.Max(reach, stdmath.Hypot(vertex.X, vertex.Y))
+	}
+	cellMinX := worldgen.FloorDiv(int(stdmath.Floor(position.X-reach)), worldgen.CellSize)
+	cellMaxX := worldgen.FloorDiv(int(stdmath.Ceil(position.X+reach)), worldgen.CellSize)
+	cellMinY := worldgen.FloorDiv(int(stdmath.Floor(position.Y-reach)), worldgen.CellSize)
+	cellMaxY := worldgen.FloorDiv(int(stdmath.Ceil(position.Y+reach)), worldgen.CellSize)
 
-			// Also check if current position is very close to any non-adjacent previous position
-			if !oscillationDetected && len(positionHistory) >= 3 {
-				for i := 0; i < len(positionHistory)-2; i++ {
-					oldPos := positionHistory[i]
-					distanceToOldPos := stdmath.Sqrt(
-						stdmath.Pow(correctedPosition.X-oldPos.X, 2) +
-						stdmath.Pow(correctedPosition.Y-oldPos.Y, 2))
-
-					if distanceToOldPos < oscillationTolerance {
-						oscillationDetected = true
-						if constants.DebugPlayerWallCollision {
-							fmt.Printf("Oscillation detected: Current position (%.4f, %.4f) is very close to previous position (%.4f, %.4f)\n",
-								correctedPosition.X, correctedPosition.Y, oldPos.X, oldPos.Y)
-						}
-						break
-					}
-				}
+	var segments []physics.WallSegment
+	for cellY := cellMinY; cellY <= cellMaxY; cellY++ {
+		for cellX := cellMinX; cellX <= cellMaxX; cellX++ {
+			cell := s.generatedWorld.GetCellAt(cellX*worldgen.CellSize, cellY*worldgen.CellSize)
+			if cell == nil || cell.Snippet == nil {
+				continue
 			}
 
-			if oscillationDetected {
-				if constants.DebugPlayerWallCollision {
-					fmt.Printf("Breaking collision resolution due to detected oscillation\n")
+			for _, outline := range cell.GetWallOutlinesInWorldCoordinates() {
+				for _, segment := range outline.Segments {
+					segments = append(segments, physics.WallSegment{A: segment.A, B: segment.B, Normal: segment.Normal})
 				}
-				break
-			}
-		}
-
-		// If position barely changed, break the loop
-		if positionChange < minPositionChange {
-			if constants.DebugPlayerWallCollision {
-				fmt.Printf("Breaking collision resolution: Position change (%.4f) below threshold (%.4f)\n", 
-					positionChange, minPositionChange)
 			}
-			break
 		}
-
-		iterations++
 	}
 
-	// If we hit the iteration limit, log it for debugging
-	if iterations == maxIterations && constants.DebugPlayerWallCollision {
-		fmt.Printf("WARNING: Hit maximum collision resolution iterations (%d). Player may be stuck.\n", maxIterations)
-	}
+	overlapping, _, _ := physics.CheckPolygonWallOverlap(hull, segments)
+	return overlapping
+}
 
-	// If there was a collision, reduce the player's velocity
-	if collisionX || collisionY || finalCollision {
-		// Reduce velocity to simulate friction with the wall
-		currentVelocity := s.player.GetVelocity()
-		s.player.SetVelocity(currentVelocity * 0) // Set to zero to prevent further movement into walls
+// Update handles the game logic and camera movement for the scene
+func (s *GameScene) Update(state *State) error {
+	// Update total elapsed time
+	s.totalTime += state.DeltaTime
 
-		// Debug output for collision velocity adjustment
-		if constants.DebugPlayerWallCollision {
-			fmt.Printf("Wall collision detected: Player velocity reduced from %.2f to 0\n", currentVelocity)
+	if s.generatedWorld == nil {
+		if err := s.Initialize(state); err != nil {
+			return err
 		}
 	}
 
-	// Update the player's collider in the collision manager
-	s.collisionManager.UpdateEntity(s.player, s.player.GetCollider())
+	// Check if player's health is zero and redirect to start screen
+	if s.player.GetHealth() <= 0 {
+		state.SceneManager.GoToScene(NewStartScene())
+		return nil
+	}
+
+	// Process the player's input; the movement is done by the physics world below
+	if err := s.player.Update(state.Input, state.DeltaTime); err != nil {
+		return err
+	}
 
 	// Update and check enemies
 	var activeEnemies []*enemies.Enemy
 	for _, enemy := range s.enemies {
 		if enemy.Update(state.DeltaTime) {
 			// Enemy should be removed (death animation completed)
-			s.collisionManager.RemoveEntity(enemy)
+			s.removeEnemy(enemy)
+			s.enemyStore.Forget(enemy)
 			continue
 		}
 
-		// Update enemy's collider in the collision manager
-		s.collisionManager.UpdateEntity(enemy, enemy.GetCollider())
-
-		// Check for collision between player and enemy using the collision manager
-		if !s.player.IsInvincible() {
-			// Use the collision manager to check for collision
-			// We use the player's collider radius plus a small buffer to ensure accurate detection
-			collision, collidedEntity := s.collisionManager.CheckCollision(enemy, enemy.GetCollider().Radius+5.0)
-			if collision && collidedEntity == s.player {
-				// Player hit by enemy, apply damage
-				s.player.TakeDamage(player.EnemyCollisionDamage)
-			}
-		}
-
 		activeEnemies = append(activeEnemies, enemy)
 	}
 	s.enemies = activeEnemies
 
-	// Handle player shooting and enemy shooting
+	// Handle player shooting; enemies shoot as their brains decide during the physics steps
 	s.handleShooting(state)
-	s.handleEnemyShooting(state)
 
-	// Update and check bullets
+	// Age the bullets and remove the ones that expired
 	var activeBullets []*projectiles.Bullet
 	for _, b := range s.bullets {
-		// Update bullet position and check if it's still active
-		shouldRemove := b.Update(state.DeltaTime)
-
-		if shouldRemove {
+		if b.Update(state.DeltaTime) {
+			s.removeBullet(b)
 			continue
 		}
+		activeBullets = append(activeBullets, b)
+	}
+	s.bullets = activeBullets
 
-		// Get bullet collider
-		bulletCollider := b.GetCollider()
-
-		// Store the bullet's previous position for continuous collision detection
-		prevPosition := math.Vector{
-			X: bulletCollider.Position.X - stdmath.Sin(b.Rotation)*b.GetSpeed()*state.DeltaTime*60.0,
-			Y: bulletCollider.Position.Y - stdmath.Cos(b.Rotation)*-b.GetSpeed()*state.DeltaTime*60.0,
+	// Fade out the debris and remove the pieces that expired
+	activeDebris := s.debris[:0]
+	for _, d := range s.debris {
+		if d.Update(state.DeltaTime) {
+			s.removeDebris(d)
+			continue
 		}
+		activeDebris = append(activeDebris, d)
+	}
+	s.debris = activeDebris
 
-		if b.IsPlayerBullet {
-			// Check for collision with enemies (only for player bullets)
-			bulletHit := false
+	if s.damageNumbers != nil {
+		s.damageNumbers.Update(state.DeltaTime)
+	}
 
-			// Use the collision manager to find nearby enemies
-			nearbyEntities := s.collisionManager.GetNearbyEntities(bulletCollider.Position, bulletCollider.Radius+40.0)
-			for _, entity := range nearbyEntities {
-				enemy, isEnemy := entity.(*enemies.Enemy)
-				if !isEnemy {
-					continue
-				}
+	// Move the player, enemies, bullets and debris in fixed steps for the elapsed time
+	// The player's hull and the debris bounce off and slide along the walls they hit
+	s.physicsWorld.Update(state.DeltaTime)
 
-				// Get the enemy's collider
-				enemyCollider := enemy.GetCollider()
-
-				// Use continuous collision detection to check for collision with this enemy
-				// For simplicity, we assume the enemy is stationary during this frame
-				collision, _, _, _ := physics.CheckContinuousCircleCircleCollision(
-					prevPosition, bulletCollider.Position, bulletCollider.Radius,
-					enemyCollider.Position, enemyCollider.Position, enemyCollider.Radius)
-
-				if collision {
-					// Enemy hit by player bullet, apply damage
-					if enemy.TakeDamage(b.Damage) {
-						// Enemy died from this damage
-						// Score or other game effects could be added here
-					}
-					bulletHit = true
-					break
-				}
-			}
+	// Add the bullets that the enemies fired during the steps
+	for _, b := range s.enemyShots {
+		s.addBullet(b)
+	}
+	s.enemyShots = s.enemyShots[:0]
+
+	// Update the colliders to the new positions and rotations
+	s.collisionManager.UpdateEntityPolygon(s.player, s.player.GetHullCollider())
+	for _, enemy := range s.enemies {
+		s.collisionManager.UpdateEntity(enemy, enemy.GetCollider())
+	}
 
-			if bulletHit {
-				// Don't add this bullet to active bullets (it hit an enemy)
+	// Ricochet the bullets off the walls they hit, or remove them when they have no bounces left
+	activeBullets = s.bullets[:0]
+	for _, b := range s.bullets {
+		// Sweep the bullet along its path this frame, so it cannot pass through thin walls
+		start := b.GetLastPosition()
+		bulletMovement := math.Vector{X: b.Position.X - start.X, Y: b.Position.Y - start.Y}
+		if hit, hitWall := s.collisionManager.SweepCircle(
+			physics.CircleCollider{Position: start, Radius: b.GetCollider().Radius}, bulletMovement); hitWall {
+			if !b.Ricochet(hit) {
+				s.removeBullet(b)
 				continue
 			}
-		} else {
-			// Check for collision with player (only for enemy bullets)
-			if !s.player.IsInvincible() {
-				// Get the player's collider
-				playerCollider := s.player.GetCollider()
-
-				// Use continuous collision detection to check for collision with the player
-				// For simplicity, we assume the player's position doesn't change significantly during this frame
-				collision, _, _, _ := physics.CheckContinuousCircleCircleCollision(
-					prevPosition, bulletCollider.Position, bulletCollider.Radius,
-					playerCollider.Position, playerCollider.Position, playerCollider.Radius)
-
-				if collision {
-					// Player hit by enemy bullet, apply damage
-					s.player.TakeDamage(b.Damage)
-					// Don't add this bullet to active bullets (it hit the player)
-					continue
-				}
-			}
 		}
 
-		// Check for collision with walls using continuous collision detection
-		// We reuse the previous position calculated earlier
-
-		// Get nearby walls
-		wallColliders := s.collisionManager.GetNearbyWalls(bulletCollider.Position, bulletCollider.Radius+40.0)
-
-		// Check for collision with any wall
-		bulletHitWall := false
+		// Hits on the player and enemies are found along the whole path of the bullet
+		s.collisionManager.UpdateEntityPolygon(b, b.GetTrailCollider())
+		activeBullets = append(activeBullets, b)
+	}
+	s.bullets = activeBullets
 
-		for _, wall := range wallColliders {
-			// Use continuous collision detection to check for collision with this wall
-			collision, _, _, _ := physics.CheckContinuousCircleCollision(
-				prevPosition, bulletCollider.Position, bulletCollider.Radius, wall)
+	// Deliver the contacts between the player, enemies and bullets to the contact listener
+	s.collisionManager.UpdateContacts()
 
-			if collision {
-				bulletHitWall = true
-				break
+	// Remove the bullets that hit the player or an enemy
+	if len(s.spentBullets) > 0 {
+		activeBullets = s.bullets[:0]
+		for _, b := range s.bullets {
+			if s.spentBullets[b] {
+				s.removeBullet(b)
+				continue
 			}
+			activeBullets = append(activeBullets, b)
 		}
+		s.bullets = activeBullets
+		clear(s.spentBullets)
+	}
 
-		if bulletHitWall {
-			// Don't add this bullet to active bullets (it hit a wall)
-			// In a more advanced implementation, we could add visual effects at the collision point
-			// or bounce the bullet off the wall using the collision normal
+	// Remove the collected rewards, pulse the others
+	activeRewards := s.rewards[:0]
+	for _, reward := range s.rewards {
+		if reward.IsCollected() {
+			s.collisionManager.RemoveEntity(reward)
 			continue
 		}
-
-		// Keep the bullet if it shouldn't be removed
-		activeBullets = append(activeBullets, b)
+		reward.Update(state.DeltaTime)
+		activeRewards = append(activeRewards, reward)
 	}
-	s.bullets = activeBullets
+	s.rewards = activeRewards
 
-	// The AABB collision detection is now handled earlier in the Update method
-	// We've replaced the continuous collision detection with axis-separated AABB collision detection
+	// Close the arena the player entered, or open it once its boss is destroyed
+	s.updateArenas()
 
 	position := s.player.GetPosition()
 	screenWidth := float64(state.World.GetWidth())
 	screenHeight := float64(state.World.GetHeight())
 
+	// Load and unload chunks around the player. Their walls and enemies are
+	// registered and removed by the chunk listener.
 	s.generatedWorld.SetPlayerPosition(position.X, position.Y)
 
-	// Register walls from newly loaded chunks with the collision manager
-	s.registerWalls()
+	// Let the director react to the damage taken and send the enemies of its waves out of view
+	if s.director != nil {
+		if health := s.player.GetHealth(); health < s.lastHealth {
+			s.director.PlayerDamaged((s.lastHealth - health) / player.MaxPlayerHealth)
+		}
+		viewRadius := stdmath.Hypot(screenWidth, screenHeight) / 2
+		for _, enemy := range s.director.Update(s.generatedWorld, position, viewRadius, s.enemies, state.DeltaTime) {
+			s.addEnemy(enemy)
+		}
+	}
+	s.lastHealth = s.player.GetHealth()
+
+	// Track the biome of the player and fade the light radius towards its setting
+	s.currentBiome = s.generatedWorld.GetBiomeAt(int(position.X), int(position.Y))
+	targetLightRadius := defaultLightRadius
+	if s.currentBiome != nil && s.currentBiome.LightRadius > 0 {
+		targetLightRadius = s.currentBiome.LightRadius
+	}
+	lightFactor := 1.0 - stdmath.Pow(1.0-lightRadiusSmoothing, state.DeltaTime*60.0)
+	s.lightRadius += (targetLightRadius - s.lightRadius) * lightFactor
 
 	// Camera system implementation
+	camera := config.Get().Camera
 	playerVelocity := s.player.GetVelocity()
 	cameraTargetX := -s.cameraPosition.X
 	cameraTargetY := -s.cameraPosition.Y
 	offsetX := position.X - cameraTargetX
 	offsetY := position.Y - cameraTargetY
-	deadZoneWidth := screenWidth * constants.CameraDeadZoneX
-	deadZoneHeight := screenHeight * constants.CameraDeadZoneY
+	deadZoneWidth := screenWidth * camera.DeadZoneX
+	deadZoneHeight := screenHeight * camera.DeadZoneY
 
 	var newCameraTargetX, newCameraTargetY float64
 
-	if playerVelocity >= constants.CameraVelocityThreshold {
+	if playerVelocity >= camera.VelocityThreshold {
 		// Use deadzone-based camera following for normal movement speed
 		newCameraTargetX = cameraTargetX
 		newCameraTargetY = cameraTargetY
@@ -774,16 +788,16 @@ func (s *GameScene) Update(state *State) error {
 		}
 	} else {
 		// Gradually center on player when moving slowly
-		centeringFactor := (constants.CameraVelocityThreshold - playerVelocity) / constants.CameraVelocityThreshold
-		newCameraTargetX = cameraTargetX + (position.X-cameraTargetX)*centeringFactor*constants.CameraCenteringStrength
-		newCameraTargetY = cameraTargetY + (position.Y-cameraTargetY)*centeringFactor*constants.CameraCenteringStrength
+		centeringFactor := (camera.VelocityThreshold - playerVelocity) / camera.VelocityThreshold
+		newCameraTargetX = cameraTargetX + (position.X-cameraTargetX)*centeringFactor*camera.CenteringStrength
+		newCameraTargetY = cameraTargetY + (position.Y-cameraTargetY)*centeringFactor*camera.CenteringStrength
 	}
 
 	targetCameraX := -newCameraTargetX
 	targetCameraY := -newCameraTargetY
 
 	// Frame-rate independent camera smoothing
-	interpolationFactor := 1.0 - stdmath.Pow(1.0-constants.CameraInterpolationFactor, state.DeltaTime*60.0)
+	interpolationFactor := 1.0 - stdmath.Pow(1.0-camera.InterpolationFactor, state.DeltaTime*60.0)
 	s.cameraPosition.X += (targetCameraX - s.cameraPosition.X) * interpolationFactor
 	s.cameraPosition.Y += (targetCameraY - s.cameraPosition.Y) * interpolationFactor
 
@@ -819,7 +833,7 @@ func (s *GameScene) Draw(screen *ebiten.Image, state *State) {
 
 	// Scale background to fit screen while maintaining aspect ratio
 	bgOp := &ebiten.DrawImageOptions{}
-	gameBg := assets.GetGameBackground()
+	gameBg := s.background()
 	bgWidth := float64(gameBg.Bounds().Dx())
 	bgHeight := float64(gameBg.Bounds().Dy())
 
@@ -846,12 +860,26 @@ func (s *GameScene) Draw(screen *ebiten.Image, state *State) {
 		enemy.Draw(tempScreen, s.cameraPosition.X, s.cameraPosition.Y, worldWidth, worldHeight)
 	}
 
+	for _, d := range s.debris {
+		d.Draw(tempScreen, s.cameraPosition.X, s.cameraPosition.Y, worldWidth, worldHeight)
+	}
+
+	for _, reward := range s.rewards {
+		reward.Draw(tempScreen, s.cameraPosition.X, s.cameraPosition.Y, worldWidth, worldHeight)
+	}
+
+	s.drawArenaGates(tempScreen, worldWidth, worldHeight)
+
 	for _, b := range s.bullets {
 		b.Draw(tempScreen, s.cameraPosition.X, s.cameraPosition.Y, worldWidth, worldHeight)
 	}
 
 	s.player.Draw(tempScreen, s.cameraPosition.X, s.cameraPosition.Y)
 
+	if s.damageNumbers != nil {
+		s.damageNumbers.Draw(tempScreen, s.cameraPosition.X, s.cameraPosition.Y, worldWidth, worldHeight)
+	}
+
 	// Apply lighting effect with brightness shader
 	if s.brightnessShader != nil {
 		playerPos := s.player.GetPosition()
@@ -862,7 +890,7 @@ func (s *GameScene) Draw(screen *ebiten.Image, state *State) {
 		op.Images[0] = tempScreen
 		op.Uniforms = map[string]any{
 			"PlayerPos": []float32{float32(screenPosX), float32(screenPosY)},
-			"Radius":    float32(float64(worldWidth) * 0.75),
+			"Radius":    float32(float64(worldWidth) * s.lightRadius),
 		}
 
 		screen.DrawRectShader(worldWidth, worldHeight, s.brightnessShader.Shader(), op)
@@ -911,9 +939,13 @@ func (s *GameScene) Draw(screen *ebiten.Image, state *State) {
 	}
 }
 
-const fireInterval = 0.25
-const enemyFireInterval = 0.5
-const enemyShootRadius = 150.0
+// background returns the background image of the biome the player is in
+func (s *GameScene) background() *ebiten.Image {
+	if s.currentBiome != nil && s.currentBiome.Background != "" {
+		return assets.GetImage(s.currentBiome.Background)
+	}
+	return assets.GetGameBackground()
+}
 
 // handleShooting manages bullet firing based on keyboard or touch input
 func (s *GameScene) handleShooting(state *State) {
@@ -930,6 +962,7 @@ func (s *GameScene) handleShooting(state *State) {
 		holding = true
 	}
 
+	fireInterval := config.Get().Weapons.FireInterval
 	if holding {
 		s.timeSinceLastShot += state.DeltaTime
 		if s.timeSinceLastShot >= fireInterval {
@@ -941,31 +974,6 @@ func (s *GameScene) handleShooting(state *State) {
 	}
 }
 
-// handleEnemyShooting makes enemies fire bullets at the player when in range
-func (s *GameScene) handleEnemyShooting(state *State) {
-	playerPos := s.player.GetPosition()
-	for _, enemy := range s.enemies {
-		dx := playerPos.X - enemy.Position.X
-		dy := playerPos.Y - enemy.Position.Y
-		if dx*dx+dy*dy <= enemyShootRadius*enemyShootRadius {
-			enemy.TimeSinceLastShot += state.DeltaTime
-			if enemy.TimeSinceLastShot >= enemyFireInterval {
-				rot := stdmath.Atan2(-dy, dx)
-				// Create a copy of the enemy position to ensure bullets spawn from the enemy
-				// Add an offset in the direction of the player to make bullets spawn from the edge of the enemy
-				offsetDistance := 10.0 // Distance from enemy center to spawn the bullet
-				offsetX := stdmath.Sin(rot) * offsetDistance
-				offsetY := stdmath.Cos(rot) * -offsetDistance
-				enemyPos := math.Vector{X: enemy.Position.X + offsetX, Y: enemy.Position.Y + offsetY}
-				s.bullets = append(s.bullets, projectiles.NewLinearBullet(enemyPos, rot, assets.EnemyBullet, false))
-				enemy.TimeSinceLastShot = 0
-			}
-		} else if enemy.TimeSinceLastShot > enemyFireInterval {
-			enemy.TimeSinceLastShot = enemyFireInterval
-		}
-	}
-}
-
 // spawnBullet creates a new bullet at the player's position with the player's rotation
 func (s *GameScene) spawnBullet() {
 	pos := s.player.GetPosition()
@@ -981,5 +989,142 @@ func (s *GameScene) spawnBullet() {
 	bullet := projectiles.NewBullet(bulletPos, rot, assets.PlayerBullet, true)
 
 	// Add the bullet to the game
+	s.addBullet(bullet)
+}
+
+// addBullet adds a bullet to the game and registers it on the layer of its side
+func (s *GameScene) addBullet(bullet *projectiles.Bullet) {
+	layer := physics.LayerEnemyBullet
+	if bullet.IsPlayerBullet {
+		layer = physics.LayerPlayerBullet
+	}
+
 	s.bullets = append(s.bullets, bullet)
+	s.collisionManager.RegisterEntityPolygon(bullet, bullet.GetTrailCollider())
+	s.collisionManager.SetEntityLayers(bullet, physics.NewCollisionLayers(layer))
+	s.physicsWorld.AddBody(bullet.NewBody())
+}
+
+// removeBullet removes a bullet from the collision manager and the physics world.
+// The caller removes it from the bullets of the scene.
+func (s *GameScene) removeBullet(bullet *projectiles.Bullet) {
+	s.collisionManager.RemoveEntity(bullet)
+	s.physicsWorld.RemoveBody(bullet.Body())
+}
+
+// addEnemy adds an enemy to the game, registers it on the enemy layer and connects its brain or boss to the scene
+func (s *GameScene) addEnemy(enemy *enemies.Enemy) {
+	s.enemies = append(s.enemies, enemy)
+	s.collisionManager.RegisterEntity(enemy, enemy.GetCollider())
+	s.collisionManager.SetEntityLayers(enemy, physics.NewCollisionLayers(physics.LayerEnemy))
+	s.physicsWorld.AddBody(enemy.NewBody())
+	enemy.SetEnvironment(&gameEnemyEnvironment{scene: s})
+}
+
+// removeEnemy removes an enemy from the collision manager and the physics world.
+// The caller removes it from the enemies of the scene.
+func (s *GameScene) removeEnemy(enemy *enemies.Enemy) {
+	s.collisionManager.RemoveEntity(enemy)
+	s.physicsWorld.RemoveBody(enemy.Body())
+}
+
+// spawnDebris bursts debris out of the position of a destroyed enemy.
+// The pieces only collide with walls, so they are registered on the debris layer.
+func (s *GameScene) spawnDebris(position math.Vector) {
+	for _, d := range projectiles.SpawnDebris(position, enemyDebrisCount, enemyDebrisSpeed) {
+		s.debris = append(s.debris, d)
+		s.collisionManager.RegisterEntity(d, d.GetCollider())
+		s.collisionManager.SetEntityLayers(d, physics.NewCollisionLayers(physics.LayerDebris))
+		s.physicsWorld.AddBody(d.NewBody())
+	}
+}
+
+// removeDebris removes a piece of debris from the collision manager and the physics world.
+// The caller removes it from the debris of the scene.
+func (s *GameScene) removeDebris(d *projectiles.Debris) {
+	s.collisionManager.RemoveEntity(d)
+	s.physicsWorld.RemoveBody(d.Body())
+}
+
+// updateArenas closes an arena once the player is well inside it and its boss is alive, and wakes the boss.
+// When a boss is destroyed its arena opens again for good and the boss drops its reward.
+func (s *GameScene) updateArenas() {
+	for key, boss := range s.bosses {
+		if !boss.IsDying {
+			continue
+		}
+		if s.lockedArena != nil && s.lockedArena.X == key.X && s.lockedArena.Y == key.Y {
+			s.unlockArena()
+		}
+		reward := boss.Boss.Definition.Reward
+		s.addReward(pickups.NewReward(boss.Position, reward.Health, reward.Score))
+		s.defeatedArenas[key] = true
+		delete(s.bosses, key)
+	}
+
+	if s.lockedArena != nil {
+		return
+	}
+
+	position := s.player.GetPosition()
+	cell := s.generatedWorld.GetCellAt(int(stdmath.Floor(position.X)), int(stdmath.Floor(position.Y)))
+	if cell == nil || !cell.IsArena {
+		return
+	}
+	boss := s.bosses[worldgen.CellCoord{X: cell.X, Y: cell.Y}]
+	if boss == nil {
+		return
+	}
+
+	localX := position.X - float64(cell.X*worldgen.CellSize)
+	localY := position.Y - float64(cell.Y*worldgen.CellSize)
+	if stdmath.Min(stdmath.Min(localX, worldgen.CellSize-localX), stdmath.Min(localY, worldgen.CellSize-localY)) < arenaLockMargin {
+		return
+	}
+
+	s.lockedArena = cell
+	for _, gate := range cell.ArenaGates() {
+		segment := physics.WallSegment{A: gate.A, B: gate.B, Normal: gate.Normal}
+		s.collisionManager.RegisterWallSegment(segment)
+		s.arenaGates = append(s.arenaGates, segment)
+	}
+	boss.Boss.Wake()
+}
+
+// unlockArena removes the gates of the locked arena
+func (s *GameScene) unlockArena() {
+	for _, segment := range s.arenaGates {
+		s.collisionManager.RemoveWallSegment(segment)
+	}
+	s.arenaGates = nil
+	s.lockedArena = nil
+}
+
+// drawArenaGates draws the gates of the locked arena as lines
+func (s *GameScene) drawArenaGates(screen *ebiten.Image, worldWidth, worldHeight int) {
+	if len(s.arenaGates) == 0 {
+		return
+	}
+	if gateImage == nil {
+		gateImage = ebiten.NewImage(1, 1)
+		gateImage.Fill(color.White)
+	}
+
+	for _, gate := range s.arenaGates {
+		dx, dy := gate.B.X-gate.A.X, gate.B.Y-gate.A.Y
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(0, -0.5)
+		op.GeoM.Scale(stdmath.Hypot(dx, dy), gateThickness)
+		op.GeoM.Rotate(stdmath.Atan2(dy, dx))
+		op.GeoM.Translate(float64(worldWidth)/2+gate.A.X+s.cameraPosition.X, float64(worldHeight)/2+gate.A.Y+s.cameraPosition.Y)
+		op.ColorScale.ScaleWithColor(gateColor)
+		screen.DrawImage(gateImage, op)
+	}
+}
+
+// addReward adds a reward to the game and registers it on the pickup layer, where only the player touches it
+func (s *GameScene) addReward(reward *pickups.Reward) {
+	s.rewards = append(s.rewards, reward)
+	s.collisionManager.RegisterEntity(reward, reward.GetCollider())
+	s.collisionManager.SetEntityLayers(reward, physics.NewCollisionLayers(physics.LayerPickup))
 }
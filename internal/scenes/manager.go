@@ -4,6 +4,7 @@ import (
 	"discoveryx/internal/core/ecs"
 	"discoveryx/internal/input"
 	"discoveryx/internal/screen"
+	"discoveryx/internal/utils/random"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -48,6 +49,7 @@ type State struct {
 	Input        *input.Manager // Access to input state for handling user interactions
 	DeltaTime    float64        // Time elapsed since last frame for frame-rate independent updates
 	World        ecs.World      // The ECS world containing all game entities
+	Seed         random.Seed    // Run seed requested for new runs (0 picks a fresh seed per run)
 }
 
 // SceneManager handles scene transitions and manages the currently active scene.
@@ -64,6 +66,7 @@ type SceneManager struct {
 	transitionFrom  *ebiten.Image   // Render target for the current scene during transitions
 	transitionTo    *ebiten.Image   // Render target for the next scene during transitions
 	screenManager   *screen.Manager // Reference to the screen manager for dimension information
+	seed            random.Seed     // Run seed passed to scenes through the State
 }
 
 // Draw renders the current scene or a transition between scenes.
@@ -81,6 +84,7 @@ func (s *SceneManager) Draw(r *ebiten.Image, inputManager *input.Manager, deltaT
 		Input:        inputManager,
 		DeltaTime:    deltaTime,
 		World:        world,
+		Seed:         s.seed,
 	}
 
 	// If no transition is in progress, simply draw the current scene directly
@@ -184,6 +188,7 @@ func (s *SceneManager) Update(inputManager *input.Manager, deltaTime float64, wo
 			Input:        inputManager,
 			DeltaTime:    deltaTime,
 			World:        world,
+			Seed:         s.seed,
 		})
 	}
 
@@ -245,6 +250,14 @@ func (s *SceneManager) SetScreenManager(manager *screen.Manager) {
 	s.screenManager = manager
 }
 
+// SetSeed sets the run seed handed to scenes through the State.
+// A fixed seed makes every new run identical, which is used for bug reports,
+// daily-challenge runs and regression tests. A zero seed lets each run pick
+// a fresh seed of its own.
+func (s *SceneManager) SetSeed(seed random.Seed) {
+	s.seed = seed
+}
+
 // GoToScene changes to a new scene with a smooth transition effect.
 // If this is the first scene being set (no current scene), it becomes
 // the current scene immediately without a transition.
@@ -0,0 +1,82 @@
+package random
+
+import "testing"
+
+// TestDeriveIsDeterministic tests that the same seed always yields the same streams
+func TestDeriveIsDeterministic(t *testing.T) {
+	seed := Seed(42)
+
+	for _, stream := range []Stream{StreamWorldGen, StreamEnemySpawn, StreamGameplay} {
+		if seed.Derive(stream) != Seed(42).Derive(stream) {
+			t.Errorf("Derive(%s) is not deterministic", stream)
+		}
+	}
+
+	a := seed.NewRand(StreamGameplay)
+	b := seed.NewRand(StreamGameplay)
+	for i := 0; i < 10; i++ {
+		if a.Int63() != b.Int63() {
+			t.Fatalf("NewRand(%s) sequences diverged at step %d", StreamGameplay, i)
+		}
+	}
+}
+
+// TestDeriveSeparatesStreams tests that streams and neighbouring seeds are independent
+func TestDeriveSeparatesStreams(t *testing.T) {
+	seed := Seed(42)
+
+	if seed.Derive(StreamWorldGen) == seed.Derive(StreamEnemySpawn) {
+		t.Error("Expected different values for different streams")
+	}
+
+	if seed.Derive(StreamBoss) == seed.Derive(StreamEnemySpawn) {
+		t.Error("Expected the boss stream to be independent of the enemy spawns")
+	}
+
+	if Seed(42).Derive(StreamWorldGen) == Seed(43).Derive(StreamWorldGen) {
+		t.Error("Expected different values for different seeds")
+	}
+}
+
+// TestParseSeed tests parsing numeric and textual seeds
+func TestParseSeed(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected Seed
+	}{
+		{name: "Positive number", input: "12345", expected: 12345},
+		{name: "Negative number", input: "-7", expected: -7},
+		{name: "Surrounding whitespace", input: " 99 ", expected: 99},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ParseSeed(tc.input)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+
+	first, _ := ParseSeed("2025-06-01")
+	again, _ := ParseSeed("2025-06-01")
+	next, _ := ParseSeed("2025-06-02")
+	if first != again {
+		t.Error("Expected textual seeds to be stable")
+	}
+	if first == next {
+		t.Error("Expected different textual seeds to differ")
+	}
+
+	if seed, _ := ParseSeed(Seed(-123).String()); seed != Seed(-123) {
+		t.Error("Expected String to round-trip through ParseSeed")
+	}
+
+	if _, err := ParseSeed(" 0 "); err == nil {
+		t.Error("Expected seed 0 to be rejected, as it picks a fresh seed per run")
+	}
+}